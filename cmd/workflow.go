@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/repository"
+	"github.com/certimate-go/certimate/internal/workflow"
+)
+
+func NewWorkflowCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "workflow",
+		Short: "Manages workflows",
+	}
+
+	command.AddCommand(workflowExportCommand(app))
+	command.AddCommand(workflowImportCommand(app))
+
+	return command
+}
+
+func workflowExportCommand(_ core.App) *cobra.Command {
+	var flagIds []string
+	var flagFormat string
+	var flagOutput string
+
+	command := &cobra.Command{
+		Use:          "export",
+		Short:        "Exports workflows as a portable bundle",
+		Example:      "workflow export --id abc123 --format yaml --output ./workflows.yaml",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := newWorkflowServiceForCommand().ExportBundle(cmd.Context(), &dtos.WorkflowExportBundleReq{
+				WorkflowIds: flagIds,
+				Format:      flagFormat,
+			})
+			if err != nil {
+				return err
+			}
+
+			if flagOutput == "" {
+				_, err = cmd.OutOrStdout().Write(res.Data)
+				return err
+			}
+
+			return os.WriteFile(flagOutput, res.Data, 0o644)
+		},
+	}
+
+	command.Flags().StringArrayVar(&flagIds, "id", nil, "ID of the workflow to export, can be repeated (default all published workflows)")
+	command.Flags().StringVar(&flagFormat, "format", "json", "bundle format, 'json' or 'yaml'")
+	command.Flags().StringVarP(&flagOutput, "output", "o", "", "path of the output file (default stdout)")
+
+	return command
+}
+
+func workflowImportCommand(_ core.App) *cobra.Command {
+	var flagDryRun bool
+
+	command := &cobra.Command{
+		Use:          "import <file>",
+		Short:        "Imports workflows from a portable bundle",
+		Example:      "workflow import ./workflows.yaml --dry-run",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			res, err := newWorkflowServiceForCommand().ImportBundle(cmd.Context(), &dtos.WorkflowImportBundleReq{
+				Data:   data,
+				DryRun: flagDryRun,
+			})
+			if err != nil {
+				return err
+			}
+
+			output, err := json.MarshalIndent(res, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(output))
+
+			if !flagDryRun {
+				// 定时任务由服务进程在启动时注册，因此正在运行的服务需重启后才能应用新的触发计划
+				fmt.Fprintln(cmd.ErrOrStderr(), "Note: restart the running server to apply schedule changes of the imported workflows.")
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(&flagDryRun, "dry-run", false, "check the bundle and report the changes without writing them")
+
+	return command
+}
+
+func newWorkflowServiceForCommand() *workflow.WorkflowService {
+	return workflow.NewWorkflowService(
+		repository.NewWorkflowRepository(),
+		repository.NewWorkflowRunRepository(),
+		repository.NewWorkflowVersionRepository(),
+		repository.NewWorkflowApprovalRepository(),
+		repository.NewAccessRepository(),
+		repository.NewCertificateRepository(),
+		repository.NewPrivateCARepository(),
+	)
+}
@@ -0,0 +1,213 @@
+package acmeaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/go-acme/lego/v5/acme"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type ACMEAccountService struct {
+	acmeAccountRepo acmeAccountRepository
+}
+
+func NewACMEAccountService(acmeAccountRepo acmeAccountRepository) *ACMEAccountService {
+	return &ACMEAccountService{
+		acmeAccountRepo: acmeAccountRepo,
+	}
+}
+
+func (s *ACMEAccountService) RolloverKey(ctx context.Context, req *dtos.ACMEAccountRolloverKeyReq) (*dtos.ACMEAccountRolloverKeyResp, error) {
+	acmeAccount, acmeClient, err := s.prepare(ctx, req.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rollover acme account key: %w", err)
+	}
+
+	newPrivateKey, err := certacme.GenerateAccountPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rollover acme account key: could not generate new private key: %w", err)
+	}
+
+	// 先持久化新私钥，再向 CA 发起轮换，以免轮换成功后因保存失败而无法再访问账户
+	acmeAccount.PendingPrivateKey = newPrivateKey
+	if _, err := s.acmeAccountRepo.Save(ctx, acmeAccount); err != nil {
+		return nil, fmt.Errorf("failed to rollover acme account key: could not save new private key: %w", err)
+	}
+
+	if _, err := acmeClient.RolloverAccountKey(ctx, &certacme.RolloverAccountKeyRequest{PrivateKey: newPrivateKey}); err != nil {
+		// CA 明确拒绝时仍使用旧私钥，可清除新私钥；其他错误（如网络中断）无法确定轮换结果，保留新私钥以便刷新账户时核对
+		var problem *acme.ProblemDetails
+		if errors.As(err, &problem) {
+			acmeAccount.PendingPrivateKey = ""
+			if _, serr := s.acmeAccountRepo.Save(ctx, acmeAccount); serr != nil {
+				app.GetLogger().Warn(fmt.Sprintf("failed to clear pending private key of acme account #%s", acmeAccount.Id), slog.Any("error", serr))
+			}
+		}
+
+		return nil, fmt.Errorf("failed to rollover acme account key: %w", err)
+	}
+
+	// 账户 URL 保持不变，已签发证书的 ARI 续期信息不受影响
+	acmeAccount.PrivateKey = newPrivateKey
+	acmeAccount.PendingPrivateKey = ""
+	if _, err := s.acmeAccountRepo.Save(ctx, acmeAccount); err != nil {
+		// 新私钥仍保存在待轮换字段中，刷新账户时将自动生效；这里再输出到日志，以防数据库不可用
+		app.GetLogger().Error(fmt.Sprintf("the key of acme account #%s has been rolled over, but failed to save the new private key", acmeAccount.Id),
+			slog.String("acmeAcctUrl", acmeAccount.ACMEAccountUrl),
+			slog.String("newPrivateKey", newPrivateKey),
+			slog.Any("error", err),
+		)
+		return nil, fmt.Errorf("failed to rollover acme account key: the key has been rolled over, but could not save the new private key: %w", err)
+	}
+
+	return &dtos.ACMEAccountRolloverKeyResp{}, nil
+}
+
+func (s *ACMEAccountService) UpdateContacts(ctx context.Context, req *dtos.ACMEAccountUpdateContactsReq) (*dtos.ACMEAccountUpdateContactsResp, error) {
+	emails := make([]string, 0, len(req.Emails))
+	for _, email := range req.Emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+
+		emails = append(emails, email)
+	}
+
+	acmeAccount, acmeClient, err := s.prepare(ctx, req.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update acme account contacts: %w", err)
+	}
+
+	updateResp, err := acmeClient.UpdateAccountContacts(ctx, &certacme.UpdateAccountContactsRequest{Emails: emails})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update acme account contacts: %w", err)
+	}
+
+	// 注意：字段 `email` 用于匹配工作流中配置的账户，因此这里不做修改
+	acmeAccount.ResourceObject = s.mergeResourceObject(acmeAccount.ResourceObject, updateResp.Account)
+	if _, err := s.acmeAccountRepo.Save(ctx, acmeAccount); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEAccountUpdateContactsResp{}, nil
+}
+
+func (s *ACMEAccountService) Deactivate(ctx context.Context, req *dtos.ACMEAccountDeactivateReq) (*dtos.ACMEAccountDeactivateResp, error) {
+	acmeAccount, acmeClient, err := s.prepare(ctx, req.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate acme account: %w", err)
+	}
+
+	if _, err := acmeClient.DeactivateAccount(ctx, &certacme.DeactivateAccountRequest{}); err != nil {
+		return nil, fmt.Errorf("failed to deactivate acme account: %w", err)
+	}
+
+	if acmeAccount.ResourceObject == nil {
+		acmeAccount.ResourceObject = &acme.Account{}
+	}
+	acmeAccount.ResourceObject.Status = acme.StatusDeactivated
+	if _, err := s.acmeAccountRepo.Save(ctx, acmeAccount); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEAccountDeactivateResp{}, nil
+}
+
+func (s *ACMEAccountService) Refresh(ctx context.Context, req *dtos.ACMEAccountRefreshReq) (*dtos.ACMEAccountRefreshResp, error) {
+	acmeAccount, err := s.acmeAccountRepo.GetById(ctx, req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	acmeClient, err := certacme.NewACMEClientWithAccount(acmeAccount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh acme account: could not initialize acme config: %w", err)
+	}
+
+	queryResp, err := acmeClient.QueryAccount(ctx, &certacme.QueryAccountRequest{})
+	if err != nil && acmeAccount.PendingPrivateKey != "" {
+		// 上一次轮换的结果未知，尝试使用新私钥访问账户，成功则说明轮换已在 CA 生效
+		queryResp, err = s.queryWithPendingKey(ctx, acmeAccount)
+		if err == nil {
+			acmeAccount.PrivateKey = acmeAccount.PendingPrivateKey
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh acme account: %w", err)
+	}
+
+	acmeAccount.PendingPrivateKey = ""
+	acmeAccount.ResourceObject = s.mergeResourceObject(acmeAccount.ResourceObject, queryResp.Account)
+	if _, err := s.acmeAccountRepo.Save(ctx, acmeAccount); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEAccountRefreshResp{
+		Status:  acmeAccount.ResourceObject.Status,
+		Contact: acmeAccount.ResourceObject.Contact,
+	}, nil
+}
+
+func (s *ACMEAccountService) prepare(ctx context.Context, accountId string) (*domain.ACMEAccount, *certacme.ACMEClient, error) {
+	acmeAccount, err := s.acmeAccountRepo.GetById(ctx, accountId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if acmeAccount.ACMEAccountUrl == "" {
+		return nil, nil, fmt.Errorf("could not manage an acme account which is not registered")
+	}
+	if acmeAccount.IsDeactivated() {
+		return nil, nil, fmt.Errorf("could not manage an acme account which is already deactivated")
+	}
+	if acmeAccount.PendingPrivateKey != "" {
+		return nil, nil, fmt.Errorf("the previous key rollover of this acme account is unfinished, please refresh it first")
+	}
+
+	acmeClient, err := certacme.NewACMEClientWithAccount(acmeAccount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not initialize acme config: %w", err)
+	}
+
+	return acmeAccount, acmeClient, nil
+}
+
+func (s *ACMEAccountService) queryWithPendingKey(ctx context.Context, acmeAccount *domain.ACMEAccount) (*certacme.QueryAccountResponse, error) {
+	pendingAccount := *acmeAccount
+	pendingAccount.PrivateKey = acmeAccount.PendingPrivateKey
+
+	acmeClient, err := certacme.NewACMEClientWithAccount(&pendingAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	return acmeClient.QueryAccount(ctx, &certacme.QueryAccountRequest{})
+}
+
+func (s *ACMEAccountService) mergeResourceObject(local *acme.Account, remote *acme.Account) *acme.Account {
+	if remote == nil {
+		return local
+	}
+
+	merged := *remote
+	if local != nil {
+		// 部分 CA 在响应中不会返回以下字段，保留本地值
+		if merged.Orders == "" {
+			merged.Orders = local.Orders
+		}
+		if !merged.TermsOfServiceAgreed {
+			merged.TermsOfServiceAgreed = local.TermsOfServiceAgreed
+		}
+	}
+
+	return &merged
+}
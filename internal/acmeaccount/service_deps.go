@@ -0,0 +1,12 @@
+package acmeaccount
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type acmeAccountRepository interface {
+	GetById(ctx context.Context, id string) (*domain.ACMEAccount, error)
+	Save(ctx context.Context, acmeAccount *domain.ACMEAccount) (*domain.ACMEAccount, error)
+}
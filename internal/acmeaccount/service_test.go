@@ -0,0 +1,189 @@
+package acmeaccount
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type mockACMEAccountRepository struct {
+	account  domain.ACMEAccount
+	saves    int
+	saveFunc func(n int, acmeAccount *domain.ACMEAccount) error
+}
+
+func (r *mockACMEAccountRepository) GetById(ctx context.Context, id string) (*domain.ACMEAccount, error) {
+	acmeAccount := r.account
+	return &acmeAccount, nil
+}
+
+func (r *mockACMEAccountRepository) Save(ctx context.Context, acmeAccount *domain.ACMEAccount) (*domain.ACMEAccount, error) {
+	r.saves++
+	if r.saveFunc != nil {
+		if err := r.saveFunc(r.saves, acmeAccount); err != nil {
+			return acmeAccount, err
+		}
+	}
+
+	r.account = *acmeAccount
+	return acmeAccount, nil
+}
+
+type mockACMEServer struct {
+	*httptest.Server
+
+	keyChanges   atomic.Int32
+	keyChangeErr bool
+
+	queries     atomic.Int32
+	queryErrors int32
+}
+
+func newMockACMEServer(t *testing.T) *mockACMEServer {
+	s := &mockACMEServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dir", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"newNonce":   s.URL + "/nonce",
+			"newAccount": s.URL + "/new-acct",
+			"newOrder":   s.URL + "/new-order",
+			"revokeCert": s.URL + "/revoke-cert",
+			"keyChange":  s.URL + "/key-change",
+		})
+	})
+	mux.HandleFunc("/nonce", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Replay-Nonce", "nonce")
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/key-change", func(w http.ResponseWriter, r *http.Request) {
+		s.keyChanges.Add(1)
+		w.Header().Set("Replay-Nonce", "nonce")
+		if s.keyChangeErr {
+			writeMockACMEProblem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/acct/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Replay-Nonce", "nonce")
+		if s.queries.Add(1) <= s.queryErrors {
+			writeMockACMEProblem(w, http.StatusUnauthorized, "urn:ietf:params:acme:error:unauthorized")
+			return
+		}
+		w.Header().Set("Location", s.URL+"/acct/1")
+		json.NewEncoder(w).Encode(acme.Account{Status: acme.StatusValid})
+	})
+
+	// lego 要求使用 HTTPS，这里通过环境变量信任测试服务器的自签名证书
+	s.Server = httptest.NewTLSServer(mux)
+	t.Cleanup(s.Close)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}), 0o600))
+	t.Setenv("LEGO_CA_CERTIFICATES", caPath)
+
+	return s
+}
+
+func writeMockACMEProblem(w http.ResponseWriter, status int, problemType string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(acme.ProblemDetails{Type: problemType, Detail: "mock error", HTTPStatus: status})
+}
+
+func newMockACMEAccount(t *testing.T, server *mockACMEServer) domain.ACMEAccount {
+	privateKey, err := certacme.GenerateAccountPrivateKey()
+	require.NoError(t, err)
+
+	return domain.ACMEAccount{
+		Meta:             domain.Meta{Id: "acct1"},
+		CA:               "custom",
+		Email:            "test@example.com",
+		PrivateKey:       privateKey,
+		ACMEDirectoryUrl: server.URL + "/dir",
+		ACMEAccountUrl:   server.URL + "/acct/1",
+		ResourceObject:   &acme.Account{Status: acme.StatusValid},
+	}
+}
+
+func TestRolloverKey_PendingKeySaveFailed(t *testing.T) {
+	server := newMockACMEServer(t)
+	repo := &mockACMEAccountRepository{account: newMockACMEAccount(t, server)}
+	repo.saveFunc = func(n int, acmeAccount *domain.ACMEAccount) error {
+		return errors.New("mock save error")
+	}
+	oldPrivateKey := repo.account.PrivateKey
+
+	_, err := NewACMEAccountService(repo).RolloverKey(context.Background(), &dtos.ACMEAccountRolloverKeyReq{AccountId: "acct1"})
+	assert.Error(t, err)
+	assert.EqualValues(t, 0, server.keyChanges.Load(), "the key must not be rolled over at the CA if the new key could not be saved")
+	assert.Equal(t, oldPrivateKey, repo.account.PrivateKey)
+}
+
+func TestRolloverKey_RejectedByCA(t *testing.T) {
+	server := newMockACMEServer(t)
+	server.keyChangeErr = true
+	repo := &mockACMEAccountRepository{account: newMockACMEAccount(t, server)}
+	oldPrivateKey := repo.account.PrivateKey
+
+	_, err := NewACMEAccountService(repo).RolloverKey(context.Background(), &dtos.ACMEAccountRolloverKeyReq{AccountId: "acct1"})
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, server.keyChanges.Load())
+	assert.Equal(t, oldPrivateKey, repo.account.PrivateKey)
+	assert.Empty(t, repo.account.PendingPrivateKey)
+}
+
+func TestRolloverKey_Succeeded(t *testing.T) {
+	server := newMockACMEServer(t)
+	repo := &mockACMEAccountRepository{account: newMockACMEAccount(t, server)}
+	oldPrivateKey := repo.account.PrivateKey
+
+	var pendingPrivateKey string
+	repo.saveFunc = func(n int, acmeAccount *domain.ACMEAccount) error {
+		if n == 1 {
+			pendingPrivateKey = acmeAccount.PendingPrivateKey
+			assert.EqualValues(t, 0, server.keyChanges.Load(), "the new key must be saved before rolling over")
+		}
+		return nil
+	}
+
+	_, err := NewACMEAccountService(repo).RolloverKey(context.Background(), &dtos.ACMEAccountRolloverKeyReq{AccountId: "acct1"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, pendingPrivateKey)
+	assert.NotEqual(t, oldPrivateKey, repo.account.PrivateKey)
+	assert.Equal(t, pendingPrivateKey, repo.account.PrivateKey)
+	assert.Empty(t, repo.account.PendingPrivateKey)
+}
+
+func TestRefresh_PromotesPendingKey(t *testing.T) {
+	server := newMockACMEServer(t)
+	server.queryErrors = 1 // 旧私钥已失效
+	repo := &mockACMEAccountRepository{account: newMockACMEAccount(t, server)}
+	pendingPrivateKey, err := certacme.GenerateAccountPrivateKey()
+	require.NoError(t, err)
+	repo.account.PendingPrivateKey = pendingPrivateKey
+
+	_, err = NewACMEAccountService(repo).RolloverKey(context.Background(), &dtos.ACMEAccountRolloverKeyReq{AccountId: "acct1"})
+	assert.Error(t, err, "a new rollover must not start while the previous one is unfinished")
+
+	_, err = NewACMEAccountService(repo).Refresh(context.Background(), &dtos.ACMEAccountRefreshReq{AccountId: "acct1"})
+	require.NoError(t, err)
+	assert.Equal(t, pendingPrivateKey, repo.account.PrivateKey)
+	assert.Empty(t, repo.account.PendingPrivateKey)
+}
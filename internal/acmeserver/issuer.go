@@ -0,0 +1,150 @@
+package acmeserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/samber/lo"
+	"github.com/xhit/go-str2duration/v2"
+
+	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/privateca"
+)
+
+func (s *Server) issueCertificate(ctx context.Context, client *domain.ACMEServerClient, csrPEM string, identifiers []string) (*domain.Certificate, error) {
+	config := client.Config
+	if config == nil {
+		return nil, fmt.Errorf("the client is not configured")
+	}
+
+	validityNotAfter := time.Time{}
+	if config.ValidityLifetime != "" {
+		duration, err := str2duration.ParseDuration(config.ValidityLifetime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validity lifetime: %w", err)
+		}
+
+		validityNotAfter = time.Now().Add(duration)
+	}
+
+	certificate := &domain.Certificate{
+		Source:             domain.CertificateSourceTypeACMEServer,
+		ACMEServerClientId: client.Id,
+	}
+
+	switch config.Issuer {
+	case domain.ACMEServerIssuerTypePrivateCA:
+		privateCA, err := s.privateCARepo.GetById(ctx, config.PrivateCAId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get private ca #%s record: %w", config.PrivateCAId, err)
+		}
+
+		issuer, err := privateca.NewIssuer(privateCA)
+		if err != nil {
+			return nil, err
+		}
+
+		issueResp, err := issuer.IssueCertificate(ctx, &privateca.IssueCertificateRequest{
+			DomainOrIPs:      identifiers,
+			CSRPEM:           csrPEM,
+			ValidityNotAfter: validityNotAfter,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		certificate.CA = domain.CAProviderTypePrivateCA.String()
+		certificate.PopulateFromPEM(issueResp.FullChainCertificate, "")
+
+	case domain.ACMEServerIssuerTypeUpstream:
+		obtainResp, err := s.obtainCertificateFromUpstream(ctx, config, csrPEM, identifiers, validityNotAfter)
+		if err != nil {
+			return nil, err
+		}
+
+		certificate.CA = obtainResp.CAProvider.String()
+		certificate.ACMEAccountUrl = obtainResp.ACMEAccountUrl
+		certificate.ACMECertificateUrl = obtainResp.ACMECertificateUrl
+		certificate.PopulateFromPEM(obtainResp.FullChainCertificate, "")
+
+	default:
+		return nil, fmt.Errorf("unsupported issuer '%s'", config.Issuer)
+	}
+
+	certificate, err := s.certificateRepo.Save(ctx, certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	return certificate, nil
+}
+
+func (s *Server) obtainCertificateFromUpstream(ctx context.Context, config *domain.ACMEServerClientConfig, csrPEM string, identifiers []string, validityNotAfter time.Time) (*certacme.ObtainCertificateResponse, error) {
+	csr, err := certcrypto.PemDecodeTox509CSR([]byte(csrPEM))
+	if err != nil {
+		return nil, err
+	}
+
+	csrKeyType, err := certcrypto.GetCSRKeyType(csr)
+	if err != nil {
+		return nil, err
+	}
+
+	providerAccessConfig := make(map[string]any)
+	if config.ProviderAccessId != "" {
+		if access, err := s.accessRepo.GetById(ctx, config.ProviderAccessId); err != nil {
+			return nil, fmt.Errorf("failed to get access #%s record: %w", config.ProviderAccessId, err)
+		} else {
+			providerAccessConfig = access.Config
+		}
+	}
+
+	caAccessConfig := make(map[string]any)
+	if config.CAProviderAccessId != "" {
+		if access, err := s.accessRepo.GetById(ctx, config.CAProviderAccessId); err != nil {
+			return nil, fmt.Errorf("failed to get access #%s record: %w", config.CAProviderAccessId, err)
+		} else {
+			caAccessConfig = access.Config
+		}
+	}
+
+	acmeCfg, err := certacme.CreateACMEConfig(ctx, &certacme.ACMEConfigOptions{
+		CAProvider:               domain.CAProviderType(config.CAProvider),
+		CAProviderAccessConfig:   caAccessConfig,
+		CAProviderExtendedConfig: config.CAProviderConfig,
+		CertifierKeyAlgorithm:    lo.If(config.KeyAlgorithm != "", domain.CertificateKeyAlgorithmType(config.KeyAlgorithm)).Else(domain.CertificateKeyAlgorithmType(csrKeyType)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize acme config: %w", err)
+	}
+
+	acmeAcct, err := certacme.CreateACMEAccountWithSingleFlight(ctx, acmeCfg, config.ContactEmail)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize acme account: %w", err)
+	}
+
+	acmeClient, err := certacme.NewACMEClientWithAccount(acmeAcct)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize acme client: %w", err)
+	}
+
+	return acmeClient.ObtainCertificate(ctx, &certacme.ObtainCertificateRequest{
+		DomainOrIPs:            identifiers,
+		PrivateKeyType:         csrKeyType,
+		CSRPEM:                 csrPEM,
+		ValidityNotAfter:       validityNotAfter,
+		ChallengeType:          lo.If(config.ChallengeType != "", config.ChallengeType).Else(certacme.CHALLENGE_TYPE_DNS01),
+		Provider:               domain.ACMEChallengeProviderType(config.Provider),
+		ProviderAccessConfig:   providerAccessConfig,
+		ProviderExtendedConfig: config.ProviderConfig,
+		DisableFollowCNAME:     config.DisableFollowCNAME,
+		Nameservers:            config.Nameservers,
+		DnsPropagationWait:     config.DnsPropagationWait,
+		DnsPropagationTimeout:  config.DnsPropagationTimeout,
+		DnsTTL:                 config.DnsTTL,
+		HttpDelayWait:          config.HttpDelayWait,
+	})
+}
@@ -0,0 +1,167 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+var supportedSignatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256,
+	jose.ES256,
+	jose.ES384,
+	jose.ES512,
+	jose.EdDSA,
+}
+
+var supportedEABSignatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.HS256,
+	jose.HS384,
+	jose.HS512,
+}
+
+type signedRequest struct {
+	Payload []byte
+	JWK     *jose.JSONWebKey         // 仅当请求使用 "jwk" 头部时存在
+	Client  *domain.ACMEServerClient // 仅当请求使用 "kid" 头部时存在
+}
+
+// 校验 RFC 8555 §6.2 中定义的 JWS 请求。
+// 除新建账户外，所有请求均需使用 "kid" 头部标识账户。
+func (s *Server) verifyRequest(ctx context.Context, baseUrl string, reqUrl string, body []byte, allowJWK bool) (*signedRequest, error) {
+	jws, err := jose.ParseSigned(string(body), supportedSignatureAlgorithms)
+	if err != nil {
+		return nil, malformedProblem("failed to parse jws: %s", err.Error())
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, malformedProblem("jws must contain exactly one signature")
+	}
+
+	header := jws.Signatures[0].Protected
+	if !s.nonces.Consume(header.Nonce) {
+		return nil, newProblem(http.StatusBadRequest, problemTypeBadNonce, "invalid or expired nonce")
+	}
+
+	if url, _ := header.ExtraHeaders["url"].(string); url != reqUrl {
+		return nil, unauthorizedProblem("the 'url' header does not match the request url")
+	}
+
+	if header.KeyID != "" && header.JSONWebKey != nil {
+		return nil, malformedProblem("jws must not contain both 'kid' and 'jwk' headers")
+	}
+
+	if header.JSONWebKey != nil {
+		if !allowJWK {
+			return nil, malformedProblem("jws must use the 'kid' header")
+		}
+		if !header.JSONWebKey.Valid() || !header.JSONWebKey.IsPublic() {
+			return nil, malformedProblem("invalid 'jwk' header")
+		}
+
+		payload, err := jws.Verify(header.JSONWebKey)
+		if err != nil {
+			return nil, malformedProblem("failed to verify jws signature")
+		}
+
+		return &signedRequest{Payload: payload, JWK: header.JSONWebKey}, nil
+	}
+
+	accountUrlPrefix := baseUrl + "/account/"
+	if !strings.HasPrefix(header.KeyID, accountUrlPrefix) {
+		return nil, newProblem(http.StatusBadRequest, problemTypeAccountDoesNotExist, "unknown account")
+	}
+
+	client, err := s.clientRepo.GetById(ctx, strings.TrimPrefix(header.KeyID, accountUrlPrefix))
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, newProblem(http.StatusBadRequest, problemTypeAccountDoesNotExist, "unknown account")
+		}
+		return nil, serverInternalProblem("failed to get account")
+	} else if client.AccountKey == "" {
+		return nil, newProblem(http.StatusBadRequest, problemTypeAccountDoesNotExist, "unknown account")
+	} else if !client.Enabled || client.AccountStatus != domain.ACMEServerAccountStatusTypeValid {
+		return nil, unauthorizedProblem("the account is not valid")
+	}
+
+	accountKey := &jose.JSONWebKey{}
+	if err := json.Unmarshal([]byte(client.AccountKey), accountKey); err != nil {
+		return nil, serverInternalProblem("failed to parse account key")
+	}
+
+	payload, err := jws.Verify(accountKey)
+	if err != nil {
+		return nil, malformedProblem("failed to verify jws signature")
+	}
+
+	return &signedRequest{Payload: payload, Client: client}, nil
+}
+
+// 校验 RFC 8555 §7.3.4 中定义的外部账户绑定，并返回对应的客户端。
+func (s *Server) verifyExternalAccountBinding(ctx context.Context, reqUrl string, eab json.RawMessage, accountKey *jose.JSONWebKey) (*domain.ACMEServerClient, error) {
+	jws, err := jose.ParseSigned(string(eab), supportedEABSignatureAlgorithms)
+	if err != nil {
+		return nil, malformedProblem("failed to parse external account binding: %s", err.Error())
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, malformedProblem("external account binding must contain exactly one signature")
+	}
+
+	header := jws.Signatures[0].Protected
+	if header.Nonce != "" {
+		return nil, malformedProblem("external account binding must not contain a 'nonce' header")
+	}
+	if url, _ := header.ExtraHeaders["url"].(string); url != reqUrl {
+		return nil, unauthorizedProblem("the 'url' header of external account binding does not match the request url")
+	}
+
+	client, err := s.clientRepo.GetByEABKid(ctx, header.KeyID)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, unauthorizedProblem("unknown external account")
+		}
+		return nil, serverInternalProblem("failed to get external account")
+	} else if !client.Enabled {
+		return nil, unauthorizedProblem("the external account is disabled")
+	}
+
+	hmacKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(client.EABHmacKey, "="))
+	if err != nil {
+		return nil, serverInternalProblem("failed to decode external account hmac key")
+	}
+
+	payload, err := jws.Verify(hmacKey)
+	if err != nil {
+		return nil, unauthorizedProblem("failed to verify external account binding signature")
+	}
+
+	boundKey := &jose.JSONWebKey{}
+	if err := json.Unmarshal(payload, boundKey); err != nil {
+		return nil, malformedProblem("invalid external account binding payload")
+	}
+
+	if thumbprint, err := jwkThumbprint(boundKey); err != nil {
+		return nil, malformedProblem("invalid external account binding payload")
+	} else if expected, _ := jwkThumbprint(accountKey); thumbprint != expected {
+		return nil, unauthorizedProblem("the external account binding does not match the account key")
+	}
+
+	return client, nil
+}
+
+func jwkThumbprint(key *jose.JSONWebKey) (string, error) {
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
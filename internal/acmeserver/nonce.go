@@ -0,0 +1,74 @@
+package acmeserver
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+const (
+	nonceTTL     = time.Hour
+	nonceMaxSize = 10000
+)
+
+type nonceEntry struct {
+	nonce   string
+	expires time.Time
+}
+
+// 所有 Nonce 的有效期相同，因此按签发顺序排列即按过期时间排列，
+// 签发时只需从队首淘汰已过期或超出容量的 Nonce。
+type nonceStore struct {
+	mtx     sync.Mutex
+	queue   *list.List
+	entries map[string]*list.Element
+}
+
+func newNonceStore() *nonceStore {
+	return &nonceStore{
+		queue:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (s *nonceStore) Issue() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	for front := s.queue.Front(); front != nil; front = s.queue.Front() {
+		if s.queue.Len() < nonceMaxSize && now.Before(front.Value.(*nonceEntry).expires) {
+			break
+		}
+
+		s.remove(front)
+	}
+
+	s.entries[nonce] = s.queue.PushBack(&nonceEntry{nonce: nonce, expires: now.Add(nonceTTL)})
+	return nonce
+}
+
+// 校验并消费一个 Nonce，每个 Nonce 仅可使用一次。
+func (s *nonceStore) Consume(nonce string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	elem, ok := s.entries[nonce]
+	if !ok {
+		return false
+	}
+
+	s.remove(elem)
+	return time.Now().Before(elem.Value.(*nonceEntry).expires)
+}
+
+func (s *nonceStore) remove(elem *list.Element) {
+	s.queue.Remove(elem)
+	delete(s.entries, elem.Value.(*nonceEntry).nonce)
+}
@@ -0,0 +1,41 @@
+package acmeserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceStore(t *testing.T) {
+	t.Run("consume once", func(t *testing.T) {
+		store := newNonceStore()
+		nonce := store.Issue()
+
+		assert.True(t, store.Consume(nonce))
+		assert.False(t, store.Consume(nonce))
+		assert.False(t, store.Consume("unknown"))
+	})
+
+	t.Run("evict expired", func(t *testing.T) {
+		store := newNonceStore()
+		expired := store.Issue()
+		store.entries[expired].Value.(*nonceEntry).expires = time.Now().Add(-time.Second)
+
+		store.Issue()
+		assert.Equal(t, 1, store.queue.Len())
+		assert.False(t, store.Consume(expired))
+	})
+
+	t.Run("bounded size", func(t *testing.T) {
+		store := newNonceStore()
+		first := store.Issue()
+		for i := 0; i < nonceMaxSize; i++ {
+			store.Issue()
+		}
+
+		assert.Equal(t, nonceMaxSize, store.queue.Len())
+		assert.Equal(t, nonceMaxSize, len(store.entries))
+		assert.False(t, store.Consume(first))
+	})
+}
@@ -0,0 +1,55 @@
+package acmeserver
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RFC 8555 §6.7 中定义的错误类型。
+const (
+	problemTypeAccountDoesNotExist     = "urn:ietf:params:acme:error:accountDoesNotExist"
+	problemTypeBadCSR                  = "urn:ietf:params:acme:error:badCSR"
+	problemTypeBadNonce                = "urn:ietf:params:acme:error:badNonce"
+	problemTypeExternalAccountRequired = "urn:ietf:params:acme:error:externalAccountRequired"
+	problemTypeMalformed               = "urn:ietf:params:acme:error:malformed"
+	problemTypeOrderNotReady           = "urn:ietf:params:acme:error:orderNotReady"
+	problemTypeRejectedIdentifier      = "urn:ietf:params:acme:error:rejectedIdentifier"
+	problemTypeServerInternal          = "urn:ietf:params:acme:error:serverInternal"
+	problemTypeUnauthorized            = "urn:ietf:params:acme:error:unauthorized"
+	problemTypeUnsupportedIdentifier   = "urn:ietf:params:acme:error:unsupportedIdentifier"
+)
+
+// 遵循 RFC 7807 的错误响应。
+type Problem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Type, p.Detail)
+}
+
+func newProblem(status int, typ string, format string, args ...any) *Problem {
+	return &Problem{
+		Type:   typ,
+		Detail: fmt.Sprintf(format, args...),
+		Status: status,
+	}
+}
+
+func malformedProblem(format string, args ...any) *Problem {
+	return newProblem(http.StatusBadRequest, problemTypeMalformed, format, args...)
+}
+
+func unauthorizedProblem(format string, args ...any) *Problem {
+	return newProblem(http.StatusUnauthorized, problemTypeUnauthorized, format, args...)
+}
+
+func notFoundProblem(format string, args ...any) *Problem {
+	return newProblem(http.StatusNotFound, problemTypeMalformed, format, args...)
+}
+
+func serverInternalProblem(format string, args ...any) *Problem {
+	return newProblem(http.StatusInternalServerError, problemTypeServerInternal, format, args...)
+}
@@ -0,0 +1,567 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+// 遵循 RFC 8555 的 ACME 服务端。
+// 所有客户端均需通过外部账户绑定（EAB）注册账户；由于客户端已通过 EAB 认证，
+// 其允许范围内的标识符视为预授权，无需客户端完成质询，签发时由 Certimate 代为处理。
+type Server struct {
+	clientRepo      acmeServerClientRepository
+	certificateRepo certificateRepository
+	privateCARepo   privateCARepository
+	accessRepo      accessRepository
+
+	nonces *nonceStore
+	orders *orderStore
+
+	// 服务端的生命周期上下文，用于异步签发证书及定期清理过期订单
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewServer(clientRepo acmeServerClientRepository, orderRepo acmeServerOrderRepository, certificateRepo certificateRepository, privateCARepo privateCARepository, accessRepo accessRepository) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		clientRepo:      clientRepo,
+		certificateRepo: certificateRepo,
+		privateCARepo:   privateCARepo,
+		accessRepo:      accessRepo,
+
+		nonces: newNonceStore(),
+		orders: newOrderStore(orderRepo),
+
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.cleanupOrders()
+	}()
+
+	return s
+}
+
+// 停止服务端，中止正在进行的签发并等待其退出。
+func (s *Server) Shutdown(ctx context.Context) {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (s *Server) cleanupOrders() {
+	// 首次清理时，将上次运行中未完成签发的订单置为无效，以免客户端无限轮询
+	if err := s.orders.Cleanup(s.ctx, true); err != nil {
+		app.GetLogger().Error("acme server could not cleanup orders", slog.Any("error", err))
+	}
+
+	ticker := time.NewTicker(orderCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.orders.Cleanup(s.ctx, false); err != nil {
+				app.GetLogger().Error("acme server could not cleanup orders", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+type Response struct {
+	StatusCode  int
+	Location    string
+	Links       []string
+	ContentType string
+	Body        any
+	RawBody     []byte
+}
+
+func (s *Server) NewNonce() string {
+	return s.nonces.Issue()
+}
+
+func (s *Server) GetDirectory(ctx context.Context, baseUrl string) (*Response, error) {
+	return &Response{
+		StatusCode: http.StatusOK,
+		Body: map[string]any{
+			"newNonce":   baseUrl + "/new-nonce",
+			"newAccount": baseUrl + "/new-account",
+			"newOrder":   baseUrl + "/new-order",
+			"meta": map[string]any{
+				"externalAccountRequired": true,
+			},
+		},
+	}, nil
+}
+
+func (s *Server) NewAccount(ctx context.Context, baseUrl string, reqUrl string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, true)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Contact                []string        `json:"contact"`
+		TermsOfServiceAgreed   bool            `json:"termsOfServiceAgreed"`
+		OnlyReturnExisting     bool            `json:"onlyReturnExisting"`
+		ExternalAccountBinding json.RawMessage `json:"externalAccountBinding"`
+	}{}
+	if err := json.Unmarshal(req.Payload, &payload); err != nil {
+		return nil, malformedProblem("invalid request payload")
+	}
+
+	thumbprint, err := jwkThumbprint(req.JWK)
+	if err != nil {
+		return nil, malformedProblem("invalid 'jwk' header")
+	}
+
+	// 账户密钥已注册，返回已有账户
+	if client, err := s.clientRepo.GetByAccountKeyThumbprint(ctx, thumbprint); err == nil {
+		if client.AccountStatus == domain.ACMEServerAccountStatusTypeDeactivated {
+			return nil, unauthorizedProblem("the account has been deactivated")
+		}
+
+		return &Response{
+			StatusCode: http.StatusOK,
+			Location:   s.accountUrl(baseUrl, client.Id),
+			Body:       s.marshalAccount(baseUrl, client),
+		}, nil
+	} else if !domain.IsRecordNotFoundError(err) {
+		return nil, serverInternalProblem("failed to get account")
+	}
+
+	if payload.OnlyReturnExisting {
+		return nil, newProblem(http.StatusBadRequest, problemTypeAccountDoesNotExist, "no account exists with the provided key")
+	}
+
+	if len(payload.ExternalAccountBinding) == 0 || string(payload.ExternalAccountBinding) == "null" {
+		return nil, newProblem(http.StatusUnauthorized, problemTypeExternalAccountRequired, "external account binding is required")
+	}
+
+	client, err := s.verifyExternalAccountBinding(ctx, reqUrl, payload.ExternalAccountBinding, req.JWK)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每组 EAB 凭据仅可绑定一个账户
+	if client.AccountKeyThumbprint != "" && client.AccountKeyThumbprint != thumbprint {
+		return nil, unauthorizedProblem("the external account has already been bound to another account")
+	}
+
+	accountKey, err := json.Marshal(req.JWK.Public())
+	if err != nil {
+		return nil, serverInternalProblem("failed to marshal account key")
+	}
+
+	client.AccountKey = string(accountKey)
+	client.AccountKeyThumbprint = thumbprint
+	client.AccountStatus = domain.ACMEServerAccountStatusTypeValid
+	client.AccountContact = payload.Contact
+	if _, err := s.clientRepo.Save(ctx, client); err != nil {
+		return nil, serverInternalProblem("failed to save account")
+	}
+
+	app.GetLogger().Info("acme server account registered", slog.String("clientId", client.Id), slog.String("clientName", client.Name))
+
+	return &Response{
+		StatusCode: http.StatusCreated,
+		Location:   s.accountUrl(baseUrl, client.Id),
+		Body:       s.marshalAccount(baseUrl, client),
+	}, nil
+}
+
+func (s *Server) UpdateAccount(ctx context.Context, baseUrl string, reqUrl string, accountId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	} else if req.Client.Id != accountId {
+		return nil, unauthorizedProblem("the account does not match the request")
+	}
+
+	client := req.Client
+	if len(req.Payload) > 0 {
+		payload := struct {
+			Contact []string `json:"contact"`
+			Status  string   `json:"status"`
+		}{}
+		if err := json.Unmarshal(req.Payload, &payload); err != nil {
+			return nil, malformedProblem("invalid request payload")
+		}
+
+		if payload.Status != "" && payload.Status != statusDeactivated {
+			return nil, malformedProblem("unsupported account status '%s'", payload.Status)
+		}
+
+		if payload.Contact != nil {
+			client.AccountContact = payload.Contact
+		}
+		if payload.Status == statusDeactivated {
+			client.AccountStatus = domain.ACMEServerAccountStatusTypeDeactivated
+		}
+
+		if _, err := s.clientRepo.Save(ctx, client); err != nil {
+			return nil, serverInternalProblem("failed to save account")
+		}
+	}
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Location:   s.accountUrl(baseUrl, client.Id),
+		Body:       s.marshalAccount(baseUrl, client),
+	}, nil
+}
+
+func (s *Server) ListAccountOrders(ctx context.Context, baseUrl string, reqUrl string, accountId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	} else if req.Client.Id != accountId {
+		return nil, unauthorizedProblem("the account does not match the request")
+	}
+
+	orderIds, err := s.orders.ListOrderIdsByClientId(ctx, req.Client.Id)
+	if err != nil {
+		return nil, serverInternalProblem("failed to list orders")
+	}
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Body: map[string]any{
+			"orders": lo.Map(orderIds, func(id string, _ int) string { return s.orderUrl(baseUrl, id) }),
+		},
+	}, nil
+}
+
+func (s *Server) NewOrder(ctx context.Context, baseUrl string, reqUrl string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Identifiers []identifier `json:"identifiers"`
+	}{}
+	if err := json.Unmarshal(req.Payload, &payload); err != nil {
+		return nil, malformedProblem("invalid request payload")
+	} else if len(payload.Identifiers) == 0 {
+		return nil, malformedProblem("no identifiers in the order")
+	}
+
+	client := req.Client
+	expires := time.Now().Add(orderTTL)
+	order := &orderResource{
+		ClientId:    client.Id,
+		Status:      statusReady,
+		Expires:     expires,
+		Identifiers: make([]identifier, 0, len(payload.Identifiers)),
+	}
+	authzs := make([]*authorizationResource, 0, len(payload.Identifiers))
+	for _, ident := range payload.Identifiers {
+		ident.Value = strings.ToLower(strings.TrimSpace(ident.Value))
+
+		switch ident.Type {
+		case "dns":
+			if ident.Value == "" || net.ParseIP(ident.Value) != nil {
+				return nil, newProblem(http.StatusBadRequest, problemTypeRejectedIdentifier, "invalid dns identifier '%s'", ident.Value)
+			}
+		case "ip":
+			if net.ParseIP(ident.Value) == nil {
+				return nil, newProblem(http.StatusBadRequest, problemTypeRejectedIdentifier, "invalid ip identifier '%s'", ident.Value)
+			}
+		default:
+			return nil, newProblem(http.StatusBadRequest, problemTypeUnsupportedIdentifier, "unsupported identifier type '%s'", ident.Type)
+		}
+
+		if !client.Config.IsIdentifierAllowed(ident.Value) {
+			return nil, newProblem(http.StatusForbidden, problemTypeRejectedIdentifier, "the identifier '%s' is not allowed for this account", ident.Value)
+		}
+
+		// 客户端已通过 EAB 认证，允许范围内的标识符视为预授权
+		authz := &authorizationResource{
+			ClientId:   client.Id,
+			Status:     statusValid,
+			Expires:    expires,
+			Identifier: identifier{Type: ident.Type, Value: strings.TrimPrefix(ident.Value, "*.")},
+			Wildcard:   strings.HasPrefix(ident.Value, "*."),
+		}
+		authzs = append(authzs, authz)
+
+		order.Identifiers = append(order.Identifiers, ident)
+	}
+
+	order, err = s.orders.AddOrder(ctx, order, authzs)
+	if err != nil {
+		return nil, serverInternalProblem("failed to save order")
+	}
+
+	return &Response{
+		StatusCode: http.StatusCreated,
+		Location:   s.orderUrl(baseUrl, order.Id),
+		Body:       s.marshalOrder(baseUrl, order),
+	}, nil
+}
+
+func (s *Server) GetOrder(ctx context.Context, baseUrl string, reqUrl string, orderId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orders.GetOrder(ctx, orderId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, notFoundProblem("order not found")
+		}
+		return nil, serverInternalProblem("failed to get order")
+	} else if order.ClientId != req.Client.Id {
+		return nil, notFoundProblem("order not found")
+	}
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Location:   s.orderUrl(baseUrl, order.Id),
+		Body:       s.marshalOrder(baseUrl, order),
+	}, nil
+}
+
+func (s *Server) GetAuthorization(ctx context.Context, baseUrl string, reqUrl string, authzId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	authz, err := s.orders.GetAuthorization(ctx, authzId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, notFoundProblem("authorization not found")
+		}
+		return nil, serverInternalProblem("failed to get authorization")
+	} else if authz.ClientId != req.Client.Id {
+		return nil, notFoundProblem("authorization not found")
+	}
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Body: map[string]any{
+			"status":     authz.Status,
+			"expires":    authz.Expires.UTC().Format(time.RFC3339),
+			"identifier": authz.Identifier,
+			"challenges": []any{},
+			"wildcard":   authz.Wildcard,
+		},
+	}, nil
+}
+
+func (s *Server) FinalizeOrder(ctx context.Context, baseUrl string, reqUrl string, orderId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		CSR string `json:"csr"`
+	}{}
+	if err := json.Unmarshal(req.Payload, &payload); err != nil {
+		return nil, malformedProblem("invalid request payload")
+	}
+
+	csrDER, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(payload.CSR, "="))
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, problemTypeBadCSR, "failed to decode csr")
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, problemTypeBadCSR, "failed to parse csr: %s", err.Error())
+	} else if err := csr.CheckSignature(); err != nil {
+		return nil, newProblem(http.StatusBadRequest, problemTypeBadCSR, "failed to verify csr signature")
+	}
+
+	order, err := s.orders.GetOrder(ctx, orderId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, notFoundProblem("order not found")
+		}
+		return nil, serverInternalProblem("failed to get order")
+	} else if order.ClientId != req.Client.Id {
+		return nil, notFoundProblem("order not found")
+	} else if order.Status != statusReady {
+		return nil, newProblem(http.StatusForbidden, problemTypeOrderNotReady, "the order is not ready for finalization")
+	}
+
+	identifiers := lo.Map(order.Identifiers, func(ident identifier, _ int) string { return ident.Value })
+	if !equalCSRIdentifiers(csr, identifiers) {
+		return nil, newProblem(http.StatusBadRequest, problemTypeBadCSR, "the csr identifiers do not match the order")
+	}
+
+	order, ok, err := s.orders.UpdateOrder(ctx, orderId, func(o *orderResource) bool {
+		if o.Status != statusReady {
+			return false
+		}
+
+		o.Status = statusProcessing
+		return true
+	})
+	if err != nil {
+		return nil, serverInternalProblem("failed to update order")
+	} else if !ok {
+		return nil, newProblem(http.StatusForbidden, problemTypeOrderNotReady, "the order is not ready for finalization")
+	}
+
+	// 向上游 CA 申请证书可能耗时较长，因此异步签发，客户端将轮询订单状态。
+	// 签发不应随请求结束而取消，因此使用服务端的生命周期上下文。
+	client := req.Client
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		certificate, err := s.issueCertificate(s.ctx, client, csrPEM, identifiers)
+		if err != nil {
+			app.GetLogger().Error("acme server could not issue certificate", slog.String("clientId", client.Id), slog.String("orderId", orderId), slog.Any("error", err))
+		} else {
+			app.GetLogger().Info("acme server certificate issued", slog.String("clientId", client.Id), slog.String("orderId", orderId), slog.String("certificateId", certificate.Id))
+		}
+
+		// 服务端停止时签发上下文已取消，但仍需记录订单的最终状态
+		if _, _, uerr := s.orders.UpdateOrder(context.WithoutCancel(s.ctx), orderId, func(o *orderResource) bool {
+			if err != nil {
+				o.Status = statusInvalid
+				o.Error = serverInternalProblem("failed to issue certificate: %s", err.Error())
+			} else {
+				o.Status = statusValid
+				o.CertificateId = certificate.Id
+			}
+			return true
+		}); uerr != nil {
+			app.GetLogger().Error("acme server could not update order", slog.String("orderId", orderId), slog.Any("error", uerr))
+		}
+	}()
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Location:   s.orderUrl(baseUrl, order.Id),
+		Body:       s.marshalOrder(baseUrl, order),
+	}, nil
+}
+
+func (s *Server) GetCertificate(ctx context.Context, baseUrl string, reqUrl string, certificateId string, body []byte) (*Response, error) {
+	req, err := s.verifyRequest(ctx, baseUrl, reqUrl, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := s.certificateRepo.GetById(ctx, certificateId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, notFoundProblem("certificate not found")
+		}
+		return nil, serverInternalProblem("failed to get certificate")
+	} else if certificate.ACMEServerClientId != req.Client.Id {
+		return nil, notFoundProblem("certificate not found")
+	}
+
+	return &Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "application/pem-certificate-chain",
+		RawBody:     []byte(certificate.Certificate),
+	}, nil
+}
+
+func (s *Server) accountUrl(baseUrl string, accountId string) string {
+	return fmt.Sprintf("%s/account/%s", baseUrl, accountId)
+}
+
+func (s *Server) orderUrl(baseUrl string, orderId string) string {
+	return fmt.Sprintf("%s/order/%s", baseUrl, orderId)
+}
+
+func (s *Server) marshalAccount(baseUrl string, client *domain.ACMEServerClient) map[string]any {
+	accountKey := &jose.JSONWebKey{}
+	_ = json.Unmarshal([]byte(client.AccountKey), accountKey)
+
+	return map[string]any{
+		"status":                 client.AccountStatus.String(),
+		"contact":                lo.Ternary(client.AccountContact != nil, client.AccountContact, []string{}),
+		"orders":                 s.accountUrl(baseUrl, client.Id) + "/orders",
+		"key":                    accountKey,
+		"externalAccountBinding": map[string]any{"kid": client.EABKid},
+	}
+}
+
+func (s *Server) marshalOrder(baseUrl string, order *orderResource) map[string]any {
+	res := map[string]any{
+		"status":         order.Status,
+		"expires":        order.Expires.UTC().Format(time.RFC3339),
+		"identifiers":    order.Identifiers,
+		"authorizations": lo.Map(order.AuthorizationIds, func(id string, _ int) string { return fmt.Sprintf("%s/authz/%s", baseUrl, id) }),
+		"finalize":       s.orderUrl(baseUrl, order.Id) + "/finalize",
+	}
+	if order.CertificateId != "" {
+		res["certificate"] = fmt.Sprintf("%s/cert/%s", baseUrl, order.CertificateId)
+	}
+	if order.Error != nil {
+		res["error"] = order.Error
+	}
+	return res
+}
+
+func equalCSRIdentifiers(csr *x509.CertificateRequest, identifiers []string) bool {
+	csrIdentifiers := make([]string, 0, len(csr.DNSNames)+len(csr.IPAddresses)+1)
+	for _, name := range csr.DNSNames {
+		csrIdentifiers = append(csrIdentifiers, strings.ToLower(name))
+	}
+	for _, ip := range csr.IPAddresses {
+		csrIdentifiers = append(csrIdentifiers, ip.String())
+	}
+	if csr.Subject.CommonName != "" {
+		csrIdentifiers = append(csrIdentifiers, strings.ToLower(csr.Subject.CommonName))
+	}
+
+	expected := lo.Map(identifiers, func(s string, _ int) string {
+		if ip := net.ParseIP(s); ip != nil {
+			return ip.String()
+		}
+		return strings.ToLower(s)
+	})
+
+	a := lo.Uniq(csrIdentifiers)
+	b := lo.Uniq(expected)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,102 @@
+package acmeserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type ACMEServerService struct {
+	clientRepo acmeServerClientRepository
+}
+
+func NewACMEServerService(clientRepo acmeServerClientRepository) *ACMEServerService {
+	return &ACMEServerService{
+		clientRepo: clientRepo,
+	}
+}
+
+func (s *ACMEServerService) CreateClient(ctx context.Context, req *dtos.ACMEServerCreateClientReq) (*dtos.ACMEServerCreateClientResp, error) {
+	if req.Config == nil {
+		return nil, fmt.Errorf("the client config is required")
+	}
+
+	switch req.Config.Issuer {
+	case domain.ACMEServerIssuerTypePrivateCA:
+		if req.Config.PrivateCAId == "" {
+			return nil, fmt.Errorf("the private ca is required")
+		}
+	case domain.ACMEServerIssuerTypeUpstream:
+		if req.Config.CAProvider == "" || req.Config.Provider == "" {
+			return nil, fmt.Errorf("the upstream ca provider and challenge provider are required")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported issuer '%s'", req.Config.Issuer)
+	}
+
+	eabKid, eabHmacKey, err := generateEABCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &domain.ACMEServerClient{
+		Name:       req.Name,
+		EABKid:     eabKid,
+		EABHmacKey: eabHmacKey,
+		Config:     req.Config,
+		Enabled:    true,
+	}
+	client, err = s.clientRepo.Save(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerCreateClientResp{
+		ClientId:   client.Id,
+		EABKid:     client.EABKid,
+		EABHmacKey: client.EABHmacKey,
+	}, nil
+}
+
+func (s *ACMEServerService) ResetClientEAB(ctx context.Context, req *dtos.ACMEServerResetClientEABReq) (*dtos.ACMEServerResetClientEABResp, error) {
+	client, err := s.clientRepo.GetById(ctx, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	eabKid, eabHmacKey, err := generateEABCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	// 重置凭据后，已绑定的账户将失效，客户端需重新注册
+	client.EABKid = eabKid
+	client.EABHmacKey = eabHmacKey
+	client.AccountKey = ""
+	client.AccountKeyThumbprint = ""
+	client.AccountStatus = ""
+	client.AccountContact = nil
+	if _, err := s.clientRepo.Save(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return &dtos.ACMEServerResetClientEABResp{
+		EABKid:     client.EABKid,
+		EABHmacKey: client.EABHmacKey,
+	}, nil
+}
+
+func generateEABCredentials() (string, string, error) {
+	hmacKey := make([]byte, 32)
+	if _, err := rand.Read(hmacKey); err != nil {
+		return "", "", err
+	}
+
+	return security.RandomString(24), base64.RawURLEncoding.EncodeToString(hmacKey), nil
+}
@@ -0,0 +1,35 @@
+package acmeserver
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type acmeServerClientRepository interface {
+	GetById(ctx context.Context, id string) (*domain.ACMEServerClient, error)
+	GetByEABKid(ctx context.Context, eabKid string) (*domain.ACMEServerClient, error)
+	GetByAccountKeyThumbprint(ctx context.Context, thumbprint string) (*domain.ACMEServerClient, error)
+	Save(ctx context.Context, client *domain.ACMEServerClient) (*domain.ACMEServerClient, error)
+}
+
+type acmeServerOrderRepository interface {
+	GetById(ctx context.Context, id string) (*domain.ACMEServerOrder, error)
+	ListByClientId(ctx context.Context, clientId string) ([]*domain.ACMEServerOrder, error)
+	ListByStatus(ctx context.Context, status string) ([]*domain.ACMEServerOrder, error)
+	Save(ctx context.Context, order *domain.ACMEServerOrder) (*domain.ACMEServerOrder, error)
+	DeleteExpired(ctx context.Context) (int, error)
+}
+
+type certificateRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Certificate, error)
+	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
+}
+
+type privateCARepository interface {
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+}
+
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+}
@@ -0,0 +1,229 @@
+package acmeserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const (
+	orderTTL             = time.Hour * 24
+	orderCleanupInterval = time.Hour
+)
+
+const (
+	statusInvalid     = "invalid"
+	statusPending     = "pending"
+	statusProcessing  = "processing"
+	statusReady       = "ready"
+	statusValid       = "valid"
+	statusDeactivated = "deactivated"
+)
+
+type identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type orderResource struct {
+	Id               string
+	ClientId         string
+	Status           string
+	Expires          time.Time
+	Identifiers      []identifier
+	AuthorizationIds []string
+	CertificateId    string
+	Error            *Problem
+}
+
+type authorizationResource struct {
+	Id         string
+	ClientId   string
+	Status     string
+	Expires    time.Time
+	Identifier identifier
+	Wildcard   bool
+}
+
+// 订单及授权持久化保存，服务重启后客户端仍可继续轮询。
+// 授权从属于订单，其 ID 由订单 ID 及其在订单中的序号组成。
+type orderStore struct {
+	mtx  sync.Mutex // 保证同一进程内订单更新的原子性
+	repo acmeServerOrderRepository
+}
+
+func newOrderStore(repo acmeServerOrderRepository) *orderStore {
+	return &orderStore{
+		repo: repo,
+	}
+}
+
+func (s *orderStore) AddOrder(ctx context.Context, o *orderResource, authzs []*authorizationResource) (*orderResource, error) {
+	order := &domain.ACMEServerOrder{
+		ClientId:  o.ClientId,
+		Status:    o.Status,
+		ExpiresAt: o.Expires,
+		Identifiers: lo.Map(o.Identifiers, func(ident identifier, _ int) domain.ACMEServerIdentifier {
+			return domain.ACMEServerIdentifier{Type: ident.Type, Value: ident.Value}
+		}),
+		Authorizations: lo.Map(authzs, func(authz *authorizationResource, _ int) *domain.ACMEServerAuthorization {
+			return &domain.ACMEServerAuthorization{
+				Status:     authz.Status,
+				Identifier: domain.ACMEServerIdentifier{Type: authz.Identifier.Type, Value: authz.Identifier.Value},
+				Wildcard:   authz.Wildcard,
+			}
+		}),
+	}
+	if _, err := s.repo.Save(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return castOrderToResource(order), nil
+}
+
+func (s *orderStore) GetOrder(ctx context.Context, id string) (*orderResource, error) {
+	order, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if time.Now().After(order.ExpiresAt) {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return castOrderToResource(order), nil
+}
+
+func (s *orderStore) ListOrderIdsByClientId(ctx context.Context, clientId string) ([]string, error) {
+	orders, err := s.repo.ListByClientId(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	orders = lo.Filter(orders, func(order *domain.ACMEServerOrder, _ int) bool { return now.Before(order.ExpiresAt) })
+	return lo.Map(orders, func(order *domain.ACMEServerOrder, _ int) string { return order.Id }), nil
+}
+
+// 原子地更新订单。如果更新函数返回 false，则放弃本次更新。
+func (s *orderStore) UpdateOrder(ctx context.Context, id string, fn func(o *orderResource) bool) (*orderResource, bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	order, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resource := castOrderToResource(order)
+	if !fn(resource) {
+		return nil, false, nil
+	}
+
+	order.Status = resource.Status
+	order.CertificateId = resource.CertificateId
+	order.Error = ""
+	if resource.Error != nil {
+		order.Error = resource.Error.Detail
+	}
+	if _, err := s.repo.Save(ctx, order); err != nil {
+		return nil, false, err
+	}
+
+	return castOrderToResource(order), true, nil
+}
+
+func (s *orderStore) GetAuthorization(ctx context.Context, id string) (*authorizationResource, error) {
+	orderId, index, ok := parseAuthorizationId(id)
+	if !ok {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	order, err := s.repo.GetById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	} else if index >= len(order.Authorizations) || time.Now().After(order.ExpiresAt) {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	authz := order.Authorizations[index]
+	return &authorizationResource{
+		Id:         id,
+		ClientId:   order.ClientId,
+		Status:     authz.Status,
+		Expires:    order.ExpiresAt,
+		Identifier: identifier{Type: authz.Identifier.Type, Value: authz.Identifier.Value},
+		Wildcard:   authz.Wildcard,
+	}, nil
+}
+
+// 清理已过期的订单，并将服务重启前未完成签发的订单置为无效。
+func (s *orderStore) Cleanup(ctx context.Context, abortProcessing bool) error {
+	if _, err := s.repo.DeleteExpired(ctx); err != nil {
+		return err
+	}
+
+	if abortProcessing {
+		orders, err := s.repo.ListByStatus(ctx, statusProcessing)
+		if err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			if _, _, err := s.UpdateOrder(ctx, order.Id, func(o *orderResource) bool {
+				o.Status = statusInvalid
+				o.Error = serverInternalProblem("the certificate issuance was interrupted")
+				return true
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func castOrderToResource(order *domain.ACMEServerOrder) *orderResource {
+	resource := &orderResource{
+		Id:       order.Id,
+		ClientId: order.ClientId,
+		Status:   order.Status,
+		Expires:  order.ExpiresAt,
+		Identifiers: lo.Map(order.Identifiers, func(ident domain.ACMEServerIdentifier, _ int) identifier {
+			return identifier{Type: ident.Type, Value: ident.Value}
+		}),
+		AuthorizationIds: make([]string, 0, len(order.Authorizations)),
+		CertificateId:    order.CertificateId,
+	}
+	for i := range order.Authorizations {
+		resource.AuthorizationIds = append(resource.AuthorizationIds, buildAuthorizationId(order.Id, i))
+	}
+	if order.Error != "" {
+		resource.Error = serverInternalProblem("%s", order.Error)
+	}
+
+	return resource
+}
+
+func buildAuthorizationId(orderId string, index int) string {
+	return fmt.Sprintf("%s-%d", orderId, index)
+}
+
+func parseAuthorizationId(id string) (string, int, bool) {
+	orderId, indexStr, ok := strings.Cut(id, "-")
+	if !ok || orderId == "" {
+		return "", 0, false
+	}
+
+	index, err := strconv.Atoi(indexStr)
+	if err != nil || index < 0 {
+		return "", 0, false
+	}
+
+	return orderId, index, true
+}
@@ -0,0 +1,117 @@
+package acmeserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockACMEServerOrderRepository struct {
+	orders map[string]*domain.ACMEServerOrder
+}
+
+func (r *mockACMEServerOrderRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerOrder, error) {
+	if order, ok := r.orders[id]; ok {
+		clone := *order
+		return &clone, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockACMEServerOrderRepository) ListByClientId(ctx context.Context, clientId string) ([]*domain.ACMEServerOrder, error) {
+	orders := make([]*domain.ACMEServerOrder, 0)
+	for _, order := range r.orders {
+		if order.ClientId == clientId {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *mockACMEServerOrderRepository) ListByStatus(ctx context.Context, status string) ([]*domain.ACMEServerOrder, error) {
+	orders := make([]*domain.ACMEServerOrder, 0)
+	for _, order := range r.orders {
+		if order.Status == status {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *mockACMEServerOrderRepository) Save(ctx context.Context, order *domain.ACMEServerOrder) (*domain.ACMEServerOrder, error) {
+	if order.Id == "" {
+		order.Id = fmt.Sprintf("order%010d", len(r.orders)+1)
+	}
+
+	clone := *order
+	r.orders[order.Id] = &clone
+	return order, nil
+}
+
+func (r *mockACMEServerOrderRepository) DeleteExpired(ctx context.Context) (int, error) {
+	ret := 0
+	for id, order := range r.orders {
+		if time.Now().After(order.ExpiresAt) {
+			delete(r.orders, id)
+			ret++
+		}
+	}
+	return ret, nil
+}
+
+func TestOrderStore(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockACMEServerOrderRepository{orders: make(map[string]*domain.ACMEServerOrder)}
+	store := newOrderStore(repo)
+
+	order, err := store.AddOrder(ctx, &orderResource{
+		ClientId:    "client",
+		Status:      statusReady,
+		Expires:     time.Now().Add(orderTTL),
+		Identifiers: []identifier{{Type: "dns", Value: "example.com"}, {Type: "dns", Value: "*.example.com"}},
+	}, []*authorizationResource{
+		{Status: statusValid, Identifier: identifier{Type: "dns", Value: "example.com"}},
+		{Status: statusValid, Identifier: identifier{Type: "dns", Value: "example.com"}, Wildcard: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, order.AuthorizationIds, 2)
+
+	// 授权可由其 ID 定位到所属订单
+	authz, err := store.GetAuthorization(ctx, order.AuthorizationIds[1])
+	require.NoError(t, err)
+	assert.Equal(t, "client", authz.ClientId)
+	assert.True(t, authz.Wildcard)
+
+	for _, id := range []string{order.Id, order.Id + "-2", order.Id + "-x", "-0"} {
+		_, err := store.GetAuthorization(ctx, id)
+		assert.ErrorIs(t, err, domain.ErrRecordNotFound, id)
+	}
+
+	// 重启前未完成签发的订单在首次清理时置为无效
+	_, ok, err := store.UpdateOrder(ctx, order.Id, func(o *orderResource) bool {
+		o.Status = statusProcessing
+		return true
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, store.Cleanup(ctx, true))
+	order, err = store.GetOrder(ctx, order.Id)
+	require.NoError(t, err)
+	assert.Equal(t, statusInvalid, order.Status)
+	assert.NotNil(t, order.Error)
+
+	// 已过期的订单不可再访问，并在清理时删除
+	repo.orders[order.Id].ExpiresAt = time.Now().Add(-time.Second)
+	_, err = store.GetOrder(ctx, order.Id)
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+
+	require.NoError(t, store.Cleanup(ctx, false))
+	assert.Empty(t, repo.orders)
+}
@@ -26,6 +26,33 @@ var caDirUrls = map[string]string{
 	domain.CAProviderTypeZeroSSL.String():             "https://acme.zerossl.com/v2/DV90",
 }
 
+// CA 在 CAA 记录中使用的标识。
+// 仅当 ACME 目录未提供 `meta.caaIdentities` 时使用。
+var caCAAIdentities = map[string][]string{
+	domain.CAProviderTypeLetsEncrypt.String():         {"letsencrypt.org"},
+	domain.CAProviderTypeLetsEncryptStaging.String():  {"letsencrypt.org"},
+	domain.CAProviderTypeActalisSSL.String():          {"actalis.it"},
+	domain.CAProviderTypeDigiCert.String():            {"digicert.com", "www.digicert.com"},
+	domain.CAProviderTypeGlobalSignAtlas.String():     {"globalsign.com"},
+	domain.CAProviderTypeGoogleTrustServices.String(): {"pki.goog"},
+	domain.CAProviderTypeSSLCom.String():              {"ssl.com"},
+	domain.CAProviderTypeSectigo.String():             {"sectigo.com", "comodoca.com"},
+	domain.CAProviderTypeZeroSSL.String():             {"sectigo.com", "zerossl.com"},
+}
+
+// CA 对应的测试环境，试运行时向测试环境申请证书，以免占用正式环境的速率限制。
+var caStagingProviders = map[string]domain.CAProviderType{
+	domain.CAProviderTypeLetsEncrypt.String():        domain.CAProviderTypeLetsEncryptStaging,
+	domain.CAProviderTypeLetsEncryptStaging.String(): domain.CAProviderTypeLetsEncryptStaging,
+}
+
+// 获取 CA 对应的测试环境。
+// 若该 CA 未提供测试环境，第二个返回值为 false。
+func GetStagingCAProvider(providerType domain.CAProviderType) (domain.CAProviderType, bool) {
+	staging, ok := caStagingProviders[providerType.String()]
+	return staging, ok
+}
+
 func getCADirUrl(providerType domain.CAProviderType, providerAccessConfig map[string]any, keyAlgorithm domain.CertificateKeyAlgorithmType) (string, error) {
 	switch providerType {
 	case domain.CAProviderTypeSectigo:
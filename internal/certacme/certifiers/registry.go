@@ -1,7 +1,9 @@
 package certifiers
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core"
@@ -14,6 +16,15 @@ type ProviderFactoryOptions struct {
 	ProviderExtendedConfig map[string]any
 	DnsPropagationTimeout  int
 	DnsTTL                 int
+	Interactor             ChallengeInteractor
+}
+
+// ChallengeInteractor 为需要人工介入的质询提供商提供与外部交互的能力。
+type ChallengeInteractor interface {
+	// 发布需要人工处理的质询记录。
+	Publish(ctx context.Context, domain, recordName, recordValue string) error
+	// 阻塞直到人工确认、超时或上下文取消。
+	WaitForConfirmation(ctx context.Context, timeout time.Duration) error
 }
 
 type Registry[T comparable] interface {
@@ -76,6 +87,7 @@ func newRegistry[T comparable]() Registry[T] {
 }
 
 var (
-	ACMEDns01Registries  = newRegistry[domain.ACMEDns01ProviderType]()
-	ACMEHttp01Registries = newRegistry[domain.ACMEHttp01ProviderType]()
+	ACMEDns01Registries     = newRegistry[domain.ACMEDns01ProviderType]()
+	ACMEHttp01Registries    = newRegistry[domain.ACMEHttp01ProviderType]()
+	ACMETlsAlpn01Registries = newRegistry[domain.ACMETlsAlpn01ProviderType]()
 )
@@ -4,6 +4,7 @@ import (
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core"
 	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/local"
+	tlschlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/tlsalpn01/local"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
@@ -14,4 +15,12 @@ func init() {
 		})
 		return provider, err
 	})
+
+	ACMETlsAlpn01Registries.MustRegister(domain.ACMETlsAlpn01ProviderTypeLocal, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		provider, err := tlschlgimpl.NewChallenger(&tlschlgimpl.ChallengerConfig{
+			ListenInterface: xmaps.GetString(options.ProviderExtendedConfig, "listenInterface"),
+			ListenPort:      xmaps.GetInt32(options.ProviderExtendedConfig, "listenPort"),
+		})
+		return provider, err
+	})
 }
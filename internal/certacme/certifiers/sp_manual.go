@@ -0,0 +1,26 @@
+package certifiers
+
+import (
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core"
+	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/manual"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+func init() {
+	ACMEDns01Registries.MustRegister(domain.ACMEDns01ProviderTypeManual, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		if options.Interactor == nil {
+			return nil, fmt.Errorf("manual challenge requires an interactive context")
+		}
+
+		provider, err := chlgimpl.NewChallenger(&chlgimpl.ChallengerConfig{
+			PublishFunc:           options.Interactor.Publish,
+			ConfirmFunc:           options.Interactor.WaitForConfirmation,
+			ConfirmationTimeout:   xmaps.GetInt(options.ProviderExtendedConfig, "confirmationTimeout"),
+			DnsPropagationTimeout: options.DnsPropagationTimeout,
+		})
+		return provider, err
+	})
+}
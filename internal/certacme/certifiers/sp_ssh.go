@@ -6,6 +6,7 @@ import (
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/pkg/core"
 	chlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/http01/ssh"
+	tlschlgimpl "github.com/certimate-go/certimate/pkg/core/certifier/challengers/tlsalpn01/ssh"
 	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
@@ -45,4 +46,41 @@ func init() {
 		})
 		return provider, err
 	})
+
+	ACMETlsAlpn01Registries.MustRegister(domain.ACMETlsAlpn01ProviderTypeSSH, func(options *ProviderFactoryOptions) (core.ACMEChallenger, error) {
+		credentials := domain.AccessConfigForSSH{}
+		if err := xmaps.Populate(options.ProviderAccessConfig, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to populate provider access config: %w", err)
+		}
+
+		jumpServers := make([]tlschlgimpl.ServerConfig, len(credentials.JumpServers))
+		for i, jumpServer := range credentials.JumpServers {
+			jumpServers[i] = tlschlgimpl.ServerConfig{
+				SshHost:          jumpServer.Host,
+				SshPort:          jumpServer.Port,
+				SshAuthMethod:    jumpServer.AuthMethod,
+				SshUsername:      jumpServer.Username,
+				SshPassword:      jumpServer.Password,
+				SshKey:           jumpServer.Key,
+				SshKeyPassphrase: jumpServer.KeyPassphrase,
+			}
+		}
+
+		provider, err := tlschlgimpl.NewChallenger(&tlschlgimpl.ChallengerConfig{
+			ServerConfig: tlschlgimpl.ServerConfig{
+				SshHost:          credentials.Host,
+				SshPort:          credentials.Port,
+				SshAuthMethod:    credentials.AuthMethod,
+				SshUsername:      credentials.Username,
+				SshPassword:      credentials.Password,
+				SshKey:           credentials.Key,
+				SshKeyPassphrase: credentials.KeyPassphrase,
+			},
+			JumpServers:   jumpServers,
+			UseSCP:        xmaps.GetBool(options.ProviderExtendedConfig, "useSCP"),
+			CertDirPath:   xmaps.GetString(options.ProviderExtendedConfig, "certDirPath"),
+			ReloadCommand: xmaps.GetString(options.ProviderExtendedConfig, "reloadCommand"),
+		})
+		return provider, err
+	})
 }
@@ -0,0 +1,184 @@
+package certacme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/dns01"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+// confirmableChallenger 表示需要人工确认后才能继续验证的质询提供商。
+type confirmableChallenger interface {
+	WaitForConfirmation(ctx context.Context) error
+}
+
+type challengeRoute struct {
+	zone     string
+	provider core.ACMEChallenger
+}
+
+// challengeRouter 按域名将质询分派给不同的提供商。
+// 匹配规则为最长后缀优先；未匹配任何路由的域名使用默认提供商。
+type challengeRouter struct {
+	routes          []challengeRoute
+	defaultProvider core.ACMEChallenger
+}
+
+var (
+	_ challenge.ProviderTimeout = (*challengeRouter)(nil)
+	_ confirmableChallenger     = (*challengeRouter)(nil)
+)
+
+func newChallengeRouter(defaultProvider core.ACMEChallenger, routes []challengeRoute) core.ACMEChallenger {
+	routes = append([]challengeRoute(nil), routes...)
+	for i := range routes {
+		routes[i].zone = normalizeChallengeZone(routes[i].zone)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].zone) > len(routes[j].zone)
+	})
+
+	router := &challengeRouter{
+		routes:          routes,
+		defaultProvider: defaultProvider,
+	}
+
+	// DNS-01 提供商可能要求顺序执行质询，只要有一个提供商如此要求，就需要对外暴露
+	var sequentialInterval time.Duration
+	var sequentialRequired bool
+	for _, provider := range router.providers() {
+		if p, ok := provider.(interface{ Sequential() time.Duration }); ok {
+			sequentialRequired = true
+			sequentialInterval = max(sequentialInterval, p.Sequential())
+		}
+	}
+	if sequentialRequired {
+		return &sequentialChallengeRouter{challengeRouter: router, interval: sequentialInterval}
+	}
+
+	return router
+}
+
+func (r *challengeRouter) Present(ctx context.Context, domain, token, keyAuth string) error {
+	provider, err := r.resolve(domain)
+	if err != nil {
+		return err
+	}
+
+	return provider.Present(ctx, domain, token, keyAuth)
+}
+
+func (r *challengeRouter) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	provider, err := r.resolve(domain)
+	if err != nil {
+		return err
+	}
+
+	return provider.CleanUp(ctx, domain, token, keyAuth)
+}
+
+func (r *challengeRouter) Timeout() (timeout, interval time.Duration) {
+	timeout = dns01.DefaultPropagationTimeout
+	interval = dns01.DefaultPollingInterval
+
+	first := true
+	for _, provider := range r.providers() {
+		if p, ok := provider.(challenge.ProviderTimeout); ok {
+			t, i := p.Timeout()
+			if first {
+				timeout, interval = t, i
+				first = false
+			} else {
+				timeout = max(timeout, t)
+				interval = min(interval, i)
+			}
+		}
+	}
+
+	return timeout, interval
+}
+
+// 判断质询提供商是否需要人工确认。
+// 质询路由总是实现了 [confirmableChallenger]，因此需进一步判断其中是否存在需要人工确认的提供商。
+func asConfirmableChallenger(provider core.ACMEChallenger) (confirmableChallenger, bool) {
+	p, ok := provider.(confirmableChallenger)
+	if !ok {
+		return nil, false
+	}
+
+	if r, ok := provider.(interface{ confirmable() bool }); ok && !r.confirmable() {
+		return nil, false
+	}
+
+	return p, true
+}
+
+func (r *challengeRouter) confirmable() bool {
+	for _, provider := range r.providers() {
+		if _, ok := asConfirmableChallenger(provider); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *challengeRouter) WaitForConfirmation(ctx context.Context) error {
+	for _, provider := range r.providers() {
+		if p, ok := provider.(confirmableChallenger); ok {
+			if err := p.WaitForConfirmation(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *challengeRouter) resolve(domain string) (core.ACMEChallenger, error) {
+	domain = normalizeChallengeZone(domain)
+	for _, route := range r.routes {
+		if domain == route.zone || strings.HasSuffix(domain, "."+route.zone) {
+			return route.provider, nil
+		}
+	}
+
+	if r.defaultProvider == nil {
+		return nil, fmt.Errorf("no challenge provider matched for domain '%s'", domain)
+	}
+
+	return r.defaultProvider, nil
+}
+
+func (r *challengeRouter) providers() []core.ACMEChallenger {
+	providers := make([]core.ACMEChallenger, 0, len(r.routes)+1)
+	if r.defaultProvider != nil {
+		providers = append(providers, r.defaultProvider)
+	}
+	for _, route := range r.routes {
+		providers = append(providers, route.provider)
+	}
+	return providers
+}
+
+type sequentialChallengeRouter struct {
+	*challengeRouter
+	interval time.Duration
+}
+
+func (r *sequentialChallengeRouter) Sequential() time.Duration {
+	return r.interval
+}
+
+func normalizeChallengeZone(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "*.")
+	s = strings.TrimSuffix(s, ".")
+	return s
+}
@@ -0,0 +1,78 @@
+package certacme
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type mockChallenger struct{}
+
+func (p *mockChallenger) Present(ctx context.Context, domain, token, keyAuth string) error {
+	return nil
+}
+
+func (p *mockChallenger) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	return nil
+}
+
+type mockSequentialChallenger struct {
+	mockChallenger
+}
+
+func (p *mockSequentialChallenger) Sequential() time.Duration {
+	return time.Second
+}
+
+type mockConfirmableChallenger struct {
+	mockChallenger
+}
+
+func (p *mockConfirmableChallenger) WaitForConfirmation(ctx context.Context) error {
+	return nil
+}
+
+func TestAsConfirmableChallenger(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider core.ACMEChallenger
+		expected bool
+	}{
+		{
+			name:     "Plain",
+			provider: &mockChallenger{},
+			expected: false,
+		},
+		{
+			name:     "Confirmable",
+			provider: &mockConfirmableChallenger{},
+			expected: true,
+		},
+		{
+			name:     "RouterWithoutConfirmable",
+			provider: newChallengeRouter(&mockChallenger{}, []challengeRoute{{zone: "example.com", provider: &mockChallenger{}}}),
+			expected: false,
+		},
+		{
+			name:     "SequentialRouterWithoutConfirmable",
+			provider: newChallengeRouter(&mockChallenger{}, []challengeRoute{{zone: "example.com", provider: &mockSequentialChallenger{}}}),
+			expected: false,
+		},
+		{
+			name:     "RouterWithConfirmable",
+			provider: newChallengeRouter(&mockChallenger{}, []challengeRoute{{zone: "example.com", provider: &mockConfirmableChallenger{}}}),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, confirmable := asConfirmableChallenger(tc.provider)
+			assert.Equal(t, tc.expected, confirmable)
+		})
+	}
+}
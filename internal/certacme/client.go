@@ -8,10 +8,12 @@ import (
 	"github.com/go-acme/lego/v5/lego"
 
 	"github.com/certimate-go/certimate/internal/app"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 )
 
 type ACMEClient struct {
 	client  *lego.Client
+	config  *lego.Config
 	account *ACMEAccount
 }
 
@@ -36,6 +38,24 @@ func NewACMEClientWithAccount(account *ACMEAccount, configures ...func(*lego.Con
 	return newACMEClientWithAccount(account, configures...)
 }
 
+// 使用证书自身的私钥（而非 ACME 账户私钥）创建客户端。
+// 该客户端仅可用于吊销证书，适用于签发证书的 ACME 账户已不可用的场景。
+func NewACMEClientWithCertificateKey(caDirUrl string, privkeyPEM string, configures ...func(*lego.Config) error) (*ACMEClient, error) {
+	if caDirUrl == "" {
+		return nil, fmt.Errorf("the acme directory url is empty")
+	}
+
+	if _, err := xcert.ParsePrivateKeyFromPEM(privkeyPEM); err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	account := &ACMEAccount{
+		PrivateKey:       privkeyPEM,
+		ACMEDirectoryUrl: caDirUrl,
+	}
+	return newACMEClientWithAccount(account, configures...)
+}
+
 func newACMEClientWithAccount(account *ACMEAccount, configures ...func(*lego.Config) error) (*ACMEClient, error) {
 	if account == nil {
 		return nil, fmt.Errorf("the acme account is nil")
@@ -63,6 +83,7 @@ func newACMEClientWithAccount(account *ACMEAccount, configures ...func(*lego.Con
 
 	return &ACMEClient{
 		client:  legoClient,
+		config:  legoCfg,
 		account: account,
 	}, nil
 }
@@ -0,0 +1,134 @@
+package certacme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"github.com/go-acme/lego/v5/acme"
+	"github.com/go-acme/lego/v5/acme/api"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+// 生成一个新的账户私钥（PEM 格式）。
+// 轮换账户密钥前，调用方应先持久化该私钥，以免轮换成功后因保存失败而无法再访问账户。
+func GenerateAccountPrivateKey() (string, error) {
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	return xcert.ConvertECPrivateKeyToPEM(newKey, false)
+}
+
+type RolloverAccountKeyRequest struct {
+	PrivateKey string // 新的账户私钥（PEM 格式）
+}
+
+type RolloverAccountKeyResponse struct{}
+
+func (c *ACMEClient) RolloverAccountKey(ctx context.Context, request *RolloverAccountKeyRequest) (*RolloverAccountKeyResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	newKey, err := xcert.ParsePrivateKeyFromPEM(request.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new private key: %w", err)
+	}
+
+	signer, ok := newKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the new private key is not a signer")
+	}
+
+	if err := c.client.Registration.KeyRollover(ctx, signer); err != nil {
+		return nil, err
+	}
+
+	return &RolloverAccountKeyResponse{}, nil
+}
+
+type UpdateAccountContactsRequest struct {
+	Emails []string
+}
+
+type UpdateAccountContactsResponse struct {
+	Account *acme.Account
+}
+
+func (c *ACMEClient) UpdateAccountContacts(ctx context.Context, request *UpdateAccountContactsRequest) (*UpdateAccountContactsResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+	if c.account.ACMEAccountUrl == "" {
+		return nil, fmt.Errorf("the acme account url is empty")
+	}
+
+	// lego 的 Registrar 仅支持使用单个邮箱更新联系方式，这里直接调用底层 API
+	core, err := api.New(c.config.HTTPClient, c.config.UserAgent, c.config.CADirURL, c.account.ACMEAccountUrl, c.account.GetPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]string, 0, len(request.Emails))
+	for _, email := range request.Emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+
+		contacts = append(contacts, "mailto:"+email)
+	}
+
+	account, err := core.Accounts.Update(ctx, c.account.ACMEAccountUrl, acme.Account{Contact: contacts})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateAccountContactsResponse{
+		Account: &account,
+	}, nil
+}
+
+type DeactivateAccountRequest struct{}
+
+type DeactivateAccountResponse struct{}
+
+func (c *ACMEClient) DeactivateAccount(ctx context.Context, request *DeactivateAccountRequest) (*DeactivateAccountResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	if err := c.client.Registration.DeleteRegistration(ctx); err != nil {
+		return nil, err
+	}
+
+	return &DeactivateAccountResponse{}, nil
+}
+
+type QueryAccountRequest struct{}
+
+type QueryAccountResponse struct {
+	Account *acme.Account
+}
+
+func (c *ACMEClient) QueryAccount(ctx context.Context, request *QueryAccountRequest) (*QueryAccountResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	regres, err := c.client.Registration.QueryRegistration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryAccountResponse{
+		Account: &regres.Account,
+	}, nil
+}
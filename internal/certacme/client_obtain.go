@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,11 +16,13 @@ import (
 	"github.com/go-acme/lego/v5/certificate"
 	"github.com/go-acme/lego/v5/challenge/dns01"
 	"github.com/go-acme/lego/v5/challenge/http01"
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v5/log"
 	"github.com/samber/lo"
 
 	"github.com/certimate-go/certimate/internal/certacme/certifiers"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core"
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 )
 
@@ -27,6 +30,7 @@ type ObtainCertificateRequest struct {
 	DomainOrIPs       []string
 	PrivateKeyType    certcrypto.KeyType
 	PrivateKeyPEM     string
+	CSRPEM            string // 如果提供了 CSR，则使用该 CSR 申请证书，且不会返回私钥
 	ValidityNotBefore time.Time
 	ValidityNotAfter  time.Time
 	NoCommonName      bool
@@ -36,6 +40,8 @@ type ObtainCertificateRequest struct {
 	Provider               domain.ACMEChallengeProviderType
 	ProviderAccessConfig   map[string]any
 	ProviderExtendedConfig map[string]any
+	ProviderMappings       []*ObtainCertificateProviderMapping
+	Interactor             certifiers.ChallengeInteractor `json:"-"`
 
 	// 解析相关
 	DisableFollowCNAME bool
@@ -49,6 +55,9 @@ type ObtainCertificateRequest struct {
 	// HTTP-01 质询相关
 	HttpDelayWait int
 
+	// TLS-ALPN-01 质询相关
+	TlsAlpnDelayWait int
+
 	// ACME 相关
 	PreferredChain string
 	ACMEProfile    string
@@ -58,6 +67,15 @@ type ObtainCertificateRequest struct {
 	ARIReplacesCertId     string
 }
 
+type ObtainCertificateProviderMapping struct {
+	// 域名后缀或托管区域，如 "example.com"。
+	// 将匹配该域名本身及其所有子域名。
+	Zone                   string
+	Provider               domain.ACMEChallengeProviderType
+	ProviderAccessConfig   map[string]any
+	ProviderExtendedConfig map[string]any
+}
+
 type ObtainCertificateResponse struct {
 	CAProvider           domain.CAProviderType
 	CSR                  string
@@ -69,6 +87,12 @@ type ObtainCertificateResponse struct {
 	ARIReplaced          bool
 }
 
+const (
+	CHALLENGE_TYPE_DNS01     = "dns-01"
+	CHALLENGE_TYPE_HTTP01    = "http-01"
+	CHALLENGE_TYPE_TLSALPN01 = "tls-alpn-01"
+)
+
 func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCertificateRequest) (*ObtainCertificateResponse, error) {
 	if request == nil {
 		return nil, fmt.Errorf("the request is nil")
@@ -76,63 +100,68 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 
 	os.Setenv("LEGO_DISABLE_CNAME_SUPPORT", strconv.FormatBool(request.DisableFollowCNAME))
 
-	const CHALLENGE_TYPE_DNS01 = "dns-01"
-	const CHALLENGE_TYPE_HTTP01 = "http-01"
+	provider, err := c.createChallengeProvider(request)
+	if err != nil {
+		return nil, err
+	}
+
 	switch strings.ToLower(request.ChallengeType) {
 	case CHALLENGE_TYPE_DNS01:
 		{
-			providerFactory, err := certifiers.ACMEDns01Registries.Get(domain.ACMEDns01ProviderType(request.Provider))
-			if err != nil {
-				return nil, err
-			}
-
-			provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
-				ProviderAccessConfig:   request.ProviderAccessConfig,
-				ProviderExtendedConfig: request.ProviderExtendedConfig,
-				DnsPropagationTimeout:  request.DnsPropagationTimeout,
-				DnsTTL:                 request.DnsTTL,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize dns-01 provider '%s': %w", request.Provider, err)
-			}
-
 			opts := &dns01.Options{}
 			opts.RecursiveNameservers = request.Nameservers
 			dns01.SetDefaultClient(dns01.NewClient(opts))
+			confirmer, confirmable := asConfirmableChallenger(provider)
 			c.client.Challenge.SetDNS01Provider(provider,
 				dns01.CondOptions(
-					request.DnsPropagationWait > 0,
+					request.DnsPropagationWait > 0 && !confirmable,
 					dns01.PropagationWait(time.Duration(request.DnsPropagationWait)*time.Second, true),
 				),
 				dns01.CondOptions(
 					len(request.Nameservers) > 0 || request.DnsPropagationWait > 0,
 					dns01.DisableAuthoritativeNssPropagationRequirement(),
 				),
+				dns01.CondOptions(
+					confirmable,
+					dns01.WrapPreCheck(func(ctx context.Context, domain, fqdn, value string, check dns01.PreCheckFunc) (bool, error) {
+						// 需要人工确认的质询，在所有记录发布后、检查传播前等待确认
+						if err := confirmer.WaitForConfirmation(ctx); err != nil {
+							return true, err
+						}
+
+						// 等价于 [dns01.PropagationWait]，二者均会替换默认的检查函数，因此无法同时设置
+						if request.DnsPropagationWait > 0 {
+							select {
+							case <-ctx.Done():
+								return true, ctx.Err()
+							case <-time.After(time.Duration(request.DnsPropagationWait) * time.Second):
+							}
+							return true, nil
+						}
+
+						return check(ctx, fqdn, value)
+					}),
+				),
 			)
 		}
 
 	case CHALLENGE_TYPE_HTTP01:
 		{
-			providerFactory, err := certifiers.ACMEHttp01Registries.Get(domain.ACMEHttp01ProviderType(request.Provider))
-			if err != nil {
-				return nil, err
-			}
-
-			provider, err := providerFactory(&certifiers.ProviderFactoryOptions{
-				ProviderAccessConfig:   request.ProviderAccessConfig,
-				ProviderExtendedConfig: request.ProviderExtendedConfig,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to initialize http-01 provider '%s': %w", request.Provider, err)
-			}
-
 			c.client.Challenge.SetHTTP01Provider(provider,
 				http01.SetDelay(time.Duration(request.HttpDelayWait)*time.Second),
 			)
 		}
 
-	default:
-		return nil, fmt.Errorf("unsupported challenge type: '%s'", request.ChallengeType)
+	case CHALLENGE_TYPE_TLSALPN01:
+		{
+			c.client.Challenge.SetTLSALPN01Provider(provider,
+				tlsalpn01.SetDelay(time.Duration(request.TlsAlpnDelayWait)*time.Second),
+			)
+		}
+	}
+
+	if request.CSRPEM != "" {
+		return c.obtainCertificateForCSR(ctx, request)
 	}
 
 	var privkey crypto.Signer
@@ -194,3 +223,150 @@ func (c *ACMEClient) ObtainCertificate(ctx context.Context, request *ObtainCerti
 		ARIReplaced:          req.ReplacesCertID != "",
 	}, nil
 }
+
+func (c *ACMEClient) obtainCertificateForCSR(ctx context.Context, request *ObtainCertificateRequest) (*ObtainCertificateResponse, error) {
+	csr, err := certcrypto.PemDecodeTox509CSR([]byte(request.CSRPEM))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csr: %w", err)
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("failed to verify csr signature: %w", err)
+	}
+
+	if len(request.DomainOrIPs) > 0 {
+		csrIdentifiers := certcrypto.ExtractDomainsCSR(csr)
+		if !equalIdentifiers(csrIdentifiers, request.DomainOrIPs) {
+			return nil, fmt.Errorf("the identifiers in csr (%s) do not match the requested ones (%s)", strings.Join(csrIdentifiers, ";"), strings.Join(request.DomainOrIPs, ";"))
+		}
+	}
+
+	req := certificate.ObtainForCSRRequest{
+		CSR:              csr,
+		Bundle:           true,
+		EnableCommonName: !request.NoCommonName,
+		PreferredChain:   request.PreferredChain,
+		Profile:          request.ACMEProfile,
+		NotBefore:        request.ValidityNotBefore,
+		NotAfter:         request.ValidityNotAfter,
+		ReplacesCertID:   lo.If(request.ARIReplacesAccountUrl == c.account.ACMEAccountUrl, request.ARIReplacesCertId).Else(""),
+	}
+	resp, err := c.client.Certificate.ObtainForCSR(ctx, req)
+	if err != nil {
+		ariErr := &acme.AlreadyReplacedError{}
+		if !errors.As(err, &ariErr) {
+			return nil, err
+		}
+
+		log.Warn("the certificate has already been replaced, try to obtain again without ARI ...")
+
+		// reset ARI and retry if failure
+		req.ReplacesCertID = ""
+		resp, err = c.client.Certificate.ObtainForCSR(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ObtainCertificateResponse{
+		CAProvider:           domain.CAProviderType(c.account.CA),
+		CSR:                  strings.TrimSpace(request.CSRPEM),
+		FullChainCertificate: strings.TrimSpace(string(resp.Certificate)),
+		IssuerCertificate:    strings.TrimSpace(string(resp.IssuerCertificate)),
+		ACMEAccountUrl:       c.account.ACMEAccountUrl,
+		ACMECertificateUrl:   resp.CertURL,
+		ARIReplaced:          req.ReplacesCertID != "",
+	}, nil
+}
+
+func equalIdentifiers(a, b []string) bool {
+	normalize := func(s []string) []string {
+		r := make([]string, 0, len(s))
+		for _, v := range s {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v != "" && !slices.Contains(r, v) {
+				r = append(r, v)
+			}
+		}
+		slices.Sort(r)
+		return r
+	}
+
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+// 创建质询提供商。质询方式作用于所有按域名映射的质询提供商，不支持混用，参见 [domain.WorkflowGraph.Verify]。
+func (c *ACMEClient) createChallengeProvider(request *ObtainCertificateRequest) (core.ACMEChallenger, error) {
+	challengeType := strings.ToLower(request.ChallengeType)
+
+	newProvider := func(provider domain.ACMEChallengeProviderType, accessConfig, extendedConfig map[string]any) (core.ACMEChallenger, error) {
+		var providerFactory certifiers.ProviderFactoryFunc
+		var err error
+		switch challengeType {
+		case CHALLENGE_TYPE_DNS01:
+			providerFactory, err = certifiers.ACMEDns01Registries.Get(domain.ACMEDns01ProviderType(provider))
+		case CHALLENGE_TYPE_HTTP01:
+			providerFactory, err = certifiers.ACMEHttp01Registries.Get(domain.ACMEHttp01ProviderType(provider))
+		case CHALLENGE_TYPE_TLSALPN01:
+			providerFactory, err = certifiers.ACMETlsAlpn01Registries.Get(domain.ACMETlsAlpn01ProviderType(provider))
+		default:
+			return nil, fmt.Errorf("unsupported challenge type: '%s'", request.ChallengeType)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		factoryOpts := &certifiers.ProviderFactoryOptions{
+			ProviderAccessConfig:   accessConfig,
+			ProviderExtendedConfig: extendedConfig,
+		}
+		if challengeType == CHALLENGE_TYPE_DNS01 {
+			factoryOpts.DnsPropagationTimeout = request.DnsPropagationTimeout
+			factoryOpts.DnsTTL = request.DnsTTL
+		}
+		if request.Interactor != nil {
+			factoryOpts.Interactor = request.Interactor
+		}
+
+		p, err := providerFactory(factoryOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize %s provider '%s': %w", challengeType, provider, err)
+		}
+
+		return p, nil
+	}
+
+	if len(request.ProviderMappings) == 0 {
+		return newProvider(request.Provider, request.ProviderAccessConfig, request.ProviderExtendedConfig)
+	}
+
+	// 按域名映射不同的质询提供商，未匹配的域名回退到默认质询提供商（如果有）
+	var defaultProvider core.ACMEChallenger
+	if request.Provider != "" {
+		p, err := newProvider(request.Provider, request.ProviderAccessConfig, request.ProviderExtendedConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		defaultProvider = p
+	}
+
+	routes := make([]challengeRoute, 0, len(request.ProviderMappings))
+	for _, mapping := range request.ProviderMappings {
+		if mapping == nil {
+			continue
+		}
+		if mapping.Zone == "" {
+			return nil, fmt.Errorf("the zone of challenge provider mapping is empty")
+		}
+
+		p, err := newProvider(mapping.Provider, mapping.ProviderAccessConfig, mapping.ProviderExtendedConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, challengeRoute{zone: mapping.Zone, provider: p})
+	}
+
+	return newChallengeRouter(defaultProvider, routes), nil
+}
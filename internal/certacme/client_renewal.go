@@ -0,0 +1,43 @@
+package certacme
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type GetRenewalInfoRequest struct {
+	Certificate string
+}
+
+type GetRenewalInfoResponse struct {
+	SuggestedWindowStart time.Time
+	SuggestedWindowEnd   time.Time
+	ExplanationUrl       string
+	RetryAfter           time.Duration
+}
+
+func (c *ACMEClient) GetRenewalInfo(ctx context.Context, request *GetRenewalInfoRequest) (*GetRenewalInfoResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	certX509, err := xcert.ParseCertificateFromPEM(request.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	renewalInfo, err := c.client.Certificate.GetRenewalInfo(ctx, certX509)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetRenewalInfoResponse{
+		SuggestedWindowStart: renewalInfo.SuggestedWindow.Start,
+		SuggestedWindowEnd:   renewalInfo.SuggestedWindow.End,
+		ExplanationUrl:       renewalInfo.ExplanationURL,
+		RetryAfter:           renewalInfo.RetryAfter,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 
 type RevokeCertificateRequest struct {
 	Certificate string
+	Reason      *uint
 }
 
 type RevokeCertificateResponse struct{}
@@ -16,7 +17,7 @@ func (c *ACMEClient) RevokeCertificate(ctx context.Context, request *RevokeCerti
 		return nil, fmt.Errorf("the request is nil")
 	}
 
-	err := c.client.Certificate.Revoke(ctx, []byte(request.Certificate))
+	err := c.client.Certificate.RevokeWithReason(ctx, []byte(request.Certificate), request.Reason)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,368 @@
+package certacme
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge/dns01"
+	"github.com/miekg/dns"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type PreflightRequest struct {
+	DomainOrIPs        []string
+	CAProvider         domain.CAProviderType
+	CADirUrl           string
+	ChallengeType      string
+	Provider           domain.ACMEChallengeProviderType
+	ProviderMappings   []*ObtainCertificateProviderMapping
+	DisableFollowCNAME bool
+	Nameservers        []string
+}
+
+// 在申请证书前执行预检，以便在创建 ACME 订单前发现问题，避免浪费 CA 的速率限额。
+// 包括：
+//   - 检查 CAA 记录是否允许所选 CA 签发证书；
+//   - 检查 `_acme-challenge` 的 CNAME 委派是否指向一个有效且可由质询提供商写入的区域。
+//
+// 仅当查询结果明确表明无法签发时才判定为失败；DNS 查询出错等无法判定的情况仅报告为未知。
+func Preflight(ctx context.Context, request *PreflightRequest) (*domain.CertificatePreflightReport, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	resolver := newPreflightResolver(request.Nameservers)
+	caaIdentities := getCAAIdentities(ctx, request.CAProvider, request.CADirUrl)
+
+	report := &domain.CertificatePreflightReport{
+		Passed:  true,
+		Domains: make([]*domain.CertificatePreflightDomainReport, 0),
+	}
+	for _, domainOrIP := range request.DomainOrIPs {
+		// IP 地址不适用 CAA 及 DNS-01 质询
+		if net.ParseIP(domainOrIP) != nil {
+			continue
+		}
+
+		domainReport := &domain.CertificatePreflightDomainReport{
+			Domain: domainOrIP,
+			Passed: true,
+			Checks: make([]*domain.CertificatePreflightCheck, 0),
+		}
+
+		domainReport.Checks = append(domainReport.Checks, checkPreflightCAA(ctx, resolver, domainOrIP, request.CAProvider, caaIdentities))
+		if strings.EqualFold(request.ChallengeType, CHALLENGE_TYPE_DNS01) {
+			domainReport.Checks = append(domainReport.Checks, checkPreflightCNAME(ctx, resolver, domainOrIP, request))
+		}
+
+		for _, check := range domainReport.Checks {
+			if check.Status == domain.CertificatePreflightStatusTypeFailed {
+				domainReport.Passed = false
+				report.Passed = false
+			}
+		}
+
+		report.Domains = append(report.Domains, domainReport)
+	}
+
+	return report, nil
+}
+
+func checkPreflightCAA(ctx context.Context, resolver *preflightResolver, domainName string, caProvider domain.CAProviderType, caaIdentities []string) *domain.CertificatePreflightCheck {
+	check := &domain.CertificatePreflightCheck{Name: domain.CertificatePreflightCheckTypeCAA}
+
+	if caProvider == domain.CAProviderTypePrivateCA {
+		check.Status = domain.CertificatePreflightStatusTypeSkipped
+		check.Message = "CAA is not applicable to the built-in private CA"
+		return check
+	}
+
+	if len(caaIdentities) == 0 {
+		check.Status = domain.CertificatePreflightStatusTypeSkipped
+		check.Message = fmt.Sprintf("the CAA identities of CA '%s' are unknown", caProvider)
+		return check
+	}
+
+	// RFC 8659 §3：从域名本身开始逐级向上查找，直到找到第一个非空的 CAA 记录集
+	var owner string
+	var records []*dns.CAA
+	for name := range dns01.UnFqdnDomainsSeq(dns.Fqdn(strings.TrimPrefix(domainName, "*."))) {
+		resp, err := resolver.Query(ctx, name, dns.TypeCAA)
+		if err != nil {
+			check.Status = domain.CertificatePreflightStatusTypeUnknown
+			check.Message = fmt.Sprintf("could not resolve CAA records of '%s': %s", name, err.Error())
+			return check
+		} else if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			check.Status = domain.CertificatePreflightStatusTypeUnknown
+			check.Message = fmt.Sprintf("could not resolve CAA records of '%s': %s", name, dns.RcodeToString[resp.Rcode])
+			return check
+		}
+
+		for _, rr := range resp.Answer {
+			if caa, ok := rr.(*dns.CAA); ok {
+				records = append(records, caa)
+			}
+		}
+		if len(records) > 0 {
+			owner = name
+			break
+		}
+	}
+
+	if len(records) == 0 {
+		check.Status = domain.CertificatePreflightStatusTypePassed
+		check.Message = "no CAA records found"
+		return check
+	}
+
+	knownTags := []string{"issue", "issuewild", "iodef", "issuemail", "issuevmc", "contactemail", "contactphone"}
+	for _, record := range records {
+		if record.Flag&128 != 0 && !slices.Contains(knownTags, strings.ToLower(record.Tag)) {
+			check.Status = domain.CertificatePreflightStatusTypeFailed
+			check.Message = fmt.Sprintf("the CAA records at '%s' contain an unknown critical property '%s'", owner, record.Tag)
+			return check
+		}
+	}
+
+	issueRecords := lo.Filter(records, func(r *dns.CAA, _ int) bool { return strings.EqualFold(r.Tag, "issue") })
+	issuewildRecords := lo.Filter(records, func(r *dns.CAA, _ int) bool { return strings.EqualFold(r.Tag, "issuewild") })
+	relevantRecords := issueRecords
+	if strings.HasPrefix(domainName, "*.") && len(issuewildRecords) > 0 {
+		relevantRecords = issuewildRecords
+	}
+
+	if len(relevantRecords) == 0 {
+		check.Status = domain.CertificatePreflightStatusTypePassed
+		check.Message = fmt.Sprintf("the CAA records at '%s' do not restrict issuance", owner)
+		return check
+	}
+
+	for _, record := range relevantRecords {
+		issuer := strings.TrimSpace(strings.SplitN(record.Value, ";", 2)[0])
+		if lo.ContainsBy(caaIdentities, func(s string) bool { return strings.EqualFold(s, issuer) }) {
+			check.Status = domain.CertificatePreflightStatusTypePassed
+			check.Message = fmt.Sprintf("issuance is authorized by the CAA record at '%s': %s \"%s\"", owner, record.Tag, record.Value)
+			return check
+		}
+	}
+
+	check.Status = domain.CertificatePreflightStatusTypeFailed
+	check.Message = fmt.Sprintf("the CAA records at '%s' do not authorize '%s' (%s), allowed: %s",
+		owner,
+		caProvider,
+		strings.Join(caaIdentities, ", "),
+		strings.Join(lo.Map(relevantRecords, func(r *dns.CAA, _ int) string { return fmt.Sprintf("%s \"%s\"", r.Tag, r.Value) }), ", "),
+	)
+	return check
+}
+
+func checkPreflightCNAME(ctx context.Context, resolver *preflightResolver, domainName string, request *PreflightRequest) *domain.CertificatePreflightCheck {
+	check := &domain.CertificatePreflightCheck{Name: domain.CertificatePreflightCheckTypeCNAME}
+
+	if request.DisableFollowCNAME {
+		check.Status = domain.CertificatePreflightStatusTypeSkipped
+		check.Message = "CNAME following is disabled"
+		return check
+	}
+
+	challengeFqdn := "_acme-challenge." + normalizeChallengeZone(domainName)
+
+	// 跟随 CNAME 链，直到最终目标
+	target := challengeFqdn
+	visited := map[string]struct{}{challengeFqdn: {}}
+	for {
+		resp, err := resolver.Query(ctx, target, dns.TypeCNAME)
+		if err != nil {
+			check.Status = domain.CertificatePreflightStatusTypeUnknown
+			check.Message = fmt.Sprintf("could not resolve CNAME record of '%s': %s", target, err.Error())
+			return check
+		}
+
+		next := ""
+		for _, rr := range resp.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(dns.CanonicalName(cname.Hdr.Name), dns.CanonicalName(target)) {
+				next = strings.ToLower(dns.CanonicalName(cname.Target))
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+
+		next = strings.TrimSuffix(next, ".")
+		if _, ok := visited[next]; ok {
+			check.Status = domain.CertificatePreflightStatusTypeFailed
+			check.Message = fmt.Sprintf("the CNAME chain of '%s' contains a loop at '%s'", challengeFqdn, next)
+			return check
+		}
+
+		visited[next] = struct{}{}
+		target = next
+	}
+
+	if target == challengeFqdn {
+		check.Status = domain.CertificatePreflightStatusTypePassed
+		check.Message = fmt.Sprintf("'%s' is not delegated via CNAME", challengeFqdn)
+		return check
+	}
+
+	opts := &dns01.Options{}
+	opts.RecursiveNameservers = request.Nameservers
+	zone, err := dns01.NewClient(opts).FindZoneByFqdn(ctx, dns.Fqdn(target))
+	if err != nil {
+		check.Status = domain.CertificatePreflightStatusTypeUnknown
+		check.Message = fmt.Sprintf("could not find the zone of CNAME target '%s' of '%s': %s", target, challengeFqdn, err.Error())
+		return check
+	}
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+
+	// 检查实际写入质询记录的提供商能否管理目标区域
+	domainMapping := matchProviderMapping(request.ProviderMappings, domainName)
+	targetMapping := matchProviderMapping(request.ProviderMappings, target)
+	domainProvider := lo.If(domainMapping != nil, lo.FromPtr(domainMapping).Provider).Else(request.Provider)
+	if domain.ACMEDns01ProviderType(domainProvider) == domain.ACMEDns01ProviderTypeManual {
+		check.Status = domain.CertificatePreflightStatusTypePassed
+		check.Message = fmt.Sprintf("'%s' is delegated to '%s' in zone '%s'", challengeFqdn, target, zone)
+		return check
+	}
+
+	if targetMapping != nil && targetMapping != domainMapping && targetMapping.Provider != domainProvider {
+		check.Status = domain.CertificatePreflightStatusTypeFailed
+		check.Message = fmt.Sprintf("'%s' is delegated to '%s' in zone '%s', which is mapped to provider '%s', but the challenge will be presented by provider '%s'", challengeFqdn, target, zone, targetMapping.Provider, domainProvider)
+		return check
+	}
+
+	if domainMapping != nil && targetMapping == nil {
+		mappingZone := normalizeChallengeZone(domainMapping.Zone)
+		if zone != mappingZone && !strings.HasSuffix(zone, "."+mappingZone) {
+			check.Status = domain.CertificatePreflightStatusTypeWarning
+			check.Message = fmt.Sprintf("'%s' is delegated to '%s' in zone '%s', which is outside zone '%s' of provider '%s'; make sure the provider can write to it", challengeFqdn, target, zone, mappingZone, domainProvider)
+			return check
+		}
+	}
+
+	check.Status = domain.CertificatePreflightStatusTypePassed
+	check.Message = fmt.Sprintf("'%s' is delegated to '%s' in zone '%s'", challengeFqdn, target, zone)
+	return check
+}
+
+func matchProviderMapping(mappings []*ObtainCertificateProviderMapping, domainName string) *ObtainCertificateProviderMapping {
+	domainName = normalizeChallengeZone(domainName)
+
+	var matched *ObtainCertificateProviderMapping
+	for _, mapping := range mappings {
+		zone := normalizeChallengeZone(mapping.Zone)
+		if zone == "" {
+			continue
+		}
+
+		if domainName == zone || strings.HasSuffix(domainName, "."+zone) {
+			if matched == nil || len(zone) > len(normalizeChallengeZone(matched.Zone)) {
+				matched = mapping
+			}
+		}
+	}
+
+	return matched
+}
+
+// 获取 CA 在 CAA 记录中使用的标识。
+// 优先读取 ACME 目录中的 `meta.caaIdentities`（RFC 8555 §7.1.1），否则使用内置列表。
+func getCAAIdentities(ctx context.Context, caProvider domain.CAProviderType, caDirUrl string) []string {
+	if caDirUrl != "" {
+		ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, caDirUrl, nil)
+		if err == nil {
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				defer resp.Body.Close()
+
+				directory := struct {
+					Meta struct {
+						CAAIdentities []string `json:"caaIdentities"`
+					} `json:"meta"`
+				}{}
+				if resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&directory) == nil && len(directory.Meta.CAAIdentities) > 0 {
+					return directory.Meta.CAAIdentities
+				}
+			}
+		}
+	}
+
+	return caCAAIdentities[caProvider.String()]
+}
+
+type preflightResolver struct {
+	client      *dns.Client
+	nameservers []string
+}
+
+func newPreflightResolver(nameservers []string) *preflightResolver {
+	servers := make([]string, 0, len(nameservers))
+	for _, ns := range nameservers {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			ns = net.JoinHostPort(ns, "53")
+		}
+		servers = append(servers, ns)
+	}
+
+	if len(servers) == 0 {
+		if config, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil {
+			for _, server := range config.Servers {
+				servers = append(servers, net.JoinHostPort(server, config.Port))
+			}
+		}
+	}
+
+	if len(servers) == 0 {
+		servers = []string{"1.1.1.1:53", "8.8.8.8:53"}
+	}
+
+	return &preflightResolver{
+		client:      &dns.Client{Timeout: time.Second * 10},
+		nameservers: servers,
+	}
+}
+
+func (r *preflightResolver) Query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(4096, false)
+	msg.RecursionDesired = true
+
+	var lastErr error
+	for _, ns := range r.nameservers {
+		resp, _, err := r.client.ExchangeContext(ctx, msg, ns)
+		if err == nil && resp.Truncated {
+			tcpClient := &dns.Client{Net: "tcp", Timeout: r.client.Timeout}
+			resp, _, err = tcpClient.ExchangeContext(ctx, msg, ns)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.Rcode == dns.RcodeServerFailure || resp.Rcode == dns.RcodeRefused {
+			lastErr = fmt.Errorf("nameserver '%s' responded with %s", ns, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
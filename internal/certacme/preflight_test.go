@@ -0,0 +1,98 @@
+package certacme
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func startTestDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { _ = server.Shutdown() })
+	<-started
+
+	return pc.LocalAddr().String()
+}
+
+func TestPreflight_CAA(t *testing.T) {
+	newCAAHandler := func(rcode int, values ...string) dns.HandlerFunc {
+		return func(w dns.ResponseWriter, r *dns.Msg) {
+			resp := new(dns.Msg)
+			resp.SetRcode(r, rcode)
+			if rcode == dns.RcodeSuccess && r.Question[0].Name == "example.com." {
+				for _, value := range values {
+					resp.Answer = append(resp.Answer, &dns.CAA{
+						Hdr:   dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeCAA, Class: dns.ClassINET, Ttl: 60},
+						Tag:   "issue",
+						Value: value,
+					})
+				}
+			}
+			_ = w.WriteMsg(resp)
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		handler      dns.HandlerFunc
+		expectStatus domain.CertificatePreflightStatusType
+		expectPassed bool
+	}{
+		{name: "authorized", handler: newCAAHandler(dns.RcodeSuccess, "letsencrypt.org"), expectStatus: domain.CertificatePreflightStatusTypePassed, expectPassed: true},
+		{name: "no records", handler: newCAAHandler(dns.RcodeSuccess), expectStatus: domain.CertificatePreflightStatusTypePassed, expectPassed: true},
+		{name: "unauthorized", handler: newCAAHandler(dns.RcodeSuccess, "pki.goog"), expectStatus: domain.CertificatePreflightStatusTypeFailed, expectPassed: false},
+		{name: "resolver failure", handler: newCAAHandler(dns.RcodeServerFailure), expectStatus: domain.CertificatePreflightStatusTypeUnknown, expectPassed: true},
+		{name: "unexpected rcode", handler: newCAAHandler(dns.RcodeNotImplemented), expectStatus: domain.CertificatePreflightStatusTypeUnknown, expectPassed: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr := startTestDNSServer(t, tc.handler)
+
+			report, err := Preflight(context.Background(), &PreflightRequest{
+				DomainOrIPs:   []string{"example.com"},
+				CAProvider:    domain.CAProviderTypeLetsEncrypt,
+				ChallengeType: CHALLENGE_TYPE_HTTP01,
+				Nameservers:   []string{addr},
+			})
+			require.NoError(t, err)
+			require.Len(t, report.Domains, 1)
+			require.Len(t, report.Domains[0].Checks, 1)
+
+			assert.Equal(t, tc.expectStatus, report.Domains[0].Checks[0].Status)
+			assert.Equal(t, tc.expectPassed, report.Passed)
+		})
+	}
+}
+
+func TestPreflight_CNAMEResolverFailure(t *testing.T) {
+	addr := startTestDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		resp := new(dns.Msg)
+		resp.SetRcode(r, dns.RcodeRefused)
+		_ = w.WriteMsg(resp)
+	})
+
+	report, err := Preflight(context.Background(), &PreflightRequest{
+		DomainOrIPs:   []string{"example.com"},
+		CAProvider:    domain.CAProviderTypeLetsEncrypt,
+		ChallengeType: CHALLENGE_TYPE_DNS01,
+		Nameservers:   []string{addr},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Domains[0].Checks, 2)
+
+	assert.Equal(t, domain.CertificatePreflightStatusTypeUnknown, report.Domains[0].Checks[1].Status)
+	assert.True(t, report.Passed)
+}
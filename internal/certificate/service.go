@@ -4,11 +4,17 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
+	acmeapi "github.com/go-acme/lego/v5/acme/api"
 	"github.com/pocketbase/dbx"
+	"github.com/samber/lo"
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certacme"
@@ -20,14 +26,23 @@ import (
 )
 
 type CertificateService struct {
+	accessRepo      accessRepository
 	acmeAccountRepo acmeAccountRepository
 	certificateRepo certificateRepository
+	workflowRepo    workflowRepository
+
+	privateCASvc privateCAService
+	workflowSvc  workflowService
 }
 
-func NewCertificateService(acmeAccountRepo acmeAccountRepository, certificateRepo certificateRepository) *CertificateService {
+func NewCertificateService(accessRepo accessRepository, acmeAccountRepo acmeAccountRepository, certificateRepo certificateRepository, workflowRepo workflowRepository, privateCASvc privateCAService, workflowSvc workflowService) *CertificateService {
 	return &CertificateService{
+		accessRepo:      accessRepo,
 		acmeAccountRepo: acmeAccountRepo,
 		certificateRepo: certificateRepo,
+		workflowRepo:    workflowRepo,
+		privateCASvc:    privateCASvc,
+		workflowSvc:     workflowSvc,
 	}
 }
 
@@ -36,6 +51,11 @@ func (s *CertificateService) InitSchedule(ctx context.Context) error {
 		s.cleanupExpiredCertificates(context.Background())
 	})
 
+	// 定时轮询 ARI 续期窗口，与工作流的触发周期无关
+	app.GetScheduler().MustAdd("pollCertificateRenewalInfo", "*/30 * * * *", func() {
+		s.pollRenewalInfo(context.Background())
+	})
+
 	return nil
 }
 
@@ -61,13 +81,16 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 				return nil, fmt.Errorf("failed to extract certs: %w", err)
 			}
 
-			keyWriter, err := zipWriter.Create(fmt.Sprintf("%s.key", canonicalName))
-			if err != nil {
-				return nil, err
-			} else {
-				_, err = keyWriter.Write([]byte(certificate.PrivateKey))
+			// 由 CSR 签发的证书不持有私钥
+			if certificate.PrivateKey != "" {
+				keyWriter, err := zipWriter.Create(fmt.Sprintf("%s.key", canonicalName))
 				if err != nil {
 					return nil, err
+				} else {
+					_, err = keyWriter.Write([]byte(certificate.PrivateKey))
+					if err != nil {
+						return nil, err
+					}
 				}
 			}
 
@@ -101,6 +124,26 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 				}
 			}
 
+			// 由内置私有 CA 签发的证书，附带完整的 CA 证书链，以便客户端导入信任
+			if certificate.CA == domain.CAProviderTypePrivateCA.String() {
+				caChainPEM, err := s.privateCASvc.GetCAChain(ctx, certificate.Certificate)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get ca chain: %w", err)
+				}
+
+				if caChainPEM != "" {
+					caChainWriter, err := zipWriter.Create(fmt.Sprintf("%s.ca-chain.crt", canonicalName))
+					if err != nil {
+						return nil, err
+					} else {
+						_, err = caChainWriter.Write([]byte(caChainPEM))
+						if err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+
 			err = zipWriter.Close()
 			if err != nil {
 				return nil, err
@@ -111,6 +154,10 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 
 	case domain.CertificateFormatTypePFX:
 		{
+			if certificate.PrivateKey == "" {
+				return nil, fmt.Errorf("could not export a certificate without private key as PFX")
+			}
+
 			pfxPassword := "certimate"
 			if req.PfxPassword != "" {
 				pfxPassword = req.PfxPassword
@@ -157,6 +204,10 @@ func (s *CertificateService) DownloadCertificate(ctx context.Context, req *dtos.
 
 	case domain.CertificateFormatTypeJKS:
 		{
+			if certificate.PrivateKey == "" {
+				return nil, fmt.Errorf("could not export a certificate without private key as JKS")
+			}
+
 			jksAlias := "certimate"
 			if req.JksAlias != "" {
 				jksAlias = req.JksAlias
@@ -222,38 +273,383 @@ func (s *CertificateService) RevokeCertificate(ctx context.Context, req *dtos.Ce
 		return nil, err
 	}
 
-	if certificate.ACMEAccountUrl == "" || certificate.ACMECertificateUrl == "" {
-		return nil, fmt.Errorf("could not revoke a certificate which is not issued in Certimate")
+	if err := s.revokeCertificate(ctx, certificate, req.Reason, req.UseCertificateKey); err != nil {
+		return nil, fmt.Errorf("failed to revoke certificate: %w", err)
 	}
-	if certificate.IsRevoked {
-		return nil, fmt.Errorf("could not revoke a certificate which is already revoked")
+
+	return &dtos.CertificateRevokeResp{}, nil
+}
+
+func (s *CertificateService) BulkRevokeCertificates(ctx context.Context, req *dtos.CertificateBulkRevokeReq) (*dtos.CertificateBulkRevokeResp, error) {
+	if req.CompromisedCertificateId == "" && req.CompromisedPrivateKey == "" && req.SubjectAltName == "" {
+		return nil, domain.ErrInvalidParams
+	}
+
+	var compromisedPubkey crypto.PublicKey
+	if req.CompromisedCertificateId != "" {
+		certificate, err := s.certificateRepo.GetById(ctx, req.CompromisedCertificateId)
+		if err != nil {
+			return nil, err
+		}
+
+		certX509, err := xcert.ParseCertificateFromPEM(certificate.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		compromisedPubkey = certX509.PublicKey
+	} else if req.CompromisedPrivateKey != "" {
+		privkey, err := xcert.ParsePrivateKeyFromPEM(req.CompromisedPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+
+		signer, ok := privkey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key")
+		}
+
+		compromisedPubkey = signer.Public()
 	}
 
-	acmeAccount, err := s.acmeAccountRepo.GetByCAAndAcctUrl(ctx, certificate.CA, certificate.ACMEAccountUrl)
+	reason := req.Reason
+	if reason == "" && compromisedPubkey != nil {
+		reason = domain.CertificateRevocationReasonTypeKeyCompromise
+	}
+
+	certificates, err := s.certificateRepo.ListActive(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to revoke certificate: could not find acme account: %w", err)
+		return nil, err
+	}
+
+	resp := &dtos.CertificateBulkRevokeResp{
+		RevokedIds: make([]string, 0),
+		Failures:   make(map[string]string),
 	}
+	for _, certificate := range certificates {
+		matched := false
 
-	acmeClient, err := certacme.NewACMEClientWithAccount(acmeAccount)
+		if compromisedPubkey != nil {
+			certX509, err := xcert.ParseCertificateFromPEM(certificate.Certificate)
+			if err != nil {
+				continue
+			}
+
+			if pubkey, ok := certX509.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); ok && pubkey.Equal(compromisedPubkey) {
+				matched = true
+			}
+		}
+
+		if !matched && req.SubjectAltName != "" {
+			matched = slices.ContainsFunc(strings.Split(certificate.SubjectAltNames, ";"), func(san string) bool {
+				return strings.EqualFold(san, req.SubjectAltName)
+			})
+		}
+
+		if !matched {
+			continue
+		}
+
+		if err := s.revokeCertificate(ctx, certificate, reason, req.UseCertificateKey); err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to revoke certificate #%s", certificate.Id), slog.Any("error", err))
+			resp.Failures[certificate.Id] = err.Error()
+			continue
+		}
+
+		resp.RevokedIds = append(resp.RevokedIds, certificate.Id)
+	}
+
+	return resp, nil
+}
+
+func (s *CertificateService) PreflightCertificate(ctx context.Context, req *dtos.CertificatePreflightReq) (*dtos.CertificatePreflightResp, error) {
+	nodeCfg := &domain.WorkflowNodeConfigForBizApply{
+		Domains:            req.Domains,
+		ChallengeType:      req.ChallengeType,
+		Provider:           req.Provider,
+		ProviderMappings:   req.ProviderMappings,
+		CAProvider:         req.CAProvider,
+		CAProviderAccessId: req.CAProviderAccessId,
+		DisableFollowCNAME: req.DisableFollowCNAME,
+		Nameservers:        req.Nameservers,
+	}
+	if req.WorkflowId != "" && req.NodeId != "" {
+		workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+		if err != nil {
+			return nil, err
+		} else if workflow.GraphContent == nil {
+			return nil, fmt.Errorf("the workflow #%s has not been released", req.WorkflowId)
+		}
+
+		node, ok := workflow.GraphContent.GetNodeById(req.NodeId)
+		if !ok || node.Type != domain.WorkflowNodeTypeBizApply {
+			return nil, fmt.Errorf("the node #%s is not an application node", req.NodeId)
+		}
+
+		thisNodeCfg := node.Data.Config.AsBizApply()
+		nodeCfg = &thisNodeCfg
+	}
+
+	if len(nodeCfg.Domains) == 0 {
+		return nil, fmt.Errorf("no domains to check")
+	}
+
+	providerMappings := make([]*certacme.ObtainCertificateProviderMapping, 0, len(nodeCfg.ProviderMappings))
+	for _, mapping := range nodeCfg.ProviderMappings {
+		providerMappings = append(providerMappings, &certacme.ObtainCertificateProviderMapping{
+			Zone:     mapping.Zone,
+			Provider: domain.ACMEChallengeProviderType(mapping.Provider),
+		})
+	}
+
+	preflightReq := &certacme.PreflightRequest{
+		DomainOrIPs:        nodeCfg.Domains,
+		CAProvider:         domain.CAProviderType(nodeCfg.CAProvider),
+		ChallengeType:      lo.If(nodeCfg.ChallengeType != "", nodeCfg.ChallengeType).Else(certacme.CHALLENGE_TYPE_DNS01),
+		Provider:           domain.ACMEChallengeProviderType(nodeCfg.Provider),
+		ProviderMappings:   providerMappings,
+		DisableFollowCNAME: nodeCfg.DisableFollowCNAME,
+		Nameservers:        nodeCfg.Nameservers,
+	}
+	if preflightReq.CAProvider != domain.CAProviderTypePrivateCA {
+		caAccessConfig := make(map[string]any)
+		if nodeCfg.CAProviderAccessId != "" {
+			if access, err := s.accessRepo.GetById(ctx, nodeCfg.CAProviderAccessId); err != nil {
+				return nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.CAProviderAccessId, err)
+			} else {
+				caAccessConfig = access.Config
+			}
+		}
+
+		acmeCfg, err := certacme.CreateACMEConfig(ctx, &certacme.ACMEConfigOptions{
+			CAProvider:             domain.CAProviderType(nodeCfg.CAProvider),
+			CAProviderAccessConfig: caAccessConfig,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		preflightReq.CAProvider = acmeCfg.CAProvider
+		preflightReq.CADirUrl = acmeCfg.CADirUrl
+	}
+
+	report, err := certacme.Preflight(ctx, preflightReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to revoke certificate: could not initialize acme config: %w", err)
+		return nil, err
+	}
+
+	return &dtos.CertificatePreflightResp{CertificatePreflightReport: report}, nil
+}
+
+func (s *CertificateService) revokeCertificate(ctx context.Context, certificate *domain.Certificate, reason domain.CertificateRevocationReasonType, useCertificateKey bool) error {
+	if certificate.IsRevoked {
+		return fmt.Errorf("could not revoke a certificate which is already revoked")
+	}
+
+	reasonCode, ok := reason.Code()
+	if !ok {
+		return fmt.Errorf("unsupported revocation reason '%s'", reason)
+	}
+
+	// 内置私有 CA 签发的证书无需向 ACME 服务器发起吊销请求，仅记录吊销状态
+	if certificate.CA != domain.CAProviderTypePrivateCA.String() {
+		if err := s.revokeCertificateWithACME(ctx, certificate, reasonCode, useCertificateKey); err != nil {
+			return err
+		}
+	}
+
+	revokedAt := time.Now()
+	certificate.IsRevoked = true
+	certificate.RevokedReason = reason
+	certificate.RevokedAt = &revokedAt
+	if _, err := s.certificateRepo.Save(ctx, certificate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *CertificateService) revokeCertificateWithACME(ctx context.Context, certificate *domain.Certificate, reasonCode *uint, useCertificateKey bool) error {
+	var acmeClient *certacme.ACMEClient
+	var acmeAccount *domain.ACMEAccount
+	if certificate.ACMEAccountUrl != "" {
+		// 仅当账户记录不存在时才回退到使用证书自身的私钥，其他错误应如实返回
+		account, err := s.acmeAccountRepo.GetByCAAndAcctUrl(ctx, certificate.CA, certificate.ACMEAccountUrl)
+		if err != nil && !errors.Is(err, domain.ErrRecordNotFound) {
+			return fmt.Errorf("could not get acme account: %w", err)
+		} else if err == nil {
+			acmeAccount = account
+		}
+	}
+
+	if !useCertificateKey && acmeAccount != nil && !acmeAccount.IsDeactivated() {
+		if certificate.ACMECertificateUrl == "" {
+			return fmt.Errorf("could not revoke a certificate which is not issued in Certimate")
+		}
+
+		client, err := certacme.NewACMEClientWithAccount(acmeAccount)
+		if err != nil {
+			return fmt.Errorf("could not initialize acme config: %w", err)
+		}
+
+		acmeClient = client
+	} else {
+		// 签发证书的 ACME 账户已不可用，使用证书自身的私钥进行吊销
+		if certificate.PrivateKey == "" {
+			return fmt.Errorf("could not revoke a certificate without the issuing acme account or its private key")
+		}
+
+		var acmeDirUrl string
+		if acmeAccount != nil {
+			acmeDirUrl = acmeAccount.ACMEDirectoryUrl
+		} else if certificate.CA != "" {
+			acmeConfig, err := certacme.CreateACMEConfig(ctx, &certacme.ACMEConfigOptions{
+				CAProvider:            domain.CAProviderType(certificate.CA),
+				CertifierKeyAlgorithm: certificate.KeyAlgorithm,
+			})
+			if err != nil {
+				return fmt.Errorf("could not initialize acme config: %w", err)
+			}
+
+			acmeDirUrl = acmeConfig.CADirUrl
+		} else {
+			return fmt.Errorf("could not revoke a certificate which is not issued by an acme ca")
+		}
+
+		client, err := certacme.NewACMEClientWithCertificateKey(acmeDirUrl, certificate.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("could not initialize acme config: %w", err)
+		}
+
+		acmeClient = client
 	}
 
 	revokeReq := &certacme.RevokeCertificateRequest{
 		Certificate: certificate.Certificate,
+		Reason:      reasonCode,
 	}
-	_, err = acmeClient.RevokeCertificate(ctx, revokeReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to revoke certificate: %w", err)
+	if _, err := acmeClient.RevokeCertificate(ctx, revokeReq); err != nil {
+		return err
 	}
 
-	certificate.IsRevoked = true
-	certificate, err = s.certificateRepo.Save(ctx, certificate)
+	return nil
+}
+
+func (s *CertificateService) pollRenewalInfo(ctx context.Context) error {
+	certificates, err := s.certificateRepo.ListActive(ctx)
 	if err != nil {
-		return nil, err
+		app.GetLogger().Error("failed to list active certificates", slog.Any("error", err))
+		return err
 	}
 
-	return &dtos.CertificateRevokeResp{}, nil
+	now := time.Now()
+	acmeClients := make(map[string]*certacme.ACMEClient)
+	polledNodes := make(map[string]struct{})
+	for _, certificate := range certificates {
+		if certificate.WorkflowId == "" || certificate.WorkflowNodeId == "" || certificate.ACMEAccountUrl == "" {
+			continue
+		}
+
+		// 同一工作流节点仅需关注最新签发的证书
+		nodeKey := certificate.WorkflowId + "/" + certificate.WorkflowNodeId
+		if _, ok := polledNodes[nodeKey]; ok {
+			continue
+		} else {
+			polledNodes[nodeKey] = struct{}{}
+		}
+
+		if certificate.ARIRenewalInfo != nil && now.Before(certificate.ARIRenewalInfo.NextCheckAt) {
+			continue
+		}
+
+		workflow, err := s.workflowRepo.GetById(ctx, certificate.WorkflowId)
+		if err != nil || workflow.GraphContent == nil {
+			continue
+		} else if node, ok := workflow.GraphContent.GetNodeById(certificate.WorkflowNodeId); !ok || node.Type != domain.WorkflowNodeTypeBizApply {
+			continue
+		} else if node.Data.Config.AsBizApply().DisableARI {
+			continue
+		}
+
+		acmeClientKey := certificate.CA + "|" + certificate.ACMEAccountUrl
+		acmeClient, ok := acmeClients[acmeClientKey]
+		if !ok {
+			acmeAccount, err := s.acmeAccountRepo.GetByCAAndAcctUrl(ctx, certificate.CA, certificate.ACMEAccountUrl)
+			if err != nil {
+				continue
+			}
+
+			acmeClient, err = certacme.NewACMEClientWithAccount(acmeAccount)
+			if err != nil {
+				app.GetLogger().Warn(fmt.Sprintf("failed to initialize acme client for certificate #%s", certificate.Id), slog.Any("error", err))
+				continue
+			}
+
+			acmeClients[acmeClientKey] = acmeClient
+		}
+
+		renewalInfoResp, err := acmeClient.GetRenewalInfo(ctx, &certacme.GetRenewalInfoRequest{Certificate: certificate.Certificate})
+		if err != nil {
+			if !errors.Is(err, acmeapi.ErrNoARI) {
+				app.GetLogger().Warn(fmt.Sprintf("failed to get renewal info of certificate #%s", certificate.Id), slog.Any("error", err))
+			}
+			continue
+		}
+
+		retryAfter := renewalInfoResp.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = time.Hour * 6
+		}
+
+		renewalInfo := &domain.CertificateARIRenewalInfo{
+			WindowStart:    renewalInfoResp.SuggestedWindowStart,
+			WindowEnd:      renewalInfoResp.SuggestedWindowEnd,
+			ExplanationUrl: renewalInfoResp.ExplanationUrl,
+			CheckedAt:      now,
+			NextCheckAt:    now.Add(retryAfter),
+		}
+		if certificate.ARIRenewalInfo != nil {
+			renewalInfo.TriggeredWindowStart = certificate.ARIRenewalInfo.TriggeredWindowStart
+		}
+		certificate.ARIRenewalInfo = renewalInfo
+		if _, err := s.certificateRepo.Save(ctx, certificate); err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to save renewal info of certificate #%s", certificate.Id), slog.Any("error", err))
+			continue
+		}
+
+		// 每个续期窗口仅触发一次运行；工作流已停用或正在运行时暂不触发，待下次轮询时再检查
+		if !renewalInfo.ShouldTriggerRenewal(now) {
+			continue
+		}
+		if !workflow.Enabled {
+			continue
+		}
+		if workflow.LastRunStatus == domain.WorkflowRunStatusTypePending ||
+			workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing ||
+			workflow.LastRunStatus == domain.WorkflowRunStatusTypeWaiting {
+			continue
+		}
+
+		app.GetLogger().Info(fmt.Sprintf("the renewal window of certificate #%s has been reached, trigger workflow #%s", certificate.Id, workflow.Id),
+			slog.Time("windowStart", certificate.ARIRenewalInfo.WindowStart),
+			slog.Time("windowEnd", certificate.ARIRenewalInfo.WindowEnd),
+			slog.String("explanationUrl", certificate.ARIRenewalInfo.ExplanationUrl),
+		)
+		if _, err := s.workflowSvc.StartRun(ctx, &dtos.WorkflowStartRunReq{
+			WorkflowId: workflow.Id,
+			RunTrigger: domain.WorkflowTriggerTypeScheduled,
+		}); err != nil {
+			app.GetLogger().Error(fmt.Sprintf("failed to trigger workflow #%s", workflow.Id), slog.Any("error", err))
+			continue
+		}
+
+		renewalInfo.TriggeredWindowStart = renewalInfo.WindowStart
+		if _, err := s.certificateRepo.Save(ctx, certificate); err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to save renewal info of certificate #%s", certificate.Id), slog.Any("error", err))
+		}
+	}
+
+	return nil
 }
 
 func (s *CertificateService) cleanupExpiredCertificates(ctx context.Context) error {
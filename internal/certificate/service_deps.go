@@ -6,14 +6,32 @@ import (
 	"github.com/pocketbase/dbx"
 
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
 )
 
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+}
+
 type acmeAccountRepository interface {
 	GetByCAAndAcctUrl(ctx context.Context, ca string, acctUrl string) (*domain.ACMEAccount, error)
 }
 
 type certificateRepository interface {
+	ListActive(ctx context.Context) ([]*domain.Certificate, error)
 	GetById(ctx context.Context, id string) (*domain.Certificate, error)
 	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
 	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
+
+type workflowRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Workflow, error)
+}
+
+type privateCAService interface {
+	GetCAChain(ctx context.Context, certPEM string) (string, error)
+}
+
+type workflowService interface {
+	StartRun(ctx context.Context, req *dtos.WorkflowStartRunReq) (*dtos.WorkflowStartRunResp, error)
+}
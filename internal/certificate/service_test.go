@@ -0,0 +1,36 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockACMEAccountRepository struct {
+	err error
+}
+
+func (r *mockACMEAccountRepository) GetByCAAndAcctUrl(ctx context.Context, ca string, acctUrl string) (*domain.ACMEAccount, error) {
+	return nil, r.err
+}
+
+func TestRevokeCertificateWithACME_AccountLookup(t *testing.T) {
+	certificate := &domain.Certificate{
+		CA:             domain.CAProviderTypeLetsEncrypt.String(),
+		ACMEAccountUrl: "https://acme.example.com/acct/1",
+	}
+
+	// 查询账户出错时如实返回，而非回退到使用证书私钥吊销
+	svc := &CertificateService{acmeAccountRepo: &mockACMEAccountRepository{err: errors.New("database is locked")}}
+	err := svc.revokeCertificateWithACME(context.Background(), certificate, nil, false)
+	assert.ErrorContains(t, err, "database is locked")
+
+	// 账户不存在时回退到使用证书私钥吊销
+	svc = &CertificateService{acmeAccountRepo: &mockACMEAccountRepository{err: domain.ErrRecordNotFound}}
+	err = svc.revokeCertificateWithACME(context.Background(), certificate, nil, false)
+	assert.ErrorContains(t, err, "without the issuing acme account or its private key")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/certimate-go/certimate/internal/certmgmt/deployers"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/core"
 )
 
 type DeployCertificateRequest struct {
@@ -26,6 +27,11 @@ func (c *Client) DeployCertificate(ctx context.Context, request *DeployCertifica
 		return nil, fmt.Errorf("the request is nil")
 	}
 
+	// 由 CSR 签发的证书不持有私钥，而部署提供商均需要私钥
+	if request.PrivateKeyPEM == "" {
+		return nil, fmt.Errorf("could not deploy a certificate without private key (it may be issued from a csr), which is required by deployment provider '%s'", request.Provider)
+	}
+
 	providerFactory, err := deployers.Registries.Get(request.Provider)
 	if err != nil {
 		return nil, err
@@ -46,3 +52,47 @@ func (c *Client) DeployCertificate(ctx context.Context, request *DeployCertifica
 
 	return &DeployCertificateResponse{}, nil
 }
+
+type ValidateDeploymentRequest struct {
+	// 提供商相关
+	Provider               domain.DeploymentProviderType
+	ProviderAccessConfig   map[string]any
+	ProviderExtendedConfig map[string]any
+}
+
+type ValidateDeploymentResponse struct {
+	// 部署提供商是否支持校验。
+	// 不支持时仅校验了提供商配置项能否正常初始化。
+	Supported bool
+}
+
+func (c *Client) ValidateDeployment(ctx context.Context, request *ValidateDeploymentRequest) (*ValidateDeploymentResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	providerFactory, err := deployers.Registries.Get(request.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := providerFactory(&deployers.ProviderFactoryOptions{
+		ProviderAccessConfig:   request.ProviderAccessConfig,
+		ProviderExtendedConfig: request.ProviderExtendedConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize deployment provider '%s': %w", request.Provider, err)
+	}
+
+	validator, ok := provider.(core.DeployerValidator)
+	if !ok {
+		return &ValidateDeploymentResponse{Supported: false}, nil
+	}
+
+	provider.SetLogger(c.logger)
+	if err := validator.Validate(ctx); err != nil {
+		return nil, err
+	}
+
+	return &ValidateDeploymentResponse{Supported: true}, nil
+}
@@ -12,12 +12,13 @@ const CollectionNameACMEAccount = "acme_accounts"
 
 type ACMEAccount struct {
 	Meta
-	CA               string        `db:"ca"          json:"ca"`
-	Email            string        `db:"email"       json:"email"`
-	PrivateKey       string        `db:"privateKey"  json:"privateKey"`
-	ACMEDirectoryUrl string        `db:"acmeDirUrl"  json:"acmeDirUrl"`
-	ACMEAccountUrl   string        `db:"acmeAcctUrl" json:"acmeAcctUrl"`
-	ResourceObject   *acme.Account `db:"resourceObj" json:"resourceObj"`
+	CA                string        `db:"ca"          json:"ca"`
+	Email             string        `db:"email"       json:"email"`
+	PrivateKey        string        `db:"privateKey"  json:"privateKey"`
+	PendingPrivateKey string        `db:"pendingPrivateKey" json:"pendingPrivateKey"` // 轮换中的新私钥，轮换完成后将被清空
+	ACMEDirectoryUrl  string        `db:"acmeDirUrl"  json:"acmeDirUrl"`
+	ACMEAccountUrl    string        `db:"acmeAcctUrl" json:"acmeAcctUrl"`
+	ResourceObject    *acme.Account `db:"resourceObj" json:"resourceObj"`
 }
 
 func (a *ACMEAccount) GetEmail() string {
@@ -35,6 +36,15 @@ func (a *ACMEAccount) GetRegistration() *acme.ExtendedAccount {
 	}
 }
 
+// 判断账户是否已失效（包括客户端主动停用、服务端吊销）。
+func (a *ACMEAccount) IsDeactivated() bool {
+	if a.ResourceObject == nil {
+		return false
+	}
+
+	return a.ResourceObject.Status == acme.StatusDeactivated || a.ResourceObject.Status == acme.StatusRevoked
+}
+
 func (a *ACMEAccount) GetPrivateKey() crypto.Signer {
 	if a.PrivateKey == "" {
 		return nil
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	CollectionNameACMEServerClient = "acme_server_clients"
+	CollectionNameACMEServerOrder  = "acme_server_orders"
+)
+
+// 通过内置 ACME 服务端接入的客户端（如 certbot、Caddy、Traefik 等）。
+// 每个客户端持有一组独立的外部账户绑定（EAB）凭据，首次注册时将与其 ACME 账户密钥绑定。
+type ACMEServerClient struct {
+	Meta
+	Name                 string                      `db:"name"                 json:"name"`
+	EABKid               string                      `db:"eabKid"               json:"eabKid"`
+	EABHmacKey           string                      `db:"eabHmacKey"           json:"-"` // Base64URL 编码
+	Config               *ACMEServerClientConfig     `db:"config"               json:"config"`
+	AccountKey           string                      `db:"accountKey"           json:"accountKey"` // JWK 格式的账户公钥
+	AccountKeyThumbprint string                      `db:"accountKeyThumbprint" json:"accountKeyThumbprint"`
+	AccountStatus        ACMEServerAccountStatusType `db:"accountStatus"        json:"accountStatus"`
+	AccountContact       []string                    `db:"accountContact"       json:"accountContact"`
+	Enabled              bool                        `db:"enabled"              json:"enabled"`
+}
+
+type ACMEServerClientConfig struct {
+	// 签发方式。
+	Issuer ACMEServerIssuerType `json:"issuer"`
+	// 允许申请的域名或 IP 地址。
+	// 将匹配该域名本身及其所有子域名；"*" 表示不做限制。
+	AllowedIdentifiers []string `json:"allowedIdentifiers"`
+	// 证书有效期，如 "90d"。
+	ValidityLifetime string `json:"validityLifetime,omitempty"`
+
+	// 内置私有 CA 相关
+	PrivateCAId string `json:"privateCAId,omitempty"`
+
+	// 上游 CA 相关
+	ContactEmail          string         `json:"contactEmail,omitempty"`
+	CAProvider            string         `json:"caProvider,omitempty"`
+	CAProviderAccessId    string         `json:"caProviderAccessId,omitempty"`
+	CAProviderConfig      map[string]any `json:"caProviderConfig,omitempty"`
+	ChallengeType         string         `json:"challengeType,omitempty"`
+	Provider              string         `json:"provider,omitempty"`
+	ProviderAccessId      string         `json:"providerAccessId,omitempty"`
+	ProviderConfig        map[string]any `json:"providerConfig,omitempty"`
+	KeyAlgorithm          string         `json:"keyAlgorithm,omitempty"`
+	Nameservers           []string       `json:"nameservers,omitempty"`
+	DnsPropagationWait    int            `json:"dnsPropagationWait,omitempty"`
+	DnsPropagationTimeout int            `json:"dnsPropagationTimeout,omitempty"`
+	DnsTTL                int            `json:"dnsTTL,omitempty"`
+	HttpDelayWait         int            `json:"httpDelayWait,omitempty"`
+	DisableFollowCNAME    bool           `json:"disableFollowCNAME,omitempty"`
+}
+
+// 判断客户端是否允许申请指定的标识符（域名或 IP 地址）。
+func (c *ACMEServerClientConfig) IsIdentifierAllowed(identifier string) bool {
+	if c == nil {
+		return false
+	}
+
+	identifier = strings.TrimPrefix(strings.ToLower(identifier), "*.")
+	for _, allowed := range c.AllowedIdentifiers {
+		allowed = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(allowed)), "*.")
+		if allowed == "" {
+			continue
+		}
+
+		if allowed == "*" || identifier == allowed || strings.HasSuffix(identifier, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ACMEServerIssuerType string
+
+func (t ACMEServerIssuerType) String() string {
+	return string(t)
+}
+
+const (
+	ACMEServerIssuerTypePrivateCA = ACMEServerIssuerType("privateca")
+	ACMEServerIssuerTypeUpstream  = ACMEServerIssuerType("upstream")
+)
+
+// 通过内置 ACME 服务端创建的订单。
+// 授权从属于订单，一并保存，以便服务重启后客户端仍可继续轮询。
+type ACMEServerOrder struct {
+	Meta
+	ClientId       string                     `db:"clientRef"      json:"clientId"`
+	Status         string                     `db:"status"         json:"status"` // 遵循 RFC 8555 §7.1.6 中定义的状态
+	ExpiresAt      time.Time                  `db:"expiresAt"      json:"expiresAt"`
+	Identifiers    []ACMEServerIdentifier     `db:"identifiers"    json:"identifiers"`
+	Authorizations []*ACMEServerAuthorization `db:"authorizations" json:"authorizations"`
+	CertificateId  string                     `db:"certificateRef" json:"certificateId"`
+	Error          string                     `db:"error"          json:"error"`
+}
+
+type ACMEServerIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ACMEServerAuthorization struct {
+	Status     string               `json:"status"`
+	Identifier ACMEServerIdentifier `json:"identifier"`
+	Wildcard   bool                 `json:"wildcard"`
+}
+
+type ACMEServerAccountStatusType string
+
+func (t ACMEServerAccountStatusType) String() string {
+	return string(t)
+}
+
+const (
+	ACMEServerAccountStatusTypeDeactivated = ACMEServerAccountStatusType("deactivated")
+	ACMEServerAccountStatusTypeValid       = ACMEServerAccountStatusType("valid")
+)
@@ -36,9 +36,13 @@ type Certificate struct {
 	ACMECertificateUrl string                          `db:"acmeCertUrl"       json:"acmeCertUrl"`
 	IsRenewed          bool                            `db:"isRenewed"         json:"isRenewed"`
 	IsRevoked          bool                            `db:"isRevoked"         json:"isRevoked"`
+	RevokedReason      CertificateRevocationReasonType `db:"revokedReason"     json:"revokedReason"`
+	RevokedAt          *time.Time                      `db:"revokedAt"         json:"revokedAt"`
+	ARIRenewalInfo     *CertificateARIRenewalInfo      `db:"ariRenewalInfo"    json:"ariRenewalInfo"`
 	WorkflowId         string                          `db:"workflowRef"       json:"workflowId"`
 	WorkflowRunId      string                          `db:"workflowRunRef"    json:"workflowRunId"`
 	WorkflowNodeId     string                          `db:"workflowNodeId"    json:"workflowNodeId"`
+	ACMEServerClientId string                          `db:"acmeServerClientRef" json:"acmeServerClientId"`
 	DeletedAt          *time.Time                      `db:"deleted" json:"deleted"`
 }
 
@@ -96,6 +100,36 @@ func (c *Certificate) PopulateFromPEM(certPEM, privkeyPEM string) *Certificate {
 	return c
 }
 
+// 由 CA 通过 ARI（RFC 9773）建议的续期窗口。
+type CertificateARIRenewalInfo struct {
+	WindowStart    time.Time `json:"windowStart"`
+	WindowEnd      time.Time `json:"windowEnd"`
+	ExplanationUrl string    `json:"explanationUrl,omitempty"`
+	CheckedAt      time.Time `json:"checkedAt"`
+	NextCheckAt    time.Time `json:"nextCheckAt"`
+
+	TriggeredWindowStart time.Time `json:"triggeredWindowStart,omitzero"` // 已触发续期运行的窗口起始时间，用于确保每个续期窗口仅触发一次
+}
+
+// 判断指定时间是否已到达建议的续期窗口。
+func (i *CertificateARIRenewalInfo) IsWindowReached(t time.Time) bool {
+	if i == nil || i.WindowStart.IsZero() {
+		return false
+	}
+
+	return !t.Before(i.WindowStart)
+}
+
+// 判断是否应在指定时间触发续期运行。
+// 每个续期窗口仅触发一次；若 CA 调整了续期窗口（如因吊销而提前），则视为新的窗口。
+func (i *CertificateARIRenewalInfo) ShouldTriggerRenewal(t time.Time) bool {
+	if !i.IsWindowReached(t) {
+		return false
+	}
+
+	return !i.TriggeredWindowStart.Equal(i.WindowStart)
+}
+
 type CertificateSourceType string
 
 func (t CertificateSourceType) String() string {
@@ -103,8 +137,9 @@ func (t CertificateSourceType) String() string {
 }
 
 const (
-	CertificateSourceTypeRequest = CertificateSourceType("request")
-	CertificateSourceTypeUpload  = CertificateSourceType("upload")
+	CertificateSourceTypeACMEServer = CertificateSourceType("acmeserver")
+	CertificateSourceTypeRequest    = CertificateSourceType("request")
+	CertificateSourceTypeUpload     = CertificateSourceType("upload")
 )
 
 type CertificateKeyAlgorithmType certcrypto.KeyType
@@ -139,6 +174,51 @@ const (
 	CertificateValidationPolicyTypeIV = CertificateValidationPolicyType("IV")
 )
 
+type CertificateRevocationReasonType string
+
+func (t CertificateRevocationReasonType) String() string {
+	return string(t)
+}
+
+// 获取 RFC 5280 中定义的吊销原因代码。
+// 如果原因为空，返回 nil；如果原因无效，返回 false。
+func (t CertificateRevocationReasonType) Code() (*uint, bool) {
+	if t == "" {
+		return nil, true
+	}
+
+	code, ok := certificateRevocationReasonCodes[t]
+	if !ok {
+		return nil, false
+	}
+
+	return &code, true
+}
+
+const (
+	CertificateRevocationReasonTypeUnspecified          = CertificateRevocationReasonType("unspecified")
+	CertificateRevocationReasonTypeKeyCompromise        = CertificateRevocationReasonType("keyCompromise")
+	CertificateRevocationReasonTypeCACompromise         = CertificateRevocationReasonType("cACompromise")
+	CertificateRevocationReasonTypeAffiliationChanged   = CertificateRevocationReasonType("affiliationChanged")
+	CertificateRevocationReasonTypeSuperseded           = CertificateRevocationReasonType("superseded")
+	CertificateRevocationReasonTypeCessationOfOperation = CertificateRevocationReasonType("cessationOfOperation")
+	CertificateRevocationReasonTypeCertificateHold      = CertificateRevocationReasonType("certificateHold")
+	CertificateRevocationReasonTypePrivilegeWithdrawn   = CertificateRevocationReasonType("privilegeWithdrawn")
+	CertificateRevocationReasonTypeAACompromise         = CertificateRevocationReasonType("aACompromise")
+)
+
+var certificateRevocationReasonCodes = map[CertificateRevocationReasonType]uint{
+	CertificateRevocationReasonTypeUnspecified:          0,
+	CertificateRevocationReasonTypeKeyCompromise:        1,
+	CertificateRevocationReasonTypeCACompromise:         2,
+	CertificateRevocationReasonTypeAffiliationChanged:   3,
+	CertificateRevocationReasonTypeSuperseded:           4,
+	CertificateRevocationReasonTypeCessationOfOperation: 5,
+	CertificateRevocationReasonTypeCertificateHold:      6,
+	CertificateRevocationReasonTypePrivilegeWithdrawn:   9,
+	CertificateRevocationReasonTypeAACompromise:         10,
+}
+
 type CertificateFormatType string
 
 func (t CertificateFormatType) String() string {
@@ -0,0 +1,44 @@
+package domain
+
+// 申请证书前的预检报告。
+type CertificatePreflightReport struct {
+	Passed  bool                                `json:"passed"`
+	Domains []*CertificatePreflightDomainReport `json:"domains"`
+}
+
+type CertificatePreflightDomainReport struct {
+	Domain string                       `json:"domain"`
+	Passed bool                         `json:"passed"`
+	Checks []*CertificatePreflightCheck `json:"checks"`
+}
+
+type CertificatePreflightCheck struct {
+	Name    CertificatePreflightCheckType  `json:"name"`
+	Status  CertificatePreflightStatusType `json:"status"`
+	Message string                         `json:"message,omitempty"`
+}
+
+type CertificatePreflightCheckType string
+
+func (t CertificatePreflightCheckType) String() string {
+	return string(t)
+}
+
+const (
+	CertificatePreflightCheckTypeCAA   = CertificatePreflightCheckType("caa")
+	CertificatePreflightCheckTypeCNAME = CertificatePreflightCheckType("cname")
+)
+
+type CertificatePreflightStatusType string
+
+func (t CertificatePreflightStatusType) String() string {
+	return string(t)
+}
+
+const (
+	CertificatePreflightStatusTypeFailed  = CertificatePreflightStatusType("failed")
+	CertificatePreflightStatusTypePassed  = CertificatePreflightStatusType("passed")
+	CertificatePreflightStatusTypeSkipped = CertificatePreflightStatusType("skipped")
+	CertificatePreflightStatusTypeWarning = CertificatePreflightStatusType("warning")
+	CertificatePreflightStatusTypeUnknown = CertificatePreflightStatusType("unknown") // 因查询出错等原因无法判定，不阻止申请
+)
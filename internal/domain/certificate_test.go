@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestCertificateARIRenewalInfo_ShouldTriggerRenewal(t *testing.T) {
+	windowStart := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		info     *domain.CertificateARIRenewalInfo
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "nil info",
+			info:     nil,
+			now:      windowStart,
+			expected: false,
+		},
+		{
+			name:     "before window",
+			info:     &domain.CertificateARIRenewalInfo{WindowStart: windowStart},
+			now:      windowStart.Add(-time.Hour),
+			expected: false,
+		},
+		{
+			name:     "window reached",
+			info:     &domain.CertificateARIRenewalInfo{WindowStart: windowStart},
+			now:      windowStart.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "window already triggered",
+			info:     &domain.CertificateARIRenewalInfo{WindowStart: windowStart, TriggeredWindowStart: windowStart},
+			now:      windowStart.Add(24 * time.Hour),
+			expected: false,
+		},
+		{
+			name:     "window moved earlier by ca",
+			info:     &domain.CertificateARIRenewalInfo{WindowStart: windowStart.Add(-48 * time.Hour), TriggeredWindowStart: windowStart},
+			now:      windowStart.Add(time.Hour),
+			expected: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.info.ShouldTriggerRenewal(tc.now))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package dtos
+
+type ACMEAccountRolloverKeyReq struct {
+	AccountId string `json:"-"`
+}
+
+type ACMEAccountRolloverKeyResp struct{}
+
+type ACMEAccountUpdateContactsReq struct {
+	AccountId string   `json:"-"`
+	Emails    []string `json:"emails"`
+}
+
+type ACMEAccountUpdateContactsResp struct{}
+
+type ACMEAccountDeactivateReq struct {
+	AccountId string `json:"-"`
+}
+
+type ACMEAccountDeactivateResp struct{}
+
+type ACMEAccountRefreshReq struct {
+	AccountId string `json:"-"`
+}
+
+type ACMEAccountRefreshResp struct {
+	Status  string   `json:"status"`
+	Contact []string `json:"contact"`
+}
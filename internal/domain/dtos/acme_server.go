@@ -0,0 +1,25 @@
+package dtos
+
+import (
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type ACMEServerCreateClientReq struct {
+	Name   string                         `json:"name"`
+	Config *domain.ACMEServerClientConfig `json:"config"`
+}
+
+type ACMEServerCreateClientResp struct {
+	ClientId   string `json:"clientId"`
+	EABKid     string `json:"eabKid"`
+	EABHmacKey string `json:"eabHmacKey"`
+}
+
+type ACMEServerResetClientEABReq struct {
+	ClientId string `json:"-"`
+}
+
+type ACMEServerResetClientEABResp struct {
+	EABKid     string `json:"eabKid"`
+	EABHmacKey string `json:"eabHmacKey"`
+}
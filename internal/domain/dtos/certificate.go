@@ -19,7 +19,41 @@ type CertificateDownloadResp struct {
 }
 
 type CertificateRevokeReq struct {
-	CertificateId string `json:"-"`
+	CertificateId     string                                 `json:"-"`
+	Reason            domain.CertificateRevocationReasonType `json:"reason,omitempty"`
+	UseCertificateKey bool                                   `json:"useCertificateKey,omitempty"`
 }
 
 type CertificateRevokeResp struct{}
+
+type CertificateBulkRevokeReq struct {
+	CompromisedCertificateId string                                 `json:"compromisedCertificateId,omitempty"`
+	CompromisedPrivateKey    string                                 `json:"compromisedPrivateKey,omitempty"`
+	SubjectAltName           string                                 `json:"subjectAltName,omitempty"`
+	Reason                   domain.CertificateRevocationReasonType `json:"reason,omitempty"`
+	UseCertificateKey        bool                                   `json:"useCertificateKey,omitempty"`
+}
+
+type CertificateBulkRevokeResp struct {
+	RevokedIds []string          `json:"revokedIds"`
+	Failures   map[string]string `json:"failures"`
+}
+
+type CertificatePreflightReq struct {
+	// 如果指定了工作流节点，则使用该申请节点的配置进行预检，忽略其他参数
+	WorkflowId string `json:"workflowId,omitempty"`
+	NodeId     string `json:"nodeId,omitempty"`
+
+	Domains            []string                                              `json:"domains,omitempty"`
+	ChallengeType      string                                                `json:"challengeType,omitempty"`
+	Provider           string                                                `json:"provider,omitempty"`
+	ProviderMappings   []domain.WorkflowNodeConfigForBizApplyProviderMapping `json:"providerMappings,omitempty"`
+	CAProvider         string                                                `json:"caProvider,omitempty"`
+	CAProviderAccessId string                                                `json:"caProviderAccessId,omitempty"`
+	DisableFollowCNAME bool                                                  `json:"disableFollowCNAME,omitempty"`
+	Nameservers        []string                                              `json:"nameservers,omitempty"`
+}
+
+type CertificatePreflightResp struct {
+	*domain.CertificatePreflightReport
+}
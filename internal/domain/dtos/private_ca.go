@@ -0,0 +1,53 @@
+package dtos
+
+import "github.com/certimate-go/certimate/internal/domain"
+
+type PrivateCAListReq struct{}
+
+// 私钥字段不会被序列化，因此可以直接返回领域模型。
+type PrivateCAListResp struct {
+	Items []*domain.PrivateCA `json:"items"`
+}
+
+type PrivateCAGetReq struct {
+	PrivateCAId string `bind:"path" json:"-"`
+}
+
+type PrivateCAGetResp struct {
+	*domain.PrivateCA
+}
+
+type PrivateCADeleteReq struct {
+	PrivateCAId string `bind:"path" json:"-"`
+}
+
+type PrivateCADeleteResp struct{}
+
+type PrivateCAGenerateReq struct {
+	Name                     string   `json:"name"`
+	CommonName               string   `json:"commonName"`
+	Organization             string   `json:"organization"`
+	KeyAlgorithm             string   `json:"keyAlgorithm"`
+	RootValidityDays         int      `json:"rootValidityDays"`
+	IntermediateValidityDays int      `json:"intermediateValidityDays"`
+	PermittedDNSDomains      []string `json:"permittedDNSDomains"`
+	ExcludedDNSDomains       []string `json:"excludedDNSDomains"`
+	PermittedIPRanges        []string `json:"permittedIPRanges"`
+	ExcludedIPRanges         []string `json:"excludedIPRanges"`
+}
+
+type PrivateCAGenerateResp struct {
+	Id string `json:"id"`
+}
+
+type PrivateCAImportReq struct {
+	Name                    string `json:"name"`
+	RootCertificate         string `json:"rootCertificate"`
+	RootPrivateKey          string `json:"rootPrivateKey"`
+	IntermediateCertificate string `json:"intermediateCertificate"`
+	IntermediatePrivateKey  string `json:"intermediatePrivateKey"`
+}
+
+type PrivateCAImportResp struct {
+	Id string `json:"id"`
+}
@@ -5,14 +5,41 @@ import (
 )
 
 type WorkflowStartRunReq struct {
-	WorkflowId string                     `json:"-"`
-	RunTrigger domain.WorkflowTriggerType `json:"trigger"`
+	WorkflowId   string                        `json:"-"`
+	RunTrigger   domain.WorkflowTriggerType    `json:"trigger"`
+	RunVariables []*domain.WorkflowRunVariable `json:"-"`
+	Variables    map[string]string             `json:"variables,omitempty"` // 覆盖开始节点中自定义变量的默认值，仅适用于手动触发
 }
 
 type WorkflowStartRunResp struct {
 	RunId string `json:"runId"`
 }
 
+type WorkflowTriggerWebhookReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	Token      string `bind:"path" json:"-"`
+	Timestamp  string `json:"-"`
+	Signature  string `json:"-"`
+	Payload    []byte `json:"-"`
+}
+
+type WorkflowTriggerWebhookResp struct {
+	RunId string `json:"runId"`
+}
+
+type WorkflowReviewApprovalReq struct {
+	RunId     string `bind:"path" json:"-"`
+	NodeId    string `bind:"path" json:"-"`
+	Action    string `json:"-"`
+	Expires   string `json:"-"`
+	Signature string `json:"-"`
+	Operator  string `json:"-"`
+	RemoteIP  string `json:"-"`
+	Comment   string `json:"-"`
+}
+
+type WorkflowReviewApprovalResp struct{}
+
 type WorkflowCancelRunReq struct {
 	WorkflowId string `bind:"path" json:"-"`
 	RunId      string `bind:"path" json:"-"`
@@ -20,8 +47,94 @@ type WorkflowCancelRunReq struct {
 
 type WorkflowCancelRunResp struct{}
 
+type WorkflowResumeRunReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	RunId      string `bind:"path" json:"-"`
+	NodeId     string `json:"nodeId,omitempty"`
+	Rejected   bool   `json:"rejected,omitempty"`
+	Operator   string `json:"-"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+type WorkflowResumeRunResp struct{}
+
+type WorkflowRetryRunReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	RunId      string `bind:"path" json:"-"`
+}
+
+type WorkflowRetryRunResp struct {
+	RunId string `json:"runId"` // 从失败节点恢复执行的新运行的 ID
+}
+
+type WorkflowDiffVersionsReq struct {
+	WorkflowId    string `bind:"path"  json:"-"`
+	FromVersionId string `bind:"query" json:"-"` // 为空时表示 ToVersionId 的上一版本
+	ToVersionId   string `bind:"query" json:"-"` // 为空时表示最新版本
+}
+
+type WorkflowDiffVersionsResp struct {
+	FromVersion int `json:"fromVersion"`
+	ToVersion   int `json:"toVersion"`
+	*domain.WorkflowGraphDiff
+}
+
+type WorkflowRollbackVersionReq struct {
+	WorkflowId string `bind:"path" json:"-"`
+	VersionId  string `bind:"path" json:"-"`
+	Operator   string `json:"-"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+type WorkflowRollbackVersionResp struct {
+	VersionId string `json:"versionId"`
+	Version   int    `json:"version"`
+}
+
+type WorkflowExportBundleReq struct {
+	WorkflowIds []string `json:"workflowIds,omitempty"` // 为空时导出全部已发布的工作流
+	Format      string   `json:"format,omitempty"`      // 文件格式，可选值 "json"、"yaml"，默认值 "json"
+}
+
+type WorkflowExportBundleResp struct {
+	Data        []byte `json:"-"` // 按指定格式序列化后的工作流包
+	ContentType string `json:"-"`
+	FileName    string `json:"-"`
+}
+
+type WorkflowImportBundleReq struct {
+	Data     []byte `json:"-"` // JSON 或 YAML 格式的工作流包
+	DryRun   bool   `json:"-"` // 仅检查而不写入
+	Operator string `json:"-"`
+}
+
+type WorkflowImportBundleResp struct {
+	Workflows         []*WorkflowImportBundleResult           `json:"workflows"`
+	MissingAccesses   []*WorkflowImportBundleMissingAccess    `json:"missingAccesses"`
+	MissingPrivateCAs []*WorkflowImportBundleMissingPrivateCA `json:"missingPrivateCAs"`
+}
+
+type WorkflowImportBundleResult struct {
+	Name             string `json:"name"`
+	WorkflowId       string `json:"workflowId,omitempty"`
+	Action           string `json:"action"`                     // 可取值 "created"、"updated"、"unchanged"、"skipped"
+	DraftOverwritten bool   `json:"draftOverwritten,omitempty"` // 已存在的工作流有尚未发布的草稿，导入时将被覆盖
+	Error            string `json:"error,omitempty"`
+}
+
+type WorkflowImportBundleMissingAccess struct {
+	domain.WorkflowBundleAccess
+	Reason string `json:"reason"`
+}
+
+type WorkflowImportBundleMissingPrivateCA struct {
+	domain.WorkflowBundlePrivateCA
+	Reason string `json:"reason"`
+}
+
 type WorkflowStatisticsResp struct {
 	Concurrency      int      `json:"concurrency"`
 	PendingRunIds    []string `json:"pendingRunIds"`
 	ProcessingRunIds []string `json:"processingRunIds"`
+	WaitingRunIds    []string `json:"waitingRunIds"`
 }
@@ -1,15 +1,19 @@
 package expr
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type (
 	ExprType               string
 	ExprComparisonOperator string
 	ExprLogicalOperator    string
+	ExprArithmeticOperator string
 	ExprValueType          string
 )
 
@@ -20,20 +24,34 @@ const (
 	LessOrEqual    ExprComparisonOperator = "lte"
 	Equal          ExprComparisonOperator = "eq"
 	NotEqual       ExprComparisonOperator = "neq"
+	In             ExprComparisonOperator = "in"
+	NotIn          ExprComparisonOperator = "nin"
 
 	And ExprLogicalOperator = "and"
 	Or  ExprLogicalOperator = "or"
 	Not ExprLogicalOperator = "not"
 
-	Number  ExprValueType = "number"
-	String  ExprValueType = "string"
-	Boolean ExprValueType = "boolean"
+	Add      ExprArithmeticOperator = "add"
+	Subtract ExprArithmeticOperator = "sub"
+	Multiply ExprArithmeticOperator = "mul"
+	Divide   ExprArithmeticOperator = "div"
+
+	Number   ExprValueType = "number"
+	String   ExprValueType = "string"
+	Boolean  ExprValueType = "boolean"
+	DateTime ExprValueType = "datetime"
+	Duration ExprValueType = "duration"
+	List     ExprValueType = "list"
+	Null     ExprValueType = "null"
 
 	ConstantExprType   ExprType = "const"
 	VariantExprType    ExprType = "var"
 	ComparisonExprType ExprType = "comparison"
 	LogicalExprType    ExprType = "logical"
 	NotExprType        ExprType = "not"
+	ArithmeticExprType ExprType = "arithmetic"
+	CallExprType       ExprType = "call"
+	ListExprType       ExprType = "list"
 )
 
 type EvalResult struct {
@@ -41,11 +59,68 @@ type EvalResult struct {
 	Value any
 }
 
+// 按值类型包装求值结果。值类型为空时，将根据值的实际类型推断。
+func newEvalResult(valueType ExprValueType, value any) *EvalResult {
+	if value == nil {
+		return &EvalResult{Type: Null}
+	}
+
+	if valueType == "" {
+		switch value.(type) {
+		case string:
+			valueType = String
+		case bool:
+			valueType = Boolean
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+			valueType = Number
+		case time.Time:
+			valueType = DateTime
+		case time.Duration:
+			valueType = Duration
+		case []string, []any, []*EvalResult:
+			valueType = List
+		default:
+			valueType = String
+		}
+	}
+
+	return &EvalResult{Type: valueType, Value: value}
+}
+
 func (e *EvalResult) GetFloat64() (float64, error) {
 	if e.Type != Number {
 		return 0, fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
+	switch value := e.Value.(type) {
+	case float64:
+		return value, nil
+	case float32:
+		return float64(value), nil
+	case int:
+		return float64(value), nil
+	case int8:
+		return float64(value), nil
+	case int16:
+		return float64(value), nil
+	case int32:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint:
+		return float64(value), nil
+	case uint8:
+		return float64(value), nil
+	case uint16:
+		return float64(value), nil
+	case uint32:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case json.Number:
+		return value.Float64()
+	}
+
 	stringValue, ok := e.Value.(string)
 	if !ok {
 		return 0, fmt.Errorf("value is not a string: %v", e.Value)
@@ -81,234 +156,295 @@ func (e *EvalResult) GetBool() (bool, error) {
 	return boolValue, nil
 }
 
-func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) GetString() (string, error) {
+	if e.Type != String {
+		return "", fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) > other.Value.(string),
-		}, nil
+	switch value := e.Value.(type) {
+	case string:
+		return value, nil
+	case time.Time:
+		return value.Format(time.RFC3339), nil
+	default:
+		return fmt.Sprintf("%v", value), nil
+	}
+}
 
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+func (e *EvalResult) GetTime() (time.Time, error) {
+	if e.Type != DateTime {
+		return time.Time{}, fmt.Errorf("type mismatch: %s", e.Type)
+	}
 
-		right, err := other.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+	switch value := e.Value.(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		return parseDateTime(value)
+	default:
+		return time.Time{}, fmt.Errorf("value is not a datetime: %v", e.Value)
+	}
+}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left > right,
-		}, nil
+func (e *EvalResult) GetDuration() (time.Duration, error) {
+	if e.Type != Duration {
+		return 0, fmt.Errorf("type mismatch: %s", e.Type)
+	}
 
+	switch value := e.Value.(type) {
+	case time.Duration:
+		return value, nil
+	case string:
+		return parseDuration(value)
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return 0, fmt.Errorf("value is not a duration: %v", e.Value)
 	}
 }
 
-func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) GetList() ([]*EvalResult, error) {
+	if e.Type != List {
+		return nil, fmt.Errorf("type mismatch: %s", e.Type)
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) >= other.Value.(string),
-		}, nil
-
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
+	switch value := e.Value.(type) {
+	case []*EvalResult:
+		return value, nil
+	case []string:
+		items := make([]*EvalResult, len(value))
+		for i, v := range value {
+			items[i] = newEvalResult(String, v)
 		}
-
-		right, err := other.GetFloat64()
-		if err != nil {
-			return nil, err
+		return items, nil
+	case []any:
+		items := make([]*EvalResult, len(value))
+		for i, v := range value {
+			items[i] = newEvalResult("", v)
 		}
-
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left >= right,
-		}, nil
-
+		return items, nil
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return nil, fmt.Errorf("value is not a list: %v", e.Value)
 	}
 }
 
-func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
+// 比较两个同类型的值，返回值小于零、等于零、大于零分别表示小于、等于、大于。
+func (e *EvalResult) compare(other *EvalResult) (int, error) {
 	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+		return 0, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
 	}
 
 	switch e.Type {
 	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) < other.Value.(string),
-		}, nil
+		left, err := e.GetString()
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := other.GetString()
+		if err != nil {
+			return 0, err
+		}
+
+		return strings.Compare(left, right), nil
 
 	case Number:
 		left, err := e.GetFloat64()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
 		right, err := other.GetFloat64()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left < right,
-		}, nil
+		return cmp.Compare(left, right), nil
 
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
-	}
-}
+	case DateTime:
+		left, err := e.GetTime()
+		if err != nil {
+			return 0, err
+		}
 
-func (e *EvalResult) LessOrEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
-	}
+		right, err := other.GetTime()
+		if err != nil {
+			return 0, err
+		}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) <= other.Value.(string),
-		}, nil
+		return left.Compare(right), nil
 
-	case Number:
-		left, err := e.GetFloat64()
+	case Duration:
+		left, err := e.GetDuration()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		right, err := other.GetFloat64()
+		right, err := other.GetDuration()
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left <= right,
-		}, nil
+		return cmp.Compare(left, right), nil
 
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		return 0, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
 }
 
-func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
+// 判断两个值是否相等。空值仅与空值相等，不会因类型不同而报错。
+func (e *EvalResult) equals(other *EvalResult) (bool, error) {
+	if e.Type == Null || other.Type == Null {
+		return e.Type == other.Type, nil
+	}
+
 	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+		return false, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
 	}
 
 	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) == other.Value.(string),
-		}, nil
-
-	case Number:
-		left, err := e.GetFloat64()
+	case Boolean:
+		left, err := e.GetBool()
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		right, err := other.GetFloat64()
+		right, err := other.GetBool()
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left == right,
-		}, nil
+		return left == right, nil
 
-	case Boolean:
-		left, err := e.GetBool()
+	case List:
+		left, err := e.GetList()
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		right, err := other.GetBool()
+		right, err := other.GetList()
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left == right,
-		}, nil
+		if len(left) != len(right) {
+			return false, nil
+		}
+		for i := range left {
+			if eq, err := left[i].equals(right[i]); err != nil || !eq {
+				return false, err
+			}
+		}
+		return true, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
+		c, err := e.compare(other)
+		if err != nil {
+			return false, err
+		}
+
+		return c == 0, nil
 	}
 }
 
-func (e *EvalResult) NotEqual(other *EvalResult) (*EvalResult, error) {
-	if e.Type != other.Type {
-		return nil, fmt.Errorf("type mismatch: %s vs %s", e.Type, other.Type)
+func (e *EvalResult) GreaterThan(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
 	}
 
-	switch e.Type {
-	case String:
-		return &EvalResult{
-			Type:  Boolean,
-			Value: e.Value.(string) != other.Value.(string),
-		}, nil
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c > 0,
+	}, nil
+}
 
-	case Number:
-		left, err := e.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+func (e *EvalResult) GreaterOrEqual(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
+	}
 
-		right, err := other.GetFloat64()
-		if err != nil {
-			return nil, err
-		}
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c >= 0,
+	}, nil
+}
 
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left != right,
-		}, nil
+func (e *EvalResult) LessThan(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
+	}
 
-	case Boolean:
-		left, err := e.GetBool()
-		if err != nil {
-			return nil, err
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c < 0,
+	}, nil
+}
+
+func (e *EvalResult) LessOrEqual(other *EvalResult) (*EvalResult, error) {
+	c, err := e.compare(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: c <= 0,
+	}, nil
+}
+
+func (e *EvalResult) Equal(other *EvalResult) (*EvalResult, error) {
+	eq, err := e.equals(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: eq,
+	}, nil
+}
+
+func (e *EvalResult) NotEqual(other *EvalResult) (*EvalResult, error) {
+	eq, err := e.equals(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: !eq,
+	}, nil
+}
+
+func (e *EvalResult) In(other *EvalResult) (*EvalResult, error) {
+	if other.Type != List {
+		return nil, fmt.Errorf("type mismatch: %s is not a list", other.Type)
+	}
+
+	items, err := other.GetList()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		// 列表中的元素类型可能各不相同，类型不同的元素视为不相等
+		if item.Type != e.Type && item.Type != Null && e.Type != Null {
+			continue
 		}
 
-		right, err := other.GetBool()
+		eq, err := e.equals(item)
 		if err != nil {
 			return nil, err
+		} else if eq {
+			return &EvalResult{Type: Boolean, Value: true}, nil
 		}
-
-		return &EvalResult{
-			Type:  Boolean,
-			Value: left != right,
-		}, nil
-
-	default:
-		return nil, fmt.Errorf("unsupported value type: %s", e.Type)
 	}
+
+	return &EvalResult{
+		Type:  Boolean,
+		Value: false,
+	}, nil
 }
 
 func (e *EvalResult) And(other *EvalResult) (*EvalResult, error) {
@@ -380,6 +516,125 @@ func (e *EvalResult) Not() (*EvalResult, error) {
 	}, nil
 }
 
+// 四则运算。支持：
+//   - 数字之间的加减乘除；
+//   - 字符串之间的拼接；
+//   - 日期时间之间相减得到时长；
+//   - 日期时间加减时长得到日期时间；
+//   - 时长之间的加减，以及时长与数字的乘除。
+func (e *EvalResult) Arithmetic(operator ExprArithmeticOperator, other *EvalResult) (*EvalResult, error) {
+	unsupported := fmt.Errorf("unsupported arithmetic: %s %s %s", e.Type, operator, other.Type)
+
+	switch {
+	case e.Type == Number && other.Type == Number:
+		left, err := e.GetFloat64()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetFloat64()
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case Add:
+			return newEvalResult(Number, left+right), nil
+		case Subtract:
+			return newEvalResult(Number, left-right), nil
+		case Multiply:
+			return newEvalResult(Number, left*right), nil
+		case Divide:
+			if right == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return newEvalResult(Number, left/right), nil
+		}
+
+	case e.Type == String && other.Type == String:
+		left, _ := e.GetString()
+		right, _ := other.GetString()
+
+		if operator == Add {
+			return newEvalResult(String, left+right), nil
+		}
+
+	case e.Type == DateTime && other.Type == DateTime:
+		left, err := e.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		if operator == Subtract {
+			return newEvalResult(Duration, left.Sub(right)), nil
+		}
+
+	case e.Type == DateTime && other.Type == Duration:
+		left, err := e.GetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case Add:
+			return newEvalResult(DateTime, left.Add(right)), nil
+		case Subtract:
+			return newEvalResult(DateTime, left.Add(-right)), nil
+		}
+
+	case e.Type == Duration && other.Type == Duration:
+		left, err := e.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case Add:
+			return newEvalResult(Duration, left+right), nil
+		case Subtract:
+			return newEvalResult(Duration, left-right), nil
+		}
+
+	case e.Type == Duration && other.Type == Number:
+		left, err := e.GetDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := other.GetFloat64()
+		if err != nil {
+			return nil, err
+		}
+
+		switch operator {
+		case Multiply:
+			return newEvalResult(Duration, time.Duration(float64(left)*right)), nil
+		case Divide:
+			if right == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return newEvalResult(Duration, time.Duration(float64(left)/right)), nil
+		}
+	}
+
+	return nil, unsupported
+}
+
 type Expr interface {
 	GetType() ExprType
 	Eval(variables map[string]map[string]any) (*EvalResult, error)
@@ -400,6 +655,10 @@ type ConstantExpr struct {
 func (c ConstantExpr) GetType() ExprType { return c.Type }
 
 func (c ConstantExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	if c.ValueType == Null {
+		return &EvalResult{Type: Null}, nil
+	}
+
 	return &EvalResult{
 		Type:  c.ValueType,
 		Value: c.Value,
@@ -413,25 +672,20 @@ type VariantExpr struct {
 
 func (v VariantExpr) GetType() ExprType { return v.Type }
 
+// 读取变量值。
+// 节点 ID 为空时表示全局变量；变量不存在时求值为空值，以便通过空值检查进行判断。
+// 选择器中未指定值类型时，将根据变量值的实际类型推断。
 func (v VariantExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
-	if v.Selector.Id == "" {
-		return nil, fmt.Errorf("node id is empty")
-	}
 	if v.Selector.Name == "" {
 		return nil, fmt.Errorf("name is empty")
 	}
 
-	if _, ok := variables[v.Selector.Id]; !ok {
-		return nil, fmt.Errorf("node %s not found", v.Selector.Id)
+	value, ok := variables[v.Selector.Id][v.Selector.Name]
+	if !ok {
+		return &EvalResult{Type: Null}, nil
 	}
 
-	if _, ok := variables[v.Selector.Id][v.Selector.Name]; !ok {
-		return nil, fmt.Errorf("variable %s not found in node %s", v.Selector.Name, v.Selector.Id)
-	}
-	return &EvalResult{
-		Type:  v.Selector.Type,
-		Value: variables[v.Selector.Id][v.Selector.Name],
-	}, nil
+	return newEvalResult(v.Selector.Type, value), nil
 }
 
 type ComparisonExpr struct {
@@ -466,6 +720,14 @@ func (c ComparisonExpr) Eval(variables map[string]map[string]any) (*EvalResult,
 		return left.Equal(right)
 	case NotEqual:
 		return left.NotEqual(right)
+	case In:
+		return left.In(right)
+	case NotIn:
+		res, err := left.In(right)
+		if err != nil {
+			return nil, err
+		}
+		return res.Not()
 	default:
 		return nil, fmt.Errorf("unknown expression operator: %s", c.Operator)
 	}
@@ -485,6 +747,16 @@ func (l LogicalExpr) Eval(variables map[string]map[string]any) (*EvalResult, err
 	if err != nil {
 		return nil, err
 	}
+
+	// 短路求值，以便如 "$x != null && len($x) > 0" 的表达式在左侧不成立时不再对右侧求值
+	if left.Type == Boolean {
+		if leftValue, err := left.GetBool(); err == nil {
+			if (l.Operator == And && !leftValue) || (l.Operator == Or && leftValue) {
+				return &EvalResult{Type: Boolean, Value: leftValue}, nil
+			}
+		}
+	}
+
 	right, err := l.Right.Eval(variables)
 	if err != nil {
 		return nil, err
@@ -515,6 +787,88 @@ func (n NotExpr) Eval(variables map[string]map[string]any) (*EvalResult, error)
 	return inner.Not()
 }
 
+type ArithmeticExpr struct {
+	Type     ExprType               `json:"type"` // arithmetic
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     Expr                   `json:"left"`
+	Right    Expr                   `json:"right"`
+}
+
+func (a ArithmeticExpr) GetType() ExprType { return a.Type }
+
+func (a ArithmeticExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	left, err := a.Left.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+	right, err := a.Right.Eval(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a.Operator {
+	case Add, Subtract, Multiply, Divide:
+		return left.Arithmetic(a.Operator, right)
+	default:
+		return nil, fmt.Errorf("unknown expression operator: %s", a.Operator)
+	}
+}
+
+type CallExpr struct {
+	Type ExprType `json:"type"` // call
+	Func string   `json:"func"`
+	Args []Expr   `json:"args"`
+}
+
+func (c CallExpr) GetType() ExprType { return c.Type }
+
+func (c CallExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	fn, ok := funcs[c.Func]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", c.Func)
+	} else if len(c.Args) < fn.minArgs || (fn.maxArgs >= 0 && len(c.Args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for function %s: %d", c.Func, len(c.Args))
+	}
+
+	args := make([]*EvalResult, len(c.Args))
+	for i, arg := range c.Args {
+		res, err := arg.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = res
+	}
+
+	res, err := fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %w", c.Func, err)
+	}
+	return res, nil
+}
+
+type ListExpr struct {
+	Type  ExprType `json:"type"` // list
+	Items []Expr   `json:"items"`
+}
+
+func (l ListExpr) GetType() ExprType { return l.Type }
+
+func (l ListExpr) Eval(variables map[string]map[string]any) (*EvalResult, error) {
+	items := make([]*EvalResult, len(l.Items))
+	for i, item := range l.Items {
+		res, err := item.Eval(variables)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = res
+	}
+
+	return &EvalResult{
+		Type:  List,
+		Value: items,
+	}, nil
+}
+
 type rawExpr struct {
 	Type ExprType `json:"type"`
 }
@@ -560,6 +914,24 @@ func UnmarshalExpr(data []byte) (Expr, error) {
 			return nil, err
 		}
 		return e.ToNotExpr()
+	case ArithmeticExprType:
+		var e ArithmeticExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToArithmeticExpr()
+	case CallExprType:
+		var e CallExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToCallExpr()
+	case ListExprType:
+		var e ListExprRaw
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return e.ToListExpr()
 	default:
 		return nil, fmt.Errorf("unknown expression type: %s", typ.Type)
 	}
@@ -628,3 +1000,69 @@ func (r NotExprRaw) ToNotExpr() (NotExpr, error) {
 		Expr: inner,
 	}, nil
 }
+
+type ArithmeticExprRaw struct {
+	Type     ExprType               `json:"type"`
+	Operator ExprArithmeticOperator `json:"operator"`
+	Left     json.RawMessage        `json:"left"`
+	Right    json.RawMessage        `json:"right"`
+}
+
+func (r ArithmeticExprRaw) ToArithmeticExpr() (ArithmeticExpr, error) {
+	left, err := UnmarshalExpr(r.Left)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	right, err := UnmarshalExpr(r.Right)
+	if err != nil {
+		return ArithmeticExpr{}, err
+	}
+	return ArithmeticExpr{
+		Type:     r.Type,
+		Operator: r.Operator,
+		Left:     left,
+		Right:    right,
+	}, nil
+}
+
+type CallExprRaw struct {
+	Type ExprType          `json:"type"`
+	Func string            `json:"func"`
+	Args []json.RawMessage `json:"args"`
+}
+
+func (r CallExprRaw) ToCallExpr() (CallExpr, error) {
+	args := make([]Expr, len(r.Args))
+	for i, arg := range r.Args {
+		e, err := UnmarshalExpr(arg)
+		if err != nil {
+			return CallExpr{}, err
+		}
+		args[i] = e
+	}
+	return CallExpr{
+		Type: r.Type,
+		Func: r.Func,
+		Args: args,
+	}, nil
+}
+
+type ListExprRaw struct {
+	Type  ExprType          `json:"type"`
+	Items []json.RawMessage `json:"items"`
+}
+
+func (r ListExprRaw) ToListExpr() (ListExpr, error) {
+	items := make([]Expr, len(r.Items))
+	for i, item := range r.Items {
+		e, err := UnmarshalExpr(item)
+		if err != nil {
+			return ListExpr{}, err
+		}
+		items[i] = e
+	}
+	return ListExpr{
+		Type:  r.Type,
+		Items: items,
+	}, nil
+}
@@ -2,6 +2,7 @@ package expr_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -118,3 +119,116 @@ func TestEvalLogicalExpr(t *testing.T) {
 		assert.True(t, result.Value.(bool))
 	})
 }
+
+func TestEvalTypedExpr(t *testing.T) {
+	now := time.Now()
+	variables := map[string]map[string]any{
+		"": {
+			"trigger.env": "prod",
+		},
+		"node1": {
+			"certificate.daysLeft":        5,
+			"certificate.validity":        true,
+			"certificate.notAfter":        now.Add(72 * time.Hour),
+			"certificate.subjectAltNames": "example.com;www.example.com",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		expr        expr.Expr
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name: "typed number",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.LessThan,
+				Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Id: "node1", Name: "certificate.daysLeft", Type: expr.Number}},
+				Right:    expr.ConstantExpr{Type: expr.ConstantExprType, Value: "7", ValueType: expr.Number},
+			},
+			expected: true,
+		},
+		{
+			name: "typed boolean",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.Equal,
+				Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Id: "node1", Name: "certificate.validity", Type: expr.Boolean}},
+				Right:    expr.ConstantExpr{Type: expr.ConstantExprType, Value: "true", ValueType: expr.Boolean},
+			},
+			expected: true,
+		},
+		{
+			name: "datetime arithmetic",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.LessThan,
+				Left: expr.ArithmeticExpr{
+					Type:     expr.ArithmeticExprType,
+					Operator: expr.Subtract,
+					Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Id: "node1", Name: "certificate.notAfter"}},
+					Right:    expr.CallExpr{Type: expr.CallExprType, Func: "now"},
+				},
+				Right: expr.ConstantExpr{Type: expr.ConstantExprType, Value: "7d", ValueType: expr.Duration},
+			},
+			expected: true,
+		},
+		{
+			name: "global variable in list",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.In,
+				Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Name: "trigger.env"}},
+				Right: expr.ListExpr{Type: expr.ListExprType, Items: []expr.Expr{
+					expr.ConstantExpr{Type: expr.ConstantExprType, Value: "prod", ValueType: expr.String},
+					expr.ConstantExpr{Type: expr.ConstantExprType, Value: "staging", ValueType: expr.String},
+				}},
+			},
+			expected: true,
+		},
+		{
+			name: "missing variable is null",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.Equal,
+				Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Id: "node1", Name: "certificate.unknown"}},
+				Right:    expr.ConstantExpr{Type: expr.ConstantExprType, ValueType: expr.Null},
+			},
+			expected: true,
+		},
+		{
+			name: "type mismatch",
+			expr: expr.ComparisonExpr{
+				Type:     expr.ComparisonExprType,
+				Operator: expr.GreaterThan,
+				Left:     expr.VariantExpr{Type: expr.VariantExprType, Selector: expr.ExprValueSelector{Id: "node1", Name: "certificate.daysLeft"}},
+				Right:    expr.ConstantExpr{Type: expr.ConstantExprType, Value: "7", ValueType: expr.String},
+			},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.expr.Eval(variables)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+				assert.Equal(t, tc.expected, result.Value, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
+
+func TestUnmarshalExtendedExpr(t *testing.T) {
+	data := []byte(`{"type":"logical","operator":"and","left":{"type":"call","func":"hasSuffix","args":[{"type":"var","selector":{"id":"node1","name":"domain","type":"string"}},{"type":"const","value":".com","valueType":"string"}]},"right":{"type":"comparison","operator":"nin","left":{"type":"arithmetic","operator":"add","left":{"type":"const","value":"1","valueType":"number"},"right":{"type":"const","value":"2","valueType":"number"}},"right":{"type":"list","items":[{"type":"const","value":"4","valueType":"number"}]}}}`)
+
+	e, err := expr.UnmarshalExpr(data)
+	assert.NoError(t, err, "failed to unmarshal expression")
+
+	result, err := e.Eval(map[string]map[string]any{"node1": {"domain": "example.com"}})
+	assert.NoError(t, err, "failed to evaluate expression")
+	assert.Equal(t, true, result.Value)
+}
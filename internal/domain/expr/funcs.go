@@ -0,0 +1,258 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type exprFunc struct {
+	minArgs int
+	maxArgs int // 负数表示不限
+	call    func(args []*EvalResult) (*EvalResult, error)
+}
+
+// 表达式中可调用的内置函数。
+var funcs = map[string]exprFunc{
+	// contains(s, substr) 判断字符串是否包含子串；contains(list, item) 判断列表是否包含元素
+	"contains": {minArgs: 2, maxArgs: 2, call: func(args []*EvalResult) (*EvalResult, error) {
+		if args[0].Type == List {
+			return args[1].In(args[0])
+		}
+
+		s, substr, err := getStringArgs2(args)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(Boolean, strings.Contains(s, substr)), nil
+	}},
+
+	// hasPrefix(s, prefix) 判断字符串是否以指定前缀开头
+	"hasPrefix": {minArgs: 2, maxArgs: 2, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, prefix, err := getStringArgs2(args)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(Boolean, strings.HasPrefix(s, prefix)), nil
+	}},
+
+	// hasSuffix(s, suffix) 判断字符串是否以指定后缀结尾
+	"hasSuffix": {minArgs: 2, maxArgs: 2, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, suffix, err := getStringArgs2(args)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(Boolean, strings.HasSuffix(s, suffix)), nil
+	}},
+
+	// matches(s, pattern) 判断字符串是否匹配正则表达式
+	"matches": {minArgs: 2, maxArgs: 2, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, pattern, err := getStringArgs2(args)
+		if err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return newEvalResult(Boolean, re.MatchString(s)), nil
+	}},
+
+	// split(s, sep) 按分隔符拆分字符串为列表，并去除各项首尾空白及空项
+	"split": {minArgs: 2, maxArgs: 2, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, sep, err := getStringArgs2(args)
+		if err != nil {
+			return nil, err
+		}
+
+		items := make([]*EvalResult, 0)
+		for _, item := range strings.Split(s, sep) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, newEvalResult(String, item))
+			}
+		}
+		return newEvalResult(List, items), nil
+	}},
+
+	// len(s) 返回字符串的字符数；len(list) 返回列表的元素数
+	"len": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		switch args[0].Type {
+		case String:
+			s, err := args[0].GetString()
+			if err != nil {
+				return nil, err
+			}
+			return newEvalResult(Number, len([]rune(s))), nil
+
+		case List:
+			items, err := args[0].GetList()
+			if err != nil {
+				return nil, err
+			}
+			return newEvalResult(Number, len(items)), nil
+
+		default:
+			return nil, fmt.Errorf("unsupported value type: %s", args[0].Type)
+		}
+	}},
+
+	// lower(s) 转换为小写
+	"lower": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(String, strings.ToLower(s)), nil
+	}},
+
+	// upper(s) 转换为大写
+	"upper": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(String, strings.ToUpper(s)), nil
+	}},
+
+	// trim(s) 去除首尾空白
+	"trim": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(String, strings.TrimSpace(s)), nil
+	}},
+
+	// isNull(x) 判断值是否为空值（变量不存在时亦为空值）
+	"isNull": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		return newEvalResult(Boolean, args[0].Type == Null), nil
+	}},
+
+	// isEmpty(x) 判断值是否为空值、空字符串或空列表
+	"isEmpty": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		switch args[0].Type {
+		case Null:
+			return newEvalResult(Boolean, true), nil
+
+		case String:
+			s, err := args[0].GetString()
+			if err != nil {
+				return nil, err
+			}
+			return newEvalResult(Boolean, s == ""), nil
+
+		case List:
+			items, err := args[0].GetList()
+			if err != nil {
+				return nil, err
+			}
+			return newEvalResult(Boolean, len(items) == 0), nil
+
+		default:
+			return newEvalResult(Boolean, false), nil
+		}
+	}},
+
+	// now() 返回当前时间
+	"now": {minArgs: 0, maxArgs: 0, call: func(args []*EvalResult) (*EvalResult, error) {
+		return newEvalResult(DateTime, time.Now()), nil
+	}},
+
+	// datetime(s) 将字符串解析为日期时间，支持 RFC 3339 格式及 "2006-01-02" 格式
+	"datetime": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := parseDateTime(s)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(DateTime, t), nil
+	}},
+
+	// duration(s) 将字符串解析为时长，如 "7d"、"1h30m"
+	"duration": {minArgs: 1, maxArgs: 1, call: func(args []*EvalResult) (*EvalResult, error) {
+		s, err := args[0].GetString()
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := parseDuration(s)
+		if err != nil {
+			return nil, err
+		}
+		return newEvalResult(Duration, d), nil
+	}},
+}
+
+func getStringArgs2(args []*EvalResult) (string, string, error) {
+	first, err := args[0].GetString()
+	if err != nil {
+		return "", "", fmt.Errorf("argument #1: %w", err)
+	}
+
+	second, err := args[1].GetString()
+	if err != nil {
+		return "", "", fmt.Errorf("argument #2: %w", err)
+	}
+
+	return first, second, nil
+}
+
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid datetime: %s", s)
+}
+
+var reDurationSegment = regexp.MustCompile(`^(\d+(?:\.\d+)?)(ms|d|h|m|s)`) // "ms" 需在 "m" 之前，以免被识别为分钟
+
+// 解析时长，在 [time.ParseDuration] 的基础上额外支持以 "d" 表示天，如 "7d"、"1d12h"。
+func parseDuration(s string) (time.Duration, error) {
+	raw := s
+	if raw == "" {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+
+	var d time.Duration
+	for raw != "" {
+		matches := reDurationSegment.FindStringSubmatch(raw)
+		if matches == nil {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+
+		value, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+
+		var unit time.Duration
+		switch matches[2] {
+		case "d":
+			unit = 24 * time.Hour
+		case "h":
+			unit = time.Hour
+		case "m":
+			unit = time.Minute
+		case "s":
+			unit = time.Second
+		case "ms":
+			unit = time.Millisecond
+		}
+
+		d += time.Duration(value * float64(unit))
+		raw = raw[len(matches[0]):]
+	}
+
+	return d, nil
+}
@@ -0,0 +1,506 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Parse 将文本形式的表达式解析为表达式树，解析结果可通过 [MarshalExpr] 序列化为 JSON。
+//
+// 语法示例：
+//
+//	$certificate.daysLeft@nodeId < 7 && !$certificate.validity@nodeId
+//	$certificate.notAfter@nodeId - now() < 7d
+//	contains($certificate.subjectAltNames@nodeId, "example.com") || $trigger.env in ["prod", "staging"]
+//	$trigger.tag != null and matches($trigger.tag, "^v\\d+")
+//
+// 其中：
+//   - 变量形如 "$name" 或 "$name@nodeId"，前者表示全局变量，后者表示指定节点的作用域变量；不存在的变量求值为 null；
+//   - 字面量支持数字、字符串（单引号或双引号）、true、false、null、时长（如 "7d"、"12h"、"1h30m"）及列表（如 "[1, 2]"）；
+//   - 运算符按优先级从低到高依次为：
+//     "||"（或 "or"）、"&&"（或 "and"）、"!"（或 "not"）、
+//     "=="、"!="、">"、">="、"<"、"<="、"in"、"not in"、
+//     "+"、"-"、"*"、"/"；
+//   - 内置函数见 [funcs]。
+func Parse(text string) (Expr, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected '%s'", tok.text)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenDuration
+	tokenVariable
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string // 对于字符串，为去除引号及转义后的值
+	pos  int
+}
+
+func tokenize(text string) ([]token, error) {
+	runes := []rune(text)
+	tokens := make([]token, 0)
+
+	isIdentRune := func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && unicode.IsLetter(runes[i]) {
+				// 数字后紧跟单位时，视为时长字面量，如 "7d"、"1h30m"
+				for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '.') {
+					i++
+				}
+				literal := string(runes[start:i])
+				if _, err := parseDuration(literal); err != nil {
+					return nil, fmt.Errorf("syntax error at position %d: invalid duration '%s'", start, literal)
+				}
+				tokens = append(tokens, token{kind: tokenDuration, text: literal, pos: start})
+			} else {
+				tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+			}
+
+		case r == '"' || r == '\'':
+			start := i
+			quote := r
+			i++
+
+			var sb strings.Builder
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == quote {
+					closed = true
+					i++
+					break
+				}
+				if c == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					case 'r':
+						sb.WriteRune('\r')
+					default:
+						sb.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("syntax error at position %d: unterminated string", start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+
+		case r == '$':
+			start := i
+			i++
+			for i < len(runes) && (isIdentRune(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && runes[i] == '@' {
+				i++
+				for i < len(runes) && (isIdentRune(runes[i]) || runes[i] == '-') {
+					i++
+				}
+			}
+			literal := string(runes[start:i])
+			if literal == "$" || strings.HasSuffix(literal, "@") {
+				return nil, fmt.Errorf("syntax error at position %d: invalid variable '%s'", start, literal)
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: literal[1:], pos: start})
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+
+		default:
+			start := i
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", ">=", "<=", "&&", "||":
+					tokens = append(tokens, token{kind: tokenOperator, text: two, pos: start})
+					i += 2
+					continue
+				}
+			}
+
+			switch r {
+			case '>', '<', '!', '+', '-', '*', '/', '(', ')', '[', ']', ',':
+				tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: start})
+				i++
+			default:
+				return nil, fmt.Errorf("syntax error at position %d: unexpected character '%c'", start, r)
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, text: "EOF", pos: len(runes)})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// 判断下一个记号是否为指定的运算符或关键字之一。
+func (p *parser) match(texts ...string) bool {
+	tok := p.peek()
+	if tok.kind != tokenOperator && tok.kind != tokenIdent {
+		return false
+	}
+
+	for _, text := range texts {
+		if tok.text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.match(text) {
+		tok := p.peek()
+		return p.errorf(tok, "expected '%s' but got '%s'", text, tok.text)
+	}
+
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("syntax error at position %d: %s", tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match("||", "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Type: LogicalExprType, Operator: Or, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match("&&", "and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Type: LogicalExprType, Operator: And, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.match("!", "not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return NotExpr{Type: NotExprType, Expr: inner}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	var operator ExprComparisonOperator
+	switch {
+	case p.match("=="):
+		operator = Equal
+	case p.match("!="):
+		operator = NotEqual
+	case p.match(">"):
+		operator = GreaterThan
+	case p.match(">="):
+		operator = GreaterOrEqual
+	case p.match("<"):
+		operator = LessThan
+	case p.match("<="):
+		operator = LessOrEqual
+	case p.match("in"):
+		operator = In
+	case p.match("not") && p.peekAt(1).kind == tokenIdent && p.peekAt(1).text == "in":
+		operator = NotIn
+		p.next()
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComparisonExpr{Type: ComparisonExprType, Operator: operator, Left: left, Right: right}, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match("+", "-") {
+		operator := ternary(p.next().text == "+", Add, Subtract)
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match("*", "/") {
+		operator := ternary(p.next().text == "*", Multiply, Divide)
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = ArithmeticExpr{Type: ArithmeticExprType, Operator: operator, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.match("-") {
+		p.next()
+
+		// 负数字面量直接折叠为常量
+		if tok := p.peek(); tok.kind == tokenNumber {
+			p.next()
+			return ConstantExpr{Type: ConstantExprType, Value: "-" + tok.text, ValueType: Number}, nil
+		}
+
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return ArithmeticExpr{
+			Type:     ArithmeticExprType,
+			Operator: Subtract,
+			Left:     ConstantExpr{Type: ConstantExprType, Value: "0", ValueType: Number},
+			Right:    inner,
+		}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Number}, nil
+
+	case tokenString:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: String}, nil
+
+	case tokenDuration:
+		return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Duration}, nil
+
+	case tokenVariable:
+		name, nodeId, _ := strings.Cut(tok.text, "@")
+		return VariantExpr{Type: VariantExprType, Selector: ExprValueSelector{Id: nodeId, Name: name}}, nil
+
+	case tokenIdent:
+		switch tok.text {
+		case "true", "false":
+			return ConstantExpr{Type: ConstantExprType, Value: tok.text, ValueType: Boolean}, nil
+		case "null":
+			return ConstantExpr{Type: ConstantExprType, ValueType: Null}, nil
+		}
+
+		if !p.match("(") {
+			return nil, p.errorf(tok, "unknown identifier '%s'", tok.text)
+		}
+		return p.parseCall(tok)
+
+	case tokenOperator:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+
+		case "[":
+			items, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return ListExpr{Type: ListExprType, Items: items}, nil
+		}
+	}
+
+	if tok.kind == tokenEOF {
+		return nil, p.errorf(tok, "unexpected end of expression")
+	}
+	return nil, p.errorf(tok, "unexpected '%s'", tok.text)
+}
+
+func (p *parser) parseCall(name token) (Expr, error) {
+	fn, ok := funcs[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function '%s'", name.text)
+	}
+
+	p.next() // "("
+	args, err := p.parseArgs(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, p.errorf(name, "wrong number of arguments for function '%s': %d", name.text, len(args))
+	}
+
+	// 以字符串字面量为参数的 datetime() 及 duration() 在解析时即折叠为常量，以便尽早发现格式错误
+	if len(args) == 1 {
+		if arg, ok := args[0].(ConstantExpr); ok && arg.ValueType == String {
+			switch name.text {
+			case "datetime":
+				if _, err := parseDateTime(arg.Value); err != nil {
+					return nil, p.errorf(name, "%s", err.Error())
+				}
+				return ConstantExpr{Type: ConstantExprType, Value: arg.Value, ValueType: DateTime}, nil
+
+			case "duration":
+				if _, err := parseDuration(arg.Value); err != nil {
+					return nil, p.errorf(name, "%s", err.Error())
+				}
+				return ConstantExpr{Type: ConstantExprType, Value: arg.Value, ValueType: Duration}, nil
+			}
+		}
+	}
+
+	return CallExpr{Type: CallExprType, Func: name.text, Args: args}, nil
+}
+
+// 解析以半角逗号分隔的表达式列表，直到遇到指定的结束符号。
+func (p *parser) parseArgs(closing string) ([]Expr, error) {
+	args := make([]Expr, 0)
+	if p.match(closing) {
+		p.next()
+		return args, nil
+	}
+
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if p.match(",") {
+			p.next()
+			continue
+		}
+		if err := p.expect(closing); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+}
+
+func ternary[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
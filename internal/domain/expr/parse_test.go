@@ -0,0 +1,154 @@
+package expr_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain/expr"
+)
+
+func TestParseExpr(t *testing.T) {
+	testCases := []struct {
+		name        string
+		text        string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:     "comparison",
+			text:     `$certificate.daysLeft@node1 <= 7`,
+			expected: `{"type":"comparison","operator":"lte","left":{"type":"var","selector":{"id":"node1","name":"certificate.daysLeft","type":""}},"right":{"type":"const","value":"7","valueType":"number"}}`,
+		},
+		{
+			name:     "precedence",
+			text:     `!$a || $b && $c == 1 + 2 * 3`,
+			expected: `{"type":"logical","operator":"or","left":{"type":"not","expr":{"type":"var","selector":{"id":"","name":"a","type":""}}},"right":{"type":"logical","operator":"and","left":{"type":"var","selector":{"id":"","name":"b","type":""}},"right":{"type":"comparison","operator":"eq","left":{"type":"var","selector":{"id":"","name":"c","type":""}},"right":{"type":"arithmetic","operator":"add","left":{"type":"const","value":"1","valueType":"number"},"right":{"type":"arithmetic","operator":"mul","left":{"type":"const","value":"2","valueType":"number"},"right":{"type":"const","value":"3","valueType":"number"}}}}}}`,
+		},
+		{
+			name:     "not in list",
+			text:     `$env not in ["prod", 'staging']`,
+			expected: `{"type":"comparison","operator":"nin","left":{"type":"var","selector":{"id":"","name":"env","type":""}},"right":{"type":"list","items":[{"type":"const","value":"prod","valueType":"string"},{"type":"const","value":"staging","valueType":"string"}]}}`,
+		},
+		{
+			name:     "datetime literal folding",
+			text:     `datetime("2025-01-01") < now()`,
+			expected: `{"type":"comparison","operator":"lt","left":{"type":"const","value":"2025-01-01","valueType":"datetime"},"right":{"type":"call","func":"now","args":[]}}`,
+		},
+		{
+			name:     "null and duration literals",
+			text:     `$x != null and 1d12h > 36h`,
+			expected: `{"type":"logical","operator":"and","left":{"type":"comparison","operator":"neq","left":{"type":"var","selector":{"id":"","name":"x","type":""}},"right":{"type":"const","value":"","valueType":"null"}},"right":{"type":"comparison","operator":"gt","left":{"type":"const","value":"1d12h","valueType":"duration"},"right":{"type":"const","value":"36h","valueType":"duration"}}}`,
+		},
+		{
+			name:        "unknown function",
+			text:        `foo($x)`,
+			expectedErr: true,
+		},
+		{
+			name:        "wrong number of arguments",
+			text:        `contains($x)`,
+			expectedErr: true,
+		},
+		{
+			name:        "unterminated string",
+			text:        `$x == "abc`,
+			expectedErr: true,
+		},
+		{
+			name:        "invalid duration",
+			text:        `$x < 7y`,
+			expectedErr: true,
+		},
+		{
+			name:        "trailing tokens",
+			text:        `$x == 1 2`,
+			expectedErr: true,
+		},
+		{
+			name:        "empty",
+			text:        ``,
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := expr.Parse(tc.text)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+				return
+			}
+
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+
+			data, err := expr.MarshalExpr(e)
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+			assert.JSONEq(t, tc.expected, string(data), "Case: %-20s", tc.name)
+
+			// 序列化后的表达式应能被还原
+			_, err = expr.UnmarshalExpr(data)
+			assert.NoError(t, err, "Case: %-20s", tc.name)
+		})
+	}
+}
+
+func TestEvalParsedExpr(t *testing.T) {
+	now := time.Now()
+	variables := map[string]map[string]any{
+		"": {
+			"trigger.env": "staging",
+			"trigger.tag": "v1.2.3",
+		},
+		"node1": {
+			"certificate.daysLeft":        30,
+			"certificate.validity":        true,
+			"certificate.notAfter":        now.Add(5 * 24 * time.Hour),
+			"certificate.subjectAltNames": "example.com;*.example.com;example.org",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		text        string
+		expected    bool
+		expectedErr bool
+	}{
+		{name: "number comparison", text: `$certificate.daysLeft@node1 > 7`, expected: true},
+		{name: "boolean variable", text: `$certificate.validity@node1 && !($certificate.daysLeft@node1 < 10)`, expected: true},
+		{name: "datetime arithmetic", text: `$certificate.notAfter@node1 - now() < 7d`, expected: true},
+		{name: "datetime plus duration", text: `now() + 30d > $certificate.notAfter@node1`, expected: true},
+		{name: "contains", text: `contains($certificate.subjectAltNames@node1, "example.org")`, expected: true},
+		{name: "hasPrefix", text: `hasPrefix($trigger.tag, "v1.")`, expected: true},
+		{name: "hasSuffix", text: `hasSuffix($trigger.tag, ".4")`, expected: false},
+		{name: "regex match", text: `matches($trigger.tag, "^v\\d+\\.\\d+\\.\\d+$")`, expected: true},
+		{name: "split and len", text: `len(split($certificate.subjectAltNames@node1, ";")) == 3`, expected: true},
+		{name: "list contains", text: `contains(split($certificate.subjectAltNames@node1, ";"), "*.example.com")`, expected: true},
+		{name: "in list", text: `$trigger.env in ["prod", "staging"]`, expected: true},
+		{name: "not in list", text: `$trigger.env not in ["prod"]`, expected: true},
+		{name: "null check", text: `$trigger.missing == null && isNull($trigger.missing@node2)`, expected: true},
+		{name: "short circuit", text: `$trigger.missing != null && len($trigger.missing) > 0`, expected: false},
+		{name: "isEmpty", text: `isEmpty($trigger.missing) && !isEmpty($trigger.env)`, expected: true},
+		{name: "case conversion", text: `upper($trigger.env) == "STAGING" or lower("X") == "y"`, expected: true},
+		{name: "arithmetic", text: `($certificate.daysLeft@node1 - 2) / 4 == 7`, expected: true},
+		{name: "negative number", text: `-1 < 0`, expected: true},
+		{name: "type mismatch", text: `$certificate.daysLeft@node1 > "7"`, expectedErr: true},
+		{name: "null ordering", text: `$trigger.missing > 1`, expectedErr: true},
+		{name: "division by zero", text: `1 / 0 == 1`, expectedErr: true},
+		{name: "invalid regex", text: `matches($trigger.tag, "(")`, expectedErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := expr.Parse(tc.text)
+			assert.NoError(t, err, "failed to parse expression")
+
+			result, err := e.Eval(variables)
+			if tc.expectedErr {
+				assert.Error(t, err, "Case: %-20s", tc.name)
+			} else {
+				assert.NoError(t, err, "Case: %-20s", tc.name)
+				assert.Equal(t, tc.expected, result.Value, "Case: %-20s", tc.name)
+			}
+		})
+	}
+}
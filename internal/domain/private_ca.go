@@ -0,0 +1,31 @@
+package domain
+
+const CollectionNamePrivateCA = "private_ca"
+
+type PrivateCA struct {
+	Meta
+	Name                    string `db:"name"                    json:"name"`
+	RootCertificate         string `db:"rootCertificate"         json:"rootCertificate"`
+	RootPrivateKey          string `db:"rootPrivateKey"          json:"-"` // 已加密；可为空，表示根证书私钥离线保存
+	IntermediateCertificate string `db:"intermediateCertificate" json:"intermediateCertificate"`
+	IntermediatePrivateKey  string `db:"intermediatePrivateKey"  json:"-"` // 已加密
+}
+
+// 获取用于签发终端证书的颁发者证书。
+// 如果存在中间证书，则为中间证书；否则为根证书。
+func (ca *PrivateCA) GetIssuerCertificate() string {
+	if ca.IntermediateCertificate != "" {
+		return ca.IntermediateCertificate
+	}
+
+	return ca.RootCertificate
+}
+
+// 获取用于签发终端证书的颁发者私钥（已加密）。
+func (ca *PrivateCA) GetIssuerPrivateKey() string {
+	if ca.IntermediateCertificate != "" {
+		return ca.IntermediatePrivateKey
+	}
+
+	return ca.RootPrivateKey
+}
@@ -163,6 +163,7 @@ const (
 	CAProviderTypeLetsEncrypt         = CAProviderType(AccessProviderTypeLetsEncrypt)
 	CAProviderTypeLetsEncryptStaging  = CAProviderType(AccessProviderTypeLetsEncryptStaging)
 	CAProviderTypeLiteSSL             = CAProviderType(AccessProviderTypeLiteSSL)
+	CAProviderTypePrivateCA           = CAProviderType("privateca") // 内置私有 CA，无对应的授权提供商
 	CAProviderTypeSectigo             = CAProviderType(AccessProviderTypeSectigo)
 	CAProviderTypeSSLCom              = CAProviderType(AccessProviderTypeSSLCOM)
 	CAProviderTypeZeroSSL             = CAProviderType(AccessProviderTypeZeroSSL)
@@ -244,6 +245,7 @@ const (
 	ACMEDns01ProviderTypeJDCloud           = ACMEDns01ProviderType(AccessProviderTypeJDCloud) // 兼容旧值，等同于 [ACMEDns01ProviderTypeJDCloudDNS]
 	ACMEDns01ProviderTypeJDCloudDNS        = ACMEDns01ProviderType(AccessProviderTypeJDCloud + "-dns")
 	ACMEDns01ProviderTypeLinode            = ACMEDns01ProviderType(AccessProviderTypeLinode)
+	ACMEDns01ProviderTypeManual            = ACMEDns01ProviderType("manual") // 手动质询，无需授权，需人工创建解析记录并确认
 	ACMEDns01ProviderTypeNamecheap         = ACMEDns01ProviderType(AccessProviderTypeNamecheap)
 	ACMEDns01ProviderTypeNameDotCom        = ACMEDns01ProviderType(AccessProviderTypeNameDotCom)
 	ACMEDns01ProviderTypeNameSilo          = ACMEDns01ProviderType(AccessProviderTypeNameSilo)
@@ -300,6 +302,24 @@ const (
 	ACMEHttp01ProviderTypeSSH   = ACMEHttp01ProviderType(AccessProviderTypeSSH)
 )
 
+type ACMETlsAlpn01ProviderType ACMEChallengeProviderType
+
+func (t ACMETlsAlpn01ProviderType) String() string {
+	return string(t)
+}
+
+/*
+ACME TLS-ALPN-01 提供商常量值。
+短横线前的部分始终等于授权提供商类型。
+
+注意：如果追加新的常量值，请保持以 ASCII 排序。
+NOTICE: If you add new constant, please keep ASCII order.
+*/
+const (
+	ACMETlsAlpn01ProviderTypeLocal = ACMETlsAlpn01ProviderType(AccessProviderTypeLocal)
+	ACMETlsAlpn01ProviderTypeSSH   = ACMETlsAlpn01ProviderType(AccessProviderTypeSSH)
+)
+
 type DeploymentProviderType string
 
 func (t DeploymentProviderType) String() string {
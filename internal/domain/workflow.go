@@ -3,6 +3,10 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/certimate-go/certimate/internal/domain/expr"
@@ -17,6 +21,8 @@ type Workflow struct {
 	Description   string                `db:"description"   json:"description"`
 	Trigger       WorkflowTriggerType   `db:"trigger"       json:"trigger"`
 	TriggerCron   string                `db:"triggerCron"   json:"triggerCron"`
+	WebhookToken  string                `db:"webhookToken"  json:"webhookToken"`  // Webhook 触发时 URL 中的访问令牌
+	WebhookSecret string                `db:"webhookSecret" json:"webhookSecret"` // Webhook 触发时校验请求签名的 HMAC 密钥
 	Enabled       bool                  `db:"enabled"       json:"enabled"`
 	GraphDraft    *WorkflowGraph        `db:"graphDraft"    json:"graphDraft"`
 	GraphContent  *WorkflowGraph        `db:"graphContent"  json:"graphContent"`
@@ -31,8 +37,18 @@ type WorkflowGraph struct {
 	Nodes []*WorkflowNode `json:"nodes"`
 }
 
+// 循环中复制的节点 ID 形如 "{nodeId}#{index}"（嵌套循环时为 "{nodeId}#{index}#{index}"），
+// 查找时会回退到其原始节点。
 func (g *WorkflowGraph) GetNodeById(nodeId string) (*WorkflowNode, bool) {
-	return g.getNodeInBlocksById(g.Nodes, nodeId)
+	if node, ok := g.getNodeInBlocksById(g.Nodes, nodeId); ok {
+		return node, true
+	}
+
+	if templateNodeId := GetWorkflowTemplateNodeId(nodeId); templateNodeId != nodeId {
+		return g.getNodeInBlocksById(g.Nodes, templateNodeId)
+	}
+
+	return nil, false
 }
 
 func (g *WorkflowGraph) getNodeInBlocksById(blocks []*WorkflowNode, nodeId string) (*WorkflowNode, bool) {
@@ -51,6 +67,17 @@ func (g *WorkflowGraph) getNodeInBlocksById(blocks []*WorkflowNode, nodeId strin
 	return nil, false
 }
 
+// 循环迭代节点 ID 的分隔符。内联调用的工作流中的节点 ID 也以此追加调用节点的后缀。
+const WorkflowNodeIterationSeparator = "#"
+
+// 获取循环中复制的（或内联调用的）节点所对应的原始节点 ID。如果不是复制的节点，则原样返回。
+func GetWorkflowTemplateNodeId(nodeId string) string {
+	if i := strings.Index(nodeId, WorkflowNodeIterationSeparator); i >= 0 {
+		return nodeId[:i]
+	}
+	return nodeId
+}
+
 func (g *WorkflowGraph) Verify() error {
 	if len(g.Nodes) < 2 {
 		return fmt.Errorf("invalid nodes length of graph")
@@ -60,15 +87,255 @@ func (g *WorkflowGraph) Verify() error {
 		return fmt.Errorf("the last node is not an end node")
 	}
 
+	return g.verifyBlocks(g.Nodes)
+}
+
+func (g *WorkflowGraph) verifyBlocks(blocks []*WorkflowNode) error {
+	for _, node := range blocks {
+		if strings.Contains(node.Id, WorkflowNodeIterationSeparator) {
+			return fmt.Errorf("the node #%s has an invalid id", node.Id)
+		}
+
+		if node.Type == WorkflowNodeTypeStart {
+			nodeCfg := node.Data.Config.AsStart()
+
+			variableKeys := make(map[string]struct{}, len(nodeCfg.Variables))
+			for _, variable := range nodeCfg.Variables {
+				if _, ok := variableKeys[variable.Key]; ok {
+					return fmt.Errorf("the start node #%s has a duplicate variable '%s'", node.Id, variable.Key)
+				}
+				variableKeys[variable.Key] = struct{}{}
+
+				if err := variable.Verify(); err != nil {
+					return fmt.Errorf("the start node #%s has an invalid variable '%s': %w", node.Id, variable.Key, err)
+				}
+			}
+
+			if nodeCfg.Trigger == WorkflowTriggerTypeEvent {
+				switch nodeCfg.EventType {
+				case WorkflowEventTypeCertificateExpiring:
+					if nodeCfg.EventExpiringDays <= 0 {
+						return fmt.Errorf("the start node #%s has an invalid expiring days '%d'", node.Id, nodeCfg.EventExpiringDays)
+					}
+
+				case WorkflowEventTypeCertificateRevoked, WorkflowEventTypeCertificateCreated:
+
+				case WorkflowEventTypeWorkflowRunCompleted:
+					switch nodeCfg.EventRunStatus {
+					case "", WorkflowRunStatusTypeSucceeded, WorkflowRunStatusTypeFailed:
+					default:
+						return fmt.Errorf("the start node #%s has an invalid run status '%s'", node.Id, nodeCfg.EventRunStatus)
+					}
+
+				default:
+					return fmt.Errorf("the start node #%s has an invalid event type '%s'", node.Id, nodeCfg.EventType)
+				}
+			}
+		}
+
+		if node.Type == WorkflowNodeTypeCondition {
+			nodeCfg := node.Data.Config.AsCondition()
+
+			switch nodeCfg.Mode {
+			case WorkflowNodeConditionModeAll, WorkflowNodeConditionModeExclusive:
+			default:
+				return fmt.Errorf("the condition node #%s has an invalid mode '%s'", node.Id, nodeCfg.Mode)
+			}
+
+			defaultBranches := 0
+			for _, branch := range node.Blocks {
+				if branch.Type != WorkflowNodeTypeBranchBlock {
+					continue
+				}
+
+				branchCfg := branch.Data.Config.AsBranchBlock()
+				if _, err := branchCfg.ResolveExpression(); err != nil {
+					return fmt.Errorf("the branch #%s of condition node #%s has an invalid expression: %w", branch.Id, node.Id, err)
+				}
+				if !branchCfg.IsDefault {
+					continue
+				}
+
+				if nodeCfg.Mode != WorkflowNodeConditionModeExclusive {
+					return fmt.Errorf("the condition node #%s has a default branch, but it is only allowed in '%s' mode", node.Id, WorkflowNodeConditionModeExclusive)
+				} else if branchCfg.Expression != nil || branchCfg.ExpressionText != "" {
+					return fmt.Errorf("the default branch #%s of condition node #%s should not have an expression", branch.Id, node.Id)
+				}
+
+				defaultBranches++
+			}
+			if defaultBranches > 1 {
+				return fmt.Errorf("the condition node #%s has more than one default branch", node.Id)
+			}
+		}
+
+		if node.Type == WorkflowNodeTypeCallWorkflow {
+			nodeCfg := node.Data.Config.AsCallWorkflow()
+
+			if nodeCfg.WorkflowId == "" {
+				return fmt.Errorf("the call workflow node #%s has no workflow specified", node.Id)
+			}
+
+			switch nodeCfg.Mode {
+			case WorkflowNodeCallWorkflowModeInline, WorkflowNodeCallWorkflowModeChild:
+			default:
+				return fmt.Errorf("the call workflow node #%s has an invalid mode '%s'", node.Id, nodeCfg.Mode)
+			}
+		}
+
+		if node.Type == WorkflowNodeTypeBizApply {
+			nodeCfg := node.Data.Config.AsBizApply()
+
+			// 质询方式作用于所有按域名映射的质询提供商，暂不支持为不同的域名使用不同的质询方式
+			for _, mapping := range nodeCfg.ProviderMappings {
+				if mapping.Provider != "" && !isACMEChallengeProviderOfType(nodeCfg.ChallengeType, mapping.Provider) {
+					return fmt.Errorf("the apply node #%s maps zone '%s' to provider '%s', which does not support the challenge type '%s'; mixing challenge types is not supported", node.Id, mapping.Zone, mapping.Provider, nodeCfg.ChallengeType)
+				}
+			}
+		}
+
+		if node.Type == WorkflowNodeTypeApproval {
+			nodeCfg := node.Data.Config.AsApproval()
+
+			if nodeCfg.Provider == "" {
+				return fmt.Errorf("the approval node #%s has no notification provider specified", node.Id)
+			} else if nodeCfg.Timeout <= 0 {
+				return fmt.Errorf("the approval node #%s has an invalid timeout '%d'", node.Id, nodeCfg.Timeout)
+			}
+
+			switch nodeCfg.TimeoutAction {
+			case WorkflowNodeApprovalTimeoutActionReject, WorkflowNodeApprovalTimeoutActionApprove:
+			default:
+				return fmt.Errorf("the approval node #%s has an invalid timeout action '%s'", node.Id, nodeCfg.TimeoutAction)
+			}
+		}
+
+		if len(node.Blocks) > 0 {
+			if err := g.verifyBlocks(node.Blocks); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// 判断质询提供商是否支持指定的质询方式。
+// HTTP-01 及 TLS-ALPN-01 的提供商数量有限，其余提供商均视为 DNS-01 提供商。
+func isACMEChallengeProviderOfType(challengeType string, provider string) bool {
+	http01Providers := []ACMEHttp01ProviderType{
+		ACMEHttp01ProviderTypeLocal,
+		ACMEHttp01ProviderTypeFTP,
+		ACMEHttp01ProviderTypeS3,
+		ACMEHttp01ProviderTypeSSH,
+	}
+	tlsalpn01Providers := []ACMETlsAlpn01ProviderType{
+		ACMETlsAlpn01ProviderTypeLocal,
+		ACMETlsAlpn01ProviderTypeSSH,
+	}
+
+	isHttp01 := slices.Contains(http01Providers, ACMEHttp01ProviderType(provider))
+	isTlsAlpn01 := slices.Contains(tlsalpn01Providers, ACMETlsAlpn01ProviderType(provider))
+	switch strings.ToLower(challengeType) {
+	case "dns-01":
+		return !isHttp01 && !isTlsAlpn01
+	case "http-01":
+		return isHttp01
+	case "tls-alpn-01":
+		return isTlsAlpn01
+	}
+
+	return true
+}
+
 func (g *WorkflowGraph) Clone() *WorkflowGraph {
 	return &WorkflowGraph{
 		Nodes: g.Nodes,
 	}
 }
 
+// 遍历工作流图中各节点配置（含嵌套的映射及列表）中引用的授权 ID，并以回调函数的返回值替换之。
+// 授权 ID 是指名称以 "AccessId" 结尾的非空字符串配置项，如 "providerAccessId"、"caProviderAccessId" 等。
+func (g *WorkflowGraph) WalkAccessIds(fn func(accessId string) string) {
+	g.walkConfigIds(func(key string) bool { return strings.HasSuffix(key, "AccessId") }, fn)
+}
+
+// 遍历工作流图中各节点配置（含嵌套的映射及列表）中引用的私有 CA ID，并以回调函数的返回值替换之。
+func (g *WorkflowGraph) WalkPrivateCAIds(fn func(privateCAId string) string) {
+	g.walkConfigIds(func(key string) bool { return key == "privateCAId" }, fn)
+}
+
+// 遍历工作流图中引用的其他工作流 ID，并以回调函数的返回值替换之。
+// 包括开始节点中事件来源工作流的 "eventWorkflowId"，以及调用工作流节点中被调用工作流的 "workflowId"。
+func (g *WorkflowGraph) WalkWorkflowIds(fn func(workflowId string) string) {
+	var walkBlocks func(blocks []*WorkflowNode)
+	walkBlocks = func(blocks []*WorkflowNode) {
+		for _, node := range blocks {
+			var key string
+			switch node.Type {
+			case WorkflowNodeTypeStart:
+				key = "eventWorkflowId"
+			case WorkflowNodeTypeCallWorkflow:
+				key = "workflowId"
+			}
+
+			if key != "" && node.Data.Config != nil {
+				if s, ok := node.Data.Config[key].(string); ok && s != "" {
+					node.Data.Config[key] = fn(s)
+				}
+			}
+			walkBlocks(node.Blocks)
+		}
+	}
+	walkBlocks(g.Nodes)
+}
+
+func (g *WorkflowGraph) walkConfigIds(match func(key string) bool, fn func(id string) string) {
+	var walkValue func(value any) any
+	walkValue = func(value any) any {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, item := range v {
+				if s, ok := item.(string); ok && s != "" && match(key) {
+					v[key] = fn(s)
+				} else {
+					v[key] = walkValue(item)
+				}
+			}
+		case []any:
+			for i, item := range v {
+				v[i] = walkValue(item)
+			}
+		}
+		return value
+	}
+
+	var walkBlocks func(blocks []*WorkflowNode)
+	walkBlocks = func(blocks []*WorkflowNode) {
+		for _, node := range blocks {
+			if node.Data.Config != nil {
+				walkValue(map[string]any(node.Data.Config))
+			}
+			walkBlocks(node.Blocks)
+		}
+	}
+	walkBlocks(g.Nodes)
+}
+
+// 深拷贝工作流图。与 [WorkflowGraph.Clone] 不同，返回的工作流图与原图不共享任何节点或配置。
+func (g *WorkflowGraph) DeepClone() (*WorkflowGraph, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &WorkflowGraph{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
 type WorkflowTriggerType string
 
 func (t WorkflowTriggerType) String() string {
@@ -78,6 +345,22 @@ func (t WorkflowTriggerType) String() string {
 const (
 	WorkflowTriggerTypeScheduled = WorkflowTriggerType("scheduled")
 	WorkflowTriggerTypeManual    = WorkflowTriggerType("manual")
+	WorkflowTriggerTypeDryRun    = WorkflowTriggerType("dryrun") // 试运行：不签发正式证书、不变更部署目标、不发送通知
+	WorkflowTriggerTypeWebhook   = WorkflowTriggerType("webhook")
+	WorkflowTriggerTypeEvent     = WorkflowTriggerType("event") // 事件驱动：订阅证书或其他工作流的事件
+)
+
+type WorkflowEventType string
+
+func (t WorkflowEventType) String() string {
+	return string(t)
+}
+
+const (
+	WorkflowEventTypeCertificateExpiring  = WorkflowEventType("certificate.expiring")   // 证书即将到期
+	WorkflowEventTypeCertificateRevoked   = WorkflowEventType("certificate.revoked")    // 证书已吊销
+	WorkflowEventTypeCertificateCreated   = WorkflowEventType("certificate.created")    // 证书已创建
+	WorkflowEventTypeWorkflowRunCompleted = WorkflowEventType("workflow_run.completed") // 工作流运行已成功或失败
 )
 
 type WorkflowNode struct {
@@ -94,49 +377,232 @@ func (t WorkflowNodeType) String() string {
 }
 
 const (
-	WorkflowNodeTypeStart       = WorkflowNodeType("start")
-	WorkflowNodeTypeEnd         = WorkflowNodeType("end")
-	WorkflowNodeTypeCondition   = WorkflowNodeType("condition")
-	WorkflowNodeTypeBranchBlock = WorkflowNodeType("branchBlock")
-	WorkflowNodeTypeTryCatch    = WorkflowNodeType("tryCatch")
-	WorkflowNodeTypeTryBlock    = WorkflowNodeType("tryBlock")
-	WorkflowNodeTypeCatchBlock  = WorkflowNodeType("catchBlock")
-	WorkflowNodeTypeDelay       = WorkflowNodeType("delay")
-	WorkflowNodeTypeBizApply    = WorkflowNodeType("bizApply")
-	WorkflowNodeTypeBizUpload   = WorkflowNodeType("bizUpload")
-	WorkflowNodeTypeBizMonitor  = WorkflowNodeType("bizMonitor")
-	WorkflowNodeTypeBizDeploy   = WorkflowNodeType("bizDeploy")
-	WorkflowNodeTypeBizNotify   = WorkflowNodeType("bizNotify")
+	WorkflowNodeTypeStart         = WorkflowNodeType("start")
+	WorkflowNodeTypeEnd           = WorkflowNodeType("end")
+	WorkflowNodeTypeCondition     = WorkflowNodeType("condition")
+	WorkflowNodeTypeBranchBlock   = WorkflowNodeType("branchBlock")
+	WorkflowNodeTypeTryCatch      = WorkflowNodeType("tryCatch")
+	WorkflowNodeTypeTryBlock      = WorkflowNodeType("tryBlock")
+	WorkflowNodeTypeCatchBlock    = WorkflowNodeType("catchBlock")
+	WorkflowNodeTypeParallel      = WorkflowNodeType("parallel")
+	WorkflowNodeTypeParallelBlock = WorkflowNodeType("parallelBlock")
+	WorkflowNodeTypeDelay         = WorkflowNodeType("delay")
+	WorkflowNodeTypeForEach       = WorkflowNodeType("forEach")
+	WorkflowNodeTypeCallWorkflow  = WorkflowNodeType("callWorkflow")
+	WorkflowNodeTypeApproval      = WorkflowNodeType("approval")
+	WorkflowNodeTypeApproveBlock  = WorkflowNodeType("approveBlock")
+	WorkflowNodeTypeRejectBlock   = WorkflowNodeType("rejectBlock")
+	WorkflowNodeTypeBizApply      = WorkflowNodeType("bizApply")
+	WorkflowNodeTypeBizUpload     = WorkflowNodeType("bizUpload")
+	WorkflowNodeTypeBizMonitor    = WorkflowNodeType("bizMonitor")
+	WorkflowNodeTypeBizDeploy     = WorkflowNodeType("bizDeploy")
+	WorkflowNodeTypeBizNotify     = WorkflowNodeType("bizNotify")
 )
 
 type WorkflowNodeData struct {
-	Name     string             `json:"name"`
-	Disabled bool               `json:"disabled,omitempty,omitzero"`
-	Config   WorkflowNodeConfig `json:"config,omitempty,omitzero"`
+	Name     string                   `json:"name"`
+	Disabled bool                     `json:"disabled,omitempty,omitzero"`
+	Config   WorkflowNodeConfig       `json:"config,omitempty,omitzero"`
+	Retry    *WorkflowNodeRetryPolicy `json:"retry,omitempty"`   // 失败重试策略
+	Timeout  int                      `json:"timeout,omitempty"` // 单次执行的超时时间（单位：秒），零值时表示不限制
+}
+
+type WorkflowNodeRetryPolicy struct {
+	MaxAttempts int                          `json:"maxAttempts"`           // 最大执行次数（含首次执行），小于等于 1 时表示不重试
+	Backoff     WorkflowNodeRetryBackoffType `json:"backoff,omitempty"`     // 退避策略，默认值 "fixed"
+	Interval    int                          `json:"interval,omitempty"`    // 重试间隔（单位：秒），指数退避时表示首次重试的间隔，零值时默认 5 秒
+	MaxInterval int                          `json:"maxInterval,omitempty"` // 指数退避时的最大重试间隔（单位：秒），零值时表示不限制
+	RetryOn     []string                     `json:"retryOn,omitempty"`     // 可重试的错误信息匹配规则（正则表达式），为空时表示所有错误均可重试
+}
+
+func (p *WorkflowNodeRetryPolicy) GetBackoffDelay(attempt int) time.Duration {
+	interval := time.Duration(p.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	if p.Backoff == WorkflowNodeRetryBackoffExponential && attempt > 1 {
+		interval = interval << min(attempt-1, 16)
+	}
+
+	if p.MaxInterval > 0 {
+		interval = min(interval, time.Duration(p.MaxInterval)*time.Second)
+	}
+
+	return interval
 }
 
+func (p *WorkflowNodeRetryPolicy) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	} else if len(p.RetryOn) == 0 {
+		return true
+	}
+
+	errmsg := err.Error()
+	for _, pattern := range p.RetryOn {
+		if pattern == "" {
+			continue
+		}
+
+		if re, rerr := regexp.Compile(pattern); rerr == nil {
+			if re.MatchString(errmsg) {
+				return true
+			}
+		} else if strings.Contains(errmsg, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WorkflowNodeRetryBackoffType string
+
+func (t WorkflowNodeRetryBackoffType) String() string {
+	return string(t)
+}
+
+const (
+	WorkflowNodeRetryBackoffExponential = WorkflowNodeRetryBackoffType("exponential")
+	WorkflowNodeRetryBackoffFixed       = WorkflowNodeRetryBackoffType("fixed")
+)
+
 type WorkflowNodeConfig map[string]any
 
+func (c WorkflowNodeConfig) AsStart() WorkflowNodeConfigForStart {
+	return WorkflowNodeConfigForStart{
+		Trigger:                WorkflowTriggerType(xmaps.GetString(c, "trigger")),
+		TriggerCron:            xmaps.GetString(c, "triggerCron"),
+		WebhookPayloadMappings: c.getStartWebhookPayloadMappings(),
+		EventType:              WorkflowEventType(xmaps.GetString(c, "eventType")),
+		EventWorkflowId:        xmaps.GetString(c, "eventWorkflowId"),
+		EventExpiringDays:      xmaps.GetInt(c, "eventExpiringDays"),
+		EventRunStatus:         WorkflowRunStatusType(xmaps.GetString(c, "eventRunStatus")),
+		Variables:              c.getStartVariables(),
+	}
+}
+
+func (c WorkflowNodeConfig) getStartVariables() []WorkflowNodeConfigForStartVariable {
+	variables := c["variables"]
+	if variables == nil {
+		return nil
+	}
+
+	variablesRaw, _ := json.Marshal(variables)
+	result := make([]WorkflowNodeConfigForStartVariable, 0)
+	if err := json.Unmarshal(variablesRaw, &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func (c WorkflowNodeConfig) getStartWebhookPayloadMappings() []WorkflowNodeConfigForStartWebhookPayloadMapping {
+	mappings := c["webhookPayloadMappings"]
+	if mappings == nil {
+		return nil
+	}
+
+	mappingsRaw, _ := json.Marshal(mappings)
+	result := make([]WorkflowNodeConfigForStartWebhookPayloadMapping, 0)
+	if err := json.Unmarshal(mappingsRaw, &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
 func (c WorkflowNodeConfig) AsDelay() WorkflowNodeConfigForDelay {
 	return WorkflowNodeConfigForDelay{
 		Wait: xmaps.GetInt(c, "wait"),
 	}
 }
 
+func (c WorkflowNodeConfig) AsCondition() WorkflowNodeConfigForCondition {
+	return WorkflowNodeConfigForCondition{
+		Mode: WorkflowNodeConditionModeType(xmaps.GetOrDefaultString(c, "mode", string(WorkflowNodeConditionModeAll))),
+	}
+}
+
 func (c WorkflowNodeConfig) AsBranchBlock() WorkflowNodeConfigForBranchBlock {
+	isDefault := xmaps.GetBool(c, "isDefault")
+	expressionText := xmaps.GetString(c, "expressionText")
+
 	expression := c["expression"]
 	if expression == nil {
-		return WorkflowNodeConfigForBranchBlock{}
+		return WorkflowNodeConfigForBranchBlock{ExpressionText: expressionText, IsDefault: isDefault}
 	}
 
 	exprRaw, _ := json.Marshal(expression)
 	expr, err := expr.UnmarshalExpr([]byte(exprRaw))
 	if err != nil {
-		return WorkflowNodeConfigForBranchBlock{}
+		return WorkflowNodeConfigForBranchBlock{ExpressionText: expressionText, IsDefault: isDefault}
 	}
 
 	return WorkflowNodeConfigForBranchBlock{
-		Expression: expr,
+		Expression:     expr,
+		ExpressionText: expressionText,
+		IsDefault:      isDefault,
+	}
+}
+
+// 返回分支的条件表达式。文本形式的表达式非空时优先于 [WorkflowNodeConfigForBranchBlock.Expression]。
+func (c WorkflowNodeConfigForBranchBlock) ResolveExpression() (expr.Expr, error) {
+	if strings.TrimSpace(c.ExpressionText) != "" {
+		return expr.Parse(c.ExpressionText)
+	}
+
+	return c.Expression, nil
+}
+
+func (c WorkflowNodeConfig) AsForEach() WorkflowNodeConfigForForEach {
+	return WorkflowNodeConfigForForEach{
+		Items:               xmaps.GetStringsBySplit(c, "items", ";"),
+		ItemsVariable:       xmaps.GetString(c, "itemsVariable"),
+		ItemsVariableNodeId: xmaps.GetString(c, "itemsVariableNodeId"),
+		ItemsSeparator:      xmaps.GetOrDefaultString(c, "itemsSeparator", ";"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsParallel() WorkflowNodeConfigForParallel {
+	return WorkflowNodeConfigForParallel{
+		MaxConcurrency: xmaps.GetInt(c, "maxConcurrency"),
+	}
+}
+
+func (c WorkflowNodeConfig) AsCallWorkflow() WorkflowNodeConfigForCallWorkflow {
+	return WorkflowNodeConfigForCallWorkflow{
+		WorkflowId: xmaps.GetString(c, "workflowId"),
+		Mode:       WorkflowNodeCallWorkflowModeType(xmaps.GetOrDefaultString(c, "mode", string(WorkflowNodeCallWorkflowModeInline))),
+		Inputs:     c.getCallWorkflowInputs(),
+	}
+}
+
+func (c WorkflowNodeConfig) getCallWorkflowInputs() []WorkflowNodeConfigForCallWorkflowInput {
+	inputs := c["inputs"]
+	if inputs == nil {
+		return nil
+	}
+
+	inputsRaw, _ := json.Marshal(inputs)
+	result := make([]WorkflowNodeConfigForCallWorkflowInput, 0)
+	if err := json.Unmarshal(inputsRaw, &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func (c WorkflowNodeConfig) AsApproval() WorkflowNodeConfigForApproval {
+	return WorkflowNodeConfigForApproval{
+		Provider:         xmaps.GetString(c, "provider"),
+		ProviderAccessId: xmaps.GetString(c, "providerAccessId"),
+		ProviderConfig:   xmaps.GetKVMapAny(c, "providerConfig"),
+		Subject:          xmaps.GetString(c, "subject"),
+		Message:          xmaps.GetString(c, "message"),
+		Timeout:          xmaps.GetInt(c, "timeout"),
+		TimeoutAction:    WorkflowNodeApprovalTimeoutActionType(xmaps.GetOrDefaultString(c, "timeoutAction", string(WorkflowNodeApprovalTimeoutActionReject))),
 	}
 }
 
@@ -149,9 +615,11 @@ func (c WorkflowNodeConfig) AsBizApply() WorkflowNodeConfigForBizApply {
 		Provider:              xmaps.GetString(c, "provider"),
 		ProviderAccessId:      xmaps.GetString(c, "providerAccessId"),
 		ProviderConfig:        xmaps.GetKVMapAny(c, "providerConfig"),
+		ProviderMappings:      c.getBizApplyProviderMappings(),
 		KeySource:             xmaps.GetOrDefaultString(c, "keySource", "auto"),
 		KeyAlgorithm:          xmaps.GetOrDefaultString(c, "keyAlgorithm", CertificateKeyAlgorithmTypeRSA2048.String()),
 		KeyContent:            xmaps.GetString(c, "keyContent"),
+		CSRContent:            xmaps.GetString(c, "csrContent"),
 		CAProvider:            xmaps.GetString(c, "caProvider"),
 		CAProviderAccessId:    xmaps.GetString(c, "caProviderAccessId"),
 		CAProviderConfig:      xmaps.GetKVMapAny(c, "caProviderConfig"),
@@ -163,13 +631,30 @@ func (c WorkflowNodeConfig) AsBizApply() WorkflowNodeConfigForBizApply {
 		DnsPropagationTimeout: xmaps.GetInt(c, "dnsPropagationTimeout"),
 		DnsTTL:                xmaps.GetInt(c, "dnsTTL"),
 		HttpDelayWait:         xmaps.GetInt(c, "httpDelayWait"),
+		TlsAlpnDelayWait:      xmaps.GetInt(c, "tlsAlpnDelayWait"),
 		DisableCommonName:     xmaps.GetBool(c, "disableCommonName"),
 		DisableFollowCNAME:    xmaps.GetBool(c, "disableFollowCNAME"),
 		DisableARI:            xmaps.GetBool(c, "disableARI"),
+		DisablePreflight:      xmaps.GetBool(c, "disablePreflight"),
 		SkipBeforeExpiryDays:  xmaps.GetInt(c, "skipBeforeExpiryDays"),
 	}
 }
 
+func (c WorkflowNodeConfig) getBizApplyProviderMappings() []WorkflowNodeConfigForBizApplyProviderMapping {
+	mappings := c["providerMappings"]
+	if mappings == nil {
+		return nil
+	}
+
+	mappingsRaw, _ := json.Marshal(mappings)
+	result := make([]WorkflowNodeConfigForBizApplyProviderMapping, 0)
+	if err := json.Unmarshal(mappingsRaw, &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
 func (c WorkflowNodeConfig) AsBizUpload() WorkflowNodeConfigForBizUpload {
 	return WorkflowNodeConfigForBizUpload{
 		Source:      xmaps.GetOrDefaultString(c, "source", "form"),
@@ -209,40 +694,254 @@ func (c WorkflowNodeConfig) AsBizNotify() WorkflowNodeConfigForBizNotify {
 	}
 }
 
+type WorkflowNodeConfigForStart struct {
+	Trigger                WorkflowTriggerType                               `json:"trigger"`                          // 触发方式
+	TriggerCron            string                                            `json:"triggerCron,omitempty"`            // 定时触发的 Cron 表达式
+	WebhookPayloadMappings []WorkflowNodeConfigForStartWebhookPayloadMapping `json:"webhookPayloadMappings,omitempty"` // Webhook 触发时请求体到运行变量的映射列表
+	EventType              WorkflowEventType                                 `json:"eventType,omitempty"`              // 事件驱动时订阅的事件类型
+	EventWorkflowId        string                                            `json:"eventWorkflowId,omitempty"`        // 事件驱动时的事件来源工作流 ID，为空时不限来源
+	EventExpiringDays      int                                               `json:"eventExpiringDays,omitempty"`      // 订阅证书即将到期事件时的剩余天数
+	EventRunStatus         WorkflowRunStatusType                             `json:"eventRunStatus,omitempty"`         // 订阅工作流运行事件时的运行状态，为空时表示成功或失败均可
+	Variables              []WorkflowNodeConfigForStartVariable              `json:"variables,omitempty"`              // 自定义变量列表
+}
+
+type WorkflowNodeConfigForStartVariable struct {
+	Key               string                           `json:"key"`                         // 变量名，将写入全局变量
+	Type              WorkflowVariableType             `json:"type"`                        // 变量类型
+	DefaultValue      string                           `json:"defaultValue,omitempty"`      // 默认值，以字符串形式表示；不适用于密钥类型
+	Description       string                           `json:"description,omitempty"`       // 变量描述
+	SecretSource      WorkflowVariableSecretSourceType `json:"secretSource,omitempty"`      // 密钥来源，仅适用于密钥类型
+	SecretAccessId    string                           `json:"secretAccessId,omitempty"`    // 密钥来源为授权时的授权记录 ID
+	SecretAccessField string                           `json:"secretAccessField,omitempty"` // 密钥来源为授权时的授权配置字段名
+	SecretEnvName     string                           `json:"secretEnvName,omitempty"`     // 密钥来源为环境变量时的环境变量名
+}
+
+var reWorkflowVariableKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// 校验变量定义。
+// 变量名不允许包含半角句点，以免与内置变量（如 "workflow.id"、"certificate.daysLeft"）冲突。
+func (v WorkflowNodeConfigForStartVariable) Verify() error {
+	if !reWorkflowVariableKey.MatchString(v.Key) {
+		return fmt.Errorf("invalid variable key")
+	}
+
+	if v.Type == WorkflowVariableTypeSecret {
+		switch v.SecretSource {
+		case WorkflowVariableSecretSourceAccess:
+			if v.SecretAccessId == "" || v.SecretAccessField == "" {
+				return fmt.Errorf("the access and its field of secret are required")
+			}
+
+		case WorkflowVariableSecretSourceEnv:
+			if !strings.HasPrefix(v.SecretEnvName, WorkflowVariableSecretEnvPrefix) || len(v.SecretEnvName) == len(WorkflowVariableSecretEnvPrefix) {
+				return fmt.Errorf("the environment variable name of secret must start with '%s'", WorkflowVariableSecretEnvPrefix)
+			}
+
+		default:
+			return fmt.Errorf("invalid secret source '%s'", v.SecretSource)
+		}
+
+		return nil
+	}
+
+	if v.DefaultValue != "" {
+		if err := v.VerifyValue(v.DefaultValue); err != nil {
+			return fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// 校验变量值是否符合变量类型。密钥类型的变量不允许赋值。
+func (v WorkflowNodeConfigForStartVariable) VerifyValue(value string) error {
+	switch v.Type {
+	case WorkflowVariableTypeString:
+		return nil
+
+	case WorkflowVariableTypeNumber:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("'%s' is not an integer", value)
+		}
+		return nil
+
+	case WorkflowVariableTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("'%s' is not a boolean", value)
+		}
+		return nil
+
+	case WorkflowVariableTypeDateTime:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("'%s' is not a RFC 3339 datetime", value)
+		}
+		return nil
+
+	case WorkflowVariableTypeSecret:
+		return fmt.Errorf("secret variable cannot be assigned")
+
+	default:
+		return fmt.Errorf("invalid variable type '%s'", v.Type)
+	}
+}
+
+type WorkflowVariableType string
+
+func (t WorkflowVariableType) String() string {
+	return string(t)
+}
+
+const (
+	WorkflowVariableTypeString   = WorkflowVariableType("string")
+	WorkflowVariableTypeNumber   = WorkflowVariableType("number")
+	WorkflowVariableTypeBoolean  = WorkflowVariableType("boolean")
+	WorkflowVariableTypeDateTime = WorkflowVariableType("datetime")
+	WorkflowVariableTypeSecret   = WorkflowVariableType("secret") // 密钥：运行时从授权或环境变量中读取，不会被持久化，并在日志中被遮蔽
+)
+
+type WorkflowVariableSecretSourceType string
+
+func (t WorkflowVariableSecretSourceType) String() string {
+	return string(t)
+}
+
+const (
+	// 从授权记录的配置字段中读取
+	WorkflowVariableSecretSourceAccess = WorkflowVariableSecretSourceType("access")
+	// 从服务器的环境变量中读取
+	WorkflowVariableSecretSourceEnv = WorkflowVariableSecretSourceType("env")
+)
+
+// 可作为密钥来源的环境变量名前缀，以免工作流读取服务器上的其他环境变量。
+const WorkflowVariableSecretEnvPrefix = "CERTIMATE_WORKFLOW_SECRET_"
+
+type WorkflowNodeConfigForStartWebhookPayloadMapping struct {
+	Path     string `json:"path"`     // JSON 请求体中的字段路径，以半角句点分隔，如 "release.tag"、"domains.0"
+	Variable string `json:"variable"` // 运行变量名，将以 "trigger." 为前缀写入全局变量
+}
+
 type WorkflowNodeConfigForDelay struct {
 	Wait int `json:"wait"` // 等待时间
 }
 
+type WorkflowNodeConfigForCondition struct {
+	Mode WorkflowNodeConditionModeType `json:"mode,omitempty"` // 分支求值模式，默认值 "all"
+}
+
+type WorkflowNodeConditionModeType string
+
+func (t WorkflowNodeConditionModeType) String() string {
+	return string(t)
+}
+
+const (
+	// 依次求值每个分支，进入所有满足条件的分支（无条件的分支总是会进入）
+	WorkflowNodeConditionModeAll = WorkflowNodeConditionModeType("all")
+	// 依次求值每个分支，仅进入第一个满足条件的分支；均不满足时进入默认分支
+	WorkflowNodeConditionModeExclusive = WorkflowNodeConditionModeType("exclusive")
+)
+
 type WorkflowNodeConfigForBranchBlock struct {
-	Expression expr.Expr `json:"expression"` // 条件表达式
+	Expression     expr.Expr `json:"expression"`               // 条件表达式
+	ExpressionText string    `json:"expressionText,omitempty"` // 文本形式的条件表达式，语法见 [expr.Parse]
+	IsDefault      bool      `json:"isDefault,omitempty"`      // 是否为默认分支，仅在 "exclusive" 模式下有效
+}
+
+type WorkflowNodeConfigForForEach struct {
+	Items               []string `json:"items,omitempty"`               // 迭代项列表，以半角分号分隔
+	ItemsVariable       string   `json:"itemsVariable,omitempty"`       // 迭代项来源变量名，如 "certificate.subjectAltNames"；非空时优先于 Items
+	ItemsVariableNodeId string   `json:"itemsVariableNodeId,omitempty"` // 迭代项来源变量的作用域节点 ID，零值时表示全局变量
+	ItemsSeparator      string   `json:"itemsSeparator,omitempty"`      // 迭代项来源变量值的分隔符，默认值 ";"
+}
+
+type WorkflowNodeConfigForParallel struct {
+	MaxConcurrency int `json:"maxConcurrency,omitempty"` // 最大并发数，零值时表示不限制
+}
+
+type WorkflowNodeConfigForCallWorkflow struct {
+	WorkflowId string                                   `json:"workflowId"`       // 被调用的工作流 ID
+	Mode       WorkflowNodeCallWorkflowModeType         `json:"mode,omitempty"`   // 调用模式，默认值 "inline"
+	Inputs     []WorkflowNodeConfigForCallWorkflowInput `json:"inputs,omitempty"` // 传入被调用工作流的前序节点输出列表
+}
+
+type WorkflowNodeConfigForCallWorkflowInput struct {
+	NodeId string `json:"nodeId"` // 前序节点 ID
+	Name   string `json:"name"`   // 前序节点输出名称，如 "certificate"；在被调用工作流中作为其开始节点的同名输出
 }
 
+type WorkflowNodeCallWorkflowModeType string
+
+func (t WorkflowNodeCallWorkflowModeType) String() string {
+	return string(t)
+}
+
+const (
+	// 将被调用工作流的节点内联到当前运行中执行
+	WorkflowNodeCallWorkflowModeInline = WorkflowNodeCallWorkflowModeType("inline")
+	// 为被调用工作流创建独立的子运行记录并等待其执行完成
+	WorkflowNodeCallWorkflowModeChild = WorkflowNodeCallWorkflowModeType("child")
+)
+
+type WorkflowNodeConfigForApproval struct {
+	Provider         string                                `json:"provider"`                 // 通知提供商
+	ProviderAccessId string                                `json:"providerAccessId"`         // 通知提供商授权记录 ID
+	ProviderConfig   map[string]any                        `json:"providerConfig,omitempty"` // 通知提供商额外配置
+	Subject          string                                `json:"subject"`                  // 审批通知主题
+	Message          string                                `json:"message"`                  // 审批通知内容，同意及拒绝链接将附加在其后
+	Timeout          int                                   `json:"timeout"`                  // 等待审批的超时时间（单位：秒）
+	TimeoutAction    WorkflowNodeApprovalTimeoutActionType `json:"timeoutAction,omitempty"`  // 超时后的处理方式，默认值 "reject"
+}
+
+type WorkflowNodeApprovalTimeoutActionType string
+
+func (t WorkflowNodeApprovalTimeoutActionType) String() string {
+	return string(t)
+}
+
+const (
+	// 超时后视为拒绝，进入拒绝分支
+	WorkflowNodeApprovalTimeoutActionReject = WorkflowNodeApprovalTimeoutActionType("reject")
+	// 超时后视为同意，继续执行
+	WorkflowNodeApprovalTimeoutActionApprove = WorkflowNodeApprovalTimeoutActionType("approve")
+)
+
 type WorkflowNodeConfigForBizApply struct {
-	Domains               []string       `json:"domains"`                         // 域名列表，以半角分号分隔
-	IPAddrs               []string       `json:"ipaddrs"`                         // IP 地址列表，以半角分号分隔
-	ContactEmail          string         `json:"contactEmail"`                    // 联系邮箱
-	ChallengeType         string         `json:"challengeType"`                   // 质询方式
-	Provider              string         `json:"provider"`                        // 质询提供商
-	ProviderAccessId      string         `json:"providerAccessId"`                // 质询提供商授权记录 ID
-	ProviderConfig        map[string]any `json:"providerConfig,omitempty"`        // 质询提供商额外配置
-	CAProvider            string         `json:"caProvider,omitempty"`            // CA 提供商（零值时使用全局配置）
-	CAProviderAccessId    string         `json:"caProviderAccessId,omitempty"`    // CA 提供商授权记录 ID
-	CAProviderConfig      map[string]any `json:"caProviderConfig,omitempty"`      // CA 提供商额外配置
-	KeySource             string         `json:"keySource"`                       // 私钥来源，可取值 "auto"、"reuse"、"custom"（零值时默认值 "auto"）
-	KeyAlgorithm          string         `json:"keyAlgorithm,omitempty"`          // 私钥算法
-	KeyContent            string         `json:"keyContent,omitempty"`            // 私钥内容
-	ValidityLifetime      string         `json:"validityLifetime,omitempty"`      // 有效期，形如 "30d"、"6h"
-	PreferredChain        string         `json:"preferredChain,omitempty"`        // 首选证书链
-	ACMEProfile           string         `json:"acmeProfile,omitempty"`           // ACME Profiles Extension
-	Nameservers           []string       `json:"nameservers,omitempty"`           // DNS 服务器列表，以半角分号分隔。等同于 lego 的 `--dns.resolvers` 参数
-	DnsPropagationWait    int            `json:"dnsPropagationWait,omitempty"`    // DNS 传播等待时间。等同于 lego 的 `--dns.propagation.wait` 参数
-	DnsPropagationTimeout int            `json:"dnsPropagationTimeout,omitempty"` // DNS 传播检查超时时间。等同于 lego 的 `--dns.timeout` 参数
-	DnsTTL                int            `json:"dnsTTL,omitempty"`                // DNS 解析记录 TTL
-	HttpDelayWait         int            `json:"httpDelayWait,omitempty"`         // HTTP 等待时间。等同于 lego 的 `--http.delay` 参数
-	DisableCommonName     bool           `json:"disableCommonName,omitempty"`     // 是否不包含 CommonName
-	DisableFollowCNAME    bool           `json:"disableFollowCNAME,omitempty"`    // 是否关闭 CNAME 跟随
-	DisableARI            bool           `json:"disableARI,omitempty"`            // 是否关闭 ARI
-	SkipBeforeExpiryDays  int            `json:"skipBeforeExpiryDays,omitempty"`  // 证书到期前多少天前跳过续期
+	Domains               []string                                       `json:"domains"`                         // 域名列表，以半角分号分隔
+	IPAddrs               []string                                       `json:"ipaddrs"`                         // IP 地址列表，以半角分号分隔
+	ContactEmail          string                                         `json:"contactEmail"`                    // 联系邮箱
+	ChallengeType         string                                         `json:"challengeType"`                   // 质询方式，可取值 "dns-01"、"http-01"、"tls-alpn-01"
+	Provider              string                                         `json:"provider"`                        // 质询提供商
+	ProviderAccessId      string                                         `json:"providerAccessId"`                // 质询提供商授权记录 ID
+	ProviderConfig        map[string]any                                 `json:"providerConfig,omitempty"`        // 质询提供商额外配置
+	ProviderMappings      []WorkflowNodeConfigForBizApplyProviderMapping `json:"providerMappings,omitempty"`      // 按域名映射的质询提供商列表，未匹配的域名使用上述默认质询提供商。均使用上述质询方式，不支持混用
+	CAProvider            string                                         `json:"caProvider,omitempty"`            // CA 提供商（零值时使用全局配置）
+	CAProviderAccessId    string                                         `json:"caProviderAccessId,omitempty"`    // CA 提供商授权记录 ID
+	CAProviderConfig      map[string]any                                 `json:"caProviderConfig,omitempty"`      // CA 提供商额外配置
+	KeySource             string                                         `json:"keySource"`                       // 私钥来源，可取值 "auto"、"reuse"、"custom"、"csr"（零值时默认值 "auto"）
+	KeyAlgorithm          string                                         `json:"keyAlgorithm,omitempty"`          // 私钥算法
+	KeyContent            string                                         `json:"keyContent,omitempty"`            // 私钥内容
+	CSRContent            string                                         `json:"csrContent,omitempty"`            // 证书签名请求内容，仅当私钥来源为 "csr" 时有效
+	ValidityLifetime      string                                         `json:"validityLifetime,omitempty"`      // 有效期，形如 "30d"、"6h"
+	PreferredChain        string                                         `json:"preferredChain,omitempty"`        // 首选证书链
+	ACMEProfile           string                                         `json:"acmeProfile,omitempty"`           // ACME Profiles Extension
+	Nameservers           []string                                       `json:"nameservers,omitempty"`           // DNS 服务器列表，以半角分号分隔。等同于 lego 的 `--dns.resolvers` 参数
+	DnsPropagationWait    int                                            `json:"dnsPropagationWait,omitempty"`    // DNS 传播等待时间。等同于 lego 的 `--dns.propagation.wait` 参数
+	DnsPropagationTimeout int                                            `json:"dnsPropagationTimeout,omitempty"` // DNS 传播检查超时时间。等同于 lego 的 `--dns.timeout` 参数
+	DnsTTL                int                                            `json:"dnsTTL,omitempty"`                // DNS 解析记录 TTL
+	HttpDelayWait         int                                            `json:"httpDelayWait,omitempty"`         // HTTP 等待时间。等同于 lego 的 `--http.delay` 参数
+	TlsAlpnDelayWait      int                                            `json:"tlsAlpnDelayWait,omitempty"`      // TLS-ALPN 等待时间。等同于 lego 的 `--tls.delay` 参数
+	DisableCommonName     bool                                           `json:"disableCommonName,omitempty"`     // 是否不包含 CommonName
+	DisableFollowCNAME    bool                                           `json:"disableFollowCNAME,omitempty"`    // 是否关闭 CNAME 跟随
+	DisableARI            bool                                           `json:"disableARI,omitempty"`            // 是否关闭 ARI
+	DisablePreflight      bool                                           `json:"disablePreflight,omitempty"`      // 是否关闭申请前的 CAA 及 DNS 预检
+	SkipBeforeExpiryDays  int                                            `json:"skipBeforeExpiryDays,omitempty"`  // 证书到期前多少天前跳过续期
+}
+
+type WorkflowNodeConfigForBizApplyProviderMapping struct {
+	Zone             string         `json:"zone"`                     // 域名后缀或托管区域，匹配其本身及所有子域名
+	Provider         string         `json:"provider"`                 // 质询提供商
+	ProviderAccessId string         `json:"providerAccessId"`         // 质询提供商授权记录 ID
+	ProviderConfig   map[string]any `json:"providerConfig,omitempty"` // 质询提供商额外配置
 }
 
 type WorkflowNodeConfigForBizUpload struct {
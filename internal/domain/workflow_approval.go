@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+)
+
+const CollectionNameWorkflowApproval = "workflow_approval"
+
+type WorkflowApproval struct {
+	Meta
+	WorkflowId string                     `db:"workflowRef" json:"workflowId"`
+	RunId      string                     `db:"runRef"      json:"runId"`
+	NodeId     string                     `db:"nodeId"      json:"nodeId"`
+	Status     WorkflowApprovalStatusType `db:"status"      json:"status"`
+	ExpiresAt  time.Time                  `db:"expiresAt"   json:"expiresAt"` // 零值时表示永不过期
+	Operator   string                     `db:"operator"    json:"operator"`
+	Comment    string                     `db:"comment"     json:"comment"`
+	DecidedAt  time.Time                  `db:"decidedAt"   json:"decidedAt"`
+}
+
+type WorkflowApprovalStatusType string
+
+const (
+	WorkflowApprovalStatusTypePending  WorkflowApprovalStatusType = "pending"
+	WorkflowApprovalStatusTypeApproved WorkflowApprovalStatusType = "approved"
+	WorkflowApprovalStatusTypeRejected WorkflowApprovalStatusType = "rejected"
+	WorkflowApprovalStatusTypeTimeout  WorkflowApprovalStatusType = "timeout"
+	WorkflowApprovalStatusTypeCanceled WorkflowApprovalStatusType = "canceled"
+)
+
+func (t WorkflowApprovalStatusType) String() string {
+	return string(t)
+}
+
+// 判断审批是否仍在等待决定。
+func (a *WorkflowApproval) IsPending() bool {
+	return a.Status == WorkflowApprovalStatusTypePending
+}
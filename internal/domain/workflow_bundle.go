@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	WorkflowBundleKind    = "CertimateWorkflowBundle"
+	WorkflowBundleVersion = "1"
+)
+
+// 工作流包，用于在不同的 Certimate 实例之间迁移工作流。
+// 工作流图中引用的授权 ID 将被替换为包内的引用标识，导入时再按名称及提供商重新映射为目标实例中的授权；
+// 引用的私有 CA 同理，按名称重新映射；引用的其他工作流须一并包含在包内，导入时映射为目标实例中的同名工作流。
+type WorkflowBundle struct {
+	Kind       string                     `json:"kind"`
+	Version    string                     `json:"version"`
+	ExportedAt time.Time                  `json:"exportedAt"`
+	Accesses   []*WorkflowBundleAccess    `json:"accesses"`
+	PrivateCAs []*WorkflowBundlePrivateCA `json:"privateCAs,omitempty"`
+	Workflows  []*WorkflowBundleWorkflow  `json:"workflows"`
+}
+
+func (b *WorkflowBundle) Verify() error {
+	if b.Kind != WorkflowBundleKind {
+		return fmt.Errorf("unsupported bundle kind: '%s'", b.Kind)
+	}
+	if b.Version != WorkflowBundleVersion {
+		return fmt.Errorf("unsupported bundle version: '%s'", b.Version)
+	}
+
+	refs := make(map[string]struct{})
+	for _, access := range b.Accesses {
+		if access.Ref == "" {
+			return errors.New("access reference is required")
+		}
+		if _, ok := refs[access.Ref]; ok {
+			return fmt.Errorf("duplicate access reference: '%s'", access.Ref)
+		}
+		if access.Name == "" || access.Provider == "" {
+			return fmt.Errorf("name and provider of access '%s' are required", access.Ref)
+		}
+		refs[access.Ref] = struct{}{}
+	}
+
+	privateCARefs := make(map[string]struct{})
+	for _, privateCA := range b.PrivateCAs {
+		if privateCA.Ref == "" {
+			return errors.New("private ca reference is required")
+		}
+		if _, ok := privateCARefs[privateCA.Ref]; ok {
+			return fmt.Errorf("duplicate private ca reference: '%s'", privateCA.Ref)
+		}
+		if privateCA.Name == "" {
+			return fmt.Errorf("name of private ca '%s' is required", privateCA.Ref)
+		}
+		privateCARefs[privateCA.Ref] = struct{}{}
+	}
+
+	names := make(map[string]struct{})
+	workflowRefs := make(map[string]struct{})
+	for _, workflow := range b.Workflows {
+		if strings.TrimSpace(workflow.Name) == "" {
+			return errors.New("workflow name is required")
+		}
+		if _, ok := names[workflow.Name]; ok {
+			return fmt.Errorf("duplicate workflow name: '%s'", workflow.Name)
+		}
+		if workflow.Graph == nil {
+			return fmt.Errorf("graph of workflow '%s' is required", workflow.Name)
+		}
+		names[workflow.Name] = struct{}{}
+
+		if workflow.Ref != "" {
+			if _, ok := workflowRefs[workflow.Ref]; ok {
+				return fmt.Errorf("duplicate workflow reference: '%s'", workflow.Ref)
+			}
+			workflowRefs[workflow.Ref] = struct{}{}
+		}
+	}
+
+	for _, workflow := range b.Workflows {
+		var err error
+		check := func(declared map[string]struct{}, kind string) func(ref string) string {
+			return func(ref string) string {
+				if _, ok := declared[ref]; !ok && err == nil {
+					err = fmt.Errorf("workflow '%s' references an undeclared %s '%s'", workflow.Name, kind, ref)
+				}
+				return ref
+			}
+		}
+		workflow.Graph.WalkAccessIds(check(refs, "access"))
+		workflow.Graph.WalkPrivateCAIds(check(privateCARefs, "private ca"))
+		workflow.Graph.WalkWorkflowIds(check(workflowRefs, "workflow"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type WorkflowBundleAccess struct {
+	Ref      string `json:"ref"`      // 包内引用标识，工作流图中的授权 ID 将被替换为此标识
+	Name     string `json:"name"`     // 授权名称
+	Provider string `json:"provider"` // 授权提供商
+}
+
+type WorkflowBundlePrivateCA struct {
+	Ref  string `json:"ref"`  // 包内引用标识，工作流图中的私有 CA ID 将被替换为此标识
+	Name string `json:"name"` // 私有 CA 名称
+}
+
+type WorkflowBundleWorkflow struct {
+	Ref         string         `json:"ref,omitempty"` // 包内引用标识，其他工作流图中引用此工作流的 ID 将被替换为此标识
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Enabled     bool           `json:"enabled"`
+	Graph       *WorkflowGraph `json:"graph"`
+}
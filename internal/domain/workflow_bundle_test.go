@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestWorkflowBundle_Verify(t *testing.T) {
+	newGraph := func(config domain.WorkflowNodeConfig) *domain.WorkflowGraph {
+		return &domain.WorkflowGraph{
+			Nodes: []*domain.WorkflowNode{
+				{Id: "start", Type: domain.WorkflowNodeTypeStart},
+				{Id: "call", Type: domain.WorkflowNodeTypeCallWorkflow, Data: domain.WorkflowNodeData{Config: config}},
+				{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+			},
+		}
+	}
+	newBundle := func(config domain.WorkflowNodeConfig) *domain.WorkflowBundle {
+		return &domain.WorkflowBundle{
+			Kind:       domain.WorkflowBundleKind,
+			Version:    domain.WorkflowBundleVersion,
+			Accesses:   []*domain.WorkflowBundleAccess{{Ref: "access_1", Name: "dns", Provider: "cloudflare"}},
+			PrivateCAs: []*domain.WorkflowBundlePrivateCA{{Ref: "privateca_1", Name: "internal"}},
+			Workflows: []*domain.WorkflowBundleWorkflow{
+				{Ref: "workflow_1", Name: "caller", Graph: newGraph(config)},
+				{Ref: "workflow_2", Name: "callee", Graph: newGraph(domain.WorkflowNodeConfig{"workflowId": "workflow_1"})},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		config  domain.WorkflowNodeConfig
+		wantErr string
+	}{
+		{name: "declared workflow reference", config: domain.WorkflowNodeConfig{"workflowId": "workflow_2"}},
+		{name: "self workflow reference", config: domain.WorkflowNodeConfig{"workflowId": "workflow_1"}},
+		{name: "declared access and private ca references", config: domain.WorkflowNodeConfig{"workflowId": "workflow_2", "providerAccessId": "access_1", "caProviderConfig": map[string]any{"privateCAId": "privateca_1"}}},
+		{name: "source workflow id", config: domain.WorkflowNodeConfig{"workflowId": "k2b3x9"}, wantErr: "undeclared workflow 'k2b3x9'"},
+		{name: "undeclared access", config: domain.WorkflowNodeConfig{"workflowId": "workflow_2", "providerAccessId": "access_2"}, wantErr: "undeclared access 'access_2'"},
+		{name: "undeclared private ca", config: domain.WorkflowNodeConfig{"workflowId": "workflow_2", "caProviderConfig": map[string]any{"privateCAId": "x7m1q0"}}, wantErr: "undeclared private ca 'x7m1q0'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newBundle(tc.config).Verify()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("duplicate workflow reference", func(t *testing.T) {
+		bundle := newBundle(domain.WorkflowNodeConfig{"workflowId": "workflow_2"})
+		bundle.Workflows[1].Ref = "workflow_1"
+		assert.ErrorContains(t, bundle.Verify(), "duplicate workflow reference")
+	})
+}
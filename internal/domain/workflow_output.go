@@ -4,12 +4,13 @@ const CollectionNameWorkflowOutput = "workflow_output"
 
 type WorkflowOutput struct {
 	Meta
-	WorkflowId string                 `db:"workflowRef" json:"workflowId"`
-	RunId      string                 `db:"runRef"      json:"runId"`
-	NodeId     string                 `db:"nodeId"      json:"nodeId"`
-	NodeConfig WorkflowNodeConfig     `db:"nodeConfig"  json:"nodeConfig"`
-	Outputs    []*WorkflowOutputEntry `db:"outputs"     json:"outputs"`
-	Succeeded  bool                   `db:"succeeded"   json:"succeeded"`
+	WorkflowId string                    `db:"workflowRef" json:"workflowId"`
+	RunId      string                    `db:"runRef"      json:"runId"`
+	NodeId     string                    `db:"nodeId"      json:"nodeId"`
+	NodeConfig WorkflowNodeConfig        `db:"nodeConfig"  json:"nodeConfig"`
+	Outputs    []*WorkflowOutputEntry    `db:"outputs"     json:"outputs"`
+	Variables  []*WorkflowOutputVariable `db:"variables"   json:"variables"`
+	Succeeded  bool                      `db:"succeeded"   json:"succeeded"`
 }
 
 type WorkflowOutputEntry struct {
@@ -18,3 +19,10 @@ type WorkflowOutputEntry struct {
 	Value     string `json:"value"`
 	ValueType string `json:"valueType"`
 }
+
+type WorkflowOutputVariable struct {
+	Scope     string `json:"scope,omitempty"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	ValueType string `json:"valueType"`
+}
@@ -8,13 +8,24 @@ const CollectionNameWorkflowRun = "workflow_run"
 
 type WorkflowRun struct {
 	Meta
-	WorkflowId string                `db:"workflowRef" json:"workflowId"`
-	Status     WorkflowRunStatusType `db:"status"      json:"status"`
-	Trigger    WorkflowTriggerType   `db:"trigger"     json:"trigger"`
-	StartedAt  time.Time             `db:"startedAt"   json:"startedAt"`
-	EndedAt    time.Time             `db:"endedAt"     json:"endedAt"`
-	Graph      *WorkflowGraph        `db:"graph"       json:"graph"`
-	Error      string                `db:"error"       json:"error"`
+	WorkflowId        string                 `db:"workflowRef"       json:"workflowId"`
+	Status            WorkflowRunStatusType  `db:"status"            json:"status"`
+	Trigger           WorkflowTriggerType    `db:"trigger"           json:"trigger"`
+	StartedAt         time.Time              `db:"startedAt"         json:"startedAt"`
+	EndedAt           time.Time              `db:"endedAt"           json:"endedAt"`
+	Graph             *WorkflowGraph         `db:"graph"             json:"graph"`
+	Error             string                 `db:"error"             json:"error"`
+	ErrorNodeId       string                 `db:"errorNodeId"       json:"errorNodeId"`
+	ResumedFromRunId  string                 `db:"resumedFromRunRef" json:"resumedFromRunId"`
+	ResumedFromNodeId string                 `db:"resumedFromNodeId" json:"resumedFromNodeId"`
+	Variables         []*WorkflowRunVariable `db:"variables"         json:"variables,omitempty"` // 触发时传入的运行变量
+	VersionId         string                 `db:"versionRef"        json:"versionId"`           // 执行的工作流版本
+}
+
+type WorkflowRunVariable struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	ValueType string `json:"valueType"`
 }
 
 type WorkflowRunStatusType string
@@ -26,6 +37,7 @@ func (t WorkflowRunStatusType) String() string {
 const (
 	WorkflowRunStatusTypePending    WorkflowRunStatusType = "pending"
 	WorkflowRunStatusTypeProcessing WorkflowRunStatusType = "processing"
+	WorkflowRunStatusTypeWaiting    WorkflowRunStatusType = "waiting"
 	WorkflowRunStatusTypeSucceeded  WorkflowRunStatusType = "succeeded"
 	WorkflowRunStatusTypeFailed     WorkflowRunStatusType = "failed"
 	WorkflowRunStatusTypeCanceled   WorkflowRunStatusType = "canceled"
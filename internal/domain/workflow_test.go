@@ -0,0 +1,139 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestWorkflowGraph_GetNodeById(t *testing.T) {
+	graph := &domain.WorkflowGraph{
+		Nodes: []*domain.WorkflowNode{
+			{Id: "start", Type: domain.WorkflowNodeTypeStart},
+			{Id: "loop", Type: domain.WorkflowNodeTypeForEach, Blocks: []*domain.WorkflowNode{
+				{Id: "apply", Type: domain.WorkflowNodeTypeBizApply},
+			}},
+			{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		nodeId     string
+		expectedId string
+	}{
+		{name: "top-level node", nodeId: "loop", expectedId: "loop"},
+		{name: "nested node", nodeId: "apply", expectedId: "apply"},
+		{name: "iteration node", nodeId: "apply#1", expectedId: "apply"},
+		{name: "nested iteration node", nodeId: "apply#1#2", expectedId: "apply"},
+		{name: "non-existent node", nodeId: "deploy#1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node, ok := graph.GetNodeById(tc.nodeId)
+			if tc.expectedId == "" {
+				assert.False(t, ok)
+				return
+			}
+
+			if assert.True(t, ok) {
+				assert.Equal(t, tc.expectedId, node.Id)
+			}
+		})
+	}
+}
+
+func TestGetWorkflowTemplateNodeId(t *testing.T) {
+	assert.Equal(t, "apply", domain.GetWorkflowTemplateNodeId("apply"))
+	assert.Equal(t, "apply", domain.GetWorkflowTemplateNodeId("apply#0"))
+	assert.Equal(t, "apply", domain.GetWorkflowTemplateNodeId("apply#0#3"))
+}
+
+func TestWorkflowGraph_WalkRefIds(t *testing.T) {
+	graph := &domain.WorkflowGraph{
+		Nodes: []*domain.WorkflowNode{
+			{Id: "start", Type: domain.WorkflowNodeTypeStart, Data: domain.WorkflowNodeData{
+				Config: domain.WorkflowNodeConfig{"trigger": "event", "eventWorkflowId": "wf_source"},
+			}},
+			{Id: "loop", Type: domain.WorkflowNodeTypeForEach, Blocks: []*domain.WorkflowNode{
+				{Id: "apply", Type: domain.WorkflowNodeTypeBizApply, Data: domain.WorkflowNodeData{
+					Config: domain.WorkflowNodeConfig{
+						"caProviderAccessId": "access_ca",
+						"caProviderConfig":   map[string]any{"privateCAId": "ca_1"},
+					},
+				}},
+				{Id: "call", Type: domain.WorkflowNodeTypeCallWorkflow, Data: domain.WorkflowNodeData{
+					Config: domain.WorkflowNodeConfig{"workflowId": "wf_callee"},
+				}},
+			}},
+			{Id: "deploy", Type: domain.WorkflowNodeTypeBizDeploy, Data: domain.WorkflowNodeData{
+				Config: domain.WorkflowNodeConfig{"workflowId": "not_a_ref"},
+			}},
+			{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+		},
+	}
+
+	collect := func(walk func(fn func(string) string)) []string {
+		ids := make([]string, 0)
+		walk(func(id string) string {
+			ids = append(ids, id)
+			return "new_" + id
+		})
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"access_ca"}, collect(graph.WalkAccessIds))
+	assert.ElementsMatch(t, []string{"ca_1"}, collect(graph.WalkPrivateCAIds))
+	assert.ElementsMatch(t, []string{"wf_source", "wf_callee"}, collect(graph.WalkWorkflowIds))
+
+	assert.Equal(t, "new_wf_source", graph.Nodes[0].Data.Config["eventWorkflowId"])
+	assert.Equal(t, "new_wf_callee", graph.Nodes[1].Blocks[1].Data.Config["workflowId"])
+	assert.Equal(t, "new_ca_1", graph.Nodes[1].Blocks[0].Data.Config["caProviderConfig"].(map[string]any)["privateCAId"])
+	assert.Equal(t, "not_a_ref", graph.Nodes[2].Data.Config["workflowId"])
+}
+
+func TestWorkflowGraph_Verify_BizApplyProviderMappings(t *testing.T) {
+	newGraph := func(challengeType string, provider string) *domain.WorkflowGraph {
+		return &domain.WorkflowGraph{
+			Nodes: []*domain.WorkflowNode{
+				{Id: "start", Type: domain.WorkflowNodeTypeStart},
+				{Id: "apply", Type: domain.WorkflowNodeTypeBizApply, Data: domain.WorkflowNodeData{
+					Config: domain.WorkflowNodeConfig{
+						"challengeType": challengeType,
+						"provider":      "cloudflare",
+						"providerMappings": []any{
+							map[string]any{"zone": "example.com", "provider": provider},
+						},
+					},
+				}},
+				{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		challengeType string
+		provider      string
+		wantErr       bool
+	}{
+		{name: "dns-01 with dns-01 provider", challengeType: "dns-01", provider: "aliyun-dns"},
+		{name: "dns-01 with http-01 provider", challengeType: "dns-01", provider: "s3", wantErr: true},
+		{name: "http-01 with http-01 provider", challengeType: "http-01", provider: "ssh"},
+		{name: "http-01 with dns-01 provider", challengeType: "http-01", provider: "aliyun-dns", wantErr: true},
+		{name: "tls-alpn-01 with tls-alpn-01 provider", challengeType: "tls-alpn-01", provider: "local"},
+		{name: "tls-alpn-01 with http-01 provider", challengeType: "tls-alpn-01", provider: "ftp", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newGraph(tc.challengeType, tc.provider).Verify()
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "mixing challenge types is not supported")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
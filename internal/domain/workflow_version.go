@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const CollectionNameWorkflowVersion = "workflow_version"
+
+type WorkflowVersion struct {
+	Meta
+	WorkflowId string         `db:"workflowRef" json:"workflowId"`
+	Version    int            `db:"version"     json:"version"` // 版本号，在同一工作流中自 1 起递增
+	Graph      *WorkflowGraph `db:"graph"       json:"graph"`
+	Author     string         `db:"author"      json:"author"`  // 发布者
+	Comment    string         `db:"comment"     json:"comment"` // 发布说明
+}
+
+type WorkflowGraphDiff struct {
+	AddedNodes   []*WorkflowGraphDiffNode `json:"addedNodes"`
+	RemovedNodes []*WorkflowGraphDiffNode `json:"removedNodes"`
+	ChangedNodes []*WorkflowGraphDiffNode `json:"changedNodes"`
+}
+
+type WorkflowGraphDiffNode struct {
+	Id      string                     `json:"id"`
+	Type    WorkflowNodeType           `json:"type"`
+	Name    string                     `json:"name"`
+	Changes []*WorkflowGraphDiffChange `json:"changes,omitempty"` // 仅适用于变更的节点
+}
+
+type WorkflowGraphDiffChange struct {
+	Path     string                      `json:"path"` // 变更的属性路径，以半角句点分隔，如 "name"、"config.providerConfig.host"
+	Type     WorkflowGraphDiffChangeType `json:"type"`
+	OldValue any                         `json:"oldValue,omitempty"`
+	NewValue any                         `json:"newValue,omitempty"`
+}
+
+type WorkflowGraphDiffChangeType string
+
+func (t WorkflowGraphDiffChangeType) String() string {
+	return string(t)
+}
+
+const (
+	WorkflowGraphDiffChangeTypeAdded    = WorkflowGraphDiffChangeType("added")
+	WorkflowGraphDiffChangeTypeRemoved  = WorkflowGraphDiffChangeType("removed")
+	WorkflowGraphDiffChangeTypeModified = WorkflowGraphDiffChangeType("modified")
+)
+
+// 差异中被视为敏感信息的配置项名称，其值将被遮蔽。
+var reWorkflowGraphDiffSensitiveKey = regexp.MustCompile(`(?i)(password|passwd|passphrase|secret|token|credential|privatekey|apikey|accesskey)`)
+
+// 用于遮蔽敏感配置项的文本。
+const workflowGraphDiffMask = "******"
+
+type workflowGraphFlatNode struct {
+	node     *WorkflowNode
+	parentId string
+}
+
+// 比较两个工作流图的结构差异，结果中的节点按其在图中的先后顺序排列。
+func DiffWorkflowGraphs(oldGraph *WorkflowGraph, newGraph *WorkflowGraph) *WorkflowGraphDiff {
+	oldNodes, oldOrder := flattenWorkflowGraph(oldGraph)
+	newNodes, newOrder := flattenWorkflowGraph(newGraph)
+
+	diff := &WorkflowGraphDiff{
+		AddedNodes:   make([]*WorkflowGraphDiffNode, 0),
+		RemovedNodes: make([]*WorkflowGraphDiffNode, 0),
+		ChangedNodes: make([]*WorkflowGraphDiffNode, 0),
+	}
+
+	for _, nodeId := range newOrder {
+		newNode := newNodes[nodeId]
+		oldNode, ok := oldNodes[nodeId]
+		if !ok {
+			diff.AddedNodes = append(diff.AddedNodes, &WorkflowGraphDiffNode{Id: nodeId, Type: newNode.node.Type, Name: newNode.node.Data.Name})
+			continue
+		}
+
+		if changes := diffWorkflowNodes(oldNode, newNode); len(changes) > 0 {
+			diff.ChangedNodes = append(diff.ChangedNodes, &WorkflowGraphDiffNode{Id: nodeId, Type: newNode.node.Type, Name: newNode.node.Data.Name, Changes: changes})
+		}
+	}
+
+	for _, nodeId := range oldOrder {
+		if _, ok := newNodes[nodeId]; !ok {
+			oldNode := oldNodes[nodeId]
+			diff.RemovedNodes = append(diff.RemovedNodes, &WorkflowGraphDiffNode{Id: nodeId, Type: oldNode.node.Type, Name: oldNode.node.Data.Name})
+		}
+	}
+
+	return diff
+}
+
+func flattenWorkflowGraph(graph *WorkflowGraph) (map[string]*workflowGraphFlatNode, []string) {
+	nodes := make(map[string]*workflowGraphFlatNode)
+	order := make([]string, 0)
+	if graph == nil {
+		return nodes, order
+	}
+
+	var walk func(blocks []*WorkflowNode, parentId string)
+	walk = func(blocks []*WorkflowNode, parentId string) {
+		for _, node := range blocks {
+			nodes[node.Id] = &workflowGraphFlatNode{node: node, parentId: parentId}
+			order = append(order, node.Id)
+			walk(node.Blocks, node.Id)
+		}
+	}
+	walk(graph.Nodes, "")
+
+	return nodes, order
+}
+
+func diffWorkflowNodes(oldNode *workflowGraphFlatNode, newNode *workflowGraphFlatNode) []*WorkflowGraphDiffChange {
+	oldValues := make(map[string]any)
+	newValues := make(map[string]any)
+	for _, item := range []struct {
+		node   *workflowGraphFlatNode
+		values map[string]any
+	}{
+		{oldNode, oldValues},
+		{newNode, newValues},
+	} {
+		item.values["type"] = item.node.node.Type.String()
+		item.values["parentId"] = item.node.parentId
+		item.values["name"] = item.node.node.Data.Name
+		item.values["disabled"] = item.node.node.Data.Disabled
+		item.values["timeout"] = item.node.node.Data.Timeout
+		if item.node.node.Data.Retry != nil {
+			flattenWorkflowNodeValue(item.values, "retry", normalizeWorkflowNodeValue(item.node.node.Data.Retry))
+		}
+		flattenWorkflowNodeValue(item.values, "config", normalizeWorkflowNodeValue(item.node.node.Data.Config))
+	}
+
+	paths := lo.Uniq(append(lo.Keys(oldValues), lo.Keys(newValues)...))
+	slices.Sort(paths)
+
+	changes := make([]*WorkflowGraphDiffChange, 0)
+	for _, path := range paths {
+		oldValue, oldOk := oldValues[path]
+		newValue, newOk := newValues[path]
+
+		var change *WorkflowGraphDiffChange
+		switch {
+		case !oldOk:
+			change = &WorkflowGraphDiffChange{Path: path, Type: WorkflowGraphDiffChangeTypeAdded, NewValue: newValue}
+		case !newOk:
+			change = &WorkflowGraphDiffChange{Path: path, Type: WorkflowGraphDiffChangeTypeRemoved, OldValue: oldValue}
+		case !reflect.DeepEqual(oldValue, newValue):
+			change = &WorkflowGraphDiffChange{Path: path, Type: WorkflowGraphDiffChangeTypeModified, OldValue: oldValue, NewValue: newValue}
+		default:
+			continue
+		}
+
+		if isWorkflowGraphDiffSensitivePath(path) {
+			if change.OldValue != nil {
+				change.OldValue = workflowGraphDiffMask
+			}
+			if change.NewValue != nil {
+				change.NewValue = workflowGraphDiffMask
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// 将值经 JSON 序列化后还原，以消除不同来源的值（如 int 与 float64）之间的类型差异。
+func normalizeWorkflowNodeValue(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// 将嵌套的映射展开为以半角句点分隔路径的键值对，列表及其他值作为整体比较。
+func flattenWorkflowNodeValue(values map[string]any, path string, value any) {
+	if m, ok := value.(map[string]any); ok {
+		for k, v := range m {
+			flattenWorkflowNodeValue(values, path+"."+k, v)
+		}
+		return
+	}
+
+	if value != nil {
+		values[path] = value
+	}
+}
+
+func isWorkflowGraphDiffSensitivePath(path string) bool {
+	segments := strings.Split(path, ".")
+	return reWorkflowGraphDiffSensitiveKey.MatchString(segments[len(segments)-1])
+}
@@ -0,0 +1,294 @@
+package privateca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+type NameConstraints struct {
+	PermittedDNSDomains []string
+	ExcludedDNSDomains  []string
+	PermittedIPRanges   []string // CIDR 格式
+	ExcludedIPRanges    []string // CIDR 格式
+}
+
+type GenerateOptions struct {
+	Name                 string
+	CommonName           string
+	Organization         string
+	KeyAlgorithm         domain.CertificateKeyAlgorithmType
+	RootValidity         time.Duration
+	IntermediateValidity time.Duration
+	NameConstraints      *NameConstraints
+}
+
+// 生成根证书及中间证书，并加密保存其私钥。
+func Generate(options *GenerateOptions) (*domain.PrivateCA, error) {
+	if options == nil {
+		return nil, fmt.Errorf("the options is nil")
+	}
+	if options.CommonName == "" {
+		return nil, fmt.Errorf("the common name is empty")
+	}
+
+	keyType := options.KeyAlgorithm.LegoKeyType()
+	if keyType == "" {
+		keyType = certcrypto.EC384
+	}
+
+	rootValidity := options.RootValidity
+	if rootValidity <= 0 {
+		rootValidity = time.Hour * 24 * 365 * 20
+	}
+
+	intermediateValidity := options.IntermediateValidity
+	if intermediateValidity <= 0 {
+		intermediateValidity = time.Hour * 24 * 365 * 5
+	}
+	if intermediateValidity > rootValidity {
+		intermediateValidity = rootValidity
+	}
+
+	now := time.Now()
+
+	rootKey, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate root private key: %w", err)
+	}
+
+	rootTemplate := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   options.CommonName + " Root CA",
+			Organization: lo.Ternary(options.Organization != "", []string{options.Organization}, nil),
+		},
+		NotBefore:             now.Add(-time.Minute * 5),
+		NotAfter:              now.Add(rootValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+	}
+	rootCert, err := signCertificate(rootTemplate, rootKey.Public(), nil, rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign root certificate: %w", err)
+	}
+
+	intermediateKey, err := certcrypto.GeneratePrivateKey(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate intermediate private key: %w", err)
+	}
+
+	intermediateTemplate := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   options.CommonName + " Intermediate CA",
+			Organization: lo.Ternary(options.Organization != "", []string{options.Organization}, nil),
+		},
+		NotBefore:             now.Add(-time.Minute * 5),
+		NotAfter:              now.Add(intermediateValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	if err := applyNameConstraints(intermediateTemplate, options.NameConstraints); err != nil {
+		return nil, err
+	}
+	intermediateCert, err := signCertificate(intermediateTemplate, intermediateKey.Public(), rootCert, rootKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign intermediate certificate: %w", err)
+	}
+
+	return buildPrivateCA(options.Name, rootCert, rootKey, intermediateCert, intermediateKey)
+}
+
+type ImportOptions struct {
+	Name                    string
+	RootCertificate         string
+	RootPrivateKey          string
+	IntermediateCertificate string
+	IntermediatePrivateKey  string
+}
+
+// 导入已有的根证书及中间证书，并加密保存其私钥。
+// 根证书私钥可为空（即离线保存），但此时必须提供中间证书及其私钥。
+func Import(options *ImportOptions) (*domain.PrivateCA, error) {
+	if options == nil {
+		return nil, fmt.Errorf("the options is nil")
+	}
+
+	rootCert, err := xcert.ParseCertificateFromPEM(options.RootCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse root certificate: %w", err)
+	} else if !rootCert.IsCA {
+		return nil, fmt.Errorf("the root certificate is not a ca certificate")
+	}
+
+	var rootKey crypto.Signer
+	if options.RootPrivateKey != "" {
+		rootKey, err = parseMatchingPrivateKey(options.RootPrivateKey, rootCert)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root private key: %w", err)
+		}
+	}
+
+	var intermediateCert *x509.Certificate
+	var intermediateKey crypto.Signer
+	if options.IntermediateCertificate != "" {
+		intermediateCert, err = xcert.ParseCertificateFromPEM(options.IntermediateCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse intermediate certificate: %w", err)
+		} else if !intermediateCert.IsCA {
+			return nil, fmt.Errorf("the intermediate certificate is not a ca certificate")
+		} else if err := intermediateCert.CheckSignatureFrom(rootCert); err != nil {
+			return nil, fmt.Errorf("the intermediate certificate is not issued by the root certificate: %w", err)
+		}
+
+		if options.IntermediatePrivateKey == "" {
+			return nil, fmt.Errorf("the intermediate private key is empty")
+		}
+
+		intermediateKey, err = parseMatchingPrivateKey(options.IntermediatePrivateKey, intermediateCert)
+		if err != nil {
+			return nil, fmt.Errorf("invalid intermediate private key: %w", err)
+		}
+	} else if rootKey == nil {
+		return nil, fmt.Errorf("either the root private key or the intermediate certificate is required")
+	}
+
+	return buildPrivateCA(options.Name, rootCert, rootKey, intermediateCert, intermediateKey)
+}
+
+func buildPrivateCA(name string, rootCert *x509.Certificate, rootKey crypto.Signer, intermediateCert *x509.Certificate, intermediateKey crypto.Signer) (*domain.PrivateCA, error) {
+	privateCA := &domain.PrivateCA{Name: name}
+	if privateCA.Name == "" {
+		privateCA.Name = rootCert.Subject.CommonName
+	}
+
+	if certPEM, err := xcert.ConvertCertificateToPEM(rootCert); err != nil {
+		return nil, err
+	} else {
+		privateCA.RootCertificate = certPEM
+	}
+
+	if rootKey != nil {
+		if keyPEM, err := xcert.ConvertPrivateKeyToPEM(rootKey, false); err != nil {
+			return nil, err
+		} else if keyEnc, err := encryptPrivateKey(keyPEM); err != nil {
+			return nil, err
+		} else {
+			privateCA.RootPrivateKey = keyEnc
+		}
+	}
+
+	if intermediateCert != nil {
+		if certPEM, err := xcert.ConvertCertificateToPEM(intermediateCert); err != nil {
+			return nil, err
+		} else {
+			privateCA.IntermediateCertificate = certPEM
+		}
+
+		if keyPEM, err := xcert.ConvertPrivateKeyToPEM(intermediateKey, false); err != nil {
+			return nil, err
+		} else if keyEnc, err := encryptPrivateKey(keyPEM); err != nil {
+			return nil, err
+		} else {
+			privateCA.IntermediatePrivateKey = keyEnc
+		}
+	}
+
+	return privateCA, nil
+}
+
+func signCertificate(template *x509.Certificate, pubkey crypto.PublicKey, parent *x509.Certificate, signer crypto.Signer) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template.SerialNumber = serialNumber
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pubkey, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+func applyNameConstraints(template *x509.Certificate, constraints *NameConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+
+	parseIPRanges := func(ranges []string) ([]*net.IPNet, error) {
+		ipnets := make([]*net.IPNet, 0, len(ranges))
+		for _, r := range ranges {
+			r = strings.TrimSpace(r)
+			if r == "" {
+				continue
+			}
+
+			_, ipnet, err := net.ParseCIDR(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ip range '%s': %w", r, err)
+			}
+
+			ipnets = append(ipnets, ipnet)
+		}
+		return ipnets, nil
+	}
+
+	template.PermittedDNSDomains = compactStrings(constraints.PermittedDNSDomains)
+	template.ExcludedDNSDomains = compactStrings(constraints.ExcludedDNSDomains)
+	if ipnets, err := parseIPRanges(constraints.PermittedIPRanges); err != nil {
+		return err
+	} else {
+		template.PermittedIPRanges = ipnets
+	}
+	if ipnets, err := parseIPRanges(constraints.ExcludedIPRanges); err != nil {
+		return err
+	} else {
+		template.ExcludedIPRanges = ipnets
+	}
+
+	template.PermittedDNSDomainsCritical = len(template.PermittedDNSDomains) > 0 || len(template.PermittedIPRanges) > 0
+	return nil
+}
+
+func parseMatchingPrivateKey(privkeyPEM string, cert *x509.Certificate) (crypto.Signer, error) {
+	privkey, err := xcert.ParsePrivateKeyFromPEM(privkeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privkey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key")
+	}
+
+	if pubkey, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !pubkey.Equal(signer.Public()) {
+		return nil, fmt.Errorf("the private key does not match the certificate")
+	}
+
+	return signer, nil
+}
+
+func compactStrings(s []string) []string {
+	return lo.Compact(lo.Map(s, func(v string, _ int) string { return strings.TrimSpace(v) }))
+}
@@ -0,0 +1,274 @@
+package privateca
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
+)
+
+const defaultValidity = time.Hour * 24 * 90
+
+var keyUsageNames = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"encipherOnly":      x509.KeyUsageEncipherOnly,
+	"decipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+var extKeyUsageNames = map[string]x509.ExtKeyUsage{
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+type Issuer struct {
+	privateCA  *domain.PrivateCA
+	issuerCert *x509.Certificate
+	issuerKey  crypto.Signer
+}
+
+func NewIssuer(privateCA *domain.PrivateCA) (*Issuer, error) {
+	if privateCA == nil {
+		return nil, fmt.Errorf("the private ca is nil")
+	}
+
+	issuerCert, err := xcert.ParseCertificateFromPEM(privateCA.GetIssuerCertificate())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issuer certificate: %w", err)
+	}
+
+	if privateCA.GetIssuerPrivateKey() == "" {
+		return nil, fmt.Errorf("the private key of issuer certificate is unavailable")
+	}
+
+	issuerKeyPEM, err := decryptPrivateKey(privateCA.GetIssuerPrivateKey())
+	if err != nil {
+		return nil, err
+	}
+
+	issuerKey, err := parseMatchingPrivateKey(issuerKeyPEM, issuerCert)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer private key: %w", err)
+	}
+
+	return &Issuer{
+		privateCA:  privateCA,
+		issuerCert: issuerCert,
+		issuerKey:  issuerKey,
+	}, nil
+}
+
+type IssueCertificateRequest struct {
+	DomainOrIPs      []string
+	PrivateKeyType   certcrypto.KeyType
+	PrivateKeyPEM    string
+	CSRPEM           string // 如果提供了 CSR，则使用该 CSR 签发证书，且不会返回私钥
+	ValidityNotAfter time.Time
+	NoCommonName     bool
+	KeyUsages        []string
+	ExtKeyUsages     []string
+}
+
+type IssueCertificateResponse struct {
+	FullChainCertificate string
+	IssuerCertificate    string
+	PrivateKey           string
+}
+
+func (i *Issuer) IssueCertificate(ctx context.Context, request *IssueCertificateRequest) (*IssueCertificateResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("the request is nil")
+	}
+
+	var pubkey crypto.PublicKey
+	var privkeyPEM string
+	domainOrIPs := request.DomainOrIPs
+	if request.CSRPEM != "" {
+		csr, err := certcrypto.PemDecodeTox509CSR([]byte(request.CSRPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse csr: %w", err)
+		} else if err := csr.CheckSignature(); err != nil {
+			return nil, fmt.Errorf("failed to verify csr signature: %w", err)
+		}
+
+		if len(domainOrIPs) == 0 {
+			domainOrIPs = certcrypto.ExtractDomainsCSR(csr)
+		}
+
+		pubkey = csr.PublicKey
+	} else {
+		var privkey crypto.Signer
+		if request.PrivateKeyPEM != "" {
+			key, err := xcert.ParsePrivateKeyFromPEM(request.PrivateKeyPEM)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse private key: %w", err)
+			} else if signer, ok := key.(crypto.Signer); !ok {
+				return nil, fmt.Errorf("unsupported private key")
+			} else {
+				privkey = signer
+			}
+		} else {
+			key, err := certcrypto.GeneratePrivateKey(request.PrivateKeyType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate private key: %w", err)
+			}
+			privkey = key
+		}
+
+		keyPEM, err := xcert.ConvertPrivateKeyToPEM(privkey, false)
+		if err != nil {
+			return nil, err
+		}
+
+		pubkey = privkey.Public()
+		privkeyPEM = keyPEM
+	}
+
+	if len(domainOrIPs) == 0 {
+		return nil, fmt.Errorf("no domains or ip addresses to issue")
+	}
+
+	keyUsage, err := parseKeyUsages(request.KeyUsages, pubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	extKeyUsage, err := parseExtKeyUsages(request.ExtKeyUsages)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	notAfter := request.ValidityNotAfter
+	if notAfter.IsZero() {
+		notAfter = now.Add(defaultValidity)
+	}
+	if notAfter.After(i.issuerCert.NotAfter) {
+		notAfter = i.issuerCert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		NotBefore:             now.Add(-time.Minute * 5),
+		NotAfter:              notAfter,
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+	}
+	for _, domainOrIP := range domainOrIPs {
+		if ip := net.ParseIP(domainOrIP); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, domainOrIP)
+		}
+	}
+	if !request.NoCommonName {
+		template.Subject = pkix.Name{CommonName: domainOrIPs[0]}
+	}
+
+	leafCert, err := signCertificate(template, pubkey, i.issuerCert, i.issuerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+
+	leafCertPEM, err := xcert.ConvertCertificateToPEM(leafCert)
+	if err != nil {
+		return nil, err
+	}
+
+	issuerCertPEM := strings.TrimSpace(i.privateCA.GetIssuerCertificate())
+	return &IssueCertificateResponse{
+		FullChainCertificate: strings.TrimSpace(leafCertPEM) + "\n\n" + issuerCertPEM + "\n",
+		IssuerCertificate:    issuerCertPEM + "\n",
+		PrivateKey:           privkeyPEM,
+	}, nil
+}
+
+func parseKeyUsages(names []string, pubkey crypto.PublicKey) (x509.KeyUsage, error) {
+	names = compactStrings(names)
+	if len(names) == 0 {
+		keyUsage := x509.KeyUsageDigitalSignature
+		if _, ok := pubkey.(*rsa.PublicKey); ok {
+			keyUsage |= x509.KeyUsageKeyEncipherment
+		}
+		return keyUsage, nil
+	}
+
+	var keyUsage x509.KeyUsage
+	for _, name := range names {
+		ku, ok := keyUsageNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unsupported key usage '%s'", name)
+		}
+		keyUsage |= ku
+	}
+	return keyUsage, nil
+}
+
+func parseExtKeyUsages(names []string) ([]x509.ExtKeyUsage, error) {
+	names = compactStrings(names)
+	if len(names) == 0 {
+		return []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, nil
+	}
+
+	extKeyUsages := make([]x509.ExtKeyUsage, 0, len(names))
+	for _, name := range lo.Uniq(names) {
+		eku, ok := extKeyUsageNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported extended key usage '%s'", name)
+		}
+		extKeyUsages = append(extKeyUsages, eku)
+	}
+	return extKeyUsages, nil
+}
+
+// 在给定的私有 CA 中查找签发了指定证书的一个，并返回其证书链（不含终端证书）。
+// 如果未找到，返回空字符串。
+func findCAChain(certPEM string, privateCAs []*domain.PrivateCA) (string, error) {
+	cert, err := xcert.ParseCertificateFromPEM(certPEM)
+	if err != nil {
+		return "", err
+	}
+
+	for _, privateCA := range privateCAs {
+		issuerCert, err := xcert.ParseCertificateFromPEM(privateCA.GetIssuerCertificate())
+		if err != nil {
+			continue
+		}
+
+		if len(cert.AuthorityKeyId) > 0 && !bytes.Equal(cert.AuthorityKeyId, issuerCert.SubjectKeyId) {
+			continue
+		}
+		if err := cert.CheckSignatureFrom(issuerCert); err != nil {
+			continue
+		}
+
+		chain := make([]string, 0, 2)
+		if privateCA.IntermediateCertificate != "" {
+			chain = append(chain, strings.TrimSpace(privateCA.IntermediateCertificate))
+		}
+		chain = append(chain, strings.TrimSpace(privateCA.RootCertificate))
+		return strings.Join(chain, "\n\n") + "\n", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,107 @@
+package privateca
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/certimate-go/certimate/internal/app"
+	xenv "github.com/certimate-go/certimate/pkg/utils/env"
+)
+
+const (
+	envSecretKey    = "CERTIMATE_PRIVATECA_SECRET"
+	secretKeyFile   = ".privateca_secret"
+	secretKeyLength = 32
+)
+
+var (
+	secretKey     string
+	secretKeyErr  error
+	secretKeyOnce sync.Once
+)
+
+// 获取用于加密 CA 私钥的密钥。
+// 优先读取环境变量，其次复用 PocketBase 的 `--encryptionEnv`，否则在数据目录下自动生成。
+func getSecretKey() (string, error) {
+	secretKeyOnce.Do(func() {
+		if s := xenv.GetString(envSecretKey); s != "" {
+			secretKey = normalizeSecretKey(s)
+			return
+		}
+
+		if envName := app.GetApp().EncryptionEnv(); envName != "" {
+			if s := os.Getenv(envName); s != "" {
+				secretKey = normalizeSecretKey(s)
+				return
+			}
+		}
+
+		path := filepath.Join(app.GetApp().DataDir(), secretKeyFile)
+		if data, err := os.ReadFile(path); err == nil {
+			secretKey = normalizeSecretKey(strings.TrimSpace(string(data)))
+			return
+		} else if !errors.Is(err, os.ErrNotExist) {
+			secretKeyErr = fmt.Errorf("failed to read private ca secret: %w", err)
+			return
+		}
+
+		s := security.RandomString(secretKeyLength)
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			secretKeyErr = fmt.Errorf("failed to write private ca secret: %w", err)
+			return
+		}
+
+		secretKey = s
+	})
+
+	return secretKey, secretKeyErr
+}
+
+// 将任意长度的密钥规范化为 AES-256 所需的 32 字节密钥。
+// 长度恰为 32 的密钥原样使用，否则取其完整的 SHA-256 摘要。
+func normalizeSecretKey(s string) string {
+	if len(s) == secretKeyLength {
+		return s
+	}
+
+	hash := sha256.Sum256([]byte(s))
+	return string(hash[:])
+}
+
+func encryptPrivateKey(privkeyPEM string) (string, error) {
+	if privkeyPEM == "" {
+		return "", nil
+	}
+
+	key, err := getSecretKey()
+	if err != nil {
+		return "", err
+	}
+
+	return security.Encrypt([]byte(privkeyPEM), key)
+}
+
+func decryptPrivateKey(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	key, err := getSecretKey()
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := security.Decrypt(ciphertext, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+
+	return string(plaintext), nil
+}
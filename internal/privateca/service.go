@@ -0,0 +1,109 @@
+package privateca
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type PrivateCAService struct {
+	privateCARepo privateCARepository
+}
+
+func NewPrivateCAService(privateCARepo privateCARepository) *PrivateCAService {
+	return &PrivateCAService{
+		privateCARepo: privateCARepo,
+	}
+}
+
+func (s *PrivateCAService) List(ctx context.Context, req *dtos.PrivateCAListReq) (*dtos.PrivateCAListResp, error) {
+	privateCAs, err := s.privateCARepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCAListResp{Items: privateCAs}, nil
+}
+
+func (s *PrivateCAService) Get(ctx context.Context, req *dtos.PrivateCAGetReq) (*dtos.PrivateCAGetResp, error) {
+	privateCA, err := s.privateCARepo.GetById(ctx, req.PrivateCAId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCAGetResp{PrivateCA: privateCA}, nil
+}
+
+func (s *PrivateCAService) Generate(ctx context.Context, req *dtos.PrivateCAGenerateReq) (*dtos.PrivateCAGenerateResp, error) {
+	privateCA, err := Generate(&GenerateOptions{
+		Name:                 req.Name,
+		CommonName:           req.CommonName,
+		Organization:         req.Organization,
+		KeyAlgorithm:         domain.CertificateKeyAlgorithmType(req.KeyAlgorithm),
+		RootValidity:         time.Duration(req.RootValidityDays) * time.Hour * 24,
+		IntermediateValidity: time.Duration(req.IntermediateValidityDays) * time.Hour * 24,
+		NameConstraints: &NameConstraints{
+			PermittedDNSDomains: req.PermittedDNSDomains,
+			ExcludedDNSDomains:  req.ExcludedDNSDomains,
+			PermittedIPRanges:   req.PermittedIPRanges,
+			ExcludedIPRanges:    req.ExcludedIPRanges,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private ca: %w", err)
+	}
+
+	privateCA, err = s.privateCARepo.Save(ctx, privateCA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCAGenerateResp{Id: privateCA.Id}, nil
+}
+
+func (s *PrivateCAService) Import(ctx context.Context, req *dtos.PrivateCAImportReq) (*dtos.PrivateCAImportResp, error) {
+	privateCA, err := Import(&ImportOptions{
+		Name:                    req.Name,
+		RootCertificate:         req.RootCertificate,
+		RootPrivateKey:          req.RootPrivateKey,
+		IntermediateCertificate: req.IntermediateCertificate,
+		IntermediatePrivateKey:  req.IntermediatePrivateKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import private ca: %w", err)
+	}
+
+	privateCA, err = s.privateCARepo.Save(ctx, privateCA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCAImportResp{Id: privateCA.Id}, nil
+}
+
+func (s *PrivateCAService) Delete(ctx context.Context, req *dtos.PrivateCADeleteReq) (*dtos.PrivateCADeleteResp, error) {
+	privateCA, err := s.privateCARepo.GetById(ctx, req.PrivateCAId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.privateCARepo.Delete(ctx, privateCA); err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrivateCADeleteResp{}, nil
+}
+
+// 查找签发了指定证书的私有 CA，并返回其证书链（不含终端证书）。
+// 如果未找到，返回空字符串。
+func (s *PrivateCAService) GetCAChain(ctx context.Context, certPEM string) (string, error) {
+	privateCAs, err := s.privateCARepo.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return findCAChain(certPEM, privateCAs)
+}
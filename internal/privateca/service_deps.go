@@ -0,0 +1,14 @@
+package privateca
+
+import (
+	"context"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type privateCARepository interface {
+	List(ctx context.Context) ([]*domain.PrivateCA, error)
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+	Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error)
+	Delete(ctx context.Context, privateCA *domain.PrivateCA) error
+}
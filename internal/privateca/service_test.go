@@ -0,0 +1,103 @@
+package privateca
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v5/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type mockPrivateCARepository struct {
+	privateCAs []*domain.PrivateCA
+}
+
+func (r *mockPrivateCARepository) List(ctx context.Context) ([]*domain.PrivateCA, error) {
+	return r.privateCAs, nil
+}
+
+func (r *mockPrivateCARepository) GetById(ctx context.Context, id string) (*domain.PrivateCA, error) {
+	for _, privateCA := range r.privateCAs {
+		if privateCA.Id == id {
+			return privateCA, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockPrivateCARepository) Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error) {
+	r.privateCAs = append(r.privateCAs, privateCA)
+	return privateCA, nil
+}
+
+func (r *mockPrivateCARepository) Delete(ctx context.Context, privateCA *domain.PrivateCA) error {
+	for i, item := range r.privateCAs {
+		if item.Id == privateCA.Id {
+			r.privateCAs = append(r.privateCAs[:i], r.privateCAs[i+1:]...)
+			return nil
+		}
+	}
+
+	return domain.ErrRecordNotFound
+}
+
+func mustGeneratePrivateCA(t *testing.T, id string, commonName string) *domain.PrivateCA {
+	t.Helper()
+
+	privateCA, err := Generate(&GenerateOptions{Name: commonName, CommonName: commonName})
+	require.NoError(t, err)
+
+	privateCA.Id = id
+	return privateCA
+}
+
+func TestPrivateCAService_GetCAChain(t *testing.T) {
+	ctx := context.Background()
+	ca1 := mustGeneratePrivateCA(t, "ca_1", "Test CA 1")
+	ca2 := mustGeneratePrivateCA(t, "ca_2", "Test CA 2")
+	svc := NewPrivateCAService(&mockPrivateCARepository{privateCAs: []*domain.PrivateCA{ca1, ca2}})
+
+	issuer, err := NewIssuer(ca2)
+	require.NoError(t, err)
+	issued, err := issuer.IssueCertificate(ctx, &IssueCertificateRequest{
+		DomainOrIPs:    []string{"example.com"},
+		PrivateKeyType: certcrypto.EC256,
+	})
+	require.NoError(t, err)
+
+	chain, err := svc.GetCAChain(ctx, issued.FullChainCertificate)
+	require.NoError(t, err)
+	assert.Contains(t, chain, strings.TrimSpace(ca2.IntermediateCertificate))
+	assert.Contains(t, chain, strings.TrimSpace(ca2.RootCertificate))
+	assert.NotContains(t, chain, strings.TrimSpace(ca1.RootCertificate))
+
+	svc = NewPrivateCAService(&mockPrivateCARepository{privateCAs: []*domain.PrivateCA{ca1}})
+	chain, err = svc.GetCAChain(ctx, issued.FullChainCertificate)
+	require.NoError(t, err)
+	assert.Empty(t, chain)
+}
+
+func TestPrivateCAService_Delete(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockPrivateCARepository{privateCAs: []*domain.PrivateCA{{Meta: domain.Meta{Id: "ca_1"}}, {Meta: domain.Meta{Id: "ca_2"}}}}
+	svc := NewPrivateCAService(repo)
+
+	_, err := svc.Delete(ctx, &dtos.PrivateCADeleteReq{PrivateCAId: "ca_1"})
+	require.NoError(t, err)
+
+	res, err := svc.List(ctx, &dtos.PrivateCAListReq{})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	assert.Equal(t, "ca_2", res.Items[0].Id)
+
+	_, err = svc.Get(ctx, &dtos.PrivateCAGetReq{PrivateCAId: "ca_1"})
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+	_, err = svc.Delete(ctx, &dtos.PrivateCADeleteReq{PrivateCAId: "ca_1"})
+	assert.ErrorIs(t, err, domain.ErrRecordNotFound)
+}
@@ -20,10 +20,24 @@ func NewACMEAccountRepository() *ACMEAccountRepository {
 	return &ACMEAccountRepository{}
 }
 
+func (r *ACMEAccountRepository) GetById(ctx context.Context, id string) (*domain.ACMEAccount, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameACMEAccount, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
 func (r *ACMEAccountRepository) GetByCAAndEmail(ctx context.Context, ca, caDirUrl, email string) (*domain.ACMEAccount, error) {
-	record, err := app.GetApp().FindFirstRecordByFilter(
+	records, err := app.GetApp().FindRecordsByFilter(
 		domain.CollectionNameACMEAccount,
 		"ca={:ca} && acmeDirUrl={:acmeDirUrl} && email={:email}",
+		"-created",
+		0, 0,
 		dbx.Params{"ca": ca, "acmeDirUrl": caDirUrl, "email": email},
 	)
 	if err != nil {
@@ -33,7 +47,19 @@ func (r *ACMEAccountRepository) GetByCAAndEmail(ctx context.Context, ca, caDirUr
 		return nil, err
 	}
 
-	return r.castRecordToModel(record)
+	// 已停用的账户无法再用于申请证书，需跳过
+	for _, record := range records {
+		acmeAccount, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		if !acmeAccount.IsDeactivated() {
+			return acmeAccount, nil
+		}
+	}
+
+	return nil, domain.ErrRecordNotFound
 }
 
 func (r *ACMEAccountRepository) GetByCAAndAcctUrl(ctx context.Context, ca string, acctUrl string) (*domain.ACMEAccount, error) {
@@ -74,6 +100,7 @@ func (r *ACMEAccountRepository) Save(ctx context.Context, acmeAccount *domain.AC
 	record.Set("ca", acmeAccount.CA)
 	record.Set("email", acmeAccount.Email)
 	record.Set("privateKey", acmeAccount.PrivateKey)
+	record.Set("pendingPrivateKey", acmeAccount.PendingPrivateKey)
 	record.Set("acmeDirUrl", acmeAccount.ACMEDirectoryUrl)
 	record.Set("acmeAcctUrl", acmeAccount.ACMEAccountUrl)
 	record.Set("resourceObj", acmeAccount.ResourceObject)
@@ -103,12 +130,13 @@ func (r *ACMEAccountRepository) castRecordToModel(record *core.Record) (*domain.
 			CreatedAt: record.GetDateTime("created").Time(),
 			UpdatedAt: record.GetDateTime("updated").Time(),
 		},
-		CA:               record.GetString("ca"),
-		Email:            record.GetString("email"),
-		PrivateKey:       record.GetString("privateKey"),
-		ACMEDirectoryUrl: record.GetString("acmeDirUrl"),
-		ACMEAccountUrl:   record.GetString("acmeAcctUrl"),
-		ResourceObject:   resourceObj,
+		CA:                record.GetString("ca"),
+		Email:             record.GetString("email"),
+		PrivateKey:        record.GetString("privateKey"),
+		PendingPrivateKey: record.GetString("pendingPrivateKey"),
+		ACMEDirectoryUrl:  record.GetString("acmeDirUrl"),
+		ACMEAccountUrl:    record.GetString("acmeAcctUrl"),
+		ResourceObject:    resourceObj,
 	}
 	return acmeAccount, nil
 }
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type ACMEServerClientRepository struct{}
+
+func NewACMEServerClientRepository() *ACMEServerClientRepository {
+	return &ACMEServerClientRepository{}
+}
+
+func (r *ACMEServerClientRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerClient, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameACMEServerClient, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerClientRepository) GetByEABKid(ctx context.Context, eabKid string) (*domain.ACMEServerClient, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameACMEServerClient,
+		"eabKid={:eabKid}",
+		dbx.Params{"eabKid": eabKid},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerClientRepository) GetByAccountKeyThumbprint(ctx context.Context, thumbprint string) (*domain.ACMEServerClient, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameACMEServerClient,
+		"accountKeyThumbprint={:thumbprint}",
+		dbx.Params{"thumbprint": thumbprint},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerClientRepository) Save(ctx context.Context, client *domain.ACMEServerClient) (*domain.ACMEServerClient, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameACMEServerClient)
+	if err != nil {
+		return client, err
+	}
+
+	var record *core.Record
+	if client.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, client.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return client, domain.ErrRecordNotFound
+			}
+			return client, err
+		}
+	}
+
+	record.Set("name", client.Name)
+	record.Set("eabKid", client.EABKid)
+	record.Set("eabHmacKey", client.EABHmacKey)
+	record.Set("config", client.Config)
+	record.Set("accountKey", client.AccountKey)
+	record.Set("accountKeyThumbprint", client.AccountKeyThumbprint)
+	record.Set("accountStatus", client.AccountStatus.String())
+	record.Set("accountContact", client.AccountContact)
+	record.Set("enabled", client.Enabled)
+	if err := app.GetApp().Save(record); err != nil {
+		return client, err
+	}
+
+	client.Id = record.Id
+	client.CreatedAt = record.GetDateTime("created").Time()
+	client.UpdatedAt = record.GetDateTime("updated").Time()
+	return client, nil
+}
+
+func (r *ACMEServerClientRepository) castRecordToModel(record *core.Record) (*domain.ACMEServerClient, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	var config *domain.ACMEServerClientConfig
+	if err := record.UnmarshalJSONField("config", &config); err != nil {
+		return nil, fmt.Errorf("field 'config' is malformed")
+	}
+
+	accountContact := make([]string, 0)
+	if err := record.UnmarshalJSONField("accountContact", &accountContact); err != nil {
+		return nil, fmt.Errorf("field 'accountContact' is malformed")
+	}
+
+	client := &domain.ACMEServerClient{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Name:                 record.GetString("name"),
+		EABKid:               record.GetString("eabKid"),
+		EABHmacKey:           record.GetString("eabHmacKey"),
+		Config:               config,
+		AccountKey:           record.GetString("accountKey"),
+		AccountKeyThumbprint: record.GetString("accountKeyThumbprint"),
+		AccountStatus:        domain.ACMEServerAccountStatusType(record.GetString("accountStatus")),
+		AccountContact:       accountContact,
+		Enabled:              record.GetBool("enabled"),
+	}
+	return client, nil
+}
+
+type ACMEServerOrderRepository struct{}
+
+func NewACMEServerOrderRepository() *ACMEServerOrderRepository {
+	return &ACMEServerOrderRepository{}
+}
+
+func (r *ACMEServerOrderRepository) GetById(ctx context.Context, id string) (*domain.ACMEServerOrder, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameACMEServerOrder, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *ACMEServerOrderRepository) ListByClientId(ctx context.Context, clientId string) ([]*domain.ACMEServerOrder, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameACMEServerOrder,
+		"clientRef={:clientId}",
+		"-created",
+		0, 0,
+		dbx.Params{"clientId": clientId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.castRecordsToModels(records)
+}
+
+func (r *ACMEServerOrderRepository) ListByStatus(ctx context.Context, status string) ([]*domain.ACMEServerOrder, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameACMEServerOrder,
+		"status={:status}",
+		"created",
+		0, 0,
+		dbx.Params{"status": status},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.castRecordsToModels(records)
+}
+
+func (r *ACMEServerOrderRepository) Save(ctx context.Context, order *domain.ACMEServerOrder) (*domain.ACMEServerOrder, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameACMEServerOrder)
+	if err != nil {
+		return order, err
+	}
+
+	var record *core.Record
+	if order.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, order.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return order, domain.ErrRecordNotFound
+			}
+			return order, err
+		}
+	}
+
+	record.Set("clientRef", order.ClientId)
+	record.Set("status", order.Status)
+	record.Set("expiresAt", order.ExpiresAt)
+	record.Set("identifiers", order.Identifiers)
+	record.Set("authorizations", order.Authorizations)
+	record.Set("certificateRef", order.CertificateId)
+	record.Set("error", order.Error)
+	if err := app.GetApp().Save(record); err != nil {
+		return order, err
+	}
+
+	order.Id = record.Id
+	order.CreatedAt = record.GetDateTime("created").Time()
+	order.UpdatedAt = record.GetDateTime("updated").Time()
+	return order, nil
+}
+
+// 删除已过期的订单，返回删除的数量。
+func (r *ACMEServerOrderRepository) DeleteExpired(ctx context.Context) (int, error) {
+	records, err := app.GetApp().FindAllRecords(
+		domain.CollectionNameACMEServerOrder,
+		dbx.NewExp("expiresAt<{:now}", dbx.Params{"now": types.NowDateTime().String()}),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var ret int
+	var errs []error
+	for _, record := range records {
+		if err := app.GetApp().Delete(record); err != nil {
+			errs = append(errs, err)
+		} else {
+			ret++
+		}
+	}
+
+	if len(errs) > 0 {
+		return ret, errors.Join(errs...)
+	}
+
+	return ret, nil
+}
+
+func (r *ACMEServerOrderRepository) castRecordsToModels(records []*core.Record) ([]*domain.ACMEServerOrder, error) {
+	orders := make([]*domain.ACMEServerOrder, 0, len(records))
+	for _, record := range records {
+		order, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+func (r *ACMEServerOrderRepository) castRecordToModel(record *core.Record) (*domain.ACMEServerOrder, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	identifiers := make([]domain.ACMEServerIdentifier, 0)
+	if err := record.UnmarshalJSONField("identifiers", &identifiers); err != nil {
+		return nil, fmt.Errorf("field 'identifiers' is malformed")
+	}
+
+	authorizations := make([]*domain.ACMEServerAuthorization, 0)
+	if err := record.UnmarshalJSONField("authorizations", &authorizations); err != nil {
+		return nil, fmt.Errorf("field 'authorizations' is malformed")
+	}
+
+	order := &domain.ACMEServerOrder{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		ClientId:       record.GetString("clientRef"),
+		Status:         record.GetString("status"),
+		ExpiresAt:      record.GetDateTime("expiresAt").Time(),
+		Identifiers:    identifiers,
+		Authorizations: authorizations,
+		CertificateId:  record.GetString("certificateRef"),
+		Error:          record.GetString("error"),
+	}
+	return order, nil
+}
@@ -74,6 +74,30 @@ func (r *CertificateRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context,
 	return r.castRecordToModel(records[0])
 }
 
+func (r *CertificateRepository) ListActive(ctx context.Context) ([]*domain.Certificate, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameCertificate,
+		"validityNotAfter>@now && isRevoked=false && deleted=null",
+		"-created",
+		0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]*domain.Certificate, 0)
+	for _, record := range records {
+		certificate, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
 func (r *CertificateRepository) Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
 	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameCertificate)
 	if err != nil {
@@ -112,9 +136,17 @@ func (r *CertificateRepository) Save(ctx context.Context, certificate *domain.Ce
 	record.Set("acmeCertUrl", certificate.ACMECertificateUrl)
 	record.Set("isRenewed", certificate.IsRenewed)
 	record.Set("isRevoked", certificate.IsRevoked)
+	record.Set("revokedReason", certificate.RevokedReason.String())
+	if certificate.RevokedAt != nil {
+		record.Set("revokedAt", *certificate.RevokedAt)
+	} else {
+		record.Set("revokedAt", nil)
+	}
+	record.Set("ariRenewalInfo", certificate.ARIRenewalInfo)
 	record.Set("workflowRef", certificate.WorkflowId)
 	record.Set("workflowRunRef", certificate.WorkflowRunId)
 	record.Set("workflowNodeId", certificate.WorkflowNodeId)
+	record.Set("acmeServerClientRef", certificate.ACMEServerClientId)
 	if err := app.GetApp().Save(record); err != nil {
 		return certificate, err
 	}
@@ -153,6 +185,11 @@ func (r *CertificateRepository) castRecordToModel(record *core.Record) (*domain.
 		return nil, fmt.Errorf("the record is nil")
 	}
 
+	var ariRenewalInfo *domain.CertificateARIRenewalInfo
+	if err := record.UnmarshalJSONField("ariRenewalInfo", &ariRenewalInfo); err != nil {
+		return nil, fmt.Errorf("field 'ariRenewalInfo' is malformed")
+	}
+
 	certificate := &domain.Certificate{
 		Meta: domain.Meta{
 			Id:        record.Id,
@@ -178,9 +215,16 @@ func (r *CertificateRepository) castRecordToModel(record *core.Record) (*domain.
 		ACMECertificateUrl: record.GetString("acmeCertUrl"),
 		IsRenewed:          record.GetBool("isRenewed"),
 		IsRevoked:          record.GetBool("isRevoked"),
+		RevokedReason:      domain.CertificateRevocationReasonType(record.GetString("revokedReason")),
+		ARIRenewalInfo:     ariRenewalInfo,
 		WorkflowId:         record.GetString("workflowRef"),
 		WorkflowRunId:      record.GetString("workflowRunRef"),
 		WorkflowNodeId:     record.GetString("workflowNodeId"),
+		ACMEServerClientId: record.GetString("acmeServerClientRef"),
+	}
+	if !record.GetDateTime("revokedAt").IsZero() {
+		revokedAt := record.GetDateTime("revokedAt").Time()
+		certificate.RevokedAt = &revokedAt
 	}
 	return certificate, nil
 }
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type PrivateCARepository struct{}
+
+func NewPrivateCARepository() *PrivateCARepository {
+	return &PrivateCARepository{}
+}
+
+func (r *PrivateCARepository) List(ctx context.Context) ([]*domain.PrivateCA, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNamePrivateCA,
+		"",
+		"-created",
+		0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	privateCAs := make([]*domain.PrivateCA, 0)
+	for _, record := range records {
+		privateCA, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		privateCAs = append(privateCAs, privateCA)
+	}
+
+	return privateCAs, nil
+}
+
+func (r *PrivateCARepository) GetById(ctx context.Context, id string) (*domain.PrivateCA, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNamePrivateCA, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *PrivateCARepository) Save(ctx context.Context, privateCA *domain.PrivateCA) (*domain.PrivateCA, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNamePrivateCA)
+	if err != nil {
+		return privateCA, err
+	}
+
+	var record *core.Record
+	if privateCA.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, privateCA.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return privateCA, domain.ErrRecordNotFound
+			}
+			return privateCA, err
+		}
+	}
+
+	record.Set("name", privateCA.Name)
+	record.Set("rootCertificate", privateCA.RootCertificate)
+	record.Set("rootPrivateKey", privateCA.RootPrivateKey)
+	record.Set("intermediateCertificate", privateCA.IntermediateCertificate)
+	record.Set("intermediatePrivateKey", privateCA.IntermediatePrivateKey)
+	if err := app.GetApp().Save(record); err != nil {
+		return privateCA, err
+	}
+
+	privateCA.Id = record.Id
+	privateCA.CreatedAt = record.GetDateTime("created").Time()
+	privateCA.UpdatedAt = record.GetDateTime("updated").Time()
+	return privateCA, nil
+}
+
+func (r *PrivateCARepository) Delete(ctx context.Context, privateCA *domain.PrivateCA) error {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNamePrivateCA, privateCA.Id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return app.GetApp().Delete(record)
+}
+
+func (r *PrivateCARepository) castRecordToModel(record *core.Record) (*domain.PrivateCA, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	privateCA := &domain.PrivateCA{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		Name:                    record.GetString("name"),
+		RootCertificate:         record.GetString("rootCertificate"),
+		RootPrivateKey:          record.GetString("rootPrivateKey"),
+		IntermediateCertificate: record.GetString("intermediateCertificate"),
+		IntermediatePrivateKey:  record.GetString("intermediatePrivateKey"),
+	}
+	return privateCA, nil
+}
@@ -43,6 +43,80 @@ func (r *WorkflowRepository) ListEnabledScheduled(ctx context.Context) ([]*domai
 	return workflows, nil
 }
 
+func (r *WorkflowRepository) ListEnabledEventDriven(ctx context.Context) ([]*domain.Workflow, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflow,
+		"enabled={:enabled} && trigger={:trigger}",
+		"-created",
+		0, 0,
+		dbx.Params{"enabled": true, "trigger": domain.WorkflowTriggerTypeEvent.String()},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]*domain.Workflow, 0)
+	for _, record := range records {
+		workflow, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
+func (r *WorkflowRepository) ListAll(ctx context.Context) ([]*domain.Workflow, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflow,
+		"",
+		"created",
+		0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]*domain.Workflow, 0)
+	for _, record := range records {
+		workflow, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
+func (r *WorkflowRepository) ListByName(ctx context.Context, name string) ([]*domain.Workflow, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflow,
+		"name={:name}",
+		"created",
+		0, 0,
+		dbx.Params{"name": name},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflows := make([]*domain.Workflow, 0)
+	for _, record := range records {
+		workflow, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflows = append(workflows, workflow)
+	}
+
+	return workflows, nil
+}
+
 func (r *WorkflowRepository) GetById(ctx context.Context, id string) (*domain.Workflow, error) {
 	record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflow, id)
 	if err != nil {
@@ -56,7 +130,12 @@ func (r *WorkflowRepository) GetById(ctx context.Context, id string) (*domain.Wo
 }
 
 func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow) (*domain.Workflow, error) {
-	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflow)
+	return r.SaveWithTx(ctx, app.GetApp(), workflow)
+}
+
+// 在指定的事务中保存工作流。
+func (r *WorkflowRepository) SaveWithTx(ctx context.Context, txApp core.App, workflow *domain.Workflow) (*domain.Workflow, error) {
+	collection, err := txApp.FindCollectionByNameOrId(domain.CollectionNameWorkflow)
 	if err != nil {
 		return workflow, err
 	}
@@ -65,7 +144,7 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	if workflow.Id == "" {
 		record = core.NewRecord(collection)
 	} else {
-		record, err = app.GetApp().FindRecordById(collection, workflow.Id)
+		record, err = txApp.FindRecordById(collection, workflow.Id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return workflow, domain.ErrRecordNotFound
@@ -78,6 +157,8 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	record.Set("description", workflow.Description)
 	record.Set("trigger", workflow.Trigger.String())
 	record.Set("triggerCron", workflow.TriggerCron)
+	record.Set("webhookToken", workflow.WebhookToken)
+	record.Set("webhookSecret", workflow.WebhookSecret)
 	record.Set("enabled", workflow.Enabled)
 	record.Set("graphDraft", workflow.GraphDraft)
 	record.Set("graphContent", workflow.GraphContent)
@@ -86,7 +167,7 @@ func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow
 	record.Set("lastRunRef", workflow.LastRunId)
 	record.Set("lastRunStatus", workflow.LastRunStatus.String())
 	record.Set("lastRunTime", workflow.LastRunTime)
-	if err := app.GetApp().Save(record); err != nil {
+	if err := txApp.Save(record); err != nil {
 		return workflow, err
 	}
 
@@ -121,6 +202,8 @@ func (r *WorkflowRepository) castRecordToModel(record *core.Record) (*domain.Wor
 		Description:   record.GetString("description"),
 		Trigger:       domain.WorkflowTriggerType(record.GetString("trigger")),
 		TriggerCron:   record.GetString("triggerCron"),
+		WebhookToken:  record.GetString("webhookToken"),
+		WebhookSecret: record.GetString("webhookSecret"),
 		Enabled:       record.GetBool("enabled"),
 		GraphDraft:    graphDraft,
 		GraphContent:  graphContent,
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type WorkflowApprovalRepository struct{}
+
+func NewWorkflowApprovalRepository() *WorkflowApprovalRepository {
+	return &WorkflowApprovalRepository{}
+}
+
+func (r *WorkflowApprovalRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context, workflowRunId string, workflowNodeId string) (*domain.WorkflowApproval, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameWorkflowApproval,
+		"runRef={:workflowRunId} && nodeId={:nodeId}",
+		dbx.Params{"workflowRunId": workflowRunId, "nodeId": workflowNodeId},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *WorkflowApprovalRepository) ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowApproval, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowApproval,
+		"runRef={:workflowRunId}",
+		"created",
+		0, 0,
+		dbx.Params{"workflowRunId": workflowRunId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.castRecordsToModels(records)
+}
+
+func (r *WorkflowApprovalRepository) ListPending(ctx context.Context) ([]*domain.WorkflowApproval, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowApproval,
+		"status={:status}",
+		"created",
+		0, 0,
+		dbx.Params{"status": string(domain.WorkflowApprovalStatusTypePending)},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.castRecordsToModels(records)
+}
+
+func (r *WorkflowApprovalRepository) Save(ctx context.Context, workflowApproval *domain.WorkflowApproval) (*domain.WorkflowApproval, error) {
+	collection, err := app.GetApp().FindCollectionByNameOrId(domain.CollectionNameWorkflowApproval)
+	if err != nil {
+		return workflowApproval, err
+	}
+
+	var record *core.Record
+	if workflowApproval.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = app.GetApp().FindRecordById(collection, workflowApproval.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return workflowApproval, domain.ErrRecordNotFound
+			}
+			return workflowApproval, err
+		}
+	}
+
+	record.Set("workflowRef", workflowApproval.WorkflowId)
+	record.Set("runRef", workflowApproval.RunId)
+	record.Set("nodeId", workflowApproval.NodeId)
+	record.Set("status", string(workflowApproval.Status))
+	record.Set("expiresAt", workflowApproval.ExpiresAt)
+	record.Set("operator", workflowApproval.Operator)
+	record.Set("comment", workflowApproval.Comment)
+	record.Set("decidedAt", workflowApproval.DecidedAt)
+	if err := app.GetApp().Save(record); err != nil {
+		return workflowApproval, err
+	}
+
+	workflowApproval.Id = record.Id
+	workflowApproval.CreatedAt = record.GetDateTime("created").Time()
+	workflowApproval.UpdatedAt = record.GetDateTime("updated").Time()
+	return workflowApproval, nil
+}
+
+// 仅当审批仍在等待决定时，才写入决定结果。返回值表示是否写入成功。
+func (r *WorkflowApprovalRepository) Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error) {
+	decided := false
+	err := app.GetApp().RunInTransaction(func(txApp core.App) error {
+		record, err := txApp.FindRecordById(domain.CollectionNameWorkflowApproval, workflowApproval.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrRecordNotFound
+			}
+			return err
+		} else if record.GetString("status") != string(domain.WorkflowApprovalStatusTypePending) {
+			return nil
+		}
+
+		record.Set("status", string(workflowApproval.Status))
+		record.Set("operator", workflowApproval.Operator)
+		record.Set("comment", workflowApproval.Comment)
+		record.Set("decidedAt", workflowApproval.DecidedAt)
+		if err := txApp.Save(record); err != nil {
+			return err
+		}
+
+		decided = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return decided, nil
+}
+
+func (r *WorkflowApprovalRepository) castRecordsToModels(records []*core.Record) ([]*domain.WorkflowApproval, error) {
+	workflowApprovals := make([]*domain.WorkflowApproval, 0, len(records))
+	for _, record := range records {
+		workflowApproval, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflowApprovals = append(workflowApprovals, workflowApproval)
+	}
+
+	return workflowApprovals, nil
+}
+
+func (r *WorkflowApprovalRepository) castRecordToModel(record *core.Record) (*domain.WorkflowApproval, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	workflowApproval := &domain.WorkflowApproval{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		WorkflowId: record.GetString("workflowRef"),
+		RunId:      record.GetString("runRef"),
+		NodeId:     record.GetString("nodeId"),
+		Status:     domain.WorkflowApprovalStatusType(record.GetString("status")),
+		ExpiresAt:  record.GetDateTime("expiresAt").Time(),
+		Operator:   record.GetString("operator"),
+		Comment:    record.GetString("comment"),
+		DecidedAt:  record.GetDateTime("decidedAt").Time(),
+	}
+	return workflowApproval, nil
+}
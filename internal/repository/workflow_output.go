@@ -62,6 +62,31 @@ func (r *WorkflowOutputRepository) GetByWorkflowRunIdAndNodeId(ctx context.Conte
 	return r.castRecordToModel(records[0])
 }
 
+func (r *WorkflowOutputRepository) ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowOutput, error) {
+	records, err := app.GetApp().FindRecordsByFilter(
+		domain.CollectionNameWorkflowOutput,
+		"runRef={:workflowRunId}",
+		"created",
+		0, 0,
+		dbx.Params{"workflowRunId": workflowRunId},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowOutputs := make([]*domain.WorkflowOutput, 0, len(records))
+	for _, record := range records {
+		workflowOutput, err := r.castRecordToModel(record)
+		if err != nil {
+			return nil, err
+		}
+
+		workflowOutputs = append(workflowOutputs, workflowOutput)
+	}
+
+	return workflowOutputs, nil
+}
+
 func (r *WorkflowOutputRepository) Save(ctx context.Context, workflowOutput *domain.WorkflowOutput) (*domain.WorkflowOutput, error) {
 	record, err := r.saveRecord(workflowOutput)
 	if err != nil {
@@ -89,6 +114,11 @@ func (r *WorkflowOutputRepository) castRecordToModel(record *core.Record) (*doma
 		return nil, fmt.Errorf("field 'outputs' is malformed")
 	}
 
+	variables := make([]*domain.WorkflowOutputVariable, 0)
+	if err := record.UnmarshalJSONField("variables", &variables); err != nil {
+		return nil, fmt.Errorf("field 'variables' is malformed")
+	}
+
 	workflowOutput := &domain.WorkflowOutput{
 		Meta: domain.Meta{
 			Id:        record.Id,
@@ -100,6 +130,7 @@ func (r *WorkflowOutputRepository) castRecordToModel(record *core.Record) (*doma
 		NodeId:     record.GetString("nodeId"),
 		NodeConfig: nodeConfig,
 		Outputs:    outputs,
+		Variables:  variables,
 		Succeeded:  record.GetBool("succeeded"),
 	}
 	return workflowOutput, nil
@@ -125,6 +156,7 @@ func (r *WorkflowOutputRepository) saveRecord(workflowOutput *domain.WorkflowOut
 	record.Set("nodeId", workflowOutput.NodeId)
 	record.Set("nodeConfig", workflowOutput.NodeConfig)
 	record.Set("outputs", workflowOutput.Outputs)
+	record.Set("variables", workflowOutput.Variables)
 	record.Set("succeeded", workflowOutput.Succeeded)
 	if err := app.GetApp().Save(record); err != nil {
 		return record, err
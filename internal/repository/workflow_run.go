@@ -56,6 +56,11 @@ func (r *WorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.Wo
 	record.Set("endedAt", workflowRun.EndedAt)
 	record.Set("graph", workflowRun.Graph)
 	record.Set("error", workflowRun.Error)
+	record.Set("errorNodeId", workflowRun.ErrorNodeId)
+	record.Set("resumedFromRunRef", workflowRun.ResumedFromRunId)
+	record.Set("resumedFromNodeId", workflowRun.ResumedFromNodeId)
+	record.Set("variables", workflowRun.Variables)
+	record.Set("versionRef", workflowRun.VersionId)
 	err = app.GetApp().Save(record)
 	if err != nil {
 		return workflowRun, err
@@ -94,6 +99,11 @@ func (r *WorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowR
 		record.Set("endedAt", workflowRun.EndedAt)
 		record.Set("graph", workflowRun.Graph)
 		record.Set("error", workflowRun.Error)
+		record.Set("errorNodeId", workflowRun.ErrorNodeId)
+		record.Set("resumedFromRunRef", workflowRun.ResumedFromRunId)
+		record.Set("resumedFromNodeId", workflowRun.ResumedFromNodeId)
+		record.Set("variables", workflowRun.Variables)
+		record.Set("versionRef", workflowRun.VersionId)
 		err = txApp.Save(record)
 		if err != nil {
 			return err
@@ -162,11 +172,12 @@ func (r *WorkflowRunRepository) ResetStatusIfHanging(ctx context.Context) error
 		var err error
 
 		_, err = txApp.DB().
-			NewQuery(fmt.Sprintf("UPDATE %s SET lastRunStatus = '%s' WHERE lastRunStatus = '%s' OR lastRunStatus = '%s'",
+			NewQuery(fmt.Sprintf("UPDATE %s SET lastRunStatus = '%s' WHERE lastRunStatus = '%s' OR lastRunStatus = '%s' OR lastRunStatus = '%s'",
 				domain.CollectionNameWorkflow,
 				domain.WorkflowRunStatusTypeCanceled.String(),
 				domain.WorkflowRunStatusTypePending.String(),
 				domain.WorkflowRunStatusTypeProcessing.String(),
+				domain.WorkflowRunStatusTypeWaiting.String(),
 			)).
 			Execute()
 		if err != nil {
@@ -174,11 +185,12 @@ func (r *WorkflowRunRepository) ResetStatusIfHanging(ctx context.Context) error
 		}
 
 		_, err = txApp.DB().
-			NewQuery(fmt.Sprintf("UPDATE %s SET lastRunStatus = '%s' WHERE lastRunStatus = '%s' OR lastRunStatus = '%s'",
-				domain.CollectionNameWorkflow,
+			NewQuery(fmt.Sprintf("UPDATE %s SET status = '%s' WHERE status = '%s' OR status = '%s' OR status = '%s'",
+				domain.CollectionNameWorkflowRun,
 				domain.WorkflowRunStatusTypeCanceled.String(),
 				domain.WorkflowRunStatusTypePending.String(),
 				domain.WorkflowRunStatusTypeProcessing.String(),
+				domain.WorkflowRunStatusTypeWaiting.String(),
 			)).
 			Execute()
 		if err != nil {
@@ -199,19 +211,29 @@ func (r *WorkflowRunRepository) castRecordToModel(record *core.Record) (*domain.
 		return nil, fmt.Errorf("field 'graph' is malformed")
 	}
 
+	variables := make([]*domain.WorkflowRunVariable, 0)
+	if err := record.UnmarshalJSONField("variables", &variables); err != nil {
+		return nil, fmt.Errorf("field 'variables' is malformed")
+	}
+
 	workflowRun := &domain.WorkflowRun{
 		Meta: domain.Meta{
 			Id:        record.Id,
 			CreatedAt: record.GetDateTime("created").Time(),
 			UpdatedAt: record.GetDateTime("updated").Time(),
 		},
-		WorkflowId: record.GetString("workflowRef"),
-		Status:     domain.WorkflowRunStatusType(record.GetString("status")),
-		Trigger:    domain.WorkflowTriggerType(record.GetString("trigger")),
-		StartedAt:  record.GetDateTime("startedAt").Time(),
-		EndedAt:    record.GetDateTime("endedAt").Time(),
-		Graph:      graph,
-		Error:      record.GetString("error"),
+		WorkflowId:        record.GetString("workflowRef"),
+		Status:            domain.WorkflowRunStatusType(record.GetString("status")),
+		Trigger:           domain.WorkflowTriggerType(record.GetString("trigger")),
+		StartedAt:         record.GetDateTime("startedAt").Time(),
+		EndedAt:           record.GetDateTime("endedAt").Time(),
+		Graph:             graph,
+		Error:             record.GetString("error"),
+		ErrorNodeId:       record.GetString("errorNodeId"),
+		ResumedFromRunId:  record.GetString("resumedFromRunRef"),
+		ResumedFromNodeId: record.GetString("resumedFromNodeId"),
+		Variables:         variables,
+		VersionId:         record.GetString("versionRef"),
 	}
 	return workflowRun, nil
 }
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type WorkflowVersionRepository struct{}
+
+func NewWorkflowVersionRepository() *WorkflowVersionRepository {
+	return &WorkflowVersionRepository{}
+}
+
+func (r *WorkflowVersionRepository) GetById(ctx context.Context, id string) (*domain.WorkflowVersion, error) {
+	record, err := app.GetApp().FindRecordById(domain.CollectionNameWorkflowVersion, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+func (r *WorkflowVersionRepository) GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error) {
+	return r.getLatestByWorkflowId(app.GetApp(), workflowId)
+}
+
+func (r *WorkflowVersionRepository) getLatestByWorkflowId(txApp core.App, workflowId string) (*domain.WorkflowVersion, error) {
+	records, err := txApp.FindRecordsByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId}",
+		"-version",
+		1, 0,
+		dbx.Params{"workflowId": workflowId},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return r.castRecordToModel(records[0])
+}
+
+func (r *WorkflowVersionRepository) GetByWorkflowIdAndVersion(ctx context.Context, workflowId string, version int) (*domain.WorkflowVersion, error) {
+	record, err := app.GetApp().FindFirstRecordByFilter(
+		domain.CollectionNameWorkflowVersion,
+		"workflowRef={:workflowId} && version={:version}",
+		dbx.Params{"workflowId": workflowId, "version": version},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return r.castRecordToModel(record)
+}
+
+// 保存工作流版本。
+// 若版本号为 0，将自动分配为该工作流的最新版本号加 1。
+func (r *WorkflowVersionRepository) Save(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error) {
+	return r.SaveWithTx(ctx, app.GetApp(), workflowVersion)
+}
+
+// 在指定的事务中保存工作流版本，以便与工作流记录一并提交或回滚。
+func (r *WorkflowVersionRepository) SaveWithTx(ctx context.Context, txApp core.App, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error) {
+	collection, err := txApp.FindCollectionByNameOrId(domain.CollectionNameWorkflowVersion)
+	if err != nil {
+		return workflowVersion, err
+	}
+
+	var record *core.Record
+	if workflowVersion.Id == "" {
+		record = core.NewRecord(collection)
+	} else {
+		record, err = txApp.FindRecordById(collection, workflowVersion.Id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return workflowVersion, domain.ErrRecordNotFound
+			}
+			return workflowVersion, err
+		}
+	}
+
+	if workflowVersion.Version == 0 {
+		latest, err := r.getLatestByWorkflowId(txApp, workflowVersion.WorkflowId)
+		if err != nil && !errors.Is(err, domain.ErrRecordNotFound) {
+			return workflowVersion, err
+		} else if latest != nil {
+			workflowVersion.Version = latest.Version + 1
+		} else {
+			workflowVersion.Version = 1
+		}
+	}
+
+	record.Set("workflowRef", workflowVersion.WorkflowId)
+	record.Set("version", workflowVersion.Version)
+	record.Set("graph", workflowVersion.Graph)
+	record.Set("author", workflowVersion.Author)
+	record.Set("comment", workflowVersion.Comment)
+	if err := txApp.Save(record); err != nil {
+		return workflowVersion, err
+	}
+
+	workflowVersion.Id = record.Id
+	workflowVersion.CreatedAt = record.GetDateTime("created").Time()
+	workflowVersion.UpdatedAt = record.GetDateTime("updated").Time()
+	return workflowVersion, nil
+}
+
+func (r *WorkflowVersionRepository) castRecordToModel(record *core.Record) (*domain.WorkflowVersion, error) {
+	if record == nil {
+		return nil, fmt.Errorf("the record is nil")
+	}
+
+	graph := &domain.WorkflowGraph{}
+	if err := record.UnmarshalJSONField("graph", &graph); err != nil {
+		return nil, fmt.Errorf("field 'graph' is malformed")
+	}
+
+	workflowVersion := &domain.WorkflowVersion{
+		Meta: domain.Meta{
+			Id:        record.Id,
+			CreatedAt: record.GetDateTime("created").Time(),
+			UpdatedAt: record.GetDateTime("updated").Time(),
+		},
+		WorkflowId: record.GetString("workflowRef"),
+		Version:    record.GetInt("version"),
+		Graph:      graph,
+		Author:     record.GetString("author"),
+		Comment:    record.GetString("comment"),
+	}
+	return workflowVersion, nil
+}
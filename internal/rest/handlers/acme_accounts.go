@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type acmeAccountService interface {
+	RolloverKey(ctx context.Context, req *dtos.ACMEAccountRolloverKeyReq) (*dtos.ACMEAccountRolloverKeyResp, error)
+	UpdateContacts(ctx context.Context, req *dtos.ACMEAccountUpdateContactsReq) (*dtos.ACMEAccountUpdateContactsResp, error)
+	Deactivate(ctx context.Context, req *dtos.ACMEAccountDeactivateReq) (*dtos.ACMEAccountDeactivateResp, error)
+	Refresh(ctx context.Context, req *dtos.ACMEAccountRefreshReq) (*dtos.ACMEAccountRefreshResp, error)
+}
+
+type ACMEAccountsHandler struct {
+	service acmeAccountService
+}
+
+func NewACMEAccountsHandler(router *router.RouterGroup[*core.RequestEvent], service acmeAccountService) {
+	handler := &ACMEAccountsHandler{
+		service: service,
+	}
+
+	group := router.Group("/acme-accounts")
+	group.POST("/{accountId}/rollover-key", handler.rolloverKey)
+	group.POST("/{accountId}/contacts", handler.updateContacts)
+	group.POST("/{accountId}/deactivate", handler.deactivate)
+	group.POST("/{accountId}/refresh", handler.refresh)
+}
+
+func (handler *ACMEAccountsHandler) rolloverKey(e *core.RequestEvent) error {
+	req := &dtos.ACMEAccountRolloverKeyReq{}
+	req.AccountId = e.Request.PathValue("accountId")
+
+	res, err := handler.service.RolloverKey(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *ACMEAccountsHandler) updateContacts(e *core.RequestEvent) error {
+	req := &dtos.ACMEAccountUpdateContactsReq{}
+	req.AccountId = e.Request.PathValue("accountId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.UpdateContacts(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *ACMEAccountsHandler) deactivate(e *core.RequestEvent) error {
+	req := &dtos.ACMEAccountDeactivateReq{}
+	req.AccountId = e.Request.PathValue("accountId")
+
+	res, err := handler.service.Deactivate(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *ACMEAccountsHandler) refresh(e *core.RequestEvent) error {
+	req := &dtos.ACMEAccountRefreshReq{}
+	req.AccountId = e.Request.PathValue("accountId")
+
+	res, err := handler.service.Refresh(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
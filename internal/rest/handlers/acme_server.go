@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/acmeserver"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type acmeServer interface {
+	NewNonce() string
+	GetDirectory(ctx context.Context, baseUrl string) (*acmeserver.Response, error)
+	NewAccount(ctx context.Context, baseUrl string, reqUrl string, body []byte) (*acmeserver.Response, error)
+	UpdateAccount(ctx context.Context, baseUrl string, reqUrl string, accountId string, body []byte) (*acmeserver.Response, error)
+	ListAccountOrders(ctx context.Context, baseUrl string, reqUrl string, accountId string, body []byte) (*acmeserver.Response, error)
+	NewOrder(ctx context.Context, baseUrl string, reqUrl string, body []byte) (*acmeserver.Response, error)
+	GetOrder(ctx context.Context, baseUrl string, reqUrl string, orderId string, body []byte) (*acmeserver.Response, error)
+	FinalizeOrder(ctx context.Context, baseUrl string, reqUrl string, orderId string, body []byte) (*acmeserver.Response, error)
+	GetAuthorization(ctx context.Context, baseUrl string, reqUrl string, authzId string, body []byte) (*acmeserver.Response, error)
+	GetCertificate(ctx context.Context, baseUrl string, reqUrl string, certificateId string, body []byte) (*acmeserver.Response, error)
+}
+
+type ACMEServerHandler struct {
+	server   acmeServer
+	basePath string
+}
+
+// 注册 RFC 8555 ACME 服务端路由。
+// 这些路由面向 ACME 客户端，通过 JWS 签名及外部账户绑定进行认证，而非管理员认证。
+func NewACMEServerHandler(router *router.RouterGroup[*core.RequestEvent], server acmeServer) {
+	handler := &ACMEServerHandler{
+		server:   server,
+		basePath: router.Prefix,
+	}
+
+	router.GET("/directory", handler.directory)
+	router.HEAD("/new-nonce", handler.newNonce)
+	router.GET("/new-nonce", handler.newNonce)
+	router.POST("/new-account", handler.newAccount)
+	router.POST("/account/{accountId}", handler.updateAccount)
+	router.POST("/account/{accountId}/orders", handler.listAccountOrders)
+	router.POST("/new-order", handler.newOrder)
+	router.POST("/order/{orderId}", handler.getOrder)
+	router.POST("/order/{orderId}/finalize", handler.finalizeOrder)
+	router.POST("/authz/{authzId}", handler.getAuthorization)
+	router.POST("/cert/{certificateId}", handler.getCertificate)
+}
+
+func (handler *ACMEServerHandler) directory(e *core.RequestEvent) error {
+	res, err := handler.server.GetDirectory(e.Request.Context(), handler.getBaseUrl(e))
+	return handler.respond(e, res, err)
+}
+
+func (handler *ACMEServerHandler) newNonce(e *core.RequestEvent) error {
+	e.Response.Header().Set("Replay-Nonce", handler.server.NewNonce())
+	e.Response.Header().Set("Cache-Control", "no-store")
+	e.Response.Header().Set("Link", fmt.Sprintf("<%s/directory>;rel=\"index\"", handler.getBaseUrl(e)))
+	if e.Request.Method == http.MethodHead {
+		return e.NoContent(http.StatusOK)
+	}
+	return e.NoContent(http.StatusNoContent)
+}
+
+func (handler *ACMEServerHandler) newAccount(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.NewAccount(ctx, baseUrl, reqUrl, body)
+	})
+}
+
+func (handler *ACMEServerHandler) updateAccount(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.UpdateAccount(ctx, baseUrl, reqUrl, e.Request.PathValue("accountId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) listAccountOrders(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.ListAccountOrders(ctx, baseUrl, reqUrl, e.Request.PathValue("accountId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) newOrder(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.NewOrder(ctx, baseUrl, reqUrl, body)
+	})
+}
+
+func (handler *ACMEServerHandler) getOrder(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.GetOrder(ctx, baseUrl, reqUrl, e.Request.PathValue("orderId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) finalizeOrder(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.FinalizeOrder(ctx, baseUrl, reqUrl, e.Request.PathValue("orderId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) getAuthorization(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.GetAuthorization(ctx, baseUrl, reqUrl, e.Request.PathValue("authzId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) getCertificate(e *core.RequestEvent) error {
+	return handler.handle(e, func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error) {
+		return handler.server.GetCertificate(ctx, baseUrl, reqUrl, e.Request.PathValue("certificateId"), body)
+	})
+}
+
+func (handler *ACMEServerHandler) handle(e *core.RequestEvent, fn func(ctx context.Context, baseUrl, reqUrl string, body []byte) (*acmeserver.Response, error)) error {
+	if contentType := e.Request.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/jose+json") {
+		return handler.respond(e, nil, &acmeserver.Problem{
+			Type:   "urn:ietf:params:acme:error:malformed",
+			Detail: "the content type must be 'application/jose+json'",
+			Status: http.StatusUnsupportedMediaType,
+		})
+	}
+
+	body, err := io.ReadAll(io.LimitReader(e.Request.Body, 1<<20))
+	if err != nil {
+		return handler.respond(e, nil, err)
+	}
+
+	baseUrl := handler.getBaseUrl(e)
+	reqUrl := handler.getRequestUrl(e)
+	res, err := fn(e.Request.Context(), baseUrl, reqUrl, body)
+	return handler.respond(e, res, err)
+}
+
+func (handler *ACMEServerHandler) respond(e *core.RequestEvent, res *acmeserver.Response, err error) error {
+	e.Response.Header().Set("Replay-Nonce", handler.server.NewNonce())
+	e.Response.Header().Set("Cache-Control", "no-store")
+	e.Response.Header().Add("Link", fmt.Sprintf("<%s/directory>;rel=\"index\"", handler.getBaseUrl(e)))
+
+	if err != nil {
+		var problem *acmeserver.Problem
+		if !errors.As(err, &problem) {
+			problem = &acmeserver.Problem{
+				Type:   "urn:ietf:params:acme:error:serverInternal",
+				Detail: err.Error(),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		data, _ := json.Marshal(problem)
+		return e.Blob(problem.Status, "application/problem+json", data)
+	}
+
+	if res.Location != "" {
+		e.Response.Header().Set("Location", res.Location)
+	}
+	for _, link := range res.Links {
+		e.Response.Header().Add("Link", link)
+	}
+
+	if res.RawBody != nil {
+		return e.Blob(res.StatusCode, res.ContentType, res.RawBody)
+	}
+
+	return e.JSON(res.StatusCode, res.Body)
+}
+
+func (handler *ACMEServerHandler) getBaseUrl(e *core.RequestEvent) string {
+	scheme := "http"
+	if e.IsTLS() || strings.EqualFold(e.Request.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, e.Request.Host, strings.TrimSuffix(handler.basePath, "/"))
+}
+
+func (handler *ACMEServerHandler) getRequestUrl(e *core.RequestEvent) string {
+	return strings.TrimSuffix(handler.getBaseUrl(e), strings.TrimSuffix(handler.basePath, "/")) + e.Request.URL.Path
+}
+
+type acmeServerService interface {
+	CreateClient(ctx context.Context, req *dtos.ACMEServerCreateClientReq) (*dtos.ACMEServerCreateClientResp, error)
+	ResetClientEAB(ctx context.Context, req *dtos.ACMEServerResetClientEABReq) (*dtos.ACMEServerResetClientEABResp, error)
+}
+
+type ACMEServerClientsHandler struct {
+	service acmeServerService
+}
+
+func NewACMEServerClientsHandler(router *router.RouterGroup[*core.RequestEvent], service acmeServerService) {
+	handler := &ACMEServerClientsHandler{
+		service: service,
+	}
+
+	group := router.Group("/acme-server/clients")
+	group.POST("", handler.createClient)
+	group.POST("/{clientId}/reset-eab", handler.resetClientEAB)
+}
+
+func (handler *ACMEServerClientsHandler) createClient(e *core.RequestEvent) error {
+	req := &dtos.ACMEServerCreateClientReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.CreateClient(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *ACMEServerClientsHandler) resetClientEAB(e *core.RequestEvent) error {
+	req := &dtos.ACMEServerResetClientEABReq{}
+	req.ClientId = e.Request.PathValue("clientId")
+
+	res, err := handler.service.ResetClientEAB(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -13,6 +13,8 @@ import (
 type certificateService interface {
 	DownloadCertificate(ctx context.Context, req *dtos.CertificateDownloadReq) (*dtos.CertificateDownloadResp, error)
 	RevokeCertificate(ctx context.Context, req *dtos.CertificateRevokeReq) (*dtos.CertificateRevokeResp, error)
+	BulkRevokeCertificates(ctx context.Context, req *dtos.CertificateBulkRevokeReq) (*dtos.CertificateBulkRevokeResp, error)
+	PreflightCertificate(ctx context.Context, req *dtos.CertificatePreflightReq) (*dtos.CertificatePreflightResp, error)
 }
 
 type CertificatesHandler struct {
@@ -27,6 +29,8 @@ func NewCertificatesHandler(router *router.RouterGroup[*core.RequestEvent], serv
 	group := router.Group("/certificates")
 	group.POST("/{certificateId}/download", handler.downloadCertificate)
 	group.POST("/{certificateId}/revoke", handler.revokeCertificate)
+	group.POST("/bulk-revoke", handler.bulkRevokeCertificates)
+	group.POST("/preflight", handler.preflightCertificate)
 
 	group.POST("/{certificateId}/archive", handler.downloadCertificate) // 兼容旧版
 }
@@ -60,3 +64,31 @@ func (handler *CertificatesHandler) revokeCertificate(e *core.RequestEvent) erro
 
 	return resp.Ok(e, res)
 }
+
+func (handler *CertificatesHandler) bulkRevokeCertificates(e *core.RequestEvent) error {
+	req := &dtos.CertificateBulkRevokeReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.BulkRevokeCertificates(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *CertificatesHandler) preflightCertificate(e *core.RequestEvent) error {
+	req := &dtos.CertificatePreflightReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.PreflightCertificate(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/rest/resp"
+)
+
+type privateCAService interface {
+	List(ctx context.Context, req *dtos.PrivateCAListReq) (*dtos.PrivateCAListResp, error)
+	Get(ctx context.Context, req *dtos.PrivateCAGetReq) (*dtos.PrivateCAGetResp, error)
+	Delete(ctx context.Context, req *dtos.PrivateCADeleteReq) (*dtos.PrivateCADeleteResp, error)
+	Generate(ctx context.Context, req *dtos.PrivateCAGenerateReq) (*dtos.PrivateCAGenerateResp, error)
+	Import(ctx context.Context, req *dtos.PrivateCAImportReq) (*dtos.PrivateCAImportResp, error)
+}
+
+type PrivateCAsHandler struct {
+	service privateCAService
+}
+
+func NewPrivateCAsHandler(router *router.RouterGroup[*core.RequestEvent], service privateCAService) {
+	handler := &PrivateCAsHandler{
+		service: service,
+	}
+
+	group := router.Group("/private-cas")
+	group.GET("", handler.list)
+	group.GET("/{privateCAId}", handler.get)
+	group.DELETE("/{privateCAId}", handler.delete)
+	group.POST("/generate", handler.generate)
+	group.POST("/import", handler.importCA)
+}
+
+func (handler *PrivateCAsHandler) list(e *core.RequestEvent) error {
+	req := &dtos.PrivateCAListReq{}
+
+	res, err := handler.service.List(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *PrivateCAsHandler) get(e *core.RequestEvent) error {
+	req := &dtos.PrivateCAGetReq{}
+	req.PrivateCAId = e.Request.PathValue("privateCAId")
+
+	res, err := handler.service.Get(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *PrivateCAsHandler) delete(e *core.RequestEvent) error {
+	req := &dtos.PrivateCADeleteReq{}
+	req.PrivateCAId = e.Request.PathValue("privateCAId")
+
+	res, err := handler.service.Delete(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *PrivateCAsHandler) generate(e *core.RequestEvent) error {
+	req := &dtos.PrivateCAGenerateReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.Generate(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *PrivateCAsHandler) importCA(e *core.RequestEvent) error {
+	req := &dtos.PrivateCAImportReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.Import(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html/template"
+	"io"
+	"net/http"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
@@ -16,6 +20,12 @@ type workflowService interface {
 	GetStatistics(ctx context.Context) (*dtos.WorkflowStatisticsResp, error)
 	StartRun(ctx context.Context, req *dtos.WorkflowStartRunReq) (*dtos.WorkflowStartRunResp, error)
 	CancelRun(ctx context.Context, req *dtos.WorkflowCancelRunReq) (*dtos.WorkflowCancelRunResp, error)
+	ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error)
+	RetryRun(ctx context.Context, req *dtos.WorkflowRetryRunReq) (*dtos.WorkflowRetryRunResp, error)
+	DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error)
+	RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error)
+	ExportBundle(ctx context.Context, req *dtos.WorkflowExportBundleReq) (*dtos.WorkflowExportBundleResp, error)
+	ImportBundle(ctx context.Context, req *dtos.WorkflowImportBundleReq) (*dtos.WorkflowImportBundleResp, error)
 	Shutdown(ctx context.Context)
 }
 
@@ -30,8 +40,14 @@ func NewWorkflowsHandler(router *router.RouterGroup[*core.RequestEvent], service
 
 	group := router.Group("/workflows")
 	group.GET("/stats", handler.getStatistics)
+	group.POST("/export", handler.exportBundle)
+	group.POST("/import", handler.importBundle)
 	group.POST("/{workflowId}/runs", handler.startRun)
 	group.POST("/{workflowId}/runs/{runId}/cancel", handler.cancelRun)
+	group.POST("/{workflowId}/runs/{runId}/resume", handler.resumeRun)
+	group.POST("/{workflowId}/runs/{runId}/retry", handler.retryRun)
+	group.GET("/{workflowId}/versions/diff", handler.diffVersions)
+	group.POST("/{workflowId}/versions/{versionId}/rollback", handler.rollbackVersion)
 }
 
 func (handler *WorkflowsHandler) getStatistics(e *core.RequestEvent) error {
@@ -49,8 +65,8 @@ func (handler *WorkflowsHandler) startRun(e *core.RequestEvent) error {
 	if err := e.BindBody(req); err != nil {
 		return resp.Err(e, err)
 	}
-	if req.RunTrigger != domain.WorkflowTriggerTypeManual {
-		return resp.Err(e, fmt.Errorf("invalid parameters: the value of 'trigger' must be 'manual'"))
+	if req.RunTrigger != domain.WorkflowTriggerTypeManual && req.RunTrigger != domain.WorkflowTriggerTypeDryRun {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the value of 'trigger' must be 'manual' or 'dryrun'"))
 	}
 
 	res, err := handler.service.StartRun(e.Request.Context(), req)
@@ -73,3 +89,227 @@ func (handler *WorkflowsHandler) cancelRun(e *core.RequestEvent) error {
 
 	return resp.Ok(e, res)
 }
+
+func (handler *WorkflowsHandler) resumeRun(e *core.RequestEvent) error {
+	req := &dtos.WorkflowResumeRunReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+	if e.Auth != nil {
+		req.Operator = e.Auth.Email()
+	}
+
+	res, err := handler.service.ResumeRun(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) retryRun(e *core.RequestEvent) error {
+	req := &dtos.WorkflowRetryRunReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.RunId = e.Request.PathValue("runId")
+
+	res, err := handler.service.RetryRun(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) diffVersions(e *core.RequestEvent) error {
+	req := &dtos.WorkflowDiffVersionsReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.FromVersionId = e.Request.URL.Query().Get("from")
+	req.ToVersionId = e.Request.URL.Query().Get("to")
+
+	res, err := handler.service.DiffVersions(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) rollbackVersion(e *core.RequestEvent) error {
+	req := &dtos.WorkflowRollbackVersionReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.VersionId = e.Request.PathValue("versionId")
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+	if e.Auth != nil {
+		req.Operator = e.Auth.Email()
+	}
+
+	res, err := handler.service.RollbackVersion(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+func (handler *WorkflowsHandler) exportBundle(e *core.RequestEvent) error {
+	req := &dtos.WorkflowExportBundleReq{}
+	if err := e.BindBody(req); err != nil {
+		return resp.Err(e, err)
+	}
+
+	res, err := handler.service.ExportBundle(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	e.Response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", res.FileName))
+	return e.Blob(http.StatusOK, res.ContentType, res.Data)
+}
+
+const workflowBundleMaxSize = 10 << 20
+
+func (handler *WorkflowsHandler) importBundle(e *core.RequestEvent) error {
+	data, err := io.ReadAll(io.LimitReader(e.Request.Body, workflowBundleMaxSize+1))
+	if err != nil {
+		return resp.Err(e, err)
+	} else if len(data) > workflowBundleMaxSize {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the bundle is too large"))
+	}
+
+	req := &dtos.WorkflowImportBundleReq{}
+	req.Data = data
+	req.DryRun = e.Request.URL.Query().Get("dryRun") == "true"
+	if e.Auth != nil {
+		req.Operator = e.Auth.Email()
+	}
+
+	res, err := handler.service.ImportBundle(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+type workflowWebhookService interface {
+	TriggerWebhook(ctx context.Context, req *dtos.WorkflowTriggerWebhookReq) (*dtos.WorkflowTriggerWebhookResp, error)
+	ReviewApproval(ctx context.Context, req *dtos.WorkflowReviewApprovalReq) (*dtos.WorkflowReviewApprovalResp, error)
+}
+
+type WorkflowWebhooksHandler struct {
+	service workflowWebhookService
+}
+
+// 注册工作流 Webhook 触发及审批路由。
+// 这些路由面向外部系统，通过 URL 中的访问令牌或签名进行认证，而非管理员认证。
+func NewWorkflowWebhooksHandler(router *router.RouterGroup[*core.RequestEvent], service workflowWebhookService) {
+	handler := &WorkflowWebhooksHandler{
+		service: service,
+	}
+
+	group := router.Group("/workflows")
+	group.POST("/{workflowId}/{token}", handler.trigger)
+
+	approvalGroup := router.Group("/approvals")
+	approvalGroup.GET("/{runId}/{nodeId}", handler.reviewApprovalPage)
+	approvalGroup.POST("/{runId}/{nodeId}", handler.reviewApproval)
+}
+
+const workflowWebhookMaxPayloadSize = 1 << 20
+
+func (handler *WorkflowWebhooksHandler) trigger(e *core.RequestEvent) error {
+	payload, err := io.ReadAll(io.LimitReader(e.Request.Body, workflowWebhookMaxPayloadSize+1))
+	if err != nil {
+		return resp.Err(e, err)
+	} else if len(payload) > workflowWebhookMaxPayloadSize {
+		return resp.Err(e, fmt.Errorf("invalid parameters: the payload is too large"))
+	}
+
+	req := &dtos.WorkflowTriggerWebhookReq{}
+	req.WorkflowId = e.Request.PathValue("workflowId")
+	req.Token = e.Request.PathValue("token")
+	req.Timestamp = e.Request.Header.Get("X-Certimate-Timestamp")
+	req.Signature = e.Request.Header.Get("X-Certimate-Signature")
+	req.Payload = payload
+
+	res, err := handler.service.TriggerWebhook(e.Request.Context(), req)
+	if err != nil {
+		return resp.Err(e, err)
+	}
+
+	return resp.Ok(e, res)
+}
+
+// 审批确认页面。
+// 审批链接通常会被邮件客户端或聊天工具预先访问，因此 GET 请求仅展示确认表单，由审批人提交后才执行审批操作。
+var workflowApprovalPageTemplate = template.Must(template.New("approval").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Certimate Approval</title>
+</head>
+<body style="font-family: sans-serif; max-width: 480px; margin: 48px auto; padding: 0 16px;">
+  <h2>{{if eq .Action "approve"}}Approve{{else}}Reject{{end}} workflow run</h2>
+  {{if .Message}}<p>{{.Message}}</p>{{else}}
+  <form method="post">
+    <input type="hidden" name="action" value="{{.Action}}">
+    <input type="hidden" name="expires" value="{{.Expires}}">
+    <input type="hidden" name="signature" value="{{.Signature}}">
+    <p><label>Your name<br><input type="text" name="operator" required maxlength="100" style="width: 100%;"></label></p>
+    <p><label>Comment<br><textarea name="comment" rows="4" maxlength="1000" style="width: 100%;"></textarea></label></p>
+    <p><button type="submit">{{if eq .Action "approve"}}Approve{{else}}Reject{{end}}</button></p>
+  </form>
+  {{end}}
+</body>
+</html>`))
+
+type workflowApprovalPageData struct {
+	Action    string
+	Expires   string
+	Signature string
+	Message   string
+}
+
+func (handler *WorkflowWebhooksHandler) reviewApprovalPage(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	return handler.renderApprovalPage(e, http.StatusOK, workflowApprovalPageData{
+		Action:    query.Get("action"),
+		Expires:   query.Get("expires"),
+		Signature: query.Get("signature"),
+	})
+}
+
+func (handler *WorkflowWebhooksHandler) reviewApproval(e *core.RequestEvent) error {
+	req := &dtos.WorkflowReviewApprovalReq{}
+	req.RunId = e.Request.PathValue("runId")
+	req.NodeId = e.Request.PathValue("nodeId")
+	req.Action = e.Request.FormValue("action")
+	req.Expires = e.Request.FormValue("expires")
+	req.Signature = e.Request.FormValue("signature")
+	req.Operator = e.Request.FormValue("operator")
+	req.Comment = e.Request.FormValue("comment")
+	req.RemoteIP = e.RealIP()
+
+	data := workflowApprovalPageData{Action: req.Action}
+	if _, err := handler.service.ReviewApproval(e.Request.Context(), req); err != nil {
+		data.Message = err.Error()
+		return handler.renderApprovalPage(e, http.StatusBadRequest, data)
+	}
+
+	data.Message = "Your decision has been recorded. You can close this page now."
+	return handler.renderApprovalPage(e, http.StatusOK, data)
+}
+
+func (handler *WorkflowWebhooksHandler) renderApprovalPage(e *core.RequestEvent, status int, data workflowApprovalPageData) error {
+	var buf bytes.Buffer
+	if err := workflowApprovalPageTemplate.Execute(&buf, data); err != nil {
+		return resp.Err(e, err)
+	}
+
+	return e.HTML(status, buf.String())
+}
@@ -1,12 +1,17 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/router"
 
+	"github.com/certimate-go/certimate/internal/acmeaccount"
+	"github.com/certimate-go/certimate/internal/acmeserver"
 	"github.com/certimate-go/certimate/internal/certificate"
 	"github.com/certimate-go/certimate/internal/notify"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/rest/handlers"
 	"github.com/certimate-go/certimate/internal/statistics"
@@ -14,29 +19,57 @@ import (
 )
 
 var (
+	acmeAccountSvc *acmeaccount.ACMEAccountService
+	acmeServerSvc  *acmeserver.ACMEServerService
+	acmeServer     *acmeserver.Server
 	certificateSvc *certificate.CertificateService
 	workflowSvc    *workflow.WorkflowService
 	statisticsSvc  *statistics.StatisticsService
 	notifySvc      *notify.NotifyService
+	privateCASvc   *privateca.PrivateCAService
 )
 
 func BindRouter(router *router.Router[*core.RequestEvent]) {
 	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
+	workflowApprovalRepo := repository.NewWorkflowApprovalRepository()
 	acmeAccountRepo := repository.NewACMEAccountRepository()
 	certificateRepo := repository.NewCertificateRepository()
 	statisticsRepo := repository.NewStatisticsRepository()
+	privateCARepo := repository.NewPrivateCARepository()
+	acmeServerClientRepo := repository.NewACMEServerClientRepository()
+	acmeServerOrderRepo := repository.NewACMEServerOrderRepository()
 
-	certificateSvc = certificate.NewCertificateService(acmeAccountRepo, certificateRepo)
-	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo)
+	acmeAccountSvc = acmeaccount.NewACMEAccountService(acmeAccountRepo)
+	acmeServerSvc = acmeserver.NewACMEServerService(acmeServerClientRepo)
+	acmeServer = acmeserver.NewServer(acmeServerClientRepo, acmeServerOrderRepo, certificateRepo, privateCARepo, accessRepo)
+	workflowSvc = workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, workflowApprovalRepo, accessRepo, certificateRepo, privateCARepo)
+	privateCASvc = privateca.NewPrivateCAService(privateCARepo)
+	certificateSvc = certificate.NewCertificateService(accessRepo, acmeAccountRepo, certificateRepo, workflowRepo, privateCASvc, workflowSvc)
 	statisticsSvc = statistics.NewStatisticsService(statisticsRepo)
 	notifySvc = notify.NewNotifyService(accessRepo)
 
 	group := router.Group("/api")
 	group.Bind(apis.RequireSuperuserAuth())
+	handlers.NewACMEAccountsHandler(group, acmeAccountSvc)
+	handlers.NewACMEServerClientsHandler(group, acmeServerSvc)
 	handlers.NewCertificatesHandler(group, certificateSvc)
 	handlers.NewWorkflowsHandler(group, workflowSvc)
 	handlers.NewStatisticsHandler(group, statisticsSvc)
 	handlers.NewNotificationsHandler(group, notifySvc)
+	handlers.NewPrivateCAsHandler(group, privateCASvc)
+
+	webhookGroup := router.Group("/api/webhooks")
+	handlers.NewWorkflowWebhooksHandler(webhookGroup, workflowSvc)
+
+	acmeGroup := router.Group("/acme")
+	handlers.NewACMEServerHandler(acmeGroup, acmeServer)
+}
+
+func Teardown() {
+	if acmeServer != nil {
+		acmeServer.Shutdown(context.Background())
+	}
 }
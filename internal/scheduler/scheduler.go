@@ -5,18 +5,24 @@ import (
 
 	"github.com/certimate-go/certimate/internal/app"
 	"github.com/certimate-go/certimate/internal/certificate"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/workflow"
 )
 
 func Setup() {
+	accessRepo := repository.NewAccessRepository()
 	workflowRepo := repository.NewWorkflowRepository()
 	workflowRunRepo := repository.NewWorkflowRunRepository()
+	workflowVersionRepo := repository.NewWorkflowVersionRepository()
+	workflowApprovalRepo := repository.NewWorkflowApprovalRepository()
 	acmeAccountRepo := repository.NewACMEAccountRepository()
 	certificateRepo := repository.NewCertificateRepository()
+	privateCARepo := repository.NewPrivateCARepository()
 
-	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo)
-	certificateSvc := certificate.NewCertificateService(acmeAccountRepo, certificateRepo)
+	workflowSvc := workflow.NewWorkflowService(workflowRepo, workflowRunRepo, workflowVersionRepo, workflowApprovalRepo, accessRepo, certificateRepo, privateCARepo)
+	privateCASvc := privateca.NewPrivateCAService(privateCARepo)
+	certificateSvc := certificate.NewCertificateService(accessRepo, acmeAccountRepo, certificateRepo, workflowRepo, privateCASvc, workflowSvc)
 
 	if err := initWorkflowScheduler(workflowSvc); err != nil {
 		app.GetLogger().Error("failed to init workflow scheduler", slog.Any("error", err))
@@ -0,0 +1,470 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+const (
+	workflowBundleFormatJSON = "json"
+	workflowBundleFormatYAML = "yaml"
+)
+
+const (
+	workflowImportActionCreated   = "created"
+	workflowImportActionUpdated   = "updated"
+	workflowImportActionUnchanged = "unchanged"
+	workflowImportActionSkipped   = "skipped"
+)
+
+const workflowImportVersionComment = "Imported from bundle"
+
+func (s *WorkflowService) ExportBundle(ctx context.Context, req *dtos.WorkflowExportBundleReq) (*dtos.WorkflowExportBundleResp, error) {
+	format := req.Format
+	if format == "" {
+		format = workflowBundleFormatJSON
+	} else if format != workflowBundleFormatJSON && format != workflowBundleFormatYAML {
+		return nil, domain.NewError(http.StatusBadRequest, fmt.Sprintf("unsupported bundle format: '%s'", format))
+	}
+
+	// 未指定工作流时导出全部已发布的工作流；显式指定的工作流必须已发布
+	var workflows []*domain.Workflow
+	if len(req.WorkflowIds) == 0 {
+		list, err := s.workflowRepo.ListAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, workflow := range list {
+			if workflow.HasContent {
+				workflows = append(workflows, workflow)
+			}
+		}
+	} else {
+		for _, workflowId := range req.WorkflowIds {
+			workflow, err := s.workflowRepo.GetById(ctx, workflowId)
+			if err != nil {
+				return nil, err
+			} else if !workflow.HasContent {
+				return nil, domain.NewError(http.StatusBadRequest, fmt.Sprintf("workflow '%s' has not been published yet", workflow.Name))
+			}
+
+			workflows = append(workflows, workflow)
+		}
+	}
+
+	bundle := &domain.WorkflowBundle{
+		Kind:       domain.WorkflowBundleKind,
+		Version:    domain.WorkflowBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Accesses:   make([]*domain.WorkflowBundleAccess, 0),
+		PrivateCAs: make([]*domain.WorkflowBundlePrivateCA, 0),
+		Workflows:  make([]*domain.WorkflowBundleWorkflow, 0, len(workflows)),
+	}
+
+	// 工作流之间的引用只能在包内解析，因此被引用的工作流须一并导出
+	workflowRefs := make(map[string]string, len(workflows))
+	for i, workflow := range workflows {
+		workflowRefs[workflow.Id] = fmt.Sprintf("workflow_%d", i+1)
+	}
+
+	// 将工作流图中的授权 ID、私有 CA ID 替换为包内引用标识，相同的授权或私有 CA 共用同一标识
+	accessRefs := make(map[string]string)
+	privateCARefs := make(map[string]string)
+	for _, workflow := range workflows {
+		graph, err := workflow.GraphContent.DeepClone()
+		if err != nil {
+			return nil, err
+		}
+
+		var walkErr error
+		graph.WalkWorkflowIds(func(workflowId string) string {
+			if ref, ok := workflowRefs[workflowId]; ok {
+				return ref
+			}
+
+			if walkErr == nil {
+				walkErr = domain.NewError(http.StatusBadRequest, fmt.Sprintf("workflow '%s' references workflow #%s which is not included in the bundle", workflow.Name, workflowId))
+			}
+			return workflowId
+		})
+		graph.WalkPrivateCAIds(func(privateCAId string) string {
+			if walkErr != nil {
+				return privateCAId
+			}
+
+			if ref, ok := privateCARefs[privateCAId]; ok {
+				return ref
+			}
+
+			privateCA, err := s.privateCARepo.GetById(ctx, privateCAId)
+			if err != nil {
+				if errors.Is(err, domain.ErrRecordNotFound) {
+					walkErr = fmt.Errorf("workflow '%s' references a non-existent private ca '%s'", workflow.Name, privateCAId)
+				} else {
+					walkErr = err
+				}
+				return privateCAId
+			}
+
+			ref := fmt.Sprintf("privateca_%d", len(bundle.PrivateCAs)+1)
+			privateCARefs[privateCAId] = ref
+			bundle.PrivateCAs = append(bundle.PrivateCAs, &domain.WorkflowBundlePrivateCA{
+				Ref:  ref,
+				Name: privateCA.Name,
+			})
+			return ref
+		})
+		graph.WalkAccessIds(func(accessId string) string {
+			if walkErr != nil {
+				return accessId
+			}
+
+			if ref, ok := accessRefs[accessId]; ok {
+				return ref
+			}
+
+			access, err := s.accessRepo.GetById(ctx, accessId)
+			if err != nil {
+				if errors.Is(err, domain.ErrRecordNotFound) {
+					walkErr = fmt.Errorf("workflow '%s' references a non-existent access '%s'", workflow.Name, accessId)
+				} else {
+					walkErr = err
+				}
+				return accessId
+			}
+
+			ref := fmt.Sprintf("access_%d", len(bundle.Accesses)+1)
+			accessRefs[accessId] = ref
+			bundle.Accesses = append(bundle.Accesses, &domain.WorkflowBundleAccess{
+				Ref:      ref,
+				Name:     access.Name,
+				Provider: access.Provider,
+			})
+			return ref
+		})
+		if walkErr != nil {
+			return nil, walkErr
+		}
+
+		bundle.Workflows = append(bundle.Workflows, &domain.WorkflowBundleWorkflow{
+			Ref:         workflowRefs[workflow.Id],
+			Name:        workflow.Name,
+			Description: workflow.Description,
+			Enabled:     workflow.Enabled,
+			Graph:       graph,
+		})
+	}
+
+	resp := &dtos.WorkflowExportBundleResp{
+		FileName: fmt.Sprintf("certimate_workflows_%s.%s", bundle.ExportedAt.Format("20060102150405"), format),
+	}
+	switch format {
+	case workflowBundleFormatJSON:
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		resp.Data = data
+		resp.ContentType = "application/json"
+
+	case workflowBundleFormatYAML:
+		data, err := yaml.Marshal(bundle)
+		if err != nil {
+			return nil, err
+		}
+		resp.Data = data
+		resp.ContentType = "application/yaml"
+	}
+
+	return resp, nil
+}
+
+func (s *WorkflowService) ImportBundle(ctx context.Context, req *dtos.WorkflowImportBundleReq) (*dtos.WorkflowImportBundleResp, error) {
+	// YAML 是 JSON 的超集，因此可统一按 YAML 解析
+	bundle := &domain.WorkflowBundle{}
+	if err := yaml.Unmarshal(req.Data, bundle); err != nil {
+		return nil, domain.NewError(http.StatusBadRequest, fmt.Sprintf("malformed workflow bundle: %s", err.Error()))
+	} else if err := bundle.Verify(); err != nil {
+		return nil, domain.NewError(http.StatusBadRequest, fmt.Sprintf("invalid workflow bundle: %s", err.Error()))
+	}
+
+	resp := &dtos.WorkflowImportBundleResp{
+		Workflows:         make([]*dtos.WorkflowImportBundleResult, 0, len(bundle.Workflows)),
+		MissingAccesses:   make([]*dtos.WorkflowImportBundleMissingAccess, 0),
+		MissingPrivateCAs: make([]*dtos.WorkflowImportBundleMissingPrivateCA, 0),
+	}
+
+	// 按名称及提供商将包内引用映射为目标实例中的授权，须恰好匹配一个
+	accessIds := make(map[string]string)
+	for _, access := range bundle.Accesses {
+		matches, err := s.accessRepo.ListByNameAndProvider(ctx, access.Name, access.Provider)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(matches) {
+		case 0:
+			resp.MissingAccesses = append(resp.MissingAccesses, &dtos.WorkflowImportBundleMissingAccess{WorkflowBundleAccess: *access, Reason: "not found"})
+		case 1:
+			accessIds[access.Ref] = matches[0].Id
+		default:
+			resp.MissingAccesses = append(resp.MissingAccesses, &dtos.WorkflowImportBundleMissingAccess{WorkflowBundleAccess: *access, Reason: "ambiguous"})
+		}
+	}
+
+	// 按名称将包内引用映射为目标实例中的私有 CA，须恰好匹配一个
+	privateCAIds := make(map[string]string)
+	if len(bundle.PrivateCAs) > 0 {
+		privateCAs, err := s.privateCARepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, privateCA := range bundle.PrivateCAs {
+			matches := lo.Filter(privateCAs, func(item *domain.PrivateCA, _ int) bool { return item.Name == privateCA.Name })
+			switch len(matches) {
+			case 0:
+				resp.MissingPrivateCAs = append(resp.MissingPrivateCAs, &dtos.WorkflowImportBundleMissingPrivateCA{WorkflowBundlePrivateCA: *privateCA, Reason: "not found"})
+			case 1:
+				privateCAIds[privateCA.Ref] = matches[0].Id
+			default:
+				resp.MissingPrivateCAs = append(resp.MissingPrivateCAs, &dtos.WorkflowImportBundleMissingPrivateCA{WorkflowBundlePrivateCA: *privateCA, Reason: "ambiguous"})
+			}
+		}
+	}
+
+	plans := make([]*workflowImportPlan, 0, len(bundle.Workflows))
+	for _, item := range bundle.Workflows {
+		plan, err := s.prepareBundleWorkflow(ctx, item, accessIds, privateCAIds)
+		if err != nil {
+			plan = &workflowImportPlan{item: item, err: err}
+		}
+
+		plans = append(plans, plan)
+	}
+
+	// 引用了无法导入的工作流时，引用方也无法导入；逐轮传递直至不再变化
+	plansByRef := lo.SliceToMap(
+		lo.Filter(plans, func(plan *workflowImportPlan, _ int) bool { return plan.item.Ref != "" }),
+		func(plan *workflowImportPlan) (string, *workflowImportPlan) { return plan.item.Ref, plan },
+	)
+	for changed := true; changed; {
+		changed = false
+		for _, plan := range plans {
+			if plan.err != nil {
+				continue
+			}
+
+			var unresolved []string
+			plan.graph.WalkWorkflowIds(func(ref string) string {
+				if target := plansByRef[ref]; target.err != nil {
+					unresolved = append(unresolved, ref)
+				}
+				return ref
+			})
+			if len(unresolved) > 0 {
+				plan.err = fmt.Errorf("unresolved workflow references: %v", unresolved)
+				changed = true
+			}
+		}
+	}
+
+	// 被其他工作流引用的新工作流需先行创建，以便引用方获得其 ID
+	if !req.DryRun {
+		for _, plan := range plans {
+			if plan.err != nil || !plan.isNew || !isWorkflowReferenced(plans, plan.item.Ref) {
+				continue
+			}
+
+			placeholder := &domain.Workflow{
+				Name:        plan.workflow.Name,
+				Description: plan.workflow.Description,
+			}
+			if _, err := s.workflowRepo.Save(ctx, placeholder); err != nil {
+				return nil, err
+			}
+
+			plan.workflow.Id = placeholder.Id
+		}
+	}
+
+	for _, plan := range plans {
+		var result *dtos.WorkflowImportBundleResult
+		if plan.err == nil {
+			plan.graph.WalkWorkflowIds(func(ref string) string {
+				return plansByRef[ref].workflow.Id
+			})
+
+			result, plan.err = s.importBundleWorkflow(ctx, plan, req)
+		}
+		if plan.err != nil {
+			result = &dtos.WorkflowImportBundleResult{
+				Name:   plan.item.Name,
+				Action: workflowImportActionSkipped,
+				Error:  plan.err.Error(),
+			}
+		}
+
+		resp.Workflows = append(resp.Workflows, result)
+	}
+
+	return resp, nil
+}
+
+type workflowImportPlan struct {
+	item     *domain.WorkflowBundleWorkflow
+	graph    *domain.WorkflowGraph // 已映射授权及私有 CA，工作流引用仍为包内引用标识
+	workflow *domain.Workflow      // 目标实例中的同名工作流；不存在时为待新建的工作流
+	isNew    bool
+	err      error
+}
+
+func (s *WorkflowService) prepareBundleWorkflow(ctx context.Context, item *domain.WorkflowBundleWorkflow, accessIds map[string]string, privateCAIds map[string]string) (*workflowImportPlan, error) {
+	graph, err := item.Graph.DeepClone()
+	if err != nil {
+		return nil, err
+	}
+
+	var unresolvedAccesses, unresolvedPrivateCAs []string
+	graph.WalkAccessIds(func(ref string) string {
+		if accessId, ok := accessIds[ref]; ok {
+			return accessId
+		}
+
+		unresolvedAccesses = append(unresolvedAccesses, ref)
+		return ref
+	})
+	graph.WalkPrivateCAIds(func(ref string) string {
+		if privateCAId, ok := privateCAIds[ref]; ok {
+			return privateCAId
+		}
+
+		unresolvedPrivateCAs = append(unresolvedPrivateCAs, ref)
+		return ref
+	})
+	if len(unresolvedAccesses) > 0 {
+		return nil, fmt.Errorf("unresolved access references: %v", unresolvedAccesses)
+	} else if len(unresolvedPrivateCAs) > 0 {
+		return nil, fmt.Errorf("unresolved private ca references: %v", unresolvedPrivateCAs)
+	} else if err := graph.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid workflow graph: %w", err)
+	}
+
+	// 以名称作为工作流的唯一标识，存在时更新，否则新建
+	existings, err := s.workflowRepo.ListByName(ctx, item.Name)
+	if err != nil {
+		return nil, err
+	} else if len(existings) > 1 {
+		return nil, fmt.Errorf("more than one workflow named '%s' exists", item.Name)
+	}
+
+	plan := &workflowImportPlan{item: item, graph: graph}
+	if len(existings) == 1 {
+		plan.workflow = existings[0]
+	} else {
+		plan.workflow = &domain.Workflow{Name: item.Name, Description: item.Description}
+		plan.isNew = true
+	}
+
+	return plan, nil
+}
+
+func (s *WorkflowService) importBundleWorkflow(ctx context.Context, plan *workflowImportPlan, req *dtos.WorkflowImportBundleReq) (*dtos.WorkflowImportBundleResult, error) {
+	item, graph, workflow := plan.item, plan.graph, plan.workflow
+
+	result := &dtos.WorkflowImportBundleResult{Name: item.Name}
+	if plan.isNew {
+		result.Action = workflowImportActionCreated
+		workflow.Enabled = item.Enabled
+	} else {
+		result.WorkflowId = workflow.Id
+
+		if workflow.HasContent && workflow.Description == item.Description {
+			unchanged, err := isWorkflowGraphEqual(workflow.GraphContent, graph)
+			if err != nil {
+				return nil, err
+			} else if unchanged {
+				result.Action = workflowImportActionUnchanged
+				return result, nil
+			}
+		}
+
+		// 发布时将以导入的工作流图覆盖草稿，因此须告知调用方
+		result.Action = workflowImportActionUpdated
+		result.DraftOverwritten = workflow.HasDraft
+		workflow.Description = item.Description
+	}
+
+	if req.DryRun {
+		return result, nil
+	}
+
+	if _, err := s.publishGraph(ctx, workflow, graph, req.Operator, workflowImportVersionComment); err != nil {
+		return nil, err
+	}
+
+	result.WorkflowId = workflow.Id
+	return result, nil
+}
+
+// 判断包内是否有工作流（含其自身）引用了指定的工作流。
+func isWorkflowReferenced(plans []*workflowImportPlan, ref string) bool {
+	if ref == "" {
+		return false
+	}
+
+	for _, plan := range plans {
+		if plan.err != nil {
+			continue
+		}
+
+		referenced := false
+		plan.graph.WalkWorkflowIds(func(id string) string {
+			referenced = referenced || id == ref
+			return id
+		})
+		if referenced {
+			return true
+		}
+	}
+	return false
+}
+
+// 判断两个工作流图是否相同。比较时忽略 JSON 的格式差异（如数值类型等）。
+func isWorkflowGraphEqual(a, b *domain.WorkflowGraph) (bool, error) {
+	unmarshal := func(g *domain.WorkflowGraph) (any, error) {
+		data, err := json.Marshal(g)
+		if err != nil {
+			return nil, err
+		}
+
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	va, err := unmarshal(a)
+	if err != nil {
+		return false, err
+	}
+	vb, err := unmarshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(va, vb), nil
+}
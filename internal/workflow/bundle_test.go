@@ -0,0 +1,196 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+type mockBundleWorkflowRepository struct {
+	workflowRepository
+
+	workflows []*domain.Workflow
+}
+
+func (r *mockBundleWorkflowRepository) ListAll(ctx context.Context) ([]*domain.Workflow, error) {
+	return r.workflows, nil
+}
+
+func (r *mockBundleWorkflowRepository) ListByName(ctx context.Context, name string) ([]*domain.Workflow, error) {
+	workflows := make([]*domain.Workflow, 0)
+	for _, workflow := range r.workflows {
+		if workflow.Name == name {
+			workflows = append(workflows, workflow)
+		}
+	}
+	return workflows, nil
+}
+
+func (r *mockBundleWorkflowRepository) GetById(ctx context.Context, id string) (*domain.Workflow, error) {
+	for _, workflow := range r.workflows {
+		if workflow.Id == id {
+			return workflow, nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockBundleWorkflowRepository) SaveWithTx(ctx context.Context, txApp core.App, workflow *domain.Workflow) (*domain.Workflow, error) {
+	panic("unexpected write in dry run")
+}
+
+type mockBundleAccessRepository struct {
+	accesses []*domain.Access
+}
+
+func (r *mockBundleAccessRepository) GetById(ctx context.Context, id string) (*domain.Access, error) {
+	for _, access := range r.accesses {
+		if access.Id == id {
+			return access, nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockBundleAccessRepository) ListByNameAndProvider(ctx context.Context, name string, provider string) ([]*domain.Access, error) {
+	accesses := make([]*domain.Access, 0)
+	for _, access := range r.accesses {
+		if access.Name == name && access.Provider == provider {
+			accesses = append(accesses, access)
+		}
+	}
+	return accesses, nil
+}
+
+type mockBundlePrivateCARepository struct {
+	privateCAs []*domain.PrivateCA
+}
+
+func (r *mockBundlePrivateCARepository) List(ctx context.Context) ([]*domain.PrivateCA, error) {
+	return r.privateCAs, nil
+}
+
+func (r *mockBundlePrivateCARepository) GetById(ctx context.Context, id string) (*domain.PrivateCA, error) {
+	for _, privateCA := range r.privateCAs {
+		if privateCA.Id == id {
+			return privateCA, nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func newBundleTestGraph(config domain.WorkflowNodeConfig) *domain.WorkflowGraph {
+	nodeType := domain.WorkflowNodeTypeBizNotify
+	if _, ok := config["workflowId"]; ok {
+		nodeType = domain.WorkflowNodeTypeCallWorkflow
+	}
+
+	return &domain.WorkflowGraph{
+		Nodes: []*domain.WorkflowNode{
+			{Id: "start", Type: domain.WorkflowNodeTypeStart},
+			{Id: "node", Type: nodeType, Data: domain.WorkflowNodeData{Config: config}},
+			{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+		},
+	}
+}
+
+func newBundleTestService(workflows []*domain.Workflow, accesses []*domain.Access, privateCAs []*domain.PrivateCA) *WorkflowService {
+	return &WorkflowService{
+		workflowRepo:  &mockBundleWorkflowRepository{workflows: workflows},
+		accessRepo:    &mockBundleAccessRepository{accesses: accesses},
+		privateCARepo: &mockBundlePrivateCARepository{privateCAs: privateCAs},
+	}
+}
+
+func TestWorkflowBundle_ExportAndImport(t *testing.T) {
+	ctx := context.Background()
+
+	// 源实例：caller 调用 callee，并引用授权及私有 CA
+	source := newBundleTestService(
+		[]*domain.Workflow{
+			{Meta: domain.Meta{Id: "wf_caller"}, Name: "caller", HasContent: true, GraphContent: newBundleTestGraph(domain.WorkflowNodeConfig{
+				"workflowId":       "wf_callee",
+				"providerAccessId": "acc_1",
+				"caProviderConfig": map[string]any{"privateCAId": "ca_1"},
+			})},
+			{Meta: domain.Meta{Id: "wf_callee"}, Name: "callee", HasContent: true, GraphContent: newBundleTestGraph(domain.WorkflowNodeConfig{})},
+			{Meta: domain.Meta{Id: "wf_draft"}, Name: "draft"},
+		},
+		[]*domain.Access{{Meta: domain.Meta{Id: "acc_1"}, Name: "dns", Provider: "cloudflare"}},
+		[]*domain.PrivateCA{{Meta: domain.Meta{Id: "ca_1"}, Name: "internal"}},
+	)
+
+	exported, err := source.ExportBundle(ctx, &dtos.WorkflowExportBundleReq{})
+	require.NoError(t, err)
+
+	bundle := &domain.WorkflowBundle{}
+	require.NoError(t, json.Unmarshal(exported.Data, bundle))
+	require.NoError(t, bundle.Verify())
+	require.Len(t, bundle.Workflows, 2, "unpublished workflows should not be exported")
+	assert.Equal(t, []*domain.WorkflowBundleAccess{{Ref: "access_1", Name: "dns", Provider: "cloudflare"}}, bundle.Accesses)
+	assert.Equal(t, []*domain.WorkflowBundlePrivateCA{{Ref: "privateca_1", Name: "internal"}}, bundle.PrivateCAs)
+	callerConfig := bundle.Workflows[0].Graph.Nodes[1].Data.Config
+	assert.Equal(t, bundle.Workflows[1].Ref, callerConfig["workflowId"])
+	assert.Equal(t, "access_1", callerConfig["providerAccessId"])
+
+	t.Run("export fails when a referenced workflow is excluded", func(t *testing.T) {
+		_, err := source.ExportBundle(ctx, &dtos.WorkflowExportBundleReq{WorkflowIds: []string{"wf_caller"}})
+		assert.ErrorContains(t, err, "not included in the bundle")
+	})
+
+	t.Run("import into an instance with matching references", func(t *testing.T) {
+		target := newBundleTestService(
+			[]*domain.Workflow{
+				{Meta: domain.Meta{Id: "wf_existing"}, Name: "callee", HasContent: true, HasDraft: true, GraphContent: newBundleTestGraph(domain.WorkflowNodeConfig{"workflowId": "wf_other"})},
+			},
+			[]*domain.Access{{Meta: domain.Meta{Id: "acc_target"}, Name: "dns", Provider: "cloudflare"}},
+			[]*domain.PrivateCA{{Meta: domain.Meta{Id: "ca_target"}, Name: "internal"}},
+		)
+
+		res, err := target.ImportBundle(ctx, &dtos.WorkflowImportBundleReq{Data: exported.Data, DryRun: true})
+		require.NoError(t, err)
+		assert.Empty(t, res.MissingAccesses)
+		assert.Empty(t, res.MissingPrivateCAs)
+		require.Len(t, res.Workflows, 2)
+		assert.Equal(t, workflowImportActionCreated, res.Workflows[0].Action)
+		assert.Empty(t, res.Workflows[0].Error)
+		assert.Equal(t, workflowImportActionUpdated, res.Workflows[1].Action)
+		assert.Equal(t, "wf_existing", res.Workflows[1].WorkflowId)
+		assert.True(t, res.Workflows[1].DraftOverwritten)
+	})
+
+	t.Run("import skips workflows referencing skipped workflows", func(t *testing.T) {
+		bundle := &domain.WorkflowBundle{
+			Kind:     domain.WorkflowBundleKind,
+			Version:  domain.WorkflowBundleVersion,
+			Accesses: []*domain.WorkflowBundleAccess{{Ref: "access_1", Name: "dns", Provider: "cloudflare"}},
+			Workflows: []*domain.WorkflowBundleWorkflow{
+				{Ref: "workflow_1", Name: "a", Graph: newBundleTestGraph(domain.WorkflowNodeConfig{"workflowId": "workflow_2"})},
+				{Ref: "workflow_2", Name: "b", Graph: newBundleTestGraph(domain.WorkflowNodeConfig{"workflowId": "workflow_3"})},
+				{Ref: "workflow_3", Name: "c", Graph: newBundleTestGraph(domain.WorkflowNodeConfig{"providerAccessId": "access_1"})},
+				{Ref: "workflow_4", Name: "d", Graph: newBundleTestGraph(domain.WorkflowNodeConfig{})},
+			},
+		}
+		data, err := json.Marshal(bundle)
+		require.NoError(t, err)
+
+		target := newBundleTestService(nil, nil, nil)
+		res, err := target.ImportBundle(ctx, &dtos.WorkflowImportBundleReq{Data: data, DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, res.MissingAccesses, 1)
+		assert.Equal(t, "not found", res.MissingAccesses[0].Reason)
+		require.Len(t, res.Workflows, 4)
+		for _, result := range res.Workflows[:3] {
+			assert.Equal(t, workflowImportActionSkipped, result.Action, result.Name)
+			assert.NotEmpty(t, result.Error, result.Name)
+		}
+		assert.Equal(t, workflowImportActionCreated, res.Workflows[3].Action)
+	})
+}
@@ -18,6 +18,11 @@ type workflowRunRepository interface {
 	ResetStatusIfHanging(ctx context.Context) error
 }
 
+type workflowApprovalRepository interface {
+	ListPending(ctx context.Context) ([]*domain.WorkflowApproval, error)
+	Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error)
+}
+
 type workflowLogRepository interface {
 	Save(ctx context.Context, workflowLog *domain.WorkflowLog) (*domain.WorkflowLog, error)
 }
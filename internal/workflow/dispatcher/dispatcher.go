@@ -37,12 +37,14 @@ type WorkflowDispatcher interface {
 	Shutdown(ctx context.Context) error
 	Start(ctx context.Context, runId string) error
 	Cancel(ctx context.Context, runId string) error
+	Resume(ctx context.Context, runId string, nodeId string, signal engine.ResumeSignal) error
 }
 
 type Statistics struct {
 	Concurrency      int
 	PendingRunIds    []string
 	ProcessingRunIds []string
+	WaitingRunIds    []string
 }
 
 type workflowDispatcher struct {
@@ -52,15 +54,23 @@ type workflowDispatcher struct {
 	taskMtx         sync.RWMutex
 	pendingRunQueue []string
 	processingTasks map[string]*taskInfo // Key: RunId
+	rebuiltRuns     map[string]string    // 服务重启后需从审批节点恢复执行的运行，Key: RunId，Value: NodeId
 
-	workflowRepo    workflowRepository
-	workflowRunRepo workflowRunRepository
-	workflowLogRepo workflowLogRepository
+	workflowRepo         workflowRepository
+	workflowRunRepo      workflowRunRepository
+	workflowApprovalRepo workflowApprovalRepository
+	workflowLogRepo      workflowLogRepository
 
 	syslog *slog.Logger
 }
 
-var _ WorkflowDispatcher = (*workflowDispatcher)(nil)
+var (
+	_ WorkflowDispatcher        = (*workflowDispatcher)(nil)
+	_ engine.ChildRunDispatcher = (*workflowDispatcher)(nil)
+)
+
+// 子运行等待相同工作流的其他运行结束时的轮询间隔。
+var childRunPollInterval = time.Second
 
 func (wd *workflowDispatcher) GetStatistics() Statistics {
 	wd.taskMtx.RLock()
@@ -70,12 +80,17 @@ func (wd *workflowDispatcher) GetStatistics() Statistics {
 		Concurrency:      wd.concurrency,
 		PendingRunIds:    make([]string, 0),
 		ProcessingRunIds: make([]string, 0),
+		WaitingRunIds:    make([]string, 0),
 	}
 	for _, pendingRunId := range wd.pendingRunQueue {
 		stats.PendingRunIds = append(stats.PendingRunIds, pendingRunId)
 	}
-	for _, processingRunId := range wd.processingTasks {
-		stats.ProcessingRunIds = append(stats.ProcessingRunIds, processingRunId.RunId)
+	for _, processingTask := range wd.processingTasks {
+		if processingTask.isWaiting() {
+			stats.WaitingRunIds = append(stats.WaitingRunIds, processingTask.RunId)
+		} else {
+			stats.ProcessingRunIds = append(stats.ProcessingRunIds, processingTask.RunId)
+		}
 	}
 
 	return stats
@@ -89,15 +104,75 @@ func (wd *workflowDispatcher) Bootup(ctx context.Context) error {
 	wd.taskMtx.Lock()
 	defer wd.taskMtx.Unlock()
 
+	// 服务重启前等待审批的运行，需在重置状态后重新排队，并从审批节点恢复执行
+	rebuiltRuns, err := wd.collectRebuildableRuns(ctx)
+	if err != nil {
+		return err
+	}
+
 	if err := wd.workflowRunRepo.ResetStatusIfHanging(ctx); err != nil {
 		return err
 	}
 
+	for _, workflowRun := range rebuiltRuns {
+		workflowRun.Status = domain.WorkflowRunStatusTypePending
+		if _, err := wd.workflowRunRepo.SaveWithCascading(ctx, workflowRun); err != nil {
+			wd.syslog.Error(fmt.Sprintf("failed to rebuild workrun #%s", workflowRun.Id), slog.Any("error", err))
+			delete(wd.rebuiltRuns, workflowRun.Id)
+			continue
+		}
+
+		wd.pendingRunQueue = append(wd.pendingRunQueue, workflowRun.Id)
+		wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s is rebuilt, it will be resumed at node #%s", workflowRun.WorkflowId, workflowRun.Id, wd.rebuiltRuns[workflowRun.Id]))
+	}
+	if len(wd.pendingRunQueue) > 0 {
+		go func() { wd.tryNextAsync() }()
+	}
+
 	wd.booted = true
 
 	return nil
 }
 
+// 收集服务重启前等待审批的运行。
+// 审批所在的运行已不再等待、或审批节点无法在运行的工作流图中定位（如位于内联调用的子工作流中）时，该运行无法重建，其审批将被取消。
+func (wd *workflowDispatcher) collectRebuildableRuns(ctx context.Context) ([]*domain.WorkflowRun, error) {
+	approvals, err := wd.workflowApprovalRepo.ListPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRuns := make([]*domain.WorkflowRun, 0)
+	for _, approval := range approvals {
+		if _, ok := wd.rebuiltRuns[approval.RunId]; ok {
+			continue
+		}
+
+		workflowRun, err := wd.workflowRunRepo.GetById(ctx, approval.RunId)
+		if err != nil && !domain.IsRecordNotFoundError(err) {
+			return nil, err
+		}
+
+		rebuildable := workflowRun != nil && workflowRun.Status == domain.WorkflowRunStatusTypeWaiting && workflowRun.Graph != nil
+		if rebuildable {
+			_, rebuildable = workflowRun.Graph.GetNodeById(approval.NodeId)
+		}
+		if !rebuildable {
+			approval.Status = domain.WorkflowApprovalStatusTypeCanceled
+			approval.DecidedAt = time.Now()
+			if _, err := wd.workflowApprovalRepo.Decide(ctx, approval); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		wd.rebuiltRuns[workflowRun.Id] = approval.NodeId
+		workflowRuns = append(workflowRuns, workflowRun)
+	}
+
+	return workflowRuns, nil
+}
+
 func (wd *workflowDispatcher) Shutdown(ctx context.Context) error {
 	if !wd.booted {
 		return fmt.Errorf("could not re-shutdown")
@@ -114,6 +189,7 @@ func (wd *workflowDispatcher) Shutdown(ctx context.Context) error {
 	wd.booted = false
 	wd.pendingRunQueue = make([]string, 0)
 	wd.processingTasks = make(map[string]*taskInfo)
+	wd.rebuiltRuns = make(map[string]string)
 	return nil
 }
 
@@ -144,7 +220,7 @@ func (wd *workflowDispatcher) Cancel(ctx context.Context, runId string) error {
 	workflowRun, err := wd.workflowRunRepo.GetById(ctx, runId)
 	if err != nil {
 		return err
-	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing {
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing && workflowRun.Status != domain.WorkflowRunStatusTypeWaiting {
 		return fmt.Errorf("workrun #%s is already completed", workflowRun.Id)
 	}
 
@@ -185,6 +261,20 @@ func (wd *workflowDispatcher) Cancel(ctx context.Context, runId string) error {
 	return nil
 }
 
+func (wd *workflowDispatcher) Resume(ctx context.Context, runId string, nodeId string, signal engine.ResumeSignal) error {
+	wd.taskMtx.RLock()
+	task, exists := wd.processingTasks[runId]
+	wd.taskMtx.RUnlock()
+
+	if !exists || task.engine == nil {
+		return fmt.Errorf("workrun #%s is not processing", runId)
+	} else if !task.isWaiting() {
+		return fmt.Errorf("workrun #%s is not waiting", runId)
+	}
+
+	return task.engine.Resume(nodeId, signal)
+}
+
 func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 	var workflow *domain.Workflow
 	var workflowRun *domain.WorkflowRun
@@ -240,10 +330,17 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 	}
 
 	// 初始化工作流引擎
-	logsBuf := make(domain.WorkflowLogs, 0)
 	we := engine.NewWorkflowEngine()
+	we.SetChildRunDispatcher(wd)
+	wd.taskMtx.Lock()
+	task.engine = we
+	wd.taskMtx.Unlock()
 	we.OnEnd(func(ctx context.Context) error {
-		if errmsg := logsBuf.ErrorString(); errmsg == "" {
+		task.runMtx.Lock()
+		defer task.runMtx.Unlock()
+
+		errmsg := task.logs.ErrorString()
+		if errmsg == "" {
 			workflowRun.Status = domain.WorkflowRunStatusTypeSucceeded
 			workflowRun.EndedAt = time.Now()
 		} else {
@@ -256,6 +353,9 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		return nil
 	})
 	we.OnError(func(ctx context.Context, err error) error {
+		task.runMtx.Lock()
+		defer task.runMtx.Unlock()
+
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			workflowRun.Status = domain.WorkflowRunStatusTypeCanceled
 			wd.workflowRunRepo.SaveWithCascading(context.Background(), workflowRun)
@@ -268,6 +368,36 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 
 		return nil
 	})
+	wd.bindNodeHooks(task, we, workflowRun)
+
+	// 执行工作流
+	execution := engine.WorkflowExecution{
+		WorkflowId:          workflow.Id,
+		WorkflowName:        workflow.Name,
+		WorkflowDescription: workflow.Description,
+		RunId:               workflowRun.Id,
+		RunTrigger:          workflowRun.Trigger,
+		RunAt:               workflowRun.StartedAt,
+		Graph:               workflowRun.Graph,
+		ResumedFromRunId:    workflowRun.ResumedFromRunId,
+		ResumedFromNodeId:   workflowRun.ResumedFromNodeId,
+		Variables:           workflowRun.Variables,
+	}
+	wd.taskMtx.Lock()
+	if nodeId, ok := wd.rebuiltRuns[workflowRun.Id]; ok {
+		// 服务重启后重建的运行，从其自身的审批节点恢复执行
+		execution.ResumedFromRunId = workflowRun.Id
+		execution.ResumedFromNodeId = nodeId
+		delete(wd.rebuiltRuns, workflowRun.Id)
+	}
+	wd.taskMtx.Unlock()
+	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s started", task.WorkflowId, task.RunId))
+	we.Invoke(task.ctx, execution)
+	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s stopped", task.WorkflowId, task.RunId))
+}
+
+// 注册节点级别的钩子，以持久化节点日志并在挂起及恢复时更新运行状态。常规运行及子运行共用。
+func (wd *workflowDispatcher) bindNodeHooks(task *taskInfo, we engine.WorkflowEngine, workflowRun *domain.WorkflowRun) {
 	we.OnNodeError(func(ctx context.Context, node *engine.Node, err error) error {
 		if errors.Is(err, engine.ErrTerminated) || errors.Is(err, engine.ErrBlocksException) {
 			return nil
@@ -282,7 +412,10 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		log.Level = int32(slog.LevelError)
 		log.Message = err.Error()
 		log.CreatedAt = time.Now()
-		logsBuf = append(logsBuf, log)
+		task.runMtx.Lock()
+		task.logs = append(task.logs, log)
+		workflowRun.ErrorNodeId = node.Id // 记录失败节点，以便后续从该节点恢复执行
+		task.runMtx.Unlock()
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -301,7 +434,9 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		log.Message = record.Message
 		log.Data = record.Data()
 		log.CreatedAt = time.Now()
-		logsBuf = append(logsBuf, log)
+		task.runMtx.Lock()
+		task.logs = append(task.logs, log)
+		task.runMtx.Unlock()
 
 		if _, err := wd.workflowLogRepo.Save(ctx, &log); err != nil {
 			wd.syslog.Error(err.Error())
@@ -310,18 +445,99 @@ func (wd *workflowDispatcher) tryExecuteAsync(task *taskInfo) {
 		return nil
 	})
 
-	// 执行工作流
-	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s started", task.WorkflowId, task.RunId))
-	we.Invoke(task.ctx, engine.WorkflowExecution{
-		WorkflowId:          workflow.Id,
-		WorkflowName:        workflow.Name,
-		WorkflowDescription: workflow.Description,
-		RunId:               workflowRun.Id,
-		RunTrigger:          workflowRun.Trigger,
-		RunAt:               workflowRun.StartedAt,
-		Graph:               workflowRun.Graph,
+	// 并行分支中可能同时有多个节点挂起，仅当所有挂起的节点均已恢复时，运行才恢复为执行中
+	we.OnNodeSuspend(func(ctx context.Context, node *engine.Node) error {
+		// 挂起期间释放工作槽位，以便等待队列中的其他任务得以执行
+		task.runMtx.Lock()
+		wd.taskMtx.Lock()
+		suspensions := wd.adjustSuspensions(task, 1)
+		wd.taskMtx.Unlock()
+
+		if suspensions == 1 {
+			workflowRun.Status = domain.WorkflowRunStatusTypeWaiting
+			wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
+		}
+		task.runMtx.Unlock()
+		wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s is waiting at node #%s", task.WorkflowId, task.RunId, node.Id))
+
+		go func() { wd.tryNextAsync() }()
+
+		return nil
+	})
+	we.OnNodeResume(func(ctx context.Context, node *engine.Node, signal *engine.ResumeSignal) error {
+		// 恢复执行的任务优先于等待队列中的任务，即使此时已达到最大并发数
+		task.runMtx.Lock()
+		wd.taskMtx.Lock()
+		suspensions := wd.adjustSuspensions(task, -1)
+		wd.taskMtx.Unlock()
+
+		if suspensions == 0 {
+			workflowRun.Status = domain.WorkflowRunStatusTypeProcessing
+			wd.workflowRunRepo.SaveWithCascading(task.ctx, workflowRun)
+		}
+		task.runMtx.Unlock()
+		wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s is resumed at node #%s", task.WorkflowId, task.RunId, node.Id))
+
+		return nil
 	})
-	wd.syslog.Info(fmt.Sprintf("workflow #%s's run #%s stopped", task.WorkflowId, task.RunId))
+}
+
+// 调整任务的挂起节点数，并同步调整其各级父运行的挂起节点数，返回调整后该任务的挂起节点数。
+// 子运行挂起时，其父运行也随之等待，因此同样不占用工作槽位。调用方需持有任务锁。
+func (wd *workflowDispatcher) adjustSuspensions(task *taskInfo, delta int) int {
+	for t := task; t != nil; t = wd.processingTasks[t.ParentRunId] {
+		t.suspensions = max(0, t.suspensions+delta)
+	}
+
+	return task.suspensions
+}
+
+// 以子运行模式调用工作流时，由调用节点所在的引擎回调。
+// 子运行需等待相同工作流的其他运行结束后才能执行；子运行在其父运行的工作槽位中执行，因此不受最大并发数的限制。
+func (wd *workflowDispatcher) DispatchChildRun(ctx context.Context, parentRunId string, workflowRun *domain.WorkflowRun, we engine.WorkflowEngine) (context.Context, func(), error) {
+	ticker := time.NewTicker(childRunPollInterval)
+	defer ticker.Stop()
+
+	logged := false
+	for {
+		wd.taskMtx.Lock()
+		if !wd.hasProcessingTask(workflowRun.WorkflowId) {
+			break
+		}
+		wd.taskMtx.Unlock()
+
+		if !logged {
+			logged = true
+			wd.syslog.Warn(fmt.Sprintf("workflow #%s's child run #%s is pending, because tasks that belonging to the same workflow already exists", workflowRun.WorkflowId, workflowRun.Id))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	ctxRun, ctxCancel := context.WithCancel(ctx)
+	task := &taskInfo{WorkflowId: workflowRun.WorkflowId, RunId: workflowRun.Id, ParentRunId: parentRunId, ctx: ctxRun, cancel: ctxCancel, engine: we}
+	wd.processingTasks[task.RunId] = task
+	wd.syslog.Info(fmt.Sprintf("workflow #%s's child run #%s is being dispatched ...", task.WorkflowId, task.RunId))
+	wd.taskMtx.Unlock()
+
+	wd.bindNodeHooks(task, we, workflowRun)
+
+	release := func() {
+		ctxCancel()
+
+		wd.taskMtx.Lock()
+		if wd.processingTasks[task.RunId] == task {
+			delete(wd.processingTasks, task.RunId)
+		}
+		wd.taskMtx.Unlock()
+
+		go func() { wd.tryNextAsync() }()
+	}
+	return ctxRun, release, nil
 }
 
 func (wd *workflowDispatcher) tryNextAsync() {
@@ -334,17 +550,9 @@ func (wd *workflowDispatcher) tryNextAsync() {
 			continue
 		}
 
-		var hasSameWorkflowTask bool // 相同 Workflow 的任务同一时间只能有一个 Run 在执行
-		for _, processingTask := range wd.processingTasks {
-			if processingTask.WorkflowId == workflowRun.WorkflowId {
-				hasSameWorkflowTask = true
-				break
-			}
-		}
-
-		if hasSameWorkflowTask {
+		if wd.hasProcessingTask(workflowRun.WorkflowId) {
 			wd.syslog.Warn(fmt.Sprintf("workflow #%s's run #%s is pending, because tasks that belonging to the same workflow already exists", workflowRun.WorkflowId, workflowRun.Id))
-		} else if len(wd.processingTasks) >= wd.concurrency && wd.concurrency > 0 {
+		} else if wd.countActiveTasks() >= wd.concurrency && wd.concurrency > 0 {
 			wd.syslog.Warn(fmt.Sprintf("workflow #%s's run #%s is pending, because the maximum concurrency (limit: %d) has been reached", workflowRun.WorkflowId, workflowRun.Id, wd.concurrency))
 		} else {
 			wd.taskMtx.RUnlock()
@@ -365,16 +573,38 @@ func (wd *workflowDispatcher) tryNextAsync() {
 	wd.taskMtx.RUnlock()
 }
 
+// 相同 Workflow 的任务同一时间只能有一个 Run 在执行。
+func (wd *workflowDispatcher) hasProcessingTask(workflowId string) bool {
+	for _, task := range wd.processingTasks {
+		if task.WorkflowId == workflowId {
+			return true
+		}
+	}
+	return false
+}
+
+func (wd *workflowDispatcher) countActiveTasks() int {
+	count := 0
+	for _, task := range wd.processingTasks {
+		if !task.isWaiting() && task.ParentRunId == "" {
+			count++
+		}
+	}
+	return count
+}
+
 func newWorkflowDispatcher() WorkflowDispatcher {
 	return &workflowDispatcher{
 		concurrency: envMaxWorkers,
 
 		pendingRunQueue: make([]string, 0),
 		processingTasks: make(map[string]*taskInfo),
+		rebuiltRuns:     make(map[string]string),
 
-		workflowRepo:    repository.NewWorkflowRepository(),
-		workflowRunRepo: repository.NewWorkflowRunRepository(),
-		workflowLogRepo: repository.NewWorkflowLogRepository(),
+		workflowRepo:         repository.NewWorkflowRepository(),
+		workflowRunRepo:      repository.NewWorkflowRunRepository(),
+		workflowApprovalRepo: repository.NewWorkflowApprovalRepository(),
+		workflowLogRepo:      repository.NewWorkflowLogRepository(),
 
 		syslog: app.GetLogger(),
 	}
@@ -0,0 +1,214 @@
+package dispatcher
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
+	"github.com/certimate-go/certimate/pkg/logging"
+)
+
+type mockWorkflowRunRepository struct {
+	workflowRuns map[string]*domain.WorkflowRun
+}
+
+func (r *mockWorkflowRunRepository) GetById(ctx context.Context, id string) (*domain.WorkflowRun, error) {
+	if workflowRun, ok := r.workflowRuns[id]; ok {
+		return workflowRun, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockWorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error) {
+	r.workflowRuns[workflowRun.Id] = workflowRun
+	return workflowRun, nil
+}
+
+func (r *mockWorkflowRunRepository) SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error) {
+	return r.Save(ctx, workflowRun)
+}
+
+func (r *mockWorkflowRunRepository) ResetStatusIfHanging(ctx context.Context) error {
+	return nil
+}
+
+type mockWorkflowApprovalRepository struct {
+	approvals []*domain.WorkflowApproval
+}
+
+func (r *mockWorkflowApprovalRepository) ListPending(ctx context.Context) ([]*domain.WorkflowApproval, error) {
+	pending := make([]*domain.WorkflowApproval, 0)
+	for _, approval := range r.approvals {
+		if approval.IsPending() {
+			pending = append(pending, approval)
+		}
+	}
+	return pending, nil
+}
+
+func (r *mockWorkflowApprovalRepository) Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error) {
+	return true, nil
+}
+
+func TestCollectRebuildableRuns(t *testing.T) {
+	graph := &domain.WorkflowGraph{Nodes: []*domain.WorkflowNode{
+		{Id: "start", Type: domain.WorkflowNodeTypeStart},
+		{Id: "loop", Type: domain.WorkflowNodeTypeForEach, Blocks: []*domain.WorkflowNode{
+			{Id: "approval", Type: domain.WorkflowNodeTypeApproval},
+		}},
+		{Id: "end", Type: domain.WorkflowNodeTypeEnd},
+	}}
+
+	runRepo := &mockWorkflowRunRepository{workflowRuns: map[string]*domain.WorkflowRun{
+		"waiting":  {Meta: domain.Meta{Id: "waiting"}, Status: domain.WorkflowRunStatusTypeWaiting, Graph: graph},
+		"canceled": {Meta: domain.Meta{Id: "canceled"}, Status: domain.WorkflowRunStatusTypeCanceled, Graph: graph},
+		"inlined":  {Meta: domain.Meta{Id: "inlined"}, Status: domain.WorkflowRunStatusTypeWaiting, Graph: graph},
+	}}
+	approvalRepo := &mockWorkflowApprovalRepository{approvals: []*domain.WorkflowApproval{
+		{RunId: "waiting", NodeId: "approval#1", Status: domain.WorkflowApprovalStatusTypePending},
+		{RunId: "waiting", NodeId: "approval#2", Status: domain.WorkflowApprovalStatusTypePending},
+		{RunId: "canceled", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypePending},
+		{RunId: "deleted", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypePending},
+		{RunId: "inlined", NodeId: "review#call", Status: domain.WorkflowApprovalStatusTypePending},
+	}}
+
+	wd := &workflowDispatcher{
+		rebuiltRuns:          make(map[string]string),
+		workflowRunRepo:      runRepo,
+		workflowApprovalRepo: approvalRepo,
+	}
+
+	workflowRuns, err := wd.collectRebuildableRuns(context.Background())
+	require.NoError(t, err)
+	if assert.Len(t, workflowRuns, 1) {
+		assert.Equal(t, "waiting", workflowRuns[0].Id)
+	}
+	assert.Equal(t, map[string]string{"waiting": "approval#1"}, wd.rebuiltRuns)
+
+	// 所在运行已不再等待的审批将被取消
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeCanceled, approvalRepo.approvals[2].Status)
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeCanceled, approvalRepo.approvals[3].Status)
+
+	// 无法在工作流图中定位的审批节点所在的运行无法重建，其审批将被取消
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeCanceled, approvalRepo.approvals[4].Status)
+	assert.Equal(t, domain.WorkflowApprovalStatusTypePending, approvalRepo.approvals[1].Status)
+}
+
+type mockWorkflowEngine struct {
+	engine.WorkflowEngine
+}
+
+func (we *mockWorkflowEngine) OnNodeError(callback func(ctx context.Context, node *engine.Node, err error) error) {
+}
+
+func (we *mockWorkflowEngine) OnNodeLogging(callback func(ctx context.Context, node *engine.Node, log logging.Record) error) {
+}
+
+func (we *mockWorkflowEngine) OnNodeSuspend(callback func(ctx context.Context, node *engine.Node) error) {
+}
+
+func (we *mockWorkflowEngine) OnNodeResume(callback func(ctx context.Context, node *engine.Node, signal *engine.ResumeSignal) error) {
+}
+
+func TestDispatchChildRun(t *testing.T) {
+	childRunPollInterval = 10 * time.Millisecond
+
+	wd := &workflowDispatcher{
+		concurrency:     1,
+		pendingRunQueue: make([]string, 0),
+		processingTasks: map[string]*taskInfo{
+			"parent": {WorkflowId: "wf1", RunId: "parent"},
+			"other":  {WorkflowId: "wf2", RunId: "other"},
+		},
+		syslog: slog.Default(),
+	}
+
+	t.Run("WaitForSameWorkflow", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			wd.taskMtx.Lock()
+			delete(wd.processingTasks, "other")
+			wd.taskMtx.Unlock()
+		}()
+
+		// 相同工作流的其他运行结束前，子运行需等待
+		childRun := &domain.WorkflowRun{Meta: domain.Meta{Id: "child"}, WorkflowId: "wf2"}
+		ctx, release, err := wd.DispatchChildRun(context.Background(), "parent", childRun, &mockWorkflowEngine{})
+		require.NoError(t, err)
+
+		wd.taskMtx.RLock()
+		task, ok := wd.processingTasks["child"]
+		_, otherExists := wd.processingTasks["other"]
+		activeTasks := wd.countActiveTasks()
+		wd.taskMtx.RUnlock()
+		require.True(t, ok)
+		assert.False(t, otherExists)
+		assert.Equal(t, "parent", task.ParentRunId)
+		assert.Equal(t, 1, activeTasks, "child runs should not occupy extra worker slots")
+
+		release()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+		wd.taskMtx.RLock()
+		_, ok = wd.processingTasks["child"]
+		wd.taskMtx.RUnlock()
+		assert.False(t, ok)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		wd.taskMtx.Lock()
+		wd.processingTasks["other"] = &taskInfo{WorkflowId: "wf2", RunId: "other"}
+		wd.taskMtx.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		childRun := &domain.WorkflowRun{Meta: domain.Meta{Id: "child"}, WorkflowId: "wf2"}
+		_, _, err := wd.DispatchChildRun(ctx, "parent", childRun, &mockWorkflowEngine{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestAdjustSuspensions(t *testing.T) {
+	parent := &taskInfo{WorkflowId: "wf1", RunId: "parent"}
+	child := &taskInfo{WorkflowId: "wf2", RunId: "child", ParentRunId: "parent"}
+	grandchild := &taskInfo{WorkflowId: "wf3", RunId: "grandchild", ParentRunId: "child"}
+	other := &taskInfo{WorkflowId: "wf4", RunId: "other"}
+
+	wd := &workflowDispatcher{
+		concurrency: 2,
+		processingTasks: map[string]*taskInfo{
+			parent.RunId:     parent,
+			child.RunId:      child,
+			grandchild.RunId: grandchild,
+			other.RunId:      other,
+		},
+	}
+
+	// 并行分支中的两个节点先后挂起，其中一个恢复后运行仍处于等待状态
+	assert.Equal(t, 1, wd.adjustSuspensions(parent, 1))
+	assert.Equal(t, 2, wd.adjustSuspensions(parent, 1))
+	assert.Equal(t, 1, wd.adjustSuspensions(parent, -1))
+	assert.True(t, parent.isWaiting())
+	assert.Equal(t, 0, wd.adjustSuspensions(parent, -1))
+	assert.False(t, parent.isWaiting())
+	assert.Equal(t, 0, wd.adjustSuspensions(parent, -1))
+
+	// 子运行挂起时，其各级父运行也随之等待，不再占用工作槽位
+	assert.Equal(t, 1, wd.adjustSuspensions(grandchild, 1))
+	assert.True(t, child.isWaiting())
+	assert.True(t, parent.isWaiting())
+	assert.False(t, other.isWaiting())
+	assert.Equal(t, 1, wd.countActiveTasks())
+
+	assert.Equal(t, 0, wd.adjustSuspensions(grandchild, -1))
+	assert.False(t, child.isWaiting())
+	assert.False(t, parent.isWaiting())
+	assert.Equal(t, 2, wd.countActiveTasks())
+}
@@ -2,12 +2,27 @@ package dispatcher
 
 import (
 	"context"
+	"sync"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
 )
 
 type taskInfo struct {
-	WorkflowId string
-	RunId      string
+	WorkflowId  string
+	RunId       string
+	ParentRunId string // 以子运行模式调用时，发起调用的运行 ID；子运行在其父运行的工作槽位中执行
 
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	engine      engine.WorkflowEngine
+	suspensions int // 尚未恢复的挂起节点数（含子运行中的挂起节点），大于零时处于等待状态，不占用工作槽位；由调度器的任务锁保护
+
+	runMtx sync.Mutex // 保护运行实体及日志缓冲，并行节点会并发地触发钩子
+	logs   domain.WorkflowLogs
+}
+
+func (t *taskInfo) isWaiting() bool {
+	return t.suspensions > 0
 }
@@ -8,11 +8,14 @@ type WorkflowContext struct {
 	WorkflowId string
 	RunId      string
 	RunGraph   *Graph
+	DryRun     bool // 是否为试运行
 
 	engine    WorkflowEngine
 	variables VariableManager
 	inputs    InOutManager
 
+	callStack []string // 子工作流的调用链（含自身），用于检测循环调用
+
 	ctx context.Context
 }
 
@@ -23,6 +26,11 @@ func (c *WorkflowContext) SetExecutingWorkflow(workflowId string, runId string,
 	return c
 }
 
+func (c *WorkflowContext) SetDryRun(dryRun bool) *WorkflowContext {
+	c.DryRun = dryRun
+	return c
+}
+
 func (c *WorkflowContext) SetEngine(engine WorkflowEngine) *WorkflowContext {
 	c.engine = engine
 	return c
@@ -52,11 +60,14 @@ func (c *WorkflowContext) Clone() *WorkflowContext {
 		WorkflowId: c.WorkflowId,
 		RunId:      c.RunId,
 		RunGraph:   c.RunGraph,
+		DryRun:     c.DryRun,
 
 		engine:    c.engine,
 		variables: c.variables,
 		inputs:    c.inputs,
 
+		callStack: c.callStack,
+
 		ctx: c.ctx,
 	}
 }
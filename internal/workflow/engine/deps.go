@@ -16,7 +16,30 @@ type certificateRepository interface {
 	Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error)
 }
 
+type privateCARepository interface {
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+}
+
+type workflowRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Workflow, error)
+}
+
+type workflowRunRepository interface {
+	SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
+}
+
+type workflowVersionRepository interface {
+	GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error)
+}
+
+type workflowApprovalRepository interface {
+	GetByWorkflowRunIdAndNodeId(ctx context.Context, workflowRunId string, workflowNodeId string) (*domain.WorkflowApproval, error)
+	Save(ctx context.Context, workflowApproval *domain.WorkflowApproval) (*domain.WorkflowApproval, error)
+	Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error)
+}
+
 type workflowOutputRepository interface {
 	GetByWorkflowIdAndNodeId(ctx context.Context, workflowId string, workflowNodeId string) (*domain.WorkflowOutput, error)
+	ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowOutput, error)
 	Save(ctx context.Context, workflowOutput *domain.WorkflowOutput) (*domain.WorkflowOutput, error)
 }
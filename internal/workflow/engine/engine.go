@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,10 +28,44 @@ type WorkflowExecution struct {
 	RunTrigger          domain.WorkflowTriggerType
 	RunAt               time.Time
 	Graph               *Graph
+
+	ResumedFromRunId  string // 从指定运行的失败节点恢复执行时，原运行 ID
+	ResumedFromNodeId string // 从指定运行的失败节点恢复执行时，失败节点 ID
+
+	Variables []*domain.WorkflowRunVariable // 触发时传入的运行变量
+
+	callStack []string // 子工作流的调用链（不含自身），用于检测循环调用
+}
+
+type ResumeSignal struct {
+	Rejected bool   // 是否拒绝继续执行
+	Operator string // 操作人
+	Comment  string // 备注
+}
+
+// 子运行的调度器，通常由工作流调度器实现。
+// 以子运行模式调用工作流时，子运行将经由它排队并注册钩子，以便与常规运行共享并发控制、日志持久化及挂起恢复等机制。
+type ChildRunDispatcher interface {
+	// 等待直至子运行可以执行，并在子运行的引擎上注册钩子。
+	//
+	// 入参：
+	//   - ctx：上下文。
+	//   - parentRunId：发起调用的运行 ID。
+	//   - workflowRun：子运行实体。
+	//   - we：子运行的引擎实例。
+	//
+	// 出参：
+	//   - ctx：子运行的上下文，子运行被取消时随之取消。
+	//   - release：释放函数，子运行结束后需调用。
+	//   - err: 错误。
+	DispatchChildRun(ctx context.Context, parentRunId string, workflowRun *domain.WorkflowRun, we WorkflowEngine) (_ctx context.Context, _release func(), _err error)
 }
 
 type WorkflowEngine interface {
 	Invoke(ctx context.Context, execution WorkflowExecution) error
+	Resume(nodeId string, signal ResumeSignal) error
+
+	SetChildRunDispatcher(dispatcher ChildRunDispatcher)
 
 	OnStart(callback func(ctx context.Context) error)
 	OnEnd(callback func(ctx context.Context) error)
@@ -37,10 +74,13 @@ type WorkflowEngine interface {
 	OnNodeEnd(callback func(ctx context.Context, node *Node, res *NodeExecutionResult) error)
 	OnNodeError(callback func(ctx context.Context, node *Node, err error) error)
 	OnNodeLogging(callback func(ctx context.Context, node *Node, log logging.Record) error)
+	OnNodeSuspend(callback func(ctx context.Context, node *Node) error)
+	OnNodeResume(callback func(ctx context.Context, node *Node, signal *ResumeSignal) error)
 }
 
 type workflowEngine struct {
-	executors map[NodeType]NodeExecutor
+	// 每次执行节点时都创建新的执行器实例，避免并行执行时共享同一执行器的状态（如日志记录器）
+	executors map[NodeType]func() NodeExecutor
 
 	hooksMtx           sync.RWMutex
 	onStartHooks       [](func(ctx context.Context) error)
@@ -50,7 +90,20 @@ type workflowEngine struct {
 	onNodeEndHooks     [](func(ctx context.Context, node *Node, res *NodeExecutionResult) error)
 	onNodeErrorHooks   [](func(ctx context.Context, node *Node, err error) error)
 	onNodeLoggingHooks [](func(ctx context.Context, node *Node, log logging.Record) error)
+	onNodeSuspendHooks [](func(ctx context.Context, node *Node) error)
+	onNodeResumeHooks  [](func(ctx context.Context, node *Node, signal *ResumeSignal) error)
+
+	suspensionsMtx sync.Mutex
+	suspensions    map[string]chan ResumeSignal // Key: NodeId
+
+	resumption *workflowResumption // 从失败节点恢复执行时的状态
 
+	childRunDispatcher ChildRunDispatcher // 子运行的调度器，为空时子运行将直接执行
+
+	secretsMtx sync.RWMutex
+	secrets    []string // 需在日志及错误信息中遮蔽的密钥
+
+	accessRepo   accessRepository
 	wfoutputRepo workflowOutputRepository
 
 	syslog *slog.Logger
@@ -59,6 +112,11 @@ type workflowEngine struct {
 var _ WorkflowEngine = (*workflowEngine)(nil)
 
 func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecution) error {
+	return we.invoke(ctx, execution, newVariableManager(), newInOutManager())
+}
+
+// 使用指定的状态管理器执行工作流，以便调用方在执行结束后读取其变量及输出。
+func (we *workflowEngine) invoke(ctx context.Context, execution WorkflowExecution, wfVars VariableManager, wfIOs InOutManager) error {
 	defer func() {
 		if r := recover(); r != nil {
 			we.fireOnErrorHooks(ctx, fmt.Errorf("workflow engine panic: %v", r))
@@ -69,9 +127,6 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 
 	we.fireOnStartHooks(ctx)
 
-	wfIOs := newInOutManager()
-
-	wfVars := newVariableManager()
 	wfVars.Set(stateVarKeyWorkflowId, execution.WorkflowId, stateValTypeString)
 	wfVars.Set(stateVarKeyWorkflowName, execution.WorkflowName, stateValTypeString)
 	wfVars.Set(stateVarKeyWorkflowDescription, execution.WorkflowDescription, stateValTypeString)
@@ -80,17 +135,33 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 	wfVars.Set(stateVarKeyErrorNodeId, "", stateValTypeString)
 	wfVars.Set(stateVarKeyErrorNodeName, "", stateValTypeString)
 	wfVars.Set(stateVarKeyErrorMessage, "", stateValTypeString)
+	for _, variable := range execution.Variables {
+		wfVars.Set(variable.Key, parseStateValue(variable.Value, variable.ValueType), variable.ValueType)
+	}
+	if err := we.resolveVariables(ctx, execution.Graph, wfVars); err != nil {
+		we.fireOnErrorHooks(ctx, err)
+		return we.maskError(err)
+	}
+
+	if execution.ResumedFromNodeId != "" {
+		if err := we.restoreResumption(ctx, execution, wfVars, wfIOs); err != nil {
+			we.fireOnErrorHooks(ctx, err)
+			return err
+		}
+	}
 
 	wfCtx := (&WorkflowContext{}).
 		SetExecutingWorkflow(execution.WorkflowId, execution.RunId, execution.Graph).
+		SetDryRun(execution.RunTrigger == domain.WorkflowTriggerTypeDryRun).
 		SetEngine(we).
 		SetInputsManager(wfIOs).
 		SetVariablesManager(wfVars).
 		SetContext(ctx)
+	wfCtx.callStack = append(slices.Clone(execution.callStack), execution.WorkflowId)
 	if err := we.executeBlocks(wfCtx, execution.Graph.Nodes); err != nil {
 		if !errors.Is(err, ErrTerminated) {
 			we.fireOnErrorHooks(ctx, err)
-			return err
+			return we.maskError(err)
 		}
 	}
 
@@ -99,6 +170,37 @@ func (we *workflowEngine) Invoke(ctx context.Context, execution WorkflowExecutio
 	return nil
 }
 
+func (we *workflowEngine) Resume(nodeId string, signal ResumeSignal) error {
+	we.suspensionsMtx.Lock()
+	defer we.suspensionsMtx.Unlock()
+
+	resumed := false
+	for suspendedNodeId, ch := range we.suspensions {
+		if nodeId != "" && nodeId != suspendedNodeId {
+			continue
+		}
+
+		select {
+		case ch <- signal:
+			resumed = true
+		default:
+		}
+	}
+
+	if !resumed {
+		if nodeId != "" {
+			return fmt.Errorf("workflow engine: node #%s is not suspended", nodeId)
+		}
+		return fmt.Errorf("workflow engine: no suspended nodes")
+	}
+
+	return nil
+}
+
+func (we *workflowEngine) SetChildRunDispatcher(dispatcher ChildRunDispatcher) {
+	we.childRunDispatcher = dispatcher
+}
+
 func (we *workflowEngine) OnStart(callback func(ctx context.Context) error) {
 	we.hooksMtx.Lock()
 	defer we.hooksMtx.Unlock()
@@ -141,24 +243,103 @@ func (we *workflowEngine) OnNodeLogging(callback func(ctx context.Context, node
 	we.onNodeLoggingHooks = append(we.onNodeLoggingHooks, callback)
 }
 
+func (we *workflowEngine) OnNodeSuspend(callback func(ctx context.Context, node *Node) error) {
+	we.hooksMtx.Lock()
+	defer we.hooksMtx.Unlock()
+	we.onNodeSuspendHooks = append(we.onNodeSuspendHooks, callback)
+}
+
+func (we *workflowEngine) OnNodeResume(callback func(ctx context.Context, node *Node, signal *ResumeSignal) error) {
+	we.hooksMtx.Lock()
+	defer we.hooksMtx.Unlock()
+	we.onNodeResumeHooks = append(we.onNodeResumeHooks, callback)
+}
+
+// 挂起节点的执行，阻塞直到被外部唤醒、超时或上下文取消。
+// 挂起期间将触发 OnNodeSuspend 钩子，调度器借此释放该运行所占用的工作槽位。
+func (we *workflowEngine) suspendNode(ctx context.Context, node *Node, timeout time.Duration) (*ResumeSignal, error) {
+	ch := make(chan ResumeSignal, 1)
+
+	we.suspensionsMtx.Lock()
+	if _, exists := we.suspensions[node.Id]; exists {
+		we.suspensionsMtx.Unlock()
+		return nil, fmt.Errorf("workflow engine: node #%s is already suspended", node.Id)
+	}
+	we.suspensions[node.Id] = ch
+	we.suspensionsMtx.Unlock()
+
+	defer func() {
+		we.suspensionsMtx.Lock()
+		delete(we.suspensions, node.Id)
+		we.suspensionsMtx.Unlock()
+	}()
+
+	we.fireOnNodeSuspendHooks(ctx, node)
+
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	var signal *ResumeSignal
+	var err error
+	select {
+	case s := <-ch:
+		signal = &s
+		if s.Rejected {
+			err = ErrSuspensionRejected
+		}
+	case <-timeoutCh:
+		err = ErrSuspensionTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	we.fireOnNodeResumeHooks(ctx, node, signal)
+
+	return signal, err
+}
+
 func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error {
-	executor, ok := we.executors[node.Type]
+	newExecutor, ok := we.executors[node.Type]
 	if !ok {
 		err := fmt.Errorf("workflow engine: no executor registered for node type: '%s'", node.Type)
 		return err
-	} else {
-		logger := slog.New(logging.NewHookHandler(nil, &logging.HookHandlerOptions{
-			Level: slog.LevelDebug,
-			WriteFunc: func(ctx context.Context, record logging.Record) error {
-				we.fireOnNodeLoggingHooks(ctx, node, record)
-				return nil
-			},
-		}))
-		executor.SetLogger(logger)
 	}
 
-	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeId, node.Id, stateValTypeString)
-	wfCtx.variables.SetScoped(node.Id, stateVarKeyNodeName, node.Data.Name, stateValTypeString)
+	logger := slog.New(logging.NewHookHandler(nil, &logging.HookHandlerOptions{
+		Level: slog.LevelDebug,
+		WriteFunc: func(ctx context.Context, record logging.Record) error {
+			we.fireOnNodeLoggingHooks(ctx, node, we.maskRecord(record))
+			return nil
+		},
+	}))
+	executor := newExecutor()
+	executor.SetLogger(logger)
+
+	// 从失败节点恢复执行时，跳过该节点之前的节点，其状态已从原运行的输出中还原
+	if we.resumption != nil && we.resumption.shouldSkip(node) {
+		return nil
+	}
+
+	// 循环中复制的节点同时以原始节点 ID 登记其变量及输出，以便循环体内按原始节点 ID 引用当前迭代的结果
+	templateNodeId := domain.GetWorkflowTemplateNodeId(node.Id)
+	setScopedAliases := func(states []VariableState) {
+		for _, state := range states {
+			wfCtx.variables.Add(state)
+			if templateNodeId != node.Id && state.Scope == node.Id {
+				state.Scope = templateNodeId
+				wfCtx.variables.Add(state)
+			}
+		}
+	}
+
+	setScopedAliases([]VariableState{
+		{Scope: node.Id, Key: stateVarKeyNodeId, Value: node.Id, ValueType: stateValTypeString},
+		{Scope: node.Id, Key: stateVarKeyNodeName, Value: node.Data.Name, ValueType: stateValTypeString},
+	})
 
 	// 节点已禁用，直接跳过执行
 	if node.Data.Disabled {
@@ -168,7 +349,7 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	we.fireOnNodeStartHooks(wfCtx.ctx, node)
 
 	execCtx := newNodeExecutionContext(wfCtx, node)
-	execRes, err := executor.Execute(execCtx)
+	execRes, err := we.executeWithRetry(execCtx, executor, logger)
 	if err != nil && !errors.Is(err, ErrTerminated) {
 		if !errors.Is(err, ErrBlocksException) {
 			wfCtx.variables.Set(stateVarKeyErrorNodeId, node.Id, stateValTypeString)
@@ -184,19 +365,22 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 
 	if execRes != nil {
 		if execRes.Variables != nil {
-			for _, variable := range execRes.Variables {
-				wfCtx.variables.Add(variable)
-			}
+			setScopedAliases(execRes.Variables)
 		}
 
 		if execRes.Outputs != nil {
 			for _, output := range execRes.Outputs {
 				wfCtx.inputs.Add(output)
+				if templateNodeId != node.Id && output.NodeId == node.Id {
+					output.NodeId = templateNodeId
+					wfCtx.inputs.Add(output)
+				}
 			}
 		}
 
+		// 试运行时不持久化节点输出，以免影响后续正式运行时的跳过判断
 		execOutputs := lo.Filter(execRes.Outputs, func(state InOutState, _ int) bool { return state.Persistent })
-		if execRes.outputForced || len(execOutputs) > 0 {
+		if !execCtx.DryRun && (execRes.outputForced || len(execOutputs) > 0) {
 			output := &domain.WorkflowOutput{
 				WorkflowId: execCtx.WorkflowId,
 				RunId:      execCtx.RunId,
@@ -204,6 +388,9 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 				NodeConfig: execCtx.Node.Data.Config,
 				Succeeded:  true, // TODO: 目前恒为 true
 			}
+			if execRes.outputNodeConfig != nil {
+				output.NodeConfig = execRes.outputNodeConfig
+			}
 			if len(execOutputs) > 0 {
 				output.Outputs = lo.Map(execOutputs, func(state InOutState, _ int) *domain.WorkflowOutputEntry {
 					return &domain.WorkflowOutputEntry{
@@ -214,6 +401,22 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 					}
 				})
 			}
+			if len(execRes.Variables) > 0 {
+				// 一并持久化变量，以便从失败节点恢复执行时还原状态
+				// 密钥类型的变量不会被持久化，恢复执行时将重新读取
+				output.Variables = lo.FilterMap(execRes.Variables, func(state VariableState, _ int) (*domain.WorkflowOutputVariable, bool) {
+					if state.ValueType == stateValTypeSecret {
+						return nil, false
+					}
+
+					return &domain.WorkflowOutputVariable{
+						Scope:     state.Scope,
+						Key:       state.Key,
+						Value:     state.ValueString(),
+						ValueType: state.ValueType,
+					}, true
+				})
+			}
 			if _, err := we.wfoutputRepo.Save(execCtx.Context(), output); err != nil {
 				we.syslog.Error("failed to save node output", slog.Any("error", err))
 			}
@@ -231,6 +434,123 @@ func (we *workflowEngine) executeNode(wfCtx *WorkflowContext, node *Node) error
 	return nil
 }
 
+// 按节点的重试策略执行节点，每次尝试均会记录到节点日志中。
+// 子节点异常、主动终止、被拒绝恢复、等待恢复超时等情况不会重试，以免子节点被重复执行。
+func (we *workflowEngine) executeWithRetry(execCtx *NodeExecutionContext, executor NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	retryPolicy := execCtx.Node.Data.Retry
+
+	maxAttempts := 1
+	if retryPolicy != nil && retryPolicy.MaxAttempts > 1 {
+		maxAttempts = retryPolicy.MaxAttempts
+	}
+
+	ctx := execCtx.Context()
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			logger.Info(fmt.Sprintf("attempt %d/%d ...", attempt, maxAttempts))
+		}
+
+		execRes, err := we.executeWithTimeout(execCtx, executor, logger)
+		if err == nil || attempt >= maxAttempts {
+			return execRes, err
+		}
+
+		if ctx.Err() != nil ||
+			errors.Is(err, ErrTerminated) ||
+			errors.Is(err, ErrBlocksException) ||
+			errors.Is(err, ErrExecutionAbandoned) ||
+			errors.Is(err, ErrSuspensionRejected) ||
+			errors.Is(err, ErrSuspensionTimeout) ||
+			!retryPolicy.IsRetryable(err) {
+			return execRes, err
+		}
+
+		delay := retryPolicy.GetBackoffDelay(attempt)
+		logger.Warn(fmt.Sprintf("attempt %d/%d failed, retry in %s: %s", attempt, maxAttempts, delay, err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return execRes, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// 节点执行超时后，等待执行器响应取消并返回的宽限时间。
+var executionTimeoutGracePeriod = 30 * time.Second
+
+// 按节点的超时时间执行节点。
+// 超时后将取消传递给执行器的上下文，并在宽限时间内等待执行器返回，以免重试时与上一次尝试并发执行。
+// 若执行器忽略了上下文的取消，宽限时间过后将不再等待，本次尝试视为超时且不再重试，其迟到的结果仅记录日志。
+// 容器节点的执行时间取决于其子节点，因此不受超时时间限制。
+func (we *workflowEngine) executeWithTimeout(execCtx *NodeExecutionContext, executor NodeExecutor, logger *slog.Logger) (*NodeExecutionResult, error) {
+	timeout := time.Duration(execCtx.Node.Data.Timeout) * time.Second
+	if timeout <= 0 || isContainerNode(execCtx.Node) {
+		return executor.Execute(execCtx)
+	}
+
+	ctx, cancel := context.WithTimeout(execCtx.Context(), timeout)
+	defer cancel()
+
+	type attemptResult struct {
+		res *NodeExecutionResult
+		err error
+	}
+
+	done := make(chan attemptResult, 1)
+	go func() {
+		var ret attemptResult
+		defer func() {
+			if r := recover(); r != nil {
+				ret.err = fmt.Errorf("workflow engine: panic in node #%s: %v", execCtx.Node.Id, r)
+			}
+			done <- ret
+		}()
+
+		ret.res, ret.err = executor.Execute(newNodeExecutionContext(&execCtx.WorkflowContext, execCtx.Node).SetContext(ctx))
+	}()
+
+	var ret attemptResult
+	select {
+	case ret = <-done:
+	case <-ctx.Done():
+		select {
+		case ret = <-done:
+		case <-time.After(executionTimeoutGracePeriod):
+			logger.Warn(fmt.Sprintf("the executor did not exit within %s after timing out, abandon this attempt", executionTimeoutGracePeriod))
+
+			go func() {
+				late := <-done
+				we.syslog.Warn(fmt.Sprintf("abandoned node #%s returned late", execCtx.Node.Id), slog.String("runId", execCtx.RunId), slog.Any("error", late.err))
+			}()
+
+			if err := execCtx.Context().Err(); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrExecutionAbandoned, err)
+			}
+			return nil, fmt.Errorf("%w after %s: %w", ErrExecutionTimeout, timeout, ErrExecutionAbandoned)
+		}
+	}
+
+	if ret.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && execCtx.Context().Err() == nil {
+		return ret.res, fmt.Errorf("%w after %s", ErrExecutionTimeout, timeout)
+	}
+
+	return ret.res, ret.err
+}
+
+// 判断节点是否为容器节点，即仅用于编排子节点的节点。
+func isContainerNode(node *Node) bool {
+	switch node.Type {
+	case NodeTypeCondition, NodeTypeBranchBlock,
+		NodeTypeTryCatch, NodeTypeTryBlock, NodeTypeCatchBlock,
+		NodeTypeForEach,
+		NodeTypeParallel, NodeTypeParallelBlock,
+		NodeTypeApproveBlock, NodeTypeRejectBlock:
+		return true
+	}
+	return false
+}
+
 func (we *workflowEngine) executeBlocks(wfCtx *WorkflowContext, blocks []*Node) error {
 	errs := make([]error, 0)
 
@@ -265,6 +585,207 @@ func (we *workflowEngine) executeBlocks(wfCtx *WorkflowContext, blocks []*Node)
 	return nil
 }
 
+// 从原运行的持久化输出中还原失败节点之前各节点的变量及输出，并记录需跳过的节点。
+// 仅使用原运行自身的输出，不会回退到更早的历史运行。
+func (we *workflowEngine) restoreResumption(ctx context.Context, execution WorkflowExecution, variables VariableManager, inputs InOutManager) error {
+	resumption, err := newWorkflowResumption(execution.Graph, execution.ResumedFromNodeId)
+	if err != nil {
+		return fmt.Errorf("workflow engine: %w", err)
+	}
+
+	outputs, err := we.wfoutputRepo.ListByWorkflowRunId(ctx, execution.ResumedFromRunId)
+	if err != nil {
+		return fmt.Errorf("workflow engine: failed to get output records of run #%s: %w", execution.ResumedFromRunId, err)
+	}
+
+	restored := 0
+	for _, output := range outputs {
+		resumption.outputNodeIds[output.NodeId] = struct{}{}
+		if !resumption.isPreceding(output.NodeId) {
+			continue
+		}
+
+		// 循环中复制的节点同时以原始节点 ID 还原，与执行时的登记方式保持一致
+		nodeIds := []string{output.NodeId}
+		if templateNodeId := domain.GetWorkflowTemplateNodeId(output.NodeId); templateNodeId != output.NodeId {
+			nodeIds = append(nodeIds, templateNodeId)
+		}
+
+		for _, nodeId := range nodeIds {
+			for _, entry := range output.Outputs {
+				inputs.Add(InOutState{
+					NodeId:     nodeId,
+					Type:       entry.Type,
+					Name:       entry.Name,
+					Value:      parseStateValue(entry.Value, entry.ValueType),
+					ValueType:  entry.ValueType,
+					Persistent: true,
+				})
+			}
+
+			for _, entry := range output.Variables {
+				scope := entry.Scope
+				if scope == output.NodeId {
+					scope = nodeId
+				}
+
+				variables.Add(VariableState{
+					Scope:     scope,
+					Key:       entry.Key,
+					Value:     parseStateValue(entry.Value, entry.ValueType),
+					ValueType: entry.ValueType,
+				})
+			}
+		}
+
+		restored++
+	}
+
+	we.resumption = resumption
+
+	we.syslog.Info(fmt.Sprintf("workflow engine: resume from node #%s, %d preceding node(s) restored", execution.ResumedFromNodeId, restored), slog.String("workflowId", execution.WorkflowId), slog.String("runId", execution.RunId))
+	return nil
+}
+
+// 从失败节点恢复执行时，用于判断各节点在原运行中是否已执行完毕。
+// 节点 ID 可能为循环中复制的节点 ID，此时需结合迭代序号判断其执行先后。
+type workflowResumption struct {
+	fromNodeId    string
+	preceding     map[string]struct{} // 静态图中位于失败节点之前的节点，Key: 原始节点 ID
+	loopChains    map[string][]string // 各节点所在的 ForEach 节点链（由外至内），Key: 原始节点 ID
+	outputNodeIds map[string]struct{} // 原运行中存在持久化输出的节点，Key: NodeId
+}
+
+func newWorkflowResumption(graph *Graph, fromNodeId string) (*workflowResumption, error) {
+	precedingNodes, found := collectPrecedingNodes(graph.Nodes, domain.GetWorkflowTemplateNodeId(fromNodeId))
+	if !found {
+		return nil, fmt.Errorf("could not resume from node #%s, because it does not exist", fromNodeId)
+	}
+
+	r := &workflowResumption{
+		fromNodeId:    fromNodeId,
+		preceding:     make(map[string]struct{}, len(precedingNodes)),
+		loopChains:    make(map[string][]string),
+		outputNodeIds: make(map[string]struct{}),
+	}
+	for _, node := range precedingNodes {
+		r.preceding[node.Id] = struct{}{}
+	}
+
+	var walk func(blocks []*Node, chain []string)
+	walk = func(blocks []*Node, chain []string) {
+		for _, node := range blocks {
+			r.loopChains[node.Id] = chain
+			if node.Type == NodeTypeForEach {
+				walk(node.Blocks, append(slices.Clone(chain), node.Id))
+			} else {
+				walk(node.Blocks, chain)
+			}
+		}
+	}
+	walk(graph.Nodes, nil)
+
+	return r, nil
+}
+
+// 判断指定节点在原运行中是否先于失败节点执行完毕。
+func (r *workflowResumption) isPreceding(nodeId string) bool {
+	if nodeId == r.fromNodeId {
+		return false
+	}
+
+	templateNodeId := domain.GetWorkflowTemplateNodeId(nodeId)
+	fromTemplateNodeId := domain.GetWorkflowTemplateNodeId(r.fromNodeId)
+
+	// 仅比较两者共同所在的循环的迭代序号：较早的迭代已执行完毕，较晚的迭代尚未执行
+	chain, fromChain := r.loopChains[templateNodeId], r.loopChains[fromTemplateNodeId]
+	common := 0
+	for common < len(chain) && common < len(fromChain) && chain[common] == fromChain[common] {
+		common++
+	}
+
+	path, fromPath := parseLoopIterationPath(nodeId), parseLoopIterationPath(r.fromNodeId)
+	for i := 0; i < common && i < len(path) && i < len(fromPath); i++ {
+		if path[i] != fromPath[i] {
+			return path[i] < fromPath[i]
+		}
+	}
+
+	_, ok := r.preceding[templateNodeId]
+	return ok
+}
+
+// 判断恢复执行时是否应跳过指定节点。
+// 可跳过执行的业务节点若在原运行中没有输出（例如因满足条件而跳过了执行），则需重新执行以还原其输出。
+func (r *workflowResumption) shouldSkip(node *Node) bool {
+	if !r.isPreceding(node.Id) {
+		return false
+	}
+
+	switch node.Type {
+	case NodeTypeBizApply, NodeTypeBizUpload, NodeTypeBizDeploy:
+		_, ok := r.outputNodeIds[node.Id]
+		return ok
+	}
+
+	return true
+}
+
+func parseLoopIterationPath(nodeId string) []int {
+	parts := strings.Split(nodeId, domain.WorkflowNodeIterationSeparator)[1:]
+	path := make([]int, 0, len(parts))
+	for _, part := range parts {
+		index, _ := strconv.Atoi(part)
+		path = append(path, index)
+	}
+	return path
+}
+
+// 按执行顺序（前序遍历）收集位于指定节点之前的所有节点，不含该节点的祖先节点。
+// 并行节点中其他分支的执行进度未知，因此不会收集。
+func collectPrecedingNodes(blocks []*Node, nodeId string) ([]*Node, bool) {
+	preceding := make([]*Node, 0)
+
+	var walk func(blocks []*Node) bool
+	walk = func(blocks []*Node) bool {
+		for _, node := range blocks {
+			if node.Id == nodeId {
+				return true
+			}
+
+			mark := len(preceding)
+			if node.Type == NodeTypeParallel {
+				for _, branch := range node.Blocks {
+					branchMark := len(preceding)
+					if walk([]*Node{branch}) {
+						return true
+					}
+					preceding = preceding[:branchMark]
+				}
+			} else if walk(node.Blocks) {
+				// 目标节点位于该节点的子节点中，该节点是祖先节点，不应跳过
+				return true
+			}
+
+			preceding = append(preceding[:mark], node)
+			preceding = append(preceding, collectAllNodes(node.Blocks)...)
+		}
+		return false
+	}
+
+	found := walk(blocks)
+	return preceding, found
+}
+
+func collectAllNodes(blocks []*Node) []*Node {
+	nodes := make([]*Node, 0)
+	for _, node := range blocks {
+		nodes = append(nodes, node)
+		nodes = append(nodes, collectAllNodes(node.Blocks)...)
+	}
+	return nodes
+}
+
 func (we *workflowEngine) fireOnStartHooks(ctx context.Context) {
 	we.hooksMtx.RLock()
 	defer we.hooksMtx.RUnlock()
@@ -286,6 +807,8 @@ func (we *workflowEngine) fireOnEndHooks(ctx context.Context) {
 }
 
 func (we *workflowEngine) fireOnErrorHooks(ctx context.Context, err error) {
+	err = we.maskError(err)
+
 	we.hooksMtx.RLock()
 	defer we.hooksMtx.RUnlock()
 	for _, cb := range we.onErrorHooks {
@@ -316,6 +839,8 @@ func (we *workflowEngine) fireOnNodeEndHooks(ctx context.Context, node *Node, re
 }
 
 func (we *workflowEngine) fireOnNodeErrorHooks(ctx context.Context, node *Node, err error) {
+	err = we.maskError(err)
+
 	we.hooksMtx.RLock()
 	defer we.hooksMtx.RUnlock()
 	for _, cb := range we.onNodeErrorHooks {
@@ -335,24 +860,53 @@ func (we *workflowEngine) fireOnNodeLoggingHooks(ctx context.Context, node *Node
 	}
 }
 
+func (we *workflowEngine) fireOnNodeSuspendHooks(ctx context.Context, node *Node) {
+	we.hooksMtx.RLock()
+	defer we.hooksMtx.RUnlock()
+	for _, cb := range we.onNodeSuspendHooks {
+		if cbErr := cb(ctx, node); cbErr != nil {
+			we.syslog.Error("workflow engine: error in onNodeSuspend hook", slog.Any("error", cbErr))
+		}
+	}
+}
+
+func (we *workflowEngine) fireOnNodeResumeHooks(ctx context.Context, node *Node, signal *ResumeSignal) {
+	we.hooksMtx.RLock()
+	defer we.hooksMtx.RUnlock()
+	for _, cb := range we.onNodeResumeHooks {
+		if cbErr := cb(ctx, node, signal); cbErr != nil {
+			we.syslog.Error("workflow engine: error in onNodeResume hook", slog.Any("error", cbErr))
+		}
+	}
+}
+
 func NewWorkflowEngine() WorkflowEngine {
 	engine := &workflowEngine{
-		executors:    make(map[NodeType]NodeExecutor),
+		executors:    make(map[NodeType]func() NodeExecutor),
+		suspensions:  make(map[string]chan ResumeSignal),
+		accessRepo:   repository.NewAccessRepository(),
 		wfoutputRepo: repository.NewWorkflowOutputRepository(),
 		syslog:       app.GetLogger(),
 	}
-	engine.executors[NodeTypeStart] = newStartNodeExecutor()
-	engine.executors[NodeTypeEnd] = newEndNodeExecutor()
-	engine.executors[NodeTypeDelay] = newDelayNodeExecutor()
-	engine.executors[NodeTypeCondition] = newConditionNodeExecutor()
-	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor()
-	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor()
-	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor()
-	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor()
-	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor()
-	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor()
-	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor()
-	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor()
-	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor()
+	engine.executors[NodeTypeStart] = newStartNodeExecutor
+	engine.executors[NodeTypeEnd] = newEndNodeExecutor
+	engine.executors[NodeTypeDelay] = newDelayNodeExecutor
+	engine.executors[NodeTypeCondition] = newConditionNodeExecutor
+	engine.executors[NodeTypeBranchBlock] = newBranchBlockNodeExecutor
+	engine.executors[NodeTypeTryCatch] = newTryCatchNodeExecutor
+	engine.executors[NodeTypeTryBlock] = newTryBlockNodeExecutor
+	engine.executors[NodeTypeCatchBlock] = newCatchBlockNodeExecutor
+	engine.executors[NodeTypeForEach] = newForEachNodeExecutor
+	engine.executors[NodeTypeParallel] = newParallelNodeExecutor
+	engine.executors[NodeTypeParallelBlock] = newParallelBlockNodeExecutor
+	engine.executors[NodeTypeCallWorkflow] = newCallWorkflowNodeExecutor
+	engine.executors[NodeTypeApproval] = newApprovalNodeExecutor
+	engine.executors[NodeTypeApproveBlock] = newApproveBlockNodeExecutor
+	engine.executors[NodeTypeRejectBlock] = newRejectBlockNodeExecutor
+	engine.executors[NodeTypeBizApply] = newBizApplyNodeExecutor
+	engine.executors[NodeTypeBizUpload] = newBizUploadNodeExecutor
+	engine.executors[NodeTypeBizMonitor] = newBizMonitorNodeExecutor
+	engine.executors[NodeTypeBizDeploy] = newBizDeployNodeExecutor
+	engine.executors[NodeTypeBizNotify] = newBizNotifyNodeExecutor
 	return engine
 }
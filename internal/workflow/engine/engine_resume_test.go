@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockWorkflowOutputRepository struct {
+	outputs []*domain.WorkflowOutput
+}
+
+func (r *mockWorkflowOutputRepository) GetByWorkflowIdAndNodeId(ctx context.Context, workflowId string, workflowNodeId string) (*domain.WorkflowOutput, error) {
+	for i := len(r.outputs) - 1; i >= 0; i-- {
+		if r.outputs[i].WorkflowId == workflowId && r.outputs[i].NodeId == workflowNodeId {
+			return r.outputs[i], nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockWorkflowOutputRepository) ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowOutput, error) {
+	outputs := make([]*domain.WorkflowOutput, 0)
+	for _, output := range r.outputs {
+		if output.RunId == workflowRunId {
+			outputs = append(outputs, output)
+		}
+	}
+	return outputs, nil
+}
+
+func (r *mockWorkflowOutputRepository) Save(ctx context.Context, workflowOutput *domain.WorkflowOutput) (*domain.WorkflowOutput, error) {
+	r.outputs = append(r.outputs, workflowOutput)
+	return workflowOutput, nil
+}
+
+// start -> apply1 -> loop[ apply2 -> deploy2 -> inner[ notify ] ] -> parallel[ branch1[ deploy3 ], branch2[ deploy4 ] ] -> end
+func newMockResumptionGraph() *Graph {
+	return &Graph{
+		Nodes: []*Node{
+			{Id: "start", Type: NodeTypeStart},
+			{Id: "apply1", Type: NodeTypeBizApply},
+			{Id: "loop", Type: NodeTypeForEach, Blocks: []*Node{
+				{Id: "apply2", Type: NodeTypeBizApply},
+				{Id: "deploy2", Type: NodeTypeBizDeploy},
+				{Id: "inner", Type: NodeTypeForEach, Blocks: []*Node{
+					{Id: "notify", Type: NodeTypeBizNotify},
+				}},
+			}},
+			{Id: "parallel", Type: NodeTypeParallel, Blocks: []*Node{
+				{Id: "branch1", Type: NodeTypeParallelBlock, Blocks: []*Node{
+					{Id: "deploy3", Type: NodeTypeBizDeploy},
+				}},
+				{Id: "branch2", Type: NodeTypeParallelBlock, Blocks: []*Node{
+					{Id: "deploy4", Type: NodeTypeBizDeploy},
+				}},
+			}},
+			{Id: "end", Type: NodeTypeEnd},
+		},
+	}
+}
+
+func TestWorkflowResumption_IsPreceding(t *testing.T) {
+	testCases := []struct {
+		name       string
+		fromNodeId string
+		preceding  []string
+		following  []string
+	}{
+		{
+			name:       "top-level node",
+			fromNodeId: "parallel",
+			preceding:  []string{"start", "apply1", "loop", "apply2#0", "notify#1#2"},
+			following:  []string{"parallel", "deploy3", "end"},
+		},
+		{
+			name:       "node in loop",
+			fromNodeId: "deploy2#1",
+			preceding:  []string{"apply1", "apply2#0", "deploy2#0", "notify#0#5", "apply2#1"},
+			following:  []string{"loop", "deploy2#1", "inner#1", "notify#1#0", "apply2#2", "parallel"},
+		},
+		{
+			name:       "node in nested loop",
+			fromNodeId: "notify#1#2",
+			preceding:  []string{"apply2#1", "deploy2#1", "notify#0#9", "notify#1#1"},
+			following:  []string{"loop", "inner#1", "notify#1#3", "apply2#2"},
+		},
+		{
+			name:       "node in parallel branch",
+			fromNodeId: "deploy4",
+			preceding:  []string{"apply1", "loop"},
+			following:  []string{"parallel", "branch1", "deploy3", "branch2"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resumption, err := newWorkflowResumption(newMockResumptionGraph(), tc.fromNodeId)
+			require.NoError(t, err)
+
+			for _, nodeId := range tc.preceding {
+				assert.True(t, resumption.isPreceding(nodeId), "node #%s should be preceding", nodeId)
+			}
+			for _, nodeId := range tc.following {
+				assert.False(t, resumption.isPreceding(nodeId), "node #%s should not be preceding", nodeId)
+			}
+		})
+	}
+}
+
+func TestRestoreResumption_OnlyUsesResumedRun(t *testing.T) {
+	repo := &mockWorkflowOutputRepository{
+		outputs: []*domain.WorkflowOutput{
+			// 更早的运行中的输出不应被还原
+			{WorkflowId: "wf", RunId: "run0", NodeId: "apply1", Outputs: []*domain.WorkflowOutputEntry{
+				{Name: "certificate", Type: stateIOTypeRef, Value: "certificate#stale", ValueType: stateValTypeString},
+			}},
+			{WorkflowId: "wf", RunId: "run1", NodeId: "apply2#0", Outputs: []*domain.WorkflowOutputEntry{
+				{Name: "certificate", Type: stateIOTypeRef, Value: "certificate#0", ValueType: stateValTypeString},
+			}},
+			{WorkflowId: "wf", RunId: "run1", NodeId: "apply2#1", Outputs: []*domain.WorkflowOutputEntry{
+				{Name: "certificate", Type: stateIOTypeRef, Value: "certificate#1", ValueType: stateValTypeString},
+			}},
+		},
+	}
+
+	we := newMockEngine()
+	we.wfoutputRepo = repo
+
+	execution := WorkflowExecution{
+		WorkflowId:        "wf",
+		RunId:             "run2",
+		Graph:             newMockResumptionGraph(),
+		ResumedFromRunId:  "run1",
+		ResumedFromNodeId: "deploy2#1",
+	}
+	variables, inputs := newVariableManager(), newInOutManager()
+	require.NoError(t, we.restoreResumption(context.Background(), execution, variables, inputs))
+
+	_, ok := inputs.Get("apply1", "certificate")
+	assert.False(t, ok)
+	state, ok := inputs.Get("apply2#1", "certificate")
+	if assert.True(t, ok) {
+		assert.Equal(t, "certificate#1", state.Value)
+	}
+	state, ok = inputs.Get("apply2", "certificate")
+	if assert.True(t, ok) {
+		assert.Equal(t, "certificate#1", state.Value)
+	}
+
+	// 原运行中没有输出的申请节点需重新执行，其余前序节点直接跳过
+	assert.False(t, we.resumption.shouldSkip(&Node{Id: "apply1", Type: NodeTypeBizApply}))
+	assert.True(t, we.resumption.shouldSkip(&Node{Id: "apply2#1", Type: NodeTypeBizApply}))
+	assert.True(t, we.resumption.shouldSkip(&Node{Id: "start", Type: NodeTypeStart}))
+	assert.False(t, we.resumption.shouldSkip(&Node{Id: "deploy2#1", Type: NodeTypeBizDeploy}))
+}
+
+func TestExecuteNode_SecretVariablesNotPersisted(t *testing.T) {
+	repo := &mockWorkflowOutputRepository{}
+	we := newMockEngine()
+	we.wfoutputRepo = repo
+	we.executors[NodeTypeBizDeploy] = func() NodeExecutor {
+		return &mockNodeExecutor{
+			execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+				execRes := newNodeExecutionResult(execCtx.Node)
+				execRes.AddVariable("token", "s3cr3t", stateValTypeSecret)
+				execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, stateValTypeBoolean)
+				execRes.outputForced = true
+				return execRes, nil
+			},
+		}
+	}
+
+	node := &Node{Id: "deploy", Type: NodeTypeBizDeploy}
+	execCtx := newMockNodeExecutionContext(node)
+	require.NoError(t, we.executeNode(&execCtx.WorkflowContext, node))
+
+	require.Len(t, repo.outputs, 1)
+	for _, variable := range repo.outputs[0].Variables {
+		assert.NotEqual(t, "token", variable.Key)
+		assert.NotEqual(t, stateValTypeSecret, variable.ValueType)
+	}
+	assert.NotEmpty(t, repo.outputs[0].Variables)
+
+	// 密钥仍可在本次运行中读取
+	state, ok := execCtx.variables.Get("token")
+	if assert.True(t, ok) {
+		assert.Equal(t, "s3cr3t", state.Value)
+	}
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockNodeExecutor struct {
+	nodeExecutor
+
+	execute func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error)
+}
+
+func (e *mockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	return e.execute(execCtx)
+}
+
+func newMockEngine() *workflowEngine {
+	return &workflowEngine{
+		executors:   make(map[NodeType]func() NodeExecutor),
+		suspensions: make(map[string]chan ResumeSignal),
+		syslog:      slog.Default(),
+	}
+}
+
+func newMockNodeExecutionContext(node *Node) *NodeExecutionContext {
+	wfCtx := (&WorkflowContext{}).
+		SetExecutingWorkflow("wf", "run", &Graph{Nodes: []*Node{node}}).
+		SetVariablesManager(newVariableManager()).
+		SetInputsManager(newInOutManager()).
+		SetContext(context.Background())
+	return newNodeExecutionContext(wfCtx, node)
+}
+
+func TestExecuteWithRetry_TimeoutWaitsForPreviousAttempt(t *testing.T) {
+	var running, maxRunning, attempts int32
+	executor := &mockNodeExecutor{
+		execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+			atomic.AddInt32(&attempts, 1)
+			cur := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prev := atomic.LoadInt32(&maxRunning)
+				if cur <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, cur) {
+					break
+				}
+			}
+
+			<-execCtx.Context().Done()
+			time.Sleep(100 * time.Millisecond) // 模拟执行器在取消后仍需一段时间才能退出
+			return nil, execCtx.Context().Err()
+		},
+	}
+
+	node := &Node{Id: "n1", Type: NodeTypeBizDeploy, Data: domain.WorkflowNodeData{
+		Timeout: 1,
+		Retry:   &domain.WorkflowNodeRetryPolicy{MaxAttempts: 2, Interval: 1},
+	}}
+
+	we := newMockEngine()
+	_, err := we.executeWithRetry(newMockNodeExecutionContext(node), executor, slog.Default())
+	assert.ErrorIs(t, err, ErrExecutionTimeout)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&attempts))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&maxRunning))
+}
+
+func TestExecuteWithTimeout_ExecutorIgnoresContext(t *testing.T) {
+	prevGracePeriod := executionTimeoutGracePeriod
+	executionTimeoutGracePeriod = 100 * time.Millisecond
+	t.Cleanup(func() { executionTimeoutGracePeriod = prevGracePeriod })
+
+	var attempts int32
+	release := make(chan struct{})
+	defer close(release)
+	executor := &mockNodeExecutor{
+		execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+			atomic.AddInt32(&attempts, 1)
+			<-release // 模拟忽略上下文取消的执行器
+			return newNodeExecutionResult(execCtx.Node), nil
+		},
+	}
+
+	node := &Node{Id: "n1", Type: NodeTypeBizDeploy, Data: domain.WorkflowNodeData{
+		Timeout: 1,
+		Retry:   &domain.WorkflowNodeRetryPolicy{MaxAttempts: 2, Interval: 1},
+	}}
+
+	we := newMockEngine()
+	start := time.Now()
+	_, err := we.executeWithRetry(newMockNodeExecutionContext(node), executor, slog.Default())
+	assert.ErrorIs(t, err, ErrExecutionTimeout)
+	assert.ErrorIs(t, err, ErrExecutionAbandoned)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts), "abandoned attempts should not be retried")
+}
+
+func TestExecuteWithTimeout_ContainerNodes(t *testing.T) {
+	executor := &mockNodeExecutor{
+		execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+			select {
+			case <-execCtx.Context().Done():
+				return nil, execCtx.Context().Err()
+			case <-time.After(1200 * time.Millisecond):
+				return newNodeExecutionResult(execCtx.Node), nil
+			}
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		nodeType    NodeType
+		expectedErr error
+	}{
+		{name: "condition", nodeType: NodeTypeCondition},
+		{name: "forEach", nodeType: NodeTypeForEach},
+		{name: "parallel", nodeType: NodeTypeParallel},
+		{name: "bizDeploy", nodeType: NodeTypeBizDeploy, expectedErr: ErrExecutionTimeout},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			node := &Node{Id: "n1", Type: tc.nodeType, Data: domain.WorkflowNodeData{Timeout: 1}}
+
+			we := newMockEngine()
+			_, err := we.executeWithTimeout(newMockNodeExecutionContext(node), executor, slog.Default())
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExecuteWithRetry_NonRetryableErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "suspension timeout", err: ErrSuspensionTimeout},
+		{name: "suspension rejected", err: ErrSuspensionRejected},
+		{name: "blocks exception", err: ErrBlocksException},
+		{name: "terminated", err: ErrTerminated},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			executor := &mockNodeExecutor{
+				execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+					atomic.AddInt32(&attempts, 1)
+					return nil, tc.err
+				},
+			}
+
+			node := &Node{Id: "n1", Type: NodeTypeApproval, Data: domain.WorkflowNodeData{
+				Retry: &domain.WorkflowNodeRetryPolicy{MaxAttempts: 3, Interval: 1},
+			}}
+
+			we := newMockEngine()
+			_, err := we.executeWithRetry(newMockNodeExecutionContext(node), executor, slog.Default())
+			assert.True(t, errors.Is(err, tc.err))
+			assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+		})
+	}
+}
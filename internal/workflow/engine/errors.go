@@ -9,4 +9,12 @@ var (
 	ErrTerminated = fmt.Errorf("workflow engine: execution was terminated")
 	// 表示工作流引擎在执行子节点时发生异常
 	ErrBlocksException = fmt.Errorf("workflow engine: error occurred when executing blocks")
+	// 表示节点执行超时
+	ErrExecutionTimeout = fmt.Errorf("workflow engine: node execution timed out")
+	// 表示节点执行超时后，执行器未在宽限时间内退出，引擎已不再等待其结果
+	ErrExecutionAbandoned = fmt.Errorf("workflow engine: node execution was abandoned")
+	// 表示挂起中的节点等待外部唤醒超时
+	ErrSuspensionTimeout = fmt.Errorf("workflow engine: timed out waiting for the suspended node to be resumed")
+	// 表示挂起中的节点被外部拒绝继续执行
+	ErrSuspensionRejected = fmt.Errorf("workflow engine: the suspended node was rejected to resume")
 )
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type NodeExecutor interface {
@@ -36,6 +38,11 @@ func (c *NodeExecutionContext) SetExecutingNode(node *Node) *NodeExecutionContex
 	return c
 }
 
+func (c *NodeExecutionContext) SetDryRun(dryRun bool) *NodeExecutionContext {
+	c.WorkflowContext.SetDryRun(dryRun)
+	return c
+}
+
 func (c *NodeExecutionContext) SetEngine(engine WorkflowEngine) *NodeExecutionContext {
 	c.WorkflowContext.SetEngine(engine)
 	return c
@@ -57,13 +64,16 @@ func (c *NodeExecutionContext) SetContext(ctx context.Context) *NodeExecutionCon
 }
 
 func newNodeExecutionContext(wfCtx *WorkflowContext, node *Node) *NodeExecutionContext {
-	return (&NodeExecutionContext{}).
+	execCtx := (&NodeExecutionContext{}).
 		SetExecutingWorkflow(wfCtx.WorkflowId, wfCtx.RunId, wfCtx.RunGraph).
 		SetExecutingNode(node).
+		SetDryRun(wfCtx.DryRun).
 		SetEngine(wfCtx.engine).
 		SetVariablesManager(wfCtx.variables).
 		SetInputsManager(wfCtx.inputs).
 		SetContext(wfCtx.ctx)
+	execCtx.callStack = wfCtx.callStack
+	return execCtx
 }
 
 type NodeExecutionResult struct {
@@ -74,9 +84,10 @@ type NodeExecutionResult struct {
 	variablesMtx sync.Mutex
 	Variables    []VariableState
 
-	outputForced bool // 即使 Outputs 为空，也强制持久化输出
-	outputsMtx   sync.Mutex
-	Outputs      []InOutState
+	outputForced     bool                      // 即使 Outputs 为空，也强制持久化输出
+	outputNodeConfig domain.WorkflowNodeConfig // 持久化的节点配置，为空时使用节点的原始配置
+	outputsMtx       sync.Mutex
+	Outputs          []InOutState
 }
 
 func (r *NodeExecutionResult) AddVariable(key string, value any, valueType string) {
@@ -0,0 +1,491 @@
+package engine
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/notify"
+	"github.com/certimate-go/certimate/internal/repository"
+	xenv "github.com/certimate-go/certimate/pkg/utils/env"
+)
+
+const (
+	ApprovalActionApprove = "approve"
+	ApprovalActionReject  = "reject"
+)
+
+// 等待审批期间查询审批记录的间隔。
+var approvalPollInterval = 5 * time.Second
+
+type approvalNodeExecutor struct {
+	nodeExecutor
+
+	accessRepo           accessRepository
+	workflowApprovalRepo workflowApprovalRepository
+}
+
+func (ne *approvalNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsApproval()
+	ne.logger.Info("ready to request approval ...", slog.Any("config", nodeCfg))
+
+	timeout := time.Duration(nodeCfg.Timeout) * time.Second
+
+	var approved bool
+	var decisionErr error
+	var signal *ResumeSignal
+	decidedAt := time.Now()
+	if execCtx.DryRun {
+		// 试运行时不发送审批通知，直接视为同意
+		ne.logger.Info("skip requesting approval in dry run, it is treated as approved")
+		approved = true
+	} else {
+		approval, err := ne.waitForDecision(execCtx, engine, timeout)
+		if err != nil {
+			return execRes, err
+		}
+
+		switch approval.Status {
+		case domain.WorkflowApprovalStatusTypeApproved:
+			approved = true
+		case domain.WorkflowApprovalStatusTypeRejected:
+			approved = false
+			decisionErr = ErrSuspensionRejected
+		case domain.WorkflowApprovalStatusTypeTimeout:
+			approved = nodeCfg.TimeoutAction == domain.WorkflowNodeApprovalTimeoutActionApprove
+			if !approved {
+				decisionErr = ErrSuspensionTimeout
+			}
+			ne.logger.Warn(fmt.Sprintf("approval timed out, it is treated as %s", lo.Ternary(approved, "approved", "rejected")), slog.Duration("timeout", timeout))
+		default:
+			return execRes, fmt.Errorf("approval is %s", approval.Status)
+		}
+
+		if approval.Status != domain.WorkflowApprovalStatusTypeTimeout {
+			signal = &ResumeSignal{Rejected: !approved, Operator: approval.Operator, Comment: approval.Comment}
+		}
+		if !approval.DecidedAt.IsZero() {
+			decidedAt = approval.DecidedAt
+		}
+	}
+
+	operator, comment := "", ""
+	if signal != nil {
+		operator, comment = signal.Operator, signal.Comment
+		if approved {
+			ne.logger.Info("approval is approved", slog.String("operator", operator), slog.Time("time", decidedAt), slog.String("comment", comment))
+		} else {
+			ne.logger.Warn("approval is rejected", slog.String("operator", operator), slog.Time("time", decidedAt), slog.String("comment", comment))
+		}
+	}
+
+	// 在执行分支之前写入审批结果，以便分支中的节点可以引用
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalApproved, approved, stateValTypeBoolean)
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalOperator, operator, stateValTypeString)
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalComment, comment, stateValTypeString)
+	execCtx.variables.SetScoped(execCtx.Node.Id, stateVarKeyApprovalDecidedAt, decidedAt, stateValTypeDateTime)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyApprovalApproved, approved, stateValTypeBoolean)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyApprovalOperator, operator, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyApprovalComment, comment, stateValTypeString)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyApprovalDecidedAt, decidedAt, stateValTypeDateTime)
+
+	if approved {
+		approveBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeApproveBlock })
+		for _, node := range approveBlocks {
+			select {
+			case <-execCtx.Context().Done():
+				return execRes, execCtx.Context().Err()
+			default:
+			}
+
+			if err := engine.executeNode(execCtx.Clone(), node); err != nil {
+				return execRes, err
+			}
+		}
+
+		return execRes, nil
+	}
+
+	// 进入拒绝分支，若其中存在 End 节点，则工作流正常结束；否则以审批被拒绝的错误结束该节点
+	rejectErrs := make([]error, 0)
+	rejectBlocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeRejectBlock })
+	for _, node := range rejectBlocks {
+		select {
+		case <-execCtx.Context().Done():
+			return execRes, execCtx.Context().Err()
+		default:
+		}
+
+		err := engine.executeNode(execCtx.Clone(), node)
+		if err != nil {
+			if errors.Is(err, ErrTerminated) {
+				return execRes, err
+			}
+			rejectErrs = append(rejectErrs, err)
+		}
+	}
+
+	if len(rejectErrs) > 0 {
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(append([]error{decisionErr}, rejectErrs...)...))
+	}
+
+	return execRes, decisionErr
+}
+
+// 发送审批请求并挂起等待决定，返回已决定的审批记录。
+// 审批状态持久化于数据库中：已发送过审批请求时（例如服务重启后恢复执行）不再重复发送；
+// 等待期间还会定期查询审批记录，因此在服务重启后或被调用的子工作流中作出的决定同样可以唤醒本节点。
+func (ne *approvalNodeExecutor) waitForDecision(execCtx *NodeExecutionContext, engine *workflowEngine, timeout time.Duration) (*domain.WorkflowApproval, error) {
+	ctx := execCtx.Context()
+
+	approval, err := ne.workflowApprovalRepo.GetByWorkflowRunIdAndNodeId(ctx, execCtx.RunId, execCtx.Node.Id)
+	if err != nil && !domain.IsRecordNotFoundError(err) {
+		return nil, fmt.Errorf("failed to get approval record: %w", err)
+	}
+
+	if approval == nil {
+		if err := ne.sendApprovalRequest(execCtx, timeout); err != nil {
+			ne.logger.Warn("could not send approval request")
+			return nil, err
+		}
+
+		approval = &domain.WorkflowApproval{
+			WorkflowId: execCtx.WorkflowId,
+			RunId:      execCtx.RunId,
+			NodeId:     execCtx.Node.Id,
+			Status:     domain.WorkflowApprovalStatusTypePending,
+		}
+		if timeout > 0 {
+			approval.ExpiresAt = time.Now().Add(timeout)
+		}
+		if approval, err = ne.workflowApprovalRepo.Save(ctx, approval); err != nil {
+			return nil, fmt.Errorf("failed to save approval record: %w", err)
+		}
+	} else if approval.IsPending() {
+		ne.logger.Info("approval request has been sent before, continue waiting ...")
+	}
+
+	if !approval.IsPending() {
+		ne.logger.Info(fmt.Sprintf("approval has been %s", approval.Status))
+		return approval, nil
+	}
+
+	remaining := timeout
+	if !approval.ExpiresAt.IsZero() {
+		remaining = time.Until(approval.ExpiresAt)
+	}
+
+	decided := *approval
+	if !approval.ExpiresAt.IsZero() && remaining <= 0 {
+		decided.Status = domain.WorkflowApprovalStatusTypeTimeout
+	} else {
+		ne.logger.Info("waiting for approval ...", slog.Duration("timeout", remaining))
+
+		pollCtx, pollCancel := context.WithCancel(ctx)
+		defer pollCancel()
+		go ne.pollDecision(pollCtx, engine, approval)
+
+		// 挂起期间调度器将释放该运行所占用的工作槽位
+		signal, err := engine.suspendNode(ctx, execCtx.Node, remaining)
+		switch {
+		case err == nil:
+			decided.Status = domain.WorkflowApprovalStatusTypeApproved
+		case errors.Is(err, ErrSuspensionRejected):
+			decided.Status = domain.WorkflowApprovalStatusTypeRejected
+		case errors.Is(err, ErrSuspensionTimeout):
+			decided.Status = domain.WorkflowApprovalStatusTypeTimeout
+		default:
+			if ctx.Err() != nil {
+				decided.Status = domain.WorkflowApprovalStatusTypeCanceled
+				decided.DecidedAt = time.Now()
+				if _, derr := ne.workflowApprovalRepo.Decide(context.WithoutCancel(ctx), &decided); derr != nil {
+					ne.logger.Warn("could not update approval record", slog.Any("error", derr))
+				}
+			}
+			return nil, err
+		}
+		if signal != nil {
+			decided.Operator, decided.Comment = signal.Operator, signal.Comment
+		}
+	}
+
+	// 以首个写入的决定为准，例如超时的同时审批人作出了决定
+	decided.DecidedAt = time.Now()
+	if ok, err := ne.workflowApprovalRepo.Decide(context.WithoutCancel(ctx), &decided); err != nil {
+		return nil, fmt.Errorf("failed to save approval decision: %w", err)
+	} else if !ok {
+		persisted, err := ne.workflowApprovalRepo.GetByWorkflowRunIdAndNodeId(context.WithoutCancel(ctx), execCtx.RunId, execCtx.Node.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get approval record: %w", err)
+		}
+		return persisted, nil
+	}
+
+	return &decided, nil
+}
+
+// 定期查询审批记录，若已在其他地方作出决定，则唤醒挂起中的节点。
+func (ne *approvalNodeExecutor) pollDecision(ctx context.Context, engine *workflowEngine, approval *domain.WorkflowApproval) {
+	ticker := time.NewTicker(approvalPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		persisted, err := ne.workflowApprovalRepo.GetByWorkflowRunIdAndNodeId(ctx, approval.RunId, approval.NodeId)
+		if err != nil || persisted.IsPending() {
+			continue
+		}
+
+		switch persisted.Status {
+		case domain.WorkflowApprovalStatusTypeApproved, domain.WorkflowApprovalStatusTypeRejected:
+			engine.Resume(approval.NodeId, ResumeSignal{
+				Rejected: persisted.Status == domain.WorkflowApprovalStatusTypeRejected,
+				Operator: persisted.Operator,
+				Comment:  persisted.Comment,
+			})
+		}
+		return
+	}
+}
+
+func (ne *approvalNodeExecutor) sendApprovalRequest(execCtx *NodeExecutionContext, timeout time.Duration) error {
+	nodeCfg := execCtx.Node.Data.Config.AsApproval()
+
+	// 读取通知提供商授权
+	providerAccessConfig := make(map[string]any)
+	if nodeCfg.ProviderAccessId != "" {
+		if access, err := ne.accessRepo.GetById(execCtx.Context(), nodeCfg.ProviderAccessId); err != nil {
+			return fmt.Errorf("failed to get access #%s record: %w", nodeCfg.ProviderAccessId, err)
+		} else {
+			providerAccessConfig = access.Config
+		}
+	}
+
+	// 生成同意及拒绝链接，有效期与等待审批的超时时间一致
+	expires := time.Now().Add(timeout).Unix()
+	approveUrl, err := buildApprovalLink(execCtx.RunId, execCtx.Node.Id, ApprovalActionApprove, expires)
+	if err != nil {
+		return err
+	}
+	rejectUrl, err := buildApprovalLink(execCtx.RunId, execCtx.Node.Id, ApprovalActionReject, expires)
+	if err != nil {
+		return err
+	}
+
+	subject, err := renderTemplate(execCtx, nodeCfg.Subject)
+	if err != nil {
+		return fmt.Errorf("failed to render notification subject: %w", err)
+	}
+	message, err := renderTemplate(execCtx, nodeCfg.Message)
+	if err != nil {
+		return fmt.Errorf("failed to render notification message: %w", err)
+	}
+	message = strings.TrimSpace(fmt.Sprintf("%s\n\nApprove: %s\nReject: %s", message, approveUrl, rejectUrl))
+
+	notifier := notify.NewClient(notify.WithLogger(ne.logger))
+	notifyReq := &notify.SendNotificationRequest{
+		Provider:               domain.NotificationProviderType(nodeCfg.Provider),
+		ProviderAccessConfig:   providerAccessConfig,
+		ProviderExtendedConfig: nodeCfg.ProviderConfig,
+		Subject:                subject,
+		Message:                message,
+	}
+	if _, err := notifier.SendNotification(execCtx.Context(), notifyReq); err != nil {
+		return err
+	}
+
+	ne.logger.Info("approval request sent")
+	return nil
+}
+
+func newApprovalNodeExecutor() NodeExecutor {
+	return &approvalNodeExecutor{
+		nodeExecutor:         nodeExecutor{logger: slog.Default()},
+		accessRepo:           repository.NewAccessRepository(),
+		workflowApprovalRepo: repository.NewWorkflowApprovalRepository(),
+	}
+}
+
+type approveBlockNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *approveBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	if err := engine.executeBlocks(execCtx.Clone(), execCtx.Node.Blocks); err != nil {
+		if errors.Is(err, ErrTerminated) {
+			return execRes, err
+		}
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
+func newApproveBlockNodeExecutor() NodeExecutor {
+	return &approveBlockNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+type rejectBlockNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *rejectBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	if err := engine.executeBlocks(execCtx.Clone(), execCtx.Node.Blocks); err != nil {
+		if errors.Is(err, ErrTerminated) {
+			return execRes, err
+		}
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
+func newRejectBlockNodeExecutor() NodeExecutor {
+	return &rejectBlockNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+// 构造审批链接，形如 "{appURL}/api/webhooks/approvals/{runId}/{nodeId}?action=approve&expires=...&signature=..."。
+func buildApprovalLink(runId string, nodeId string, action string, expires int64) (string, error) {
+	signature, err := signApprovalLink(runId, nodeId, action, expires)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("action", action)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signature)
+
+	appURL := strings.TrimRight(app.GetApp().Settings().Meta.AppURL, "/")
+	return fmt.Sprintf("%s/api/webhooks/approvals/%s/%s?%s", appURL, url.PathEscape(runId), url.PathEscape(nodeId), query.Encode()), nil
+}
+
+const (
+	envApprovalSecretKey    = "CERTIMATE_WORKFLOW_APPROVAL_SECRET"
+	approvalSecretKeyFile   = ".approval_secret"
+	approvalSecretKeyLength = 32
+)
+
+var (
+	approvalSecretKey     string
+	approvalSecretKeyErr  error
+	approvalSecretKeyOnce sync.Once
+)
+
+// 获取用于签名审批链接的密钥。
+// 优先读取环境变量，否则在数据目录下自动生成并持久化，以确保服务重启后已发出的审批链接仍然有效。
+func getApprovalSecretKey() (string, error) {
+	approvalSecretKeyOnce.Do(func() {
+		if s := xenv.GetString(envApprovalSecretKey); s != "" {
+			approvalSecretKey = s
+			return
+		}
+
+		path := filepath.Join(app.GetApp().DataDir(), approvalSecretKeyFile)
+		if data, err := os.ReadFile(path); err == nil {
+			approvalSecretKey = strings.TrimSpace(string(data))
+			return
+		} else if !errors.Is(err, os.ErrNotExist) {
+			approvalSecretKeyErr = fmt.Errorf("failed to read approval secret: %w", err)
+			return
+		}
+
+		s := security.RandomString(approvalSecretKeyLength)
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			approvalSecretKeyErr = fmt.Errorf("failed to write approval secret: %w", err)
+			return
+		}
+
+		approvalSecretKey = s
+	})
+
+	return approvalSecretKey, approvalSecretKeyErr
+}
+
+// 计算审批链接签名：HMAC-SHA256(key, runId + "." + nodeId + "." + action + "." + expires)，以十六进制表示。
+func signApprovalLink(runId string, nodeId string, action string, expires int64) (string, error) {
+	secret, err := getApprovalSecretKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get approval signing key: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{runId, nodeId, action, strconv.FormatInt(expires, 10)}, ".")))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// 校验审批链接的签名及有效期。
+func VerifyApprovalLink(runId string, nodeId string, action string, expires string, signature string, now time.Time) error {
+	if action != ApprovalActionApprove && action != ApprovalActionReject {
+		return errors.New("invalid approval action")
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errors.New("invalid approval link expiration")
+	} else if now.Unix() > unix {
+		return errors.New("approval link has expired")
+	}
+
+	expected, err := signApprovalLink(runId, nodeId, action, unix)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return errors.New("approval link signature mismatch")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockWorkflowApprovalRepository struct {
+	mtx       sync.Mutex
+	approvals map[string]*domain.WorkflowApproval // Key: RunId/NodeId
+}
+
+func (r *mockWorkflowApprovalRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context, workflowRunId string, workflowNodeId string) (*domain.WorkflowApproval, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if approval, ok := r.approvals[workflowRunId+"/"+workflowNodeId]; ok {
+		copied := *approval
+		return &copied, nil
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockWorkflowApprovalRepository) Save(ctx context.Context, workflowApproval *domain.WorkflowApproval) (*domain.WorkflowApproval, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	copied := *workflowApproval
+	r.approvals[workflowApproval.RunId+"/"+workflowApproval.NodeId] = &copied
+	return workflowApproval, nil
+}
+
+func (r *mockWorkflowApprovalRepository) Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	approval, ok := r.approvals[workflowApproval.RunId+"/"+workflowApproval.NodeId]
+	if !ok {
+		return false, domain.ErrRecordNotFound
+	} else if !approval.IsPending() {
+		return false, nil
+	}
+
+	copied := *workflowApproval
+	r.approvals[workflowApproval.RunId+"/"+workflowApproval.NodeId] = &copied
+	return true, nil
+}
+
+func newMockApprovalExecution(repo *mockWorkflowApprovalRepository) (*approvalNodeExecutor, *NodeExecutionContext, *workflowEngine) {
+	we := newMockEngine()
+	ne := &approvalNodeExecutor{
+		nodeExecutor:         nodeExecutor{logger: slog.Default()},
+		workflowApprovalRepo: repo,
+	}
+
+	node := &Node{Id: "approval", Type: NodeTypeApproval}
+	execCtx := newMockNodeExecutionContext(node)
+	execCtx.SetEngine(we)
+	return ne, execCtx, we
+}
+
+func TestApprovalWaitForDecision_AlreadyDecided(t *testing.T) {
+	repo := &mockWorkflowApprovalRepository{approvals: map[string]*domain.WorkflowApproval{
+		"run/approval": {RunId: "run", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypeRejected, Operator: "alice"},
+	}}
+	ne, execCtx, _ := newMockApprovalExecution(repo)
+
+	// 服务重启后恢复执行时，已作出的决定不应重新发送审批请求或再次等待
+	approval, err := ne.waitForDecision(execCtx, execCtx.engine.(*workflowEngine), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeRejected, approval.Status)
+	assert.Equal(t, "alice", approval.Operator)
+}
+
+func TestApprovalWaitForDecision_Expired(t *testing.T) {
+	repo := &mockWorkflowApprovalRepository{approvals: map[string]*domain.WorkflowApproval{
+		"run/approval": {RunId: "run", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypePending, ExpiresAt: time.Now().Add(-time.Minute)},
+	}}
+	ne, execCtx, _ := newMockApprovalExecution(repo)
+
+	approval, err := ne.waitForDecision(execCtx, execCtx.engine.(*workflowEngine), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeTimeout, approval.Status)
+
+	persisted, _ := repo.GetByWorkflowRunIdAndNodeId(context.Background(), "run", "approval")
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeTimeout, persisted.Status)
+}
+
+func TestApprovalWaitForDecision_DecidedElsewhere(t *testing.T) {
+	prevInterval := approvalPollInterval
+	approvalPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { approvalPollInterval = prevInterval })
+
+	repo := &mockWorkflowApprovalRepository{approvals: map[string]*domain.WorkflowApproval{
+		"run/approval": {RunId: "run", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypePending, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	ne, execCtx, _ := newMockApprovalExecution(repo)
+
+	// 模拟在调度器之外（例如子工作流中或服务重启期间）作出的决定
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		repo.Decide(context.Background(), &domain.WorkflowApproval{
+			RunId:    "run",
+			NodeId:   "approval",
+			Status:   domain.WorkflowApprovalStatusTypeApproved,
+			Operator: "bob",
+		})
+	}()
+
+	approval, err := ne.waitForDecision(execCtx, execCtx.engine.(*workflowEngine), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeApproved, approval.Status)
+	assert.Equal(t, "bob", approval.Operator)
+}
+
+func TestApprovalWaitForDecision_Canceled(t *testing.T) {
+	repo := &mockWorkflowApprovalRepository{approvals: map[string]*domain.WorkflowApproval{
+		"run/approval": {RunId: "run", NodeId: "approval", Status: domain.WorkflowApprovalStatusTypePending},
+	}}
+	ne, execCtx, _ := newMockApprovalExecution(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	execCtx.SetContext(ctx)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := ne.waitForDecision(execCtx, execCtx.engine.(*workflowEngine), 0)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	persisted, _ := repo.GetByWorkflowRunIdAndNodeId(context.Background(), "run", "approval")
+	assert.Equal(t, domain.WorkflowApprovalStatusTypeCanceled, persisted.Status)
+}
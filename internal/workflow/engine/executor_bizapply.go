@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"log/slog"
@@ -8,21 +9,26 @@ import (
 	"math"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-acme/lego/v5/acme/api"
+	"github.com/go-acme/lego/v5/certcrypto"
 	"github.com/go-acme/lego/v5/lego"
 	"github.com/samber/lo"
 	"github.com/xhit/go-str2duration/v2"
 
 	"github.com/certimate-go/certimate/internal/certacme"
+	"github.com/certimate-go/certimate/internal/certacme/certifiers"
 	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/privateca"
 	"github.com/certimate-go/certimate/internal/repository"
 	"github.com/certimate-go/certimate/internal/settings"
 	"github.com/certimate-go/certimate/internal/tools/mproc"
 	xcert "github.com/certimate-go/certimate/pkg/utils/cert"
 	xcertkey "github.com/certimate-go/certimate/pkg/utils/cert/key"
 	xenv "github.com/certimate-go/certimate/pkg/utils/env"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
 )
 
 var envMultiProc = true
@@ -35,6 +41,7 @@ const (
 	BizApplyKeySourceAuto   = "auto"
 	BizApplyKeySourceReuse  = "reuse"
 	BizApplyKeySourceCustom = "custom"
+	BizApplyKeySourceCSR    = "csr"
 )
 
 /**
@@ -43,6 +50,7 @@ const (
  *
  * Variables:
  *   - "node.skipped": boolean
+ *   - "challenge.records": string
  *   - "certificate.commanName": string
  *   - "certificate.subjectAltNames": string
  *   - "certificate.notBefore": datetime
@@ -56,13 +64,22 @@ type bizApplyNodeExecutor struct {
 
 	accessRepo      accessRepository
 	certificateRepo certificateRepository
+	privateCARepo   privateCARepository
 	wfoutputRepo    workflowOutputRepository
 }
 
 func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
 	execRes := newNodeExecutionResult(execCtx.Node)
 
-	nodeCfg := execCtx.Node.Data.Config.AsBizApply()
+	nodeRawCfg, err := ne.renderNodeConfig(execCtx)
+	if err != nil {
+		return execRes, err
+	} else {
+		// 持久化渲染后的配置，以便下次执行时按实际申请的域名判断能否跳过
+		execRes.outputNodeConfig = nodeRawCfg
+	}
+
+	nodeCfg := nodeRawCfg.AsBizApply()
 	ne.logger.Info("ready to request certificate ...", slog.Any("config", nodeCfg))
 
 	// 查询上次执行结果
@@ -82,7 +99,7 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 	}
 
 	// 检测是否可以跳过本次执行
-	if skippable, reason := ne.checkCanSkip(execCtx, lastOutput, lastCertificate); skippable {
+	if skippable, reason := ne.checkCanSkip(execCtx, &nodeCfg, lastOutput, lastCertificate); skippable {
 		ne.logger.Info(fmt.Sprintf("skip this application, because %s", reason))
 
 		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, true, stateValTypeBoolean)
@@ -103,6 +120,18 @@ func (ne *bizApplyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExe
 		return execRes, err
 	}
 
+	// 试运行时不保存证书实体，仅输出变量以便后续节点渲染
+	if execCtx.DryRun {
+		if obtainResp != nil {
+			certificate := &domain.Certificate{}
+			certificate.PopulateFromPEM(obtainResp.FullChainCertificate, obtainResp.PrivateKey)
+			ne.setVariablesOfResult(execCtx, execRes, certificate)
+		}
+
+		ne.logger.Info("application completed (dry run)")
+		return execRes, nil
+	}
+
 	// 保存证书实体
 	certificate := &domain.Certificate{
 		Source:             domain.CertificateSourceTypeRequest,
@@ -156,8 +185,7 @@ func (ne *bizApplyNodeExecutor) getLastOutputArtifacts(execCtx *NodeExecutionCon
 	return lastOutput, nil, nil
 }
 
-func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, lastOutput *domain.WorkflowOutput, lastCertificate *domain.Certificate) (_skip bool, _reason string) {
-	thisNodeCfg := execCtx.Node.Data.Config.AsBizApply()
+func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, thisNodeCfg *domain.WorkflowNodeConfigForBizApply, lastOutput *domain.WorkflowOutput, lastCertificate *domain.Certificate) (_skip bool, _reason string) {
 
 	if lastOutput != nil && lastOutput.Succeeded {
 		// 比较和上次申请时的关键配置（即影响证书签发的）参数是否一致
@@ -181,6 +209,11 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 		if !maps.Equal(thisNodeCfg.ProviderConfig, lastNodeCfg.ProviderConfig) {
 			return false, "the configuration item 'ProviderConfig' changed"
 		}
+		if !slices.EqualFunc(thisNodeCfg.ProviderMappings, lastNodeCfg.ProviderMappings, func(a, b domain.WorkflowNodeConfigForBizApplyProviderMapping) bool {
+			return a.Zone == b.Zone && a.Provider == b.Provider && a.ProviderAccessId == b.ProviderAccessId && maps.Equal(a.ProviderConfig, b.ProviderConfig)
+		}) {
+			return false, "the configuration item 'ProviderMappings' changed"
+		}
 		if thisNodeCfg.CAProvider != lastNodeCfg.CAProvider {
 			return false, "the configuration item 'CAProvider' changed"
 		}
@@ -196,6 +229,12 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 		if thisNodeCfg.KeySource == BizApplyKeySourceCustom && thisNodeCfg.KeyContent != lastNodeCfg.KeyContent {
 			return false, "the configuration item 'KeyContent' changed"
 		}
+		if thisNodeCfg.KeySource != lastNodeCfg.KeySource && (thisNodeCfg.KeySource == BizApplyKeySourceCSR || lastNodeCfg.KeySource == BizApplyKeySourceCSR) {
+			return false, "the configuration item 'KeySource' changed"
+		}
+		if thisNodeCfg.KeySource == BizApplyKeySourceCSR && thisNodeCfg.CSRContent != lastNodeCfg.CSRContent {
+			return false, "the configuration item 'CSRContent' changed"
+		}
 		if thisNodeCfg.ValidityLifetime != lastNodeCfg.ValidityLifetime {
 			return false, "the configuration item 'ValidityLifetime' changed"
 		}
@@ -215,6 +254,10 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 			return false, "the last requested certificate has been revoked"
 		}
 
+		if !thisNodeCfg.DisableARI && lastCertificate.ARIRenewalInfo.IsWindowReached(time.Now()) {
+			return false, "the last requested certificate has reached the renewal window suggested by ARI"
+		}
+
 		renewalInterval := time.Duration(thisNodeCfg.SkipBeforeExpiryDays) * time.Hour * 24
 		expirationTime := time.Until(lastCertificate.ValidityNotAfter)
 		daysLeft := int(math.Floor(expirationTime.Hours() / 24))
@@ -229,6 +272,22 @@ func (ne *bizApplyNodeExecutor) checkCanSkip(execCtx *NodeExecutionContext, last
 	return false, ""
 }
 
+// 渲染配置中的域名及 IP 地址模板，例如循环中的 "{{ $loop.item }}"，返回新的配置，原配置不会被修改。
+func (ne *bizApplyNodeExecutor) renderNodeConfig(execCtx *NodeExecutionContext) (domain.WorkflowNodeConfig, error) {
+	config := maps.Clone(execCtx.Node.Data.Config)
+	if config == nil {
+		return domain.WorkflowNodeConfig{}, nil
+	}
+
+	rendered, err := renderTemplateConfig(execCtx, lo.PickByKeys(config, []string{"domains", "ipaddrs"}))
+	if err != nil {
+		return nil, err
+	}
+
+	maps.Copy(config, rendered)
+	return config, nil
+}
+
 func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, lastCertificate *domain.Certificate) (*certacme.ObtainCertificateResponse, error) {
 	// 读取私钥算法
 	// 如果复用私钥，则保持算法一致
@@ -259,6 +318,20 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 				return nil, fmt.Errorf("could not parse custom private key: unsupported algorithm")
 			}
 		}
+	case BizApplyKeySourceCSR:
+		csr, err := certcrypto.PemDecodeTox509CSR([]byte(nodeCfg.CSRContent))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse custom csr: %w", err)
+		} else if csrKeyType, err := certcrypto.GetCSRKeyType(csr); err != nil {
+			return nil, fmt.Errorf("could not parse custom csr: %w", err)
+		} else {
+			keyAlgorithm = domain.CertificateKeyAlgorithmType(csrKeyType)
+		}
+	}
+
+	// 如果使用内置私有 CA，则直接签发证书，无需经过 ACME 流程
+	if domain.CAProviderType(nodeCfg.CAProvider) == domain.CAProviderTypePrivateCA {
+		return ne.execIssueCertificateWithPrivateCA(execCtx, nodeCfg, keyAlgorithm, lastCertificate)
 	}
 
 	// 读取质询提供商授权
@@ -271,6 +344,26 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 		}
 	}
 
+	// 读取按域名映射的质询提供商授权
+	providerMappings := make([]*certacme.ObtainCertificateProviderMapping, 0, len(nodeCfg.ProviderMappings))
+	for _, mapping := range nodeCfg.ProviderMappings {
+		mappingAccessConfig := make(map[string]any)
+		if mapping.ProviderAccessId != "" {
+			if access, err := ne.accessRepo.GetById(execCtx.Context(), mapping.ProviderAccessId); err != nil {
+				return nil, fmt.Errorf("failed to get access #%s record: %w", mapping.ProviderAccessId, err)
+			} else {
+				mappingAccessConfig = access.Config
+			}
+		}
+
+		providerMappings = append(providerMappings, &certacme.ObtainCertificateProviderMapping{
+			Zone:                   mapping.Zone,
+			Provider:               domain.ACMEChallengeProviderType(mapping.Provider),
+			ProviderAccessConfig:   mappingAccessConfig,
+			ProviderExtendedConfig: mapping.ProviderConfig,
+		})
+	}
+
 	// 读取证书颁发机构授权
 	caAccessConfig := make(map[string]any)
 	if nodeCfg.CAProviderAccessId != "" {
@@ -292,8 +385,38 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 	if err != nil {
 		ne.logger.Warn("could not initialize acme config")
 		return nil, err
-	} else {
-		ne.logger.Info("acme config initialized", slog.String("acmeDirUrl", acmeCfg.CADirUrl))
+	}
+
+	// 试运行时，如果 CA 提供测试环境，则改为向测试环境申请证书
+	dryRunStaging := false
+	if execCtx.DryRun {
+		if stagingProvider, ok := certacme.GetStagingCAProvider(acmeCfg.CAProvider); ok {
+			acmeOpts.CAProvider = stagingProvider
+			acmeOpts.CAProviderAccessConfig = nil
+			acmeOpts.CAProviderExtendedConfig = nil
+			acmeCfg, err = certacme.CreateACMEConfig(execCtx.Context(), acmeOpts)
+			if err != nil {
+				ne.logger.Warn("could not initialize acme config")
+				return nil, err
+			}
+
+			dryRunStaging = true
+		}
+	}
+
+	ne.logger.Info("acme config initialized", slog.String("acmeDirUrl", acmeCfg.CADirUrl))
+
+	// 执行预检，在创建订单前发现 CAA 或 CNAME 委派问题
+	if !nodeCfg.DisablePreflight {
+		if err := ne.execPreflight(execCtx, nodeCfg, acmeCfg, providerMappings); err != nil {
+			return nil, err
+		}
+	}
+
+	// 试运行时，如果 CA 未提供测试环境，则跳过下单
+	if execCtx.DryRun && !dryRunStaging {
+		ne.logger.Info(fmt.Sprintf("skip ordering in dry run, because the ca provider '%s' has no staging environment", acmeCfg.CAProvider))
+		return nil, nil
 	}
 
 	// 初始化 ACME 账户
@@ -323,6 +446,7 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 				}
 				return ""
 			}),
+		CSRPEM: lo.If(nodeCfg.KeySource == BizApplyKeySourceCSR, nodeCfg.CSRContent).Else(""),
 		ValidityNotAfter: lo.
 			If(nodeCfg.ValidityLifetime == "", time.Time{}).
 			ElseF(func() time.Time {
@@ -337,12 +461,14 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 		Provider:               domain.ACMEChallengeProviderType(nodeCfg.Provider),
 		ProviderAccessConfig:   providerAccessConfig,
 		ProviderExtendedConfig: nodeCfg.ProviderConfig,
+		ProviderMappings:       providerMappings,
 		DisableFollowCNAME:     nodeCfg.DisableFollowCNAME,
 		Nameservers:            nodeCfg.Nameservers,
 		DnsPropagationWait:     nodeCfg.DnsPropagationWait,
 		DnsPropagationTimeout:  nodeCfg.DnsPropagationTimeout,
 		DnsTTL:                 nodeCfg.DnsTTL,
 		HttpDelayWait:          nodeCfg.HttpDelayWait,
+		TlsAlpnDelayWait:       nodeCfg.TlsAlpnDelayWait,
 		PreferredChain:         nodeCfg.PreferredChain,
 		ACMEProfile:            nodeCfg.ACMEProfile,
 		ARIReplacesAccountUrl: lo.
@@ -378,6 +504,12 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 			}),
 	}
 
+	// 测试环境无法替换正式环境签发的证书，因此试运行时不使用 ARI
+	if execCtx.DryRun {
+		obtainReq.ARIReplacesAccountUrl = ""
+		obtainReq.ARIReplacesCertId = ""
+	}
+
 	// 构造证书申请时所需的 lego 配置项
 	legoCertifierCfg := &lego.NewConfig(nil).Certificate
 	globalSettingsForPersistence := settings.GetGlobalSettingsForSSLProvider()
@@ -385,8 +517,15 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 		legoCertifierCfg.Timeout = time.Duration(globalSettingsForPersistence.Timeout) * time.Second
 	}
 
+	// 如果需要人工介入质询，则注入交互器
+	// 注意交互需要与工作流引擎通信，因此这种情况下不使用多进程模式
+	interactive := ne.checkRequiresInteraction(nodeCfg)
+	if interactive {
+		obtainReq.Interactor = &bizApplyChallengeInteractor{execCtx: execCtx, logger: ne.logger}
+	}
+
 	// 如果启用多进程模式，发送指令
-	if envMultiProc {
+	if envMultiProc && !interactive {
 		type InData struct {
 			Request *certacme.ObtainCertificateRequest `json:"request,omitempty"`
 
@@ -437,6 +576,136 @@ func (ne *bizApplyNodeExecutor) execObtainCertificate(execCtx *NodeExecutionCont
 	return obtainResp, nil
 }
 
+func (ne *bizApplyNodeExecutor) execPreflight(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, acmeCfg *certacme.ACMEConfig, providerMappings []*certacme.ObtainCertificateProviderMapping) error {
+	report, err := certacme.Preflight(execCtx.Context(), &certacme.PreflightRequest{
+		DomainOrIPs:        nodeCfg.Domains,
+		CAProvider:         acmeCfg.CAProvider,
+		CADirUrl:           acmeCfg.CADirUrl,
+		ChallengeType:      nodeCfg.ChallengeType,
+		Provider:           domain.ACMEChallengeProviderType(nodeCfg.Provider),
+		ProviderMappings:   providerMappings,
+		DisableFollowCNAME: nodeCfg.DisableFollowCNAME,
+		Nameservers:        nodeCfg.Nameservers,
+	})
+	if err != nil {
+		ne.logger.Warn("could not run preflight checks")
+		return err
+	}
+
+	failedDomains := make([]string, 0)
+	for _, domainReport := range report.Domains {
+		for _, check := range domainReport.Checks {
+			attrs := []any{slog.String("domain", domainReport.Domain), slog.String("check", check.Name.String()), slog.String("status", check.Status.String())}
+			switch check.Status {
+			case domain.CertificatePreflightStatusTypeFailed:
+				ne.logger.Error(fmt.Sprintf("preflight check failed: %s", check.Message), attrs...)
+			case domain.CertificatePreflightStatusTypeWarning, domain.CertificatePreflightStatusTypeUnknown:
+				ne.logger.Warn(fmt.Sprintf("preflight check %s: %s", check.Status, check.Message), attrs...)
+			default:
+				ne.logger.Info(fmt.Sprintf("preflight check %s: %s", check.Status, check.Message), attrs...)
+			}
+		}
+
+		if !domainReport.Passed {
+			failedDomains = append(failedDomains, domainReport.Domain)
+		}
+	}
+
+	if !report.Passed {
+		return fmt.Errorf("preflight checks failed for domain(s): %s", strings.Join(failedDomains, ", "))
+	}
+
+	ne.logger.Info("preflight checks passed")
+	return nil
+}
+
+func (ne *bizApplyNodeExecutor) execIssueCertificateWithPrivateCA(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizApply, keyAlgorithm domain.CertificateKeyAlgorithmType, lastCertificate *domain.Certificate) (*certacme.ObtainCertificateResponse, error) {
+	privateCAId := xmaps.GetString(nodeCfg.CAProviderConfig, "privateCAId")
+	if privateCAId == "" {
+		return nil, fmt.Errorf("the private ca is not specified")
+	}
+
+	privateCA, err := ne.privateCARepo.GetById(execCtx.Context(), privateCAId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get private ca #%s record: %w", privateCAId, err)
+	}
+
+	issuer, err := privateca.NewIssuer(privateCA)
+	if err != nil {
+		ne.logger.Warn("could not initialize private ca issuer")
+		return nil, err
+	} else {
+		ne.logger.Info("private ca issuer initialized", slog.String("privateCAId", privateCA.Id))
+	}
+
+	// 试运行时跳过签发
+	if execCtx.DryRun {
+		ne.logger.Info("skip issuing in dry run")
+		return nil, nil
+	}
+
+	issueReq := &privateca.IssueCertificateRequest{
+		DomainOrIPs:    lo.Concat(nodeCfg.Domains, nodeCfg.IPAddrs),
+		PrivateKeyType: keyAlgorithm.LegoKeyType(),
+		PrivateKeyPEM: lo.
+			If(nodeCfg.KeySource == BizApplyKeySourceAuto, "").
+			ElseF(func() string {
+				switch nodeCfg.KeySource {
+				case BizApplyKeySourceReuse:
+					if lastCertificate != nil {
+						return lastCertificate.PrivateKey
+					}
+				case BizApplyKeySourceCustom:
+					return nodeCfg.KeyContent
+				}
+				return ""
+			}),
+		CSRPEM: lo.If(nodeCfg.KeySource == BizApplyKeySourceCSR, nodeCfg.CSRContent).Else(""),
+		ValidityNotAfter: lo.
+			If(nodeCfg.ValidityLifetime == "", time.Time{}).
+			ElseF(func() time.Time {
+				duration, err := str2duration.ParseDuration(nodeCfg.ValidityLifetime)
+				if err != nil {
+					return time.Time{}
+				}
+				return time.Now().Add(duration)
+			}),
+		NoCommonName: nodeCfg.DisableCommonName,
+		KeyUsages:    xmaps.GetStringsBySplit(nodeCfg.CAProviderConfig, "keyUsages", ";"),
+		ExtKeyUsages: xmaps.GetStringsBySplit(nodeCfg.CAProviderConfig, "extKeyUsages", ";"),
+	}
+	issueResp, err := issuer.IssueCertificate(execCtx.Context(), issueReq)
+	if err != nil {
+		ne.logger.Warn("could not issue certificate")
+		return nil, err
+	}
+
+	return &certacme.ObtainCertificateResponse{
+		CAProvider:           domain.CAProviderTypePrivateCA,
+		FullChainCertificate: issueResp.FullChainCertificate,
+		IssuerCertificate:    issueResp.IssuerCertificate,
+		PrivateKey:           issueResp.PrivateKey,
+	}, nil
+}
+
+func (ne *bizApplyNodeExecutor) checkRequiresInteraction(nodeCfg *domain.WorkflowNodeConfigForBizApply) bool {
+	if nodeCfg.ChallengeType != certacme.CHALLENGE_TYPE_DNS01 {
+		return false
+	}
+
+	if domain.ACMEDns01ProviderType(nodeCfg.Provider) == domain.ACMEDns01ProviderTypeManual {
+		return true
+	}
+
+	for _, mapping := range nodeCfg.ProviderMappings {
+		if domain.ACMEDns01ProviderType(mapping.Provider) == domain.ACMEDns01ProviderTypeManual {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ne *bizApplyNodeExecutor) setOuputsOfResult(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, certificate *domain.Certificate, persistent bool) {
 	if certificate != nil {
 		key := "certificate"
@@ -493,6 +762,49 @@ func newBizApplyNodeExecutor() NodeExecutor {
 		nodeExecutor:    nodeExecutor{logger: slog.Default()},
 		accessRepo:      repository.NewAccessRepository(),
 		certificateRepo: repository.NewCertificateRepository(),
+		privateCARepo:   repository.NewPrivateCARepository(),
 		wfoutputRepo:    repository.NewWorkflowOutputRepository(),
 	}
 }
+
+type bizApplyChallengeInteractor struct {
+	execCtx *NodeExecutionContext
+	logger  *slog.Logger
+
+	recordsMtx sync.Mutex
+	records    []string
+}
+
+var _ certifiers.ChallengeInteractor = (*bizApplyChallengeInteractor)(nil)
+
+func (i *bizApplyChallengeInteractor) Publish(ctx context.Context, domain, recordName, recordValue string) error {
+	i.logger.Info("manual challenge record is required, please create it and then confirm to continue", slog.String("domain", domain), slog.String("type", "TXT"), slog.String("name", recordName), slog.String("value", recordValue))
+
+	i.recordsMtx.Lock()
+	defer i.recordsMtx.Unlock()
+
+	// 以 "名称 TXT 值" 的形式发布到节点作用域变量，多条记录以半角分号分隔
+	i.records = append(i.records, fmt.Sprintf("%s TXT %s", recordName, recordValue))
+	i.execCtx.variables.SetScoped(i.execCtx.Node.Id, stateVarKeyChallengeRecords, strings.Join(i.records, ";"), stateValTypeString)
+
+	return nil
+}
+
+func (i *bizApplyChallengeInteractor) WaitForConfirmation(ctx context.Context, timeout time.Duration) error {
+	engine, ok := i.execCtx.engine.(*workflowEngine)
+	if !ok {
+		return fmt.Errorf("the workflow engine does not support suspension")
+	}
+
+	i.logger.Info("waiting for confirmation ...", slog.Duration("timeout", timeout))
+
+	signal, err := engine.suspendNode(ctx, i.execCtx.Node, timeout)
+	if signal != nil {
+		i.logger.Info("confirmation received", slog.String("operator", signal.Operator), slog.Bool("rejected", signal.Rejected), slog.String("comment", signal.Comment))
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
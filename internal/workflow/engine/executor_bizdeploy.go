@@ -17,6 +17,7 @@ import (
  *
  * Variables:
  *   - "node.skipped": boolean
+ *   - "deployment.validated": boolean (dry run only)
  */
 type bizDeployNodeExecutor struct {
 	nodeExecutor
@@ -42,6 +43,11 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 
+	// 试运行时仅校验部署配置，不实际部署
+	if execCtx.DryRun {
+		return ne.execValidate(execCtx, &nodeCfg)
+	}
+
 	// 获取前序节点输出证书
 	var inputCertificate *domain.Certificate
 	if inputState, ok := execCtx.inputs.Get(nodeCfg.CertificateOutputNodeId, "certificate"); ok {
@@ -88,12 +94,19 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 
+	// 渲染部署提供商配置
+	providerExtendedConfig, err := renderTemplateConfig(execCtx, nodeCfg.ProviderConfig)
+	if err != nil {
+		ne.logger.Warn("could not render provider config")
+		return execRes, err
+	}
+
 	// 部署证书
 	deployer := certmgmt.NewClient(certmgmt.WithLogger(ne.logger))
 	deployReq := &certmgmt.DeployCertificateRequest{
 		Provider:               domain.DeploymentProviderType(nodeCfg.Provider),
 		ProviderAccessConfig:   providerAccessConfig,
-		ProviderExtendedConfig: nodeCfg.ProviderConfig,
+		ProviderExtendedConfig: providerExtendedConfig,
 		CertificatePEM:         inputCertificate.Certificate,
 		PrivateKeyPEM:          inputCertificate.PrivateKey,
 	}
@@ -109,6 +122,50 @@ func (ne *bizDeployNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	return execRes, nil
 }
 
+func (ne *bizDeployNodeExecutor) execValidate(execCtx *NodeExecutionContext, nodeCfg *domain.WorkflowNodeConfigForBizDeploy) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyNodeSkipped, false, stateValTypeBoolean)
+
+	// 读取部署提供商授权
+	providerAccessConfig := make(map[string]any)
+	if nodeCfg.ProviderAccessId != "" {
+		if access, err := ne.accessRepo.GetById(execCtx.Context(), nodeCfg.ProviderAccessId); err != nil {
+			return nil, fmt.Errorf("failed to get access #%s record: %w", nodeCfg.ProviderAccessId, err)
+		} else {
+			providerAccessConfig = access.Config
+		}
+	}
+
+	// 渲染部署提供商配置
+	providerExtendedConfig, err := renderTemplateConfig(execCtx, nodeCfg.ProviderConfig)
+	if err != nil {
+		ne.logger.Warn("could not render provider config")
+		return execRes, err
+	}
+
+	// 校验部署配置
+	deployer := certmgmt.NewClient(certmgmt.WithLogger(ne.logger))
+	validateReq := &certmgmt.ValidateDeploymentRequest{
+		Provider:               domain.DeploymentProviderType(nodeCfg.Provider),
+		ProviderAccessConfig:   providerAccessConfig,
+		ProviderExtendedConfig: providerExtendedConfig,
+	}
+	if validateResp, err := deployer.ValidateDeployment(execCtx.Context(), validateReq); err != nil {
+		ne.logger.Warn("could not validate deployment")
+		return execRes, err
+	} else if !validateResp.Supported {
+		// 部署提供商未实现校验时，需明确告知，避免误以为已校验通过
+		execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyDeploymentValidated, false, stateValTypeBoolean)
+		// 日志中附带校验结果，以便在试运行日志中逐个节点标注
+		ne.logger.Warn(fmt.Sprintf("validation not supported by the deployment provider '%s', only the access record was checked", nodeCfg.Provider), slog.Bool(stateVarKeyDeploymentValidated, false))
+		return execRes, nil
+	}
+
+	execRes.AddVariableWithScope(execCtx.Node.Id, stateVarKeyDeploymentValidated, true, stateValTypeBoolean)
+	ne.logger.Info("deployment validated (dry run)", slog.Bool(stateVarKeyDeploymentValidated, true))
+	return execRes, nil
+}
+
 func (ne *bizDeployNodeExecutor) getLastOutputArtifacts(execCtx *NodeExecutionContext) (*domain.WorkflowOutput, error) {
 	lastOutput, err := ne.wfoutputRepo.GetByWorkflowIdAndNodeId(execCtx.Context(), execCtx.WorkflowId, execCtx.Node.Id)
 	if err != nil && !domain.IsRecordNotFoundError(err) {
@@ -3,9 +3,6 @@ package engine
 import (
 	"fmt"
 	"log/slog"
-	"regexp"
-	"strings"
-	"time"
 
 	"github.com/certimate-go/certimate/internal/domain"
 	"github.com/certimate-go/certimate/internal/notify"
@@ -41,29 +38,20 @@ func (ne *bizNotifyNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	}
 
 	// 渲染通知模板
-	reMustache := regexp.MustCompile(`\{\{\s*(\$[^\s]+)\s*\}\}`)
-	reMustacheReplacer := func(match string) string {
-		mustache := strings.TrimSpace(match[2 : len(match)-2])
-		if mustache == "" {
-			return match
-		}
-
-		key := mustache[1:]
-		if key == "" {
-			return match
-		} else if key == "now" {
-			return time.Now().Format(time.RFC3339)
-		}
-
-		// TODO: 支持作用域变量
-		if state, ok := execCtx.variables.Get(key); ok {
-			return state.ValueString()
-		}
+	subject, err := renderTemplate(execCtx, nodeCfg.Subject)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render notification subject: %w", err)
+	}
+	message, err := renderTemplate(execCtx, nodeCfg.Message)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to render notification message: %w", err)
+	}
 
-		return match
+	// 试运行时仅渲染通知内容，不实际推送
+	if execCtx.DryRun {
+		ne.logger.Info("skip sending notification in dry run", slog.String("subject", subject), slog.String("message", message))
+		return execRes, nil
 	}
-	subject := reMustache.ReplaceAllStringFunc(nodeCfg.Subject, reMustacheReplacer)
-	message := reMustache.ReplaceAllStringFunc(nodeCfg.Message, reMustacheReplacer)
 
 	// 推送通知
 	notifier := notify.NewClient(notify.WithLogger(ne.logger))
@@ -173,6 +173,16 @@ func (ne *bizUploadNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 		}
 	}
 
+	// 试运行时不保存证书实体，仅输出变量以便后续节点渲染
+	if execCtx.DryRun {
+		certificate := &domain.Certificate{}
+		certificate.PopulateFromPEM(certPEM, privkeyPEM)
+		ne.setVariablesOfResult(execCtx, execRes, certificate)
+
+		ne.logger.Info("uploading completed (dry run)")
+		return execRes, nil
+	}
+
 	// 保存证书实体
 	certificate := &domain.Certificate{
 		Source:         domain.CertificateSourceTypeUpload,
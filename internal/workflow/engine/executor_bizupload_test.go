@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type mockCertificateRepository struct {
+	saved []*domain.Certificate
+}
+
+func (r *mockCertificateRepository) GetById(ctx context.Context, id string) (*domain.Certificate, error) {
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockCertificateRepository) GetByWorkflowRunIdAndNodeId(ctx context.Context, workflowRunId string, workflowNodeId string) (*domain.Certificate, error) {
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockCertificateRepository) Save(ctx context.Context, certificate *domain.Certificate) (*domain.Certificate, error) {
+	r.saved = append(r.saved, certificate)
+	return certificate, nil
+}
+
+func generateTestCertificatePEM(t *testing.T) (string, string) {
+	t.Helper()
+
+	privkey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privkey.PublicKey, privkey)
+	require.NoError(t, err)
+
+	privkeyDER, err := x509.MarshalECPrivateKey(privkey)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	privkeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privkeyDER})
+	return string(certPEM), string(privkeyPEM)
+}
+
+func TestBizUploadExecute_DryRunDoesNotSave(t *testing.T) {
+	certPEM, privkeyPEM := generateTestCertificatePEM(t)
+
+	certificateRepo := &mockCertificateRepository{}
+	ne := &bizUploadNodeExecutor{
+		nodeExecutor:    nodeExecutor{logger: slog.Default()},
+		certificateRepo: certificateRepo,
+		wfoutputRepo:    &mockWorkflowOutputRepository{},
+	}
+
+	node := &Node{Id: "upload", Type: NodeTypeBizUpload, Data: domain.WorkflowNodeData{
+		Config: domain.WorkflowNodeConfig{
+			"source":      BizUploadSourceForm,
+			"certificate": certPEM,
+			"privateKey":  privkeyPEM,
+		},
+	}}
+	execCtx := newMockNodeExecutionContext(node).SetDryRun(true)
+
+	execRes, err := ne.Execute(execCtx)
+	require.NoError(t, err)
+	assert.Empty(t, certificateRepo.saved)
+	assert.Empty(t, execRes.Outputs)
+
+	// 试运行时仍需输出证书变量，以便后续节点渲染
+	var commonName any
+	for _, variable := range execRes.Variables {
+		if variable.Key == stateVarKeyCertificateCommonName {
+			commonName = variable.Value
+		}
+	}
+	assert.Equal(t, "example.com", commonName)
+}
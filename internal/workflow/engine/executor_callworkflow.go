@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/repository"
+	"github.com/certimate-go/certimate/pkg/logging"
+)
+
+// 子工作流的最大调用深度。
+const callWorkflowMaxDepth = 8
+
+/**
+ * Inputs:
+ *   - 由配置项 "inputs" 指定的前序节点输出，在被调用工作流中作为其开始节点的同名输出
+ *
+ * Outputs:
+ *   - 被调用工作流中各节点的输出，作为本节点的同名输出
+ *   - ref: "run": string（仅子运行模式）
+ *
+ * Variables:
+ *   - 被调用工作流中产生的变量（密钥类型的变量除外），其中作用域变量的作用域将变更为本节点
+ */
+type callWorkflowNodeExecutor struct {
+	nodeExecutor
+
+	workflowRepo        workflowRepository
+	workflowRunRepo     workflowRunRepository
+	workflowVersionRepo workflowVersionRepository
+}
+
+func (ne *callWorkflowNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsCallWorkflow()
+	ne.logger.Info("ready to call workflow ...", slog.Any("config", nodeCfg))
+
+	// 检测循环调用
+	if slices.Contains(execCtx.callStack, nodeCfg.WorkflowId) {
+		callChain := append(slices.Clone(execCtx.callStack), nodeCfg.WorkflowId)
+		return execRes, fmt.Errorf("recursive workflow call detected: %s", strings.Join(callChain, " -> "))
+	} else if len(execCtx.callStack) >= callWorkflowMaxDepth {
+		return execRes, fmt.Errorf("the maximum depth (limit: %d) of workflow calls has been exceeded", callWorkflowMaxDepth)
+	}
+
+	// 读取被调用工作流
+	workflow, err := ne.workflowRepo.GetById(execCtx.Context(), nodeCfg.WorkflowId)
+	if err != nil {
+		return execRes, fmt.Errorf("failed to get workflow #%s record: %w", nodeCfg.WorkflowId, err)
+	} else if workflow.GraphContent == nil {
+		return execRes, fmt.Errorf("workflow #%s has not been published", workflow.Id)
+	} else if err := workflow.GraphContent.Verify(); err != nil {
+		return execRes, fmt.Errorf("workflow #%s graph content is invalid: %w", workflow.Id, err)
+	}
+
+	graph := workflow.GraphContent.Clone()
+
+	// 将选定的前序节点输出作为被调用工作流开始节点的输出传入
+	startNode := graph.Nodes[0]
+	inputs := make([]InOutState, 0, len(nodeCfg.Inputs))
+	for _, input := range nodeCfg.Inputs {
+		state, ok := execCtx.inputs.Get(input.NodeId, input.Name)
+		if !ok {
+			return execRes, fmt.Errorf("output '%s' of node #%s not found", input.Name, input.NodeId)
+		}
+
+		inputState := *state
+		inputState.NodeId = startNode.Id
+		inputState.Persistent = false
+		inputs = append(inputs, inputState)
+	}
+
+	var variables VariableManager
+	var outputs InOutManager
+	var baseVariables []VariableState
+	switch nodeCfg.Mode {
+	case domain.WorkflowNodeCallWorkflowModeInline:
+		baseVariables = execCtx.variables.All()
+		variables, outputs, err = ne.executeInline(execCtx, workflow, graph, inputs)
+
+	case domain.WorkflowNodeCallWorkflowModeChild:
+		variables, outputs, err = ne.executeChild(execCtx, execRes, workflow, graph, inputs)
+
+	default:
+		return execRes, fmt.Errorf("unsupported call workflow mode: '%s'", nodeCfg.Mode)
+	}
+	if err != nil {
+		return execRes, err
+	}
+
+	// 回传被调用工作流产生的变量及输出
+	for _, state := range diffVariableStates(baseVariables, variables.All()) {
+		if ne.isReservedVariable(state.Key) || state.ValueType == stateValTypeSecret {
+			continue
+		}
+
+		if state.Scope == "" {
+			execRes.AddVariable(state.Key, state.Value, state.ValueType)
+		} else {
+			execRes.AddVariableWithScope(execCtx.Node.Id, state.Key, state.Value, state.ValueType)
+		}
+	}
+	for _, state := range diffInOutStates(inputs, outputs.All()) {
+		state.NodeId = execCtx.Node.Id
+		execRes.addOutputState(state)
+	}
+
+	ne.logger.Info("workflow call completed")
+	return execRes, nil
+}
+
+// 内联模式：被调用工作流的节点作为当前运行的一部分执行，日志及输出均归属于当前运行。
+func (ne *callWorkflowNodeExecutor) executeInline(execCtx *NodeExecutionContext, workflow *domain.Workflow, graph *Graph, inputs []InOutState) (VariableManager, InOutManager, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	ne.logger.Info(fmt.Sprintf("run workflow #%s inline ...", workflow.Id))
+
+	variables := newVariableManagerWithStates(execCtx.variables.All())
+	outputs := newInOutManagerWithStates(inputs)
+
+	callCtx := execCtx.Clone().
+		SetVariablesManager(variables).
+		SetInputsManager(outputs)
+	callCtx.callStack = append(slices.Clone(execCtx.callStack), workflow.Id)
+
+	// 被调用工作流的自定义变量：与当前运行中同名的变量优先，其次为默认值
+	if err := engine.resolveVariables(execCtx.Context(), graph, variables); err != nil {
+		return nil, nil, err
+	}
+
+	// 被调用工作流的节点将作为当前运行的一部分执行，需追加调用节点的后缀，以免多次内联同一工作流时节点 ID 冲突
+	renameInlineBlocks(graph.Nodes, getInlineCallSuffix(execCtx.Node.Id))
+
+	// 被调用工作流的结束节点仅结束其自身，不影响当前运行
+	if err := engine.executeBlocks(callCtx, graph.Nodes); err != nil && !errors.Is(err, ErrTerminated) {
+		return nil, nil, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return variables, outputs, nil
+}
+
+// 子运行模式：为被调用工作流创建独立的运行记录，在当前节点内同步执行并等待其完成。
+// 子运行经由调度器排队并注册钩子，但不占用额外的工作槽位；子运行中的节点日志将同时转发至当前节点。
+func (ne *callWorkflowNodeExecutor) executeChild(execCtx *NodeExecutionContext, execRes *NodeExecutionResult, workflow *domain.Workflow, graph *Graph, inputs []InOutState) (VariableManager, InOutManager, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	ctx := execCtx.Context()
+
+	runTrigger := domain.WorkflowTriggerTypeManual
+	if state, ok := execCtx.variables.Get(stateVarKeyRunTrigger); ok {
+		runTrigger = domain.WorkflowTriggerType(state.ValueString())
+	}
+
+	versionId := ""
+	if version, err := ne.workflowVersionRepo.GetLatestByWorkflowId(ctx, workflow.Id); err == nil {
+		versionId = version.Id
+	} else if !domain.IsRecordNotFoundError(err) {
+		return nil, nil, fmt.Errorf("failed to get workflow version: %w", err)
+	}
+
+	workflowRun := &domain.WorkflowRun{
+		WorkflowId: workflow.Id,
+		Status:     domain.WorkflowRunStatusTypePending,
+		Trigger:    runTrigger,
+		StartedAt:  time.Now(),
+		Graph:      graph,
+		VersionId:  versionId,
+	}
+	if resp, err := ne.workflowRunRepo.SaveWithCascading(ctx, workflowRun); err != nil {
+		return nil, nil, fmt.Errorf("failed to save child workflow run record: %w", err)
+	} else {
+		workflowRun = resp
+	}
+
+	ne.logger.Info(fmt.Sprintf("run workflow #%s as child run #%s ...", workflow.Id, workflowRun.Id))
+	execRes.AddOutputWithPersistent(stateIOTypeRef, "run", fmt.Sprintf("%s#%s", domain.CollectionNameWorkflowRun, workflowRun.Id), stateValTypeString)
+
+	childEngine := NewWorkflowEngine().(*workflowEngine)
+	childEngine.SetChildRunDispatcher(engine.childRunDispatcher)
+	childEngine.OnNodeLogging(func(ctx context.Context, node *Node, log logging.Record) error {
+		record := log.Clone()
+		record.Message = fmt.Sprintf("[%s] %s", node.Data.Name, record.Message)
+		return ne.logger.Handler().Handle(ctx, record)
+	})
+
+	// 经由调度器排队，以遵循相同工作流同一时间只能有一个运行在执行的限制，并由调度器持久化子运行的日志及状态
+	if engine.childRunDispatcher != nil {
+		childCtx, release, err := engine.childRunDispatcher.DispatchChildRun(ctx, execCtx.RunId, workflowRun, childEngine)
+		if err != nil {
+			workflowRun.Status = domain.WorkflowRunStatusTypeCanceled
+			workflowRun.EndedAt = time.Now()
+			workflowRun.Error = err.Error()
+			if _, serr := ne.workflowRunRepo.SaveWithCascading(context.WithoutCancel(ctx), workflowRun); serr != nil {
+				ne.logger.Warn("could not update child workflow run record", slog.Any("error", serr))
+			}
+			return nil, nil, fmt.Errorf("could not dispatch child workflow run #%s: %w", workflowRun.Id, err)
+		}
+		defer release()
+
+		ctx = childCtx
+	} else {
+		childEngine.OnNodeError(func(ctx context.Context, node *Node, err error) error {
+			if !errors.Is(err, ErrBlocksException) {
+				workflowRun.ErrorNodeId = node.Id
+			}
+			return nil
+		})
+	}
+
+	workflowRun.Status = domain.WorkflowRunStatusTypeProcessing
+	if _, err := ne.workflowRunRepo.SaveWithCascading(ctx, workflowRun); err != nil {
+		ne.logger.Warn("could not update child workflow run record", slog.Any("error", err))
+	}
+
+	variables := newVariableManager()
+	outputs := newInOutManagerWithStates(inputs)
+	err := childEngine.invoke(ctx, WorkflowExecution{
+		WorkflowId:          workflow.Id,
+		WorkflowName:        workflow.Name,
+		WorkflowDescription: workflow.Description,
+		RunId:               workflowRun.Id,
+		RunTrigger:          workflowRun.Trigger,
+		RunAt:               workflowRun.StartedAt,
+		Graph:               graph,
+		callStack:           execCtx.callStack,
+	}, variables, outputs)
+
+	workflowRun.EndedAt = time.Now()
+	switch {
+	case err == nil:
+		workflowRun.Status = domain.WorkflowRunStatusTypeSucceeded
+	case errors.Is(err, context.Canceled):
+		workflowRun.Status = domain.WorkflowRunStatusTypeCanceled
+		workflowRun.Error = err.Error()
+	default:
+		workflowRun.Status = domain.WorkflowRunStatusTypeFailed
+		workflowRun.Error = err.Error()
+	}
+	if _, serr := ne.workflowRunRepo.SaveWithCascading(context.WithoutCancel(ctx), workflowRun); serr != nil {
+		ne.logger.Warn("could not update child workflow run record", slog.Any("error", serr))
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("child workflow run #%s failed: %w", workflowRun.Id, err)
+	}
+
+	return variables, outputs, nil
+}
+
+// 获取内联执行的节点 ID 的后缀。调用节点本身位于循环中时，其 ID 已含迭代后缀，因此各次迭代的后缀也互不相同。
+func getInlineCallSuffix(callNodeId string) string {
+	return domain.WorkflowNodeIterationSeparator + callNodeId
+}
+
+// 为内联执行的节点 ID 追加后缀。执行时仍会以原始节点 ID 登记其变量及输出，因此被调用工作流内部的引用不受影响。
+// ForEach 节点的子节点将在各次迭代时继承该节点的后缀，因此不再处理。
+func renameInlineBlocks(blocks []*Node, suffix string) {
+	for _, node := range blocks {
+		node.Id = node.Id + suffix
+		if node.Type != NodeTypeForEach {
+			renameInlineBlocks(node.Blocks, suffix)
+		}
+	}
+}
+
+// 被调用工作流中的系统变量不回传，以免覆盖当前运行的同名变量。
+func (ne *callWorkflowNodeExecutor) isReservedVariable(key string) bool {
+	switch key {
+	case stateVarKeyNodeId, stateVarKeyNodeName:
+		return true
+	}
+
+	return strings.HasPrefix(key, "workflow.") ||
+		strings.HasPrefix(key, "run.") ||
+		strings.HasPrefix(key, "error.") ||
+		strings.HasPrefix(key, "loop.")
+}
+
+func newCallWorkflowNodeExecutor() NodeExecutor {
+	return &callWorkflowNodeExecutor{
+		nodeExecutor:        nodeExecutor{logger: slog.Default()},
+		workflowRepo:        repository.NewWorkflowRepository(),
+		workflowRunRepo:     repository.NewWorkflowRunRepository(),
+		workflowVersionRepo: repository.NewWorkflowVersionRepository(),
+	}
+}
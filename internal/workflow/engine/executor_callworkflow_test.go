@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestRenameInlineBlocks(t *testing.T) {
+	newCalleeGraph := func() *Graph {
+		return &Graph{Nodes: []*Node{
+			{Id: "start", Type: NodeTypeStart},
+			{Id: "condition", Type: NodeTypeCondition, Blocks: []*Node{
+				{Id: "branch", Type: NodeTypeBranchBlock, Blocks: []*Node{
+					{Id: "apply", Type: NodeTypeBizApply},
+				}},
+			}},
+			{Id: "loop", Type: NodeTypeForEach, Blocks: []*Node{
+				{Id: "deploy", Type: NodeTypeBizDeploy},
+			}},
+			{Id: "end", Type: NodeTypeEnd},
+		}}
+	}
+
+	// 同一工作流被两个调用节点内联时，节点 ID 互不冲突
+	graph1, graph2 := newCalleeGraph(), newCalleeGraph()
+	renameInlineBlocks(graph1.Nodes, getInlineCallSuffix("call1"))
+	renameInlineBlocks(graph2.Nodes, getInlineCallSuffix("call2"))
+
+	ids1 := lo.Map(collectAllNodes(graph1.Nodes), func(node *Node, _ int) string { return node.Id })
+	ids2 := lo.Map(collectAllNodes(graph2.Nodes), func(node *Node, _ int) string { return node.Id })
+	assert.Equal(t, []string{"start#call1", "condition#call1", "branch#call1", "apply#call1", "loop#call1", "deploy", "end#call1"}, ids1)
+	for _, id := range ids1 {
+		if id != "deploy" {
+			assert.NotContains(t, ids2, id)
+		}
+	}
+
+	// 原始节点 ID 仍可用于登记变量及输出
+	assert.Equal(t, "apply", domain.GetWorkflowTemplateNodeId(ids1[3]))
+
+	// ForEach 节点的子节点在迭代时继承其后缀
+	loopNode := graph1.Nodes[2]
+	blocks := copyLoopBlocks(loopNode.Blocks, getLoopIterationSuffix(loopNode.Id, 0))
+	assert.Equal(t, "deploy#call1#0", blocks[0].Id)
+	assert.Equal(t, "deploy", domain.GetWorkflowTemplateNodeId(blocks[0].Id))
+
+	// 调用节点本身位于循环中时，各次迭代的后缀互不相同
+	assert.NotEqual(t, getInlineCallSuffix("call1#0"), getInlineCallSuffix("call1#1"))
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 
 	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type conditionNodeExecutor struct {
@@ -22,8 +24,13 @@ func (ne *conditionNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 
 	execRes := newNodeExecutionResult(execCtx.Node)
 
-	errs := make([]error, 0)
+	nodeCfg := execCtx.Node.Data.Config.AsCondition()
 	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeBranchBlock })
+	if nodeCfg.Mode == domain.WorkflowNodeConditionModeExclusive {
+		return ne.executeExclusive(execCtx, engine, blocks)
+	}
+
+	errs := make([]error, 0)
 	for _, node := range blocks {
 		ctx := execCtx.Context()
 		select {
@@ -48,6 +55,54 @@ func (ne *conditionNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeEx
 	return execRes, nil
 }
 
+func (ne *conditionNodeExecutor) executeExclusive(execCtx *NodeExecutionContext, engine *workflowEngine, blocks []*Node) (*NodeExecutionResult, error) {
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	// 仅进入第一个满足条件的分支，均不满足时进入默认分支
+	var matchedBranch, defaultBranch *Node
+	for _, node := range blocks {
+		if node.Data.Disabled {
+			continue
+		}
+
+		branchCfg := node.Data.Config.AsBranchBlock()
+		if branchCfg.IsDefault {
+			if defaultBranch == nil {
+				defaultBranch = node
+			}
+			continue
+		}
+
+		matched, err := evalBranchExpression(&execCtx.WorkflowContext, branchCfg)
+		if err != nil {
+			ne.logger.Warn(fmt.Sprintf("failed to eval expr of branch #%s: %+v", node.Id, err))
+			return execRes, err
+		} else if matched {
+			matchedBranch = node
+			break
+		}
+	}
+
+	if matchedBranch == nil {
+		if defaultBranch == nil {
+			ne.logger.Info("skip all branches, because no conditions met")
+			return execRes, nil
+		}
+
+		ne.logger.Info("enter the default branch, because no conditions met")
+		matchedBranch = defaultBranch
+	}
+
+	if err := engine.executeNode(execCtx.Clone(), matchedBranch); err != nil {
+		if errors.Is(err, ErrTerminated) {
+			return execRes, err
+		}
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
 func newConditionNodeExecutor() NodeExecutor {
 	return &conditionNodeExecutor{
 		nodeExecutor: nodeExecutor{logger: slog.Default()},
@@ -62,26 +117,16 @@ func (ne *branchBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*Node
 	execRes := newNodeExecutionResult(execCtx.Node)
 
 	nodeCfg := execCtx.Node.Data.Config.AsBranchBlock()
-	if nodeCfg.Expression == nil {
+	if nodeCfg.Expression == nil && nodeCfg.ExpressionText == "" {
 		ne.logger.Info("enter this branch without any conditions")
 	} else {
-		variables := lo.Reduce(execCtx.variables.All(), func(acc map[string]map[string]any, state VariableState, _ int) map[string]map[string]any {
-			if _, ok := acc[state.Scope]; !ok {
-				acc[state.Scope] = make(map[string]any)
-			}
-
-			// 这里需要把所有值都转换为字符串形式，因为 Expression.Eval 仅支持字符串类型的值
-			acc[state.Scope][state.Key] = state.ValueString()
-			return acc
-		}, make(map[string]map[string]any))
-
-		rs, err := nodeCfg.Expression.Eval(variables)
+		matched, err := evalBranchExpression(&execCtx.WorkflowContext, nodeCfg)
 		if err != nil {
 			ne.logger.Warn(fmt.Sprintf("failed to eval expr: %+v", err))
 			return execRes, err
 		}
 
-		if rs.Value == false {
+		if !matched {
 			ne.logger.Info("skip this branch, because condition not met")
 			return execRes, nil
 		} else {
@@ -105,3 +150,29 @@ func newBranchBlockNodeExecutor() NodeExecutor {
 		nodeExecutor: nodeExecutor{logger: slog.Default()},
 	}
 }
+
+func evalBranchExpression(wfCtx *WorkflowContext, branchCfg domain.WorkflowNodeConfigForBranchBlock) (bool, error) {
+	expression, err := branchCfg.ResolveExpression()
+	if err != nil {
+		return false, err
+	} else if expression == nil {
+		return true, nil
+	}
+
+	// 保留变量值的原始类型，以便表达式中进行日期时间运算等操作
+	variables := lo.Reduce(wfCtx.variables.All(), func(acc map[string]map[string]any, state VariableState, _ int) map[string]map[string]any {
+		if _, ok := acc[state.Scope]; !ok {
+			acc[state.Scope] = make(map[string]any)
+		}
+
+		acc[state.Scope][state.Key] = state.Value
+		return acc
+	}, make(map[string]map[string]any))
+
+	rs, err := expression.Eval(variables)
+	if err != nil {
+		return false, err
+	}
+
+	return rs.Value != false, nil
+}
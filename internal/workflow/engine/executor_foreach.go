@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+type forEachNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *forEachNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	items, err := ne.resolveItems(execCtx)
+	if err != nil {
+		ne.logger.Warn(fmt.Sprintf("failed to resolve loop items: %+v", err))
+		return execRes, err
+	} else if len(items) == 0 {
+		ne.logger.Info("skip this loop, because there are no items")
+		return execRes, nil
+	}
+
+	ne.logger.Info(fmt.Sprintf("ready to iterate over %d item(s) ...", len(items)))
+
+	// 嵌套循环时，内层循环会覆盖外层循环的变量，因此需要在结束后还原
+	prevItem, hasPrevItem := execCtx.variables.Take(stateVarKeyLoopItem)
+	prevIndex, hasPrevIndex := execCtx.variables.Take(stateVarKeyLoopIndex)
+	defer func() {
+		execCtx.variables.Remove(stateVarKeyLoopItem)
+		execCtx.variables.Remove(stateVarKeyLoopIndex)
+		if hasPrevItem {
+			execCtx.variables.Add(*prevItem)
+		}
+		if hasPrevIndex {
+			execCtx.variables.Add(*prevIndex)
+		}
+	}()
+
+	for i, item := range items {
+		ctx := execCtx.Context()
+		select {
+		case <-ctx.Done():
+			return execRes, ctx.Err()
+		default:
+		}
+
+		ne.logger.Info(fmt.Sprintf("iteration #%d: %s", i, item))
+
+		execCtx.variables.Set(stateVarKeyLoopItem, item, stateValTypeString)
+		execCtx.variables.Set(stateVarKeyLoopIndex, i, stateValTypeNumber)
+
+		blocks := copyLoopBlocks(execCtx.Node.Blocks, getLoopIterationSuffix(execCtx.Node.Id, i))
+		if err := engine.executeBlocks(execCtx.Clone(), blocks); err != nil {
+			return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+		}
+	}
+
+	return execRes, nil
+}
+
+func (ne *forEachNodeExecutor) resolveItems(execCtx *NodeExecutionContext) ([]string, error) {
+	nodeCfg := execCtx.Node.Data.Config.AsForEach()
+
+	items := nodeCfg.Items
+	if nodeCfg.ItemsVariable != "" {
+		state, ok := execCtx.variables.GetScoped(nodeCfg.ItemsVariableNodeId, nodeCfg.ItemsVariable)
+		if !ok {
+			if nodeCfg.ItemsVariableNodeId != "" {
+				return nil, fmt.Errorf("variable '%s' of node #%s not found", nodeCfg.ItemsVariable, nodeCfg.ItemsVariableNodeId)
+			}
+			return nil, fmt.Errorf("variable '%s' not found", nodeCfg.ItemsVariable)
+		}
+
+		items = strings.Split(state.ValueString(), nodeCfg.ItemsSeparator)
+	}
+
+	items = lo.Map(items, func(s string, _ int) string { return strings.TrimSpace(s) })
+	items = lo.Filter(items, func(s string, _ int) bool { return s != "" })
+	return items, nil
+}
+
+func newForEachNodeExecutor() NodeExecutor {
+	return &forEachNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+// 获取循环中复制的子节点 ID 的后缀。嵌套循环时会保留外层循环的迭代序号，以确保节点 ID 唯一。
+func getLoopIterationSuffix(loopNodeId string, index int) string {
+	suffix := strings.TrimPrefix(loopNodeId, domain.GetWorkflowTemplateNodeId(loopNodeId))
+	return suffix + domain.WorkflowNodeIterationSeparator + strconv.Itoa(index)
+}
+
+// 复制子节点，复制的节点 ID 会追加迭代后缀，以免各次迭代的输出及跳过判断相互干扰。
+// 嵌套的 ForEach 节点仅复制其自身，子节点留待内层循环处理。
+//
+// 节点配置不做任何替换，其中的 "{{ $loop.item }}"、"{{ $loop.index }}" 在节点执行时由模板引擎解析，
+// 参见 [renderTemplateConfig]，以便 Shell 命令等字段中的循环变量同样经过转义。
+func copyLoopBlocks(blocks []*Node, suffix string) []*Node {
+	return lo.Map(blocks, func(node *Node, _ int) *Node {
+		copied := &Node{
+			Id:     node.Id + suffix,
+			Type:   node.Type,
+			Data:   node.Data,
+			Blocks: node.Blocks,
+		}
+		if node.Type != NodeTypeForEach && len(node.Blocks) > 0 {
+			copied.Blocks = copyLoopBlocks(node.Blocks, suffix)
+		}
+		return copied
+	})
+}
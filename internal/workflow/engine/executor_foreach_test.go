@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestCopyLoopBlocks(t *testing.T) {
+	blocks := []*Node{
+		{Id: "apply", Type: NodeTypeBizApply, Data: domain.WorkflowNodeData{
+			Name:   "apply {{ $loop.item }}",
+			Config: domain.WorkflowNodeConfig{"domains": "{{ $loop.item }}"},
+		}},
+		{Id: "cond", Type: NodeTypeCondition, Blocks: []*Node{
+			{Id: "branch", Type: NodeTypeBranchBlock, Blocks: []*Node{
+				{Id: "deploy", Type: NodeTypeBizDeploy, Data: domain.WorkflowNodeData{
+					Config: domain.WorkflowNodeConfig{"certificateOutputNodeId": "apply"},
+				}},
+			}},
+		}},
+		{Id: "inner", Type: NodeTypeForEach, Blocks: []*Node{
+			{Id: "notify", Type: NodeTypeBizNotify},
+		}},
+	}
+
+	rendered := copyLoopBlocks(blocks, getLoopIterationSuffix("loop", 2))
+	assert.Equal(t, "apply#2", rendered[0].Id)
+	assert.Equal(t, "apply {{ $loop.item }}", rendered[0].Data.Name)
+	assert.Equal(t, "{{ $loop.item }}", rendered[0].Data.Config["domains"], "loop variables are left to the template engine")
+	assert.Equal(t, "cond#2", rendered[1].Id)
+	assert.Equal(t, "branch#2", rendered[1].Blocks[0].Id)
+	assert.Equal(t, "deploy#2", rendered[1].Blocks[0].Blocks[0].Id)
+	assert.Equal(t, "inner#2", rendered[2].Id)
+	assert.Equal(t, "notify", rendered[2].Blocks[0].Id, "children of nested loops are left to the inner loop")
+
+	// 模板节点不应被修改
+	assert.Equal(t, "apply", blocks[0].Id)
+	assert.Equal(t, "{{ $loop.item }}", blocks[0].Data.Config["domains"])
+
+	// 内层循环的迭代节点 ID 保留外层循环的迭代序号
+	nested := copyLoopBlocks(rendered[2].Blocks, getLoopIterationSuffix(rendered[2].Id, 0))
+	assert.Equal(t, "notify#2#0", nested[0].Id)
+}
+
+func TestRenderTemplateConfig_LoopVariables(t *testing.T) {
+	execCtx := newMockNodeExecutionContext(&Node{Id: "deploy#0", Type: NodeTypeBizDeploy})
+	execCtx.variables.Set(stateVarKeyLoopItem, "example.com; rm -rf /", stateValTypeString)
+	execCtx.variables.Set(stateVarKeyLoopIndex, 0, stateValTypeNumber)
+
+	rendered, err := renderTemplateConfig(execCtx, map[string]any{
+		"domains":     "{{ $loop.item }}",
+		"postCommand": "echo {{ $loop.index }} {{ $loop.item }}",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com; rm -rf /", rendered["domains"])
+	assert.Equal(t, `echo '0' 'example.com; rm -rf /'`, rendered["postCommand"], "loop variables in commands should be shell-quoted")
+}
+
+func TestExecuteNode_LoopIterationAliases(t *testing.T) {
+	we := newMockEngine()
+	we.executors[NodeTypeBizApply] = func() NodeExecutor {
+		return &mockNodeExecutor{
+			execute: func(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+				execRes := newNodeExecutionResult(execCtx.Node)
+				execRes.AddOutput(stateIOTypeRef, "certificate", execCtx.Node.Id, stateValTypeString)
+				return execRes, nil
+			},
+		}
+	}
+
+	node := &Node{Id: "apply#1", Type: NodeTypeBizApply}
+	execCtx := newMockNodeExecutionContext(node)
+	execCtx.SetDryRun(true)
+	assert.NoError(t, we.executeNode(&execCtx.WorkflowContext, node))
+
+	output, ok := execCtx.inputs.Get("apply#1", "certificate")
+	if assert.True(t, ok) {
+		assert.Equal(t, "apply#1", output.Value)
+	}
+	output, ok = execCtx.inputs.Get("apply", "certificate")
+	if assert.True(t, ok) {
+		assert.Equal(t, "apply#1", output.Value)
+	}
+	_, ok = execCtx.variables.GetScoped("apply", stateVarKeyNodeId)
+	assert.True(t, ok)
+}
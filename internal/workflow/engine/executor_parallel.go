@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+type parallelNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	nodeCfg := execCtx.Node.Data.Config.AsParallel()
+	blocks := lo.Filter(execCtx.Node.Blocks, func(n *Node, _ int) bool { return n.Type == NodeTypeParallelBlock })
+	if len(blocks) == 0 {
+		return execRes, nil
+	}
+
+	concurrency := nodeCfg.MaxConcurrency
+	if concurrency <= 0 || concurrency > len(blocks) {
+		concurrency = len(blocks)
+	}
+	ne.logger.Info(fmt.Sprintf("run %d block(s) in parallel with a maximum concurrency of %d ...", len(blocks), concurrency))
+
+	// 每个分支都持有一份独立的状态副本，互不干扰；
+	// 全部分支执行完毕后，再按分支的声明顺序依次合并回工作流上下文，以保证结果是确定的。
+	type blockResult struct {
+		variables VariableManager
+		inputs    InOutManager
+		err       error
+	}
+
+	baseVariables := execCtx.variables.All()
+	baseInputs := execCtx.inputs.All()
+	results := make([]*blockResult, len(blocks))
+
+	ctx := execCtx.Context()
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i, node := range blocks {
+		result := &blockResult{
+			variables: newVariableManagerWithStates(baseVariables),
+			inputs:    newInOutManagerWithStates(baseInputs),
+		}
+		results[i] = result
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				result.err = ctx.Err()
+				return
+			}
+
+			defer func() {
+				if r := recover(); r != nil {
+					result.err = fmt.Errorf("workflow engine: panic in parallel block #%s: %v", node.Id, r)
+				}
+			}()
+
+			blockCtx := execCtx.Clone().
+				SetVariablesManager(result.variables).
+				SetInputsManager(result.inputs)
+			result.err = engine.executeNode(blockCtx, node)
+		}()
+	}
+	wg.Wait()
+
+	errs := make([]error, 0)
+	terminated := false
+	for _, result := range results {
+		for _, state := range diffVariableStates(baseVariables, result.variables.All()) {
+			execCtx.variables.Add(state)
+		}
+		for _, state := range diffInOutStates(baseInputs, result.inputs.All()) {
+			execCtx.inputs.Add(state)
+		}
+
+		if result.err != nil {
+			if errors.Is(result.err, ErrTerminated) {
+				terminated = true
+				continue
+			}
+			errs = append(errs, result.err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return execRes, err
+	}
+
+	if len(errs) > 0 {
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, errors.Join(errs...))
+	}
+
+	if terminated {
+		return execRes, ErrTerminated
+	}
+
+	return execRes, nil
+}
+
+func newParallelNodeExecutor() NodeExecutor {
+	return &parallelNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
+
+type parallelBlockNodeExecutor struct {
+	nodeExecutor
+}
+
+func (ne *parallelBlockNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecutionResult, error) {
+	var engine *workflowEngine
+	if we, ok := execCtx.engine.(*workflowEngine); !ok {
+		panic("unreachable")
+	} else {
+		engine = we
+	}
+
+	execRes := newNodeExecutionResult(execCtx.Node)
+
+	if err := engine.executeBlocks(execCtx.Clone(), execCtx.Node.Blocks); err != nil {
+		return execRes, fmt.Errorf("%w: %w", ErrBlocksException, err)
+	}
+
+	return execRes, nil
+}
+
+func newParallelBlockNodeExecutor() NodeExecutor {
+	return &parallelBlockNodeExecutor{
+		nodeExecutor: nodeExecutor{logger: slog.Default()},
+	}
+}
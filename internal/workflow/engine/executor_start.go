@@ -1,9 +1,17 @@
 package engine
 
 import (
+	"fmt"
 	"log/slog"
+
+	"github.com/certimate-go/certimate/internal/domain"
 )
 
+/**
+ * Outputs:
+ *   - ref: "certificate": string（仅由证书事件触发时）
+ *   - ref: "run": string（仅由工作流运行事件触发时）
+ */
 type startNodeExecutor struct {
 	nodeExecutor
 }
@@ -13,6 +21,14 @@ func (ne *startNodeExecutor) Execute(execCtx *NodeExecutionContext) (*NodeExecut
 
 	ne.logger.Info("the workflow is starting")
 
+	// 由事件触发时，将触发事件的实体作为本节点的输出，以便后续节点引用
+	if state, ok := execCtx.variables.Get("trigger.certificate.id"); ok && state.ValueString() != "" {
+		execRes.AddOutputWithPersistent(stateIOTypeRef, "certificate", fmt.Sprintf("%s#%s", domain.CollectionNameCertificate, state.ValueString()), stateValTypeString)
+	}
+	if state, ok := execCtx.variables.Get("trigger.run.id"); ok && state.ValueString() != "" {
+		execRes.AddOutputWithPersistent(stateIOTypeRef, "run", fmt.Sprintf("%s#%s", domain.CollectionNameWorkflowRun, state.ValueString()), stateValTypeString)
+	}
+
 	return execRes, nil
 }
 
@@ -2,10 +2,13 @@ package engine
 
 import (
 	"fmt"
+	"reflect"
 	"slices"
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/samber/lo"
 )
 
 type VariableState struct {
@@ -29,6 +32,8 @@ func (s VariableState) ValueString() string {
 			return "-"
 		}
 		return valueAsTime.Format(time.RFC3339)
+	case stateValTypeSecret:
+		return secretMask
 	default:
 		return fmt.Sprintf("[%s]%v", s.ValueType, s.Value)
 	}
@@ -155,6 +160,20 @@ func newVariableManager() VariableManager {
 	}
 }
 
+func newVariableManagerWithStates(states []VariableState) VariableManager {
+	return &variableManager{
+		states: slices.Clone(states),
+	}
+}
+
+// 计算相对于基准状态新增或变更的变量状态，结果按 changed 中的原有顺序排列。
+func diffVariableStates(base []VariableState, changed []VariableState) []VariableState {
+	return lo.Filter(changed, func(state VariableState, _ int) bool {
+		prev, ok := lo.Find(base, func(item VariableState) bool { return item.Scope == state.Scope && item.Key == state.Key })
+		return !ok || !reflect.DeepEqual(prev, state)
+	})
+}
+
 type InOutState struct {
 	NodeId     string
 	Type       string
@@ -278,11 +297,45 @@ func newInOutManager() InOutManager {
 	}
 }
 
+func newInOutManagerWithStates(states []InOutState) InOutManager {
+	return &inoutManager{
+		states: slices.Clone(states),
+	}
+}
+
+// 计算相对于基准状态新增或变更的输入输出状态，结果按 changed 中的原有顺序排列。
+func diffInOutStates(base []InOutState, changed []InOutState) []InOutState {
+	return lo.Filter(changed, func(state InOutState, _ int) bool {
+		prev, ok := lo.Find(base, func(item InOutState) bool { return item.NodeId == state.NodeId && item.Name == state.Name })
+		return !ok || !reflect.DeepEqual(prev, state)
+	})
+}
+
+// 将持久化的字符串形式的值按值类型还原。
+func parseStateValue(value string, valueType string) any {
+	switch valueType {
+	case stateValTypeNumber:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		return 0
+	case stateValTypeBoolean:
+		b, _ := strconv.ParseBool(value)
+		return b
+	case stateValTypeDateTime:
+		t, _ := time.Parse(time.RFC3339, value)
+		return t
+	default:
+		return value
+	}
+}
+
 const (
 	stateValTypeBoolean  = "boolean"
 	stateValTypeDateTime = "datetime"
 	stateValTypeNumber   = "number"
 	stateValTypeString   = "string"
+	stateValTypeSecret   = "secret" // 密钥，值为字符串，不会被持久化
 )
 
 const (
@@ -301,6 +354,8 @@ const (
 	stateVarKeyErrorNodeId                = "error.nodeId"                // ValueType: "string"
 	stateVarKeyErrorNodeName              = "error.nodeName"              // ValueType: "string"
 	stateVarKeyErrorMessage               = "error.message"               // ValueType: "string"
+	stateVarKeyLoopItem                   = "loop.item"                   // ValueType: "string"
+	stateVarKeyLoopIndex                  = "loop.index"                  // ValueType: "number"
 	stateVarKeyCertificateDomain          = "certificate.domain"          // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateCommonName]
 	stateVarKeyCertificateDomains         = "certificate.domains"         // 已废弃，仅为兼容旧版而保留，请使用 [stateVarKeyCertificateSubjectAltNames]
 	stateVarKeyCertificateCommonName      = "certificate.commonName"      // ValueType: "string"
@@ -310,4 +365,10 @@ const (
 	stateVarKeyCertificateHoursLeft       = "certificate.hoursLeft"       // ValueType: "number"
 	stateVarKeyCertificateDaysLeft        = "certificate.daysLeft"        // ValueType: "number"
 	stateVarKeyCertificateValidity        = "certificate.validity"        // ValueType: "boolean"
+	stateVarKeyChallengeRecords           = "challenge.records"           // ValueType: "string"
+	stateVarKeyDeploymentValidated        = "deployment.validated"        // ValueType: "boolean"
+	stateVarKeyApprovalApproved           = "approval.approved"           // ValueType: "boolean"
+	stateVarKeyApprovalOperator           = "approval.operator"           // ValueType: "string"
+	stateVarKeyApprovalComment            = "approval.comment"            // ValueType: "string"
+	stateVarKeyApprovalDecidedAt          = "approval.decidedAt"          // ValueType: "datetime"
 )
@@ -0,0 +1,511 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// 模板渲染结果的最大长度，超出时视为渲染失败。
+	templateMaxOutputSize = 1 << 20
+	// 模板中单个 range 动作的最大迭代次数，超出时视为渲染失败。
+	templateMaxRangeCount = 10000
+	// 模板渲染的最长时间，超出时视为渲染失败。
+	templateExecutionTimeout = 5 * time.Second
+)
+
+// 旧版占位符，形如 "{{ $key }}"。
+var reTemplateLegacyMustache = regexp.MustCompile(`\{\{\s*\$([^\s{}]+)\s*\}\}`)
+
+/**
+ * 渲染模板。
+ *
+ * 模板基于 Go 的 text/template 语法，支持 if、range、with 等控制结构。
+ * 模板仅能调用 [templateFuncs] 中提供的函数，无法访问文件系统、环境变量或网络。
+ *
+ * 模板的根数据包含：
+ *   - .vars：全局变量，以变量名为键；
+ *   - .nodes：各节点的作用域变量及输出，以节点 ID 为键，形如 .nodes.{nodeId}.vars 及 .nodes.{nodeId}.outputs。
+ *
+ * 由于变量名中通常含有 "."，推荐使用 var、output 函数读取，例如：
+ *   - {{ var "certificate.commonName" "nodeId" | default "-" }}
+ *   - {{ var "certificate.notAfter" "nodeId" | date "2006-01-02" }}
+ *   - {{ range split ";" (var "certificate.subjectAltNames" "nodeId") }}{{ . }}{{ end }}
+ *
+ * 为兼容旧版，形如 "{{ $key }}" 的占位符仍会被替换为对应的全局变量值。
+ */
+func renderTemplate(execCtx *NodeExecutionContext, text string) (string, error) {
+	return renderTemplateWithEscaper(execCtx, text, "")
+}
+
+// 渲染将作为 Shell 命令执行的模板。
+// 变量值可能来自 Webhook 请求等不可信的来源，因此每个输出值都会自动经过 shellquote 转义，
+// 以避免命令注入。如需拼接命令片段，应在工作流中定义可信的变量，而非依赖模板输出。
+func renderShellTemplate(execCtx *NodeExecutionContext, text string) (string, error) {
+	return renderTemplateWithEscaper(execCtx, text, "shellquote")
+}
+
+func renderTemplateWithEscaper(execCtx *NodeExecutionContext, text string, escaper string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("").
+		Option("missingkey=error").
+		Funcs(newTemplateFuncs(execCtx)).
+		Parse(convertLegacyTemplate(execCtx, text))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	// 含 define 定义的关联模板，以免通过 template 动作绕过转义及限制
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+
+		if escaper != "" {
+			escapeTemplateNode(t.Tree.Root, escaper)
+		}
+		limitTemplateRangeNode(t.Tree.Root)
+	}
+
+	ctx := execCtx.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, templateExecutionTimeout)
+	defer cancel()
+
+	// text/template 不支持中途取消，因此在协程中执行，超时后不再等待其结果；
+	// 此后模板一旦产生输出便会因上下文已取消而中止
+	var buf strings.Builder
+	data := newTemplateData(execCtx)
+	done := make(chan error, 1)
+	go func() {
+		done <- tmpl.Execute(&templateLimitedWriter{ctx: ctx, w: &buf, n: templateMaxOutputSize}, data)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("failed to render template: %w", err)
+		}
+	case <-ctx.Done():
+		return "", fmt.Errorf("failed to render template: %w", ctx.Err())
+	}
+
+	return buf.String(), nil
+}
+
+// 渲染配置中的全部字符串字段（含嵌套的映射及列表），返回新的配置，原配置不会被修改。
+// 键名以 "command" 结尾（不区分大小写）的字段将作为 Shell 命令渲染，参见 [renderShellTemplate]。
+// 任一字段渲染失败时返回错误；如需输出字面量 "{{"，可写作 `{{ "{{" }}`。升级至 v0.5.0 时，旧版配置中的字面量 "{{" 已按此方式转义。
+func renderTemplateConfig(execCtx *NodeExecutionContext, config map[string]any) (map[string]any, error) {
+	var render func(key string, name string, value any) (any, error)
+	render = func(key string, name string, value any) (any, error) {
+		switch v := value.(type) {
+		case string:
+			renderFn := renderTemplate
+			if strings.HasSuffix(strings.ToLower(name), "command") {
+				renderFn = renderShellTemplate
+			}
+
+			rendered, err := renderFn(execCtx, v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render config '%s': %w", key, err)
+			}
+			return rendered, nil
+
+		case map[string]any:
+			m := make(map[string]any, len(v))
+			for k, item := range v {
+				rendered, err := render(strings.TrimPrefix(key+"."+k, "."), k, item)
+				if err != nil {
+					return nil, err
+				}
+				m[k] = rendered
+			}
+			return m, nil
+
+		case []any:
+			l := make([]any, len(v))
+			for i, item := range v {
+				rendered, err := render(fmt.Sprintf("%s[%d]", key, i), name, item)
+				if err != nil {
+					return nil, err
+				}
+				l[i] = rendered
+			}
+			return l, nil
+
+		default:
+			return value, nil
+		}
+	}
+
+	if config == nil {
+		return nil, nil
+	}
+
+	rendered, err := render("", "", config)
+	if err != nil {
+		return nil, err
+	}
+	return rendered.(map[string]any), nil
+}
+
+// 在模板中每个产生输出的动作末尾追加转义函数，与 html/template 的自动转义方式类似。
+// 模板中的字符串字面量由工作流作者编写，视为可信而不做转义；已显式以该函数结尾的动作不会被重复转义。
+func escapeTemplateNode(node parse.Node, escaper string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeTemplateNode(child, escaper)
+		}
+
+	case *parse.ActionNode:
+		// 变量声明及赋值不产生输出
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+
+		cmds := n.Pipe.Cmds
+		if len(cmds) == 1 && len(cmds[0].Args) == 1 {
+			if _, ok := cmds[0].Args[0].(*parse.StringNode); ok {
+				return
+			}
+		}
+		if len(cmds) > 0 {
+			if ident, ok := cmds[len(cmds)-1].Args[0].(*parse.IdentifierNode); ok && ident.Ident == escaper {
+				return
+			}
+		}
+		n.Pipe.Cmds = append(cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escaper).SetPos(n.Pos)},
+		})
+
+	case *parse.IfNode:
+		escapeTemplateNode(n.List, escaper)
+		escapeTemplateNode(n.ElseList, escaper)
+
+	case *parse.RangeNode:
+		escapeTemplateNode(n.List, escaper)
+		escapeTemplateNode(n.ElseList, escaper)
+
+	case *parse.WithNode:
+		escapeTemplateNode(n.List, escaper)
+		escapeTemplateNode(n.ElseList, escaper)
+	}
+}
+
+// 在模板中每个 range 动作的末尾追加 limitRange 函数，以限制其迭代次数，避免长时间占用执行器。
+func limitTemplateRangeNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			limitTemplateRangeNode(child)
+		}
+
+	case *parse.IfNode:
+		limitTemplateRangeNode(n.List)
+		limitTemplateRangeNode(n.ElseList)
+
+	case *parse.RangeNode:
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("limitRange").SetPos(n.Pos)},
+		})
+		limitTemplateRangeNode(n.List)
+		limitTemplateRangeNode(n.ElseList)
+
+	case *parse.WithNode:
+		limitTemplateRangeNode(n.List)
+		limitTemplateRangeNode(n.ElseList)
+	}
+}
+
+// 将旧版占位符 "{{ $key }}" 转换为等价的 "{{ var "key" | str }}"。
+// 若变量不存在，则原样输出占位符，与旧版行为一致；若该名称已在模板中声明为模板变量，则不做转换。
+func convertLegacyTemplate(execCtx *NodeExecutionContext, text string) string {
+	return reTemplateLegacyMustache.ReplaceAllStringFunc(text, func(match string) string {
+		key := reTemplateLegacyMustache.FindStringSubmatch(match)[1]
+
+		name, _, _ := strings.Cut(key, ".")
+		if regexp.MustCompile(`\$` + regexp.QuoteMeta(name) + `\s*(,|:?=)`).MatchString(text) {
+			return match
+		}
+
+		if key == "now" {
+			return `{{ now | date "RFC3339" }}`
+		} else if _, ok := execCtx.variables.Get(key); ok {
+			return fmt.Sprintf(`{{ var %s | str }}`, strconv.Quote(key))
+		}
+
+		return fmt.Sprintf(`{{ %s }}`, strconv.Quote(match))
+	})
+}
+
+func newTemplateData(execCtx *NodeExecutionContext) map[string]any {
+	vars := make(map[string]any)
+	nodes := make(map[string]any)
+	ensureNode := func(nodeId string) map[string]any {
+		if node, ok := nodes[nodeId]; ok {
+			return node.(map[string]any)
+		}
+
+		node := map[string]any{"vars": make(map[string]any), "outputs": make(map[string]any)}
+		nodes[nodeId] = node
+		return node
+	}
+
+	for _, state := range execCtx.variables.All() {
+		if state.Scope == "" {
+			vars[state.Key] = state.Value
+		} else {
+			ensureNode(state.Scope)["vars"].(map[string]any)[state.Key] = state.Value
+		}
+	}
+
+	for _, state := range execCtx.inputs.All() {
+		ensureNode(state.NodeId)["outputs"].(map[string]any)[state.Name] = state.Value
+	}
+
+	return map[string]any{
+		"vars":  vars,
+		"nodes": nodes,
+	}
+}
+
+func newTemplateFuncs(execCtx *NodeExecutionContext) template.FuncMap {
+	return template.FuncMap{
+		// var "key" 读取全局变量；var "key" "nodeId" 读取节点的作用域变量。变量不存在时返回空值
+		"var": func(key string, scope ...string) (any, error) {
+			if len(scope) > 1 {
+				return nil, errors.New("var: too many arguments")
+			}
+
+			var state *VariableState
+			var ok bool
+			if len(scope) == 0 || scope[0] == "" {
+				state, ok = execCtx.variables.Get(key)
+			} else {
+				state, ok = execCtx.variables.GetScoped(scope[0], key)
+			}
+			if !ok {
+				return nil, nil
+			}
+			return state.Value, nil
+		},
+
+		// output "nodeId" "name" 读取节点的输出。输出不存在时返回空值
+		"output": func(nodeId string, name string) any {
+			if state, ok := execCtx.inputs.Get(nodeId, name); ok {
+				return state.Value
+			}
+			return nil
+		},
+
+		// now 返回当前时间
+		"now": func() time.Time {
+			return time.Now()
+		},
+
+		// date "layout" value 格式化日期时间。layout 可以是 Go 的时间格式，也可以是 "RFC3339"、"DateTime"、"DateOnly" 等预置格式名；
+		// value 可以是日期时间、RFC 3339 或 "2006-01-02" 格式的字符串，或以秒为单位的 Unix 时间戳
+		"date": func(layout string, value any) (string, error) {
+			t, err := templateToTime(value)
+			if err != nil {
+				return "", err
+			} else if t.IsZero() {
+				return "", nil
+			}
+
+			switch layout {
+			case "", "RFC3339":
+				layout = time.RFC3339
+			case "RFC1123":
+				layout = time.RFC1123
+			case "DateTime":
+				layout = time.DateTime
+			case "DateOnly":
+				layout = time.DateOnly
+			case "TimeOnly":
+				layout = time.TimeOnly
+			}
+			return t.Format(layout), nil
+		},
+
+		// default "fallback" value 当值为空值、零值、空字符串或空集合时返回 fallback
+		"default": func(fallback any, value any) any {
+			if templateIsEmpty(value) {
+				return fallback
+			}
+			return value
+		},
+
+		// str value 将值转换为字符串，日期时间将以 RFC 3339 格式输出
+		"str":       templateToString,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"contains":  func(substr string, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix string, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix": func(suffix string, s string) bool { return strings.HasSuffix(s, suffix) },
+		"replace":   func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+		"join": func(sep string, list []any) string {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = templateToString(item)
+			}
+			return strings.Join(items, sep)
+		},
+		"split": func(sep string, s any) []any {
+			items := make([]any, 0)
+			for _, item := range strings.Split(templateToString(s), sep) {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items
+		},
+		"toJson": func(value any) (string, error) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+
+		// shellquote value 将值转义为单个 POSIX Shell 参数
+		"shellquote": func(value any) string {
+			return templateShellQuote(templateToString(value))
+		},
+
+		// limitRange value 校验 range 的迭代次数，渲染时会自动追加到每个 range 动作的末尾
+		"limitRange": func(value any) (any, error) {
+			count := 0
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				count = int(min(rv.Int(), templateMaxRangeCount+1))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				count = int(min(rv.Uint(), templateMaxRangeCount+1))
+			case reflect.Array, reflect.Map, reflect.Slice:
+				count = rv.Len()
+			}
+			if count > templateMaxRangeCount {
+				return nil, fmt.Errorf("range: too many iterations, the limit is %d", templateMaxRangeCount)
+			}
+			return value, nil
+		},
+	}
+}
+
+func templateShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func templateToString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return "-"
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func templateToTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, nil
+		}
+		return *v, nil
+	case string:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("date: invalid datetime: %s", v)
+	case int:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case float64:
+		return time.Unix(int64(v), 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("date: unsupported value type: %T", value)
+	}
+}
+
+func templateIsEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+	if t, ok := value.(time.Time); ok {
+		return t.IsZero()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+type templateLimitedWriter struct {
+	ctx context.Context
+	w   *strings.Builder
+	n   int
+}
+
+func (lw *templateLimitedWriter) Write(p []byte) (int, error) {
+	if err := lw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(p) > lw.n {
+		return 0, fmt.Errorf("template output exceeds the limit of %d bytes", templateMaxOutputSize)
+	}
+
+	lw.n -= len(p)
+	return lw.w.Write(p)
+}
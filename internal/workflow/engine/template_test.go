@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTemplateTestExecutionContext() *NodeExecutionContext {
+	execCtx := newMockNodeExecutionContext(&Node{Id: "deploy", Type: NodeTypeBizDeploy})
+	execCtx.variables.Set("certificate.commonName", "example.com", stateValTypeString)
+	execCtx.variables.Set("trigger.tag", "v1; rm -rf / #'", stateValTypeString)
+	execCtx.variables.SetScoped("apply", "certificate.commonName", "scoped.example.com", stateValTypeString)
+	return execCtx
+}
+
+func TestRenderTemplate(t *testing.T) {
+	execCtx := newTemplateTestExecutionContext()
+
+	testCases := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain text", text: "hello", want: "hello"},
+		{name: "legacy placeholder", text: "cn={{ $certificate.commonName }}", want: "cn=example.com"},
+		{name: "legacy unknown placeholder", text: "{{ $unknown }}", want: "{{ $unknown }}"},
+		{name: "scoped variable", text: `{{ var "certificate.commonName" "apply" }}`, want: "scoped.example.com"},
+		{name: "default", text: `{{ var "missing" | default "-" }}`, want: "-"},
+		{name: "shellquote", text: `{{ var "trigger.tag" | shellquote }}`, want: `'v1; rm -rf / #'\'''`},
+		{name: "missing key", text: "{{ .Names }}", wantErr: true},
+		{name: "syntax error", text: "{{ if }}", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := renderTemplate(execCtx, tc.text)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRenderShellTemplate(t *testing.T) {
+	execCtx := newTemplateTestExecutionContext()
+
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain command", text: "nginx -s reload", want: "nginx -s reload"},
+		{name: "untrusted value", text: `echo {{ var "trigger.tag" }}`, want: `echo 'v1; rm -rf / #'\'''`},
+		{name: "legacy placeholder", text: "cp cert.pem /etc/{{ $certificate.commonName }}.pem", want: "cp cert.pem /etc/'example.com'.pem"},
+		{name: "explicit shellquote", text: `echo {{ var "trigger.tag" | shellquote }}`, want: `echo 'v1; rm -rf / #'\'''`},
+		{name: "control structures", text: `{{ $cn := var "certificate.commonName" }}{{ if $cn }}echo {{ $cn }}{{ end }}`, want: "echo 'example.com'"},
+		{name: "string literal", text: `echo {{ "{{" }}`, want: "echo {{"},
+		{name: "range", text: `{{ range split ";" "a;b" }}touch {{ . }};{{ end }}`, want: "touch 'a';touch 'b';"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := renderShellTemplate(execCtx, tc.text)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRenderTemplateConfig(t *testing.T) {
+	execCtx := newTemplateTestExecutionContext()
+
+	config := map[string]any{
+		"certPath":    "/etc/{{ $certificate.commonName }}.pem",
+		"postCommand": `echo {{ var "trigger.tag" }}`,
+		"nested":      map[string]any{"items": []any{"{{ $certificate.commonName }}", 1}},
+	}
+	rendered, err := renderTemplateConfig(execCtx, config)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/example.com.pem", rendered["certPath"])
+	assert.Equal(t, `echo 'v1; rm -rf / #'\'''`, rendered["postCommand"])
+	assert.Equal(t, []any{"example.com", 1}, rendered["nested"].(map[string]any)["items"])
+	assert.Equal(t, "/etc/{{ $certificate.commonName }}.pem", config["certPath"], "the original config should not be modified")
+
+	_, err = renderTemplateConfig(execCtx, map[string]any{"preCommand": "docker ps --format '{{.Names}}'"})
+	assert.ErrorContains(t, err, "preCommand")
+
+	rendered, err = renderTemplateConfig(execCtx, map[string]any{"preCommand": `docker ps --format '{{ "{{" }}.Names}}'`})
+	require.NoError(t, err)
+	assert.Equal(t, `docker ps --format '{{.Names}}'`, rendered["preCommand"])
+}
+
+func TestRenderTemplate_Limits(t *testing.T) {
+	execCtx := newTemplateTestExecutionContext()
+
+	got, err := renderTemplate(execCtx, `{{ range $i := 3 }}{{ $i }}{{ end }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "012", got)
+
+	_, err = renderTemplate(execCtx, `{{ range 1000000000 }}{{ end }}`)
+	assert.ErrorContains(t, err, "too many iterations")
+
+	// 关联模板同样会被限制及转义
+	_, err = renderTemplate(execCtx, `{{ define "loop" }}{{ range 1000000000 }}{{ end }}{{ end }}{{ template "loop" }}`)
+	assert.ErrorContains(t, err, "too many iterations")
+	got, err = renderShellTemplate(execCtx, `{{ define "tag" }}{{ var "trigger.tag" }}{{ end }}echo {{ template "tag" }}`)
+	require.NoError(t, err)
+	assert.Equal(t, `echo 'v1; rm -rf / #'\'''`, got)
+
+	// 渲染超时后立即返回，不等待模板执行结束
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	execCtx.SetContext(ctx)
+	start := time.Now()
+	_, err = renderTemplate(execCtx, `{{ range 10000 }}{{ range 10000 }}{{ "" }}{{ end }}{{ end }}`)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/pkg/logging"
+	xmaps "github.com/certimate-go/certimate/pkg/utils/maps"
+)
+
+// 密钥在日志及错误信息中的遮蔽文本。
+const secretMask = "******"
+
+// 按开始节点中的自定义变量定义写入全局变量。
+// 非密钥类型的变量仅在尚未赋值（如未在启动运行时传入）时写入默认值；密钥类型的变量总是在运行时重新读取，且不会被持久化。
+func (we *workflowEngine) resolveVariables(ctx context.Context, graph *Graph, wfVars VariableManager) error {
+	if graph == nil || len(graph.Nodes) == 0 || graph.Nodes[0].Type != NodeTypeStart {
+		return nil
+	}
+
+	nodeCfg := graph.Nodes[0].Data.Config.AsStart()
+	for _, variable := range nodeCfg.Variables {
+		if variable.Type != domain.WorkflowVariableTypeSecret {
+			if _, ok := wfVars.Get(variable.Key); !ok && variable.DefaultValue != "" {
+				wfVars.Set(variable.Key, parseStateValue(variable.DefaultValue, variable.Type.String()), variable.Type.String())
+			}
+			continue
+		}
+
+		secret, err := we.resolveSecret(ctx, variable)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret variable '%s': %w", variable.Key, err)
+		}
+
+		we.addSecret(secret)
+		wfVars.Set(variable.Key, secret, stateValTypeSecret)
+	}
+
+	return nil
+}
+
+func (we *workflowEngine) resolveSecret(ctx context.Context, variable domain.WorkflowNodeConfigForStartVariable) (string, error) {
+	switch variable.SecretSource {
+	case domain.WorkflowVariableSecretSourceAccess:
+		access, err := we.accessRepo.GetById(ctx, variable.SecretAccessId)
+		if err != nil {
+			return "", fmt.Errorf("failed to get access #%s record: %w", variable.SecretAccessId, err)
+		}
+
+		if _, ok := access.Config[variable.SecretAccessField]; !ok {
+			return "", fmt.Errorf("field '%s' not found in access #%s", variable.SecretAccessField, variable.SecretAccessId)
+		}
+		return xmaps.GetString(access.Config, variable.SecretAccessField), nil
+
+	case domain.WorkflowVariableSecretSourceEnv:
+		if !strings.HasPrefix(variable.SecretEnvName, domain.WorkflowVariableSecretEnvPrefix) {
+			return "", fmt.Errorf("environment variable '%s' is not allowed", variable.SecretEnvName)
+		}
+
+		value, ok := os.LookupEnv(variable.SecretEnvName)
+		if !ok {
+			return "", fmt.Errorf("environment variable '%s' not found", variable.SecretEnvName)
+		}
+		return value, nil
+
+	default:
+		return "", fmt.Errorf("unsupported secret source: '%s'", variable.SecretSource)
+	}
+}
+
+func (we *workflowEngine) addSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	we.secretsMtx.Lock()
+	defer we.secretsMtx.Unlock()
+
+	for _, item := range we.secrets {
+		if item == secret {
+			return
+		}
+	}
+	we.secrets = append(we.secrets, secret)
+
+	// 序列化为 JSON 后密钥中的特殊字符将被转义，因此一并遮蔽转义后的形式
+	if data, err := json.Marshal(secret); err == nil {
+		if escaped := string(data[1 : len(data)-1]); escaped != secret {
+			we.secrets = append(we.secrets, escaped)
+		}
+	}
+}
+
+func (we *workflowEngine) maskSecrets(s string) string {
+	we.secretsMtx.RLock()
+	defer we.secretsMtx.RUnlock()
+
+	for _, secret := range we.secrets {
+		s = strings.ReplaceAll(s, secret, secretMask)
+	}
+	return s
+}
+
+func (we *workflowEngine) hasSecrets() bool {
+	we.secretsMtx.RLock()
+	defer we.secretsMtx.RUnlock()
+
+	return len(we.secrets) > 0
+}
+
+// 遮蔽日志记录的消息及属性中出现的密钥。
+func (we *workflowEngine) maskRecord(record logging.Record) logging.Record {
+	if !we.hasSecrets() {
+		return record
+	}
+
+	masked := slog.NewRecord(record.Time, record.Level, we.maskSecrets(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(we.maskAttr(attr))
+		return true
+	})
+	return logging.Record{Record: masked}
+}
+
+func (we *workflowEngine) maskAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, we.maskSecrets(attr.Value.String()))
+
+	case slog.KindGroup:
+		attrs := attr.Value.Group()
+		maskedAttrs := make([]slog.Attr, len(attrs))
+		for i, subAttr := range attrs {
+			maskedAttrs[i] = we.maskAttr(subAttr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(maskedAttrs...)}
+
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, we.maskSecrets(err.Error()))
+		}
+
+		// 对于结构体等复杂类型，序列化后检查其中是否包含密钥
+		data, err := json.Marshal(attr.Value.Any())
+		if err != nil {
+			return attr
+		}
+
+		maskedData := we.maskSecrets(string(data))
+		if maskedData == string(data) {
+			return attr
+		}
+
+		var value any
+		if err := json.Unmarshal([]byte(maskedData), &value); err != nil {
+			return slog.String(attr.Key, maskedData)
+		}
+		return slog.Any(attr.Key, value)
+	}
+
+	return attr
+}
+
+// 遮蔽错误信息中出现的密钥，遮蔽后的错误仍可通过 [errors.Is] 等方法判断原始错误。
+func (we *workflowEngine) maskError(err error) error {
+	if err == nil || !we.hasSecrets() {
+		return err
+	}
+
+	message := err.Error()
+	if maskedMessage := we.maskSecrets(message); maskedMessage != message {
+		return &maskedError{err: err, message: maskedMessage}
+	}
+	return err
+}
+
+type maskedError struct {
+	err     error
+	message string
+}
+
+func (e *maskedError) Error() string {
+	return e.message
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,226 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+)
+
+// 事件触发链路的运行变量键，记录事件传递所经过的工作流 ID，以半角逗号分隔。
+// 用于识别并阻断工作流之间因相互订阅而产生的循环触发。
+const eventVarKeyChain = "trigger.chain"
+
+type workflowEvent struct {
+	Type        domain.WorkflowEventType
+	Certificate *domain.Certificate
+	WorkflowRun *domain.WorkflowRun
+	DaysLeft    int // 仅用于证书即将到期事件
+}
+
+// 返回事件的来源工作流 ID。
+func (e *workflowEvent) sourceWorkflowId() string {
+	if e.Certificate != nil {
+		return e.Certificate.WorkflowId
+	}
+	if e.WorkflowRun != nil {
+		return e.WorkflowRun.WorkflowId
+	}
+	return ""
+}
+
+// 判断工作流是否订阅了该事件。
+func (e *workflowEvent) matches(workflow *domain.Workflow) bool {
+	if workflow.GraphContent == nil || len(workflow.GraphContent.Nodes) == 0 {
+		return false
+	}
+
+	nodeCfg := workflow.GraphContent.Nodes[0].Data.Config.AsStart()
+	if nodeCfg.Trigger != domain.WorkflowTriggerTypeEvent || nodeCfg.EventType != e.Type {
+		return false
+	} else if nodeCfg.EventWorkflowId != "" && nodeCfg.EventWorkflowId != e.sourceWorkflowId() {
+		return false
+	}
+
+	switch e.Type {
+	case domain.WorkflowEventTypeCertificateExpiring:
+		return nodeCfg.EventExpiringDays == e.DaysLeft
+	case domain.WorkflowEventTypeWorkflowRunCompleted:
+		return nodeCfg.EventRunStatus == "" || nodeCfg.EventRunStatus == e.WorkflowRun.Status
+	}
+
+	return true
+}
+
+// 将触发事件的实体注入为运行变量。
+func (e *workflowEvent) variables(chain []string) []*domain.WorkflowRunVariable {
+	variables := make([]*domain.WorkflowRunVariable, 0)
+	addVariable := func(key string, value string, valueType string) {
+		variables = append(variables, &domain.WorkflowRunVariable{Key: key, Value: value, ValueType: valueType})
+	}
+
+	addVariable("trigger.event", e.Type.String(), "string")
+	addVariable(eventVarKeyChain, strings.Join(chain, ","), "string")
+
+	if e.Certificate != nil {
+		addVariable("trigger.certificate.id", e.Certificate.Id, "string")
+		addVariable("trigger.certificate.subjectAltNames", e.Certificate.SubjectAltNames, "string")
+		addVariable("trigger.certificate.serialNumber", e.Certificate.SerialNumber, "string")
+		addVariable("trigger.certificate.notBefore", e.Certificate.ValidityNotBefore.Format(time.RFC3339), "datetime")
+		addVariable("trigger.certificate.notAfter", e.Certificate.ValidityNotAfter.Format(time.RFC3339), "datetime")
+		addVariable("trigger.certificate.workflowId", e.Certificate.WorkflowId, "string")
+		if e.Type == domain.WorkflowEventTypeCertificateExpiring {
+			addVariable("trigger.certificate.daysLeft", strconv.Itoa(e.DaysLeft), "number")
+		}
+		if e.Type == domain.WorkflowEventTypeCertificateRevoked {
+			addVariable("trigger.certificate.revokedReason", string(e.Certificate.RevokedReason), "string")
+		}
+	}
+
+	if e.WorkflowRun != nil {
+		addVariable("trigger.workflow.id", e.WorkflowRun.WorkflowId, "string")
+		addVariable("trigger.run.id", e.WorkflowRun.Id, "string")
+		addVariable("trigger.run.status", e.WorkflowRun.Status.String(), "string")
+		addVariable("trigger.run.error", e.WorkflowRun.Error, "string")
+	}
+
+	return variables
+}
+
+func (s *WorkflowService) dispatchEvent(ctx context.Context, event *workflowEvent) error {
+	workflows, err := s.workflowRepo.ListEnabledEventDriven(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.dispatchEventTo(ctx, event, workflows)
+}
+
+func (s *WorkflowService) dispatchEventTo(ctx context.Context, event *workflowEvent, workflows []*domain.Workflow) error {
+	var chain []string
+	if sourceWorkflowId := event.sourceWorkflowId(); sourceWorkflowId != "" {
+		chain = append(s.resolveEventChain(ctx, event), sourceWorkflowId)
+	}
+
+	var errs []error
+	for _, workflow := range workflows {
+		if !event.matches(workflow) {
+			continue
+		}
+
+		if slices.Contains(chain, workflow.Id) {
+			app.GetLogger().Warn(fmt.Sprintf("skip triggering workflow #%s by event '%s', because a circular trigger is detected", workflow.Id, event.Type), slog.String("chain", strings.Join(chain, " -> ")))
+			continue
+		}
+
+		app.GetLogger().Info(fmt.Sprintf("workflow #%s is triggered by event '%s' ...", workflow.Id, event.Type))
+
+		_, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+			WorkflowId:   workflow.Id,
+			RunTrigger:   domain.WorkflowTriggerTypeEvent,
+			RunVariables: event.variables(chain),
+		})
+		if err != nil {
+			app.GetLogger().Warn(fmt.Sprintf("failed to start event-driven run for workflow #%s", workflow.Id), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// 读取产生该事件的运行本身的事件触发链路。
+func (s *WorkflowService) resolveEventChain(ctx context.Context, event *workflowEvent) []string {
+	workflowRun := event.WorkflowRun
+	if workflowRun == nil && event.Certificate != nil && event.Certificate.WorkflowRunId != "" {
+		if run, err := s.workflowRunRepo.GetById(ctx, event.Certificate.WorkflowRunId); err == nil {
+			workflowRun = run
+		}
+	}
+	if workflowRun == nil || workflowRun.Trigger != domain.WorkflowTriggerTypeEvent {
+		return nil
+	}
+
+	for _, variable := range workflowRun.Variables {
+		if variable.Key == eventVarKeyChain && variable.Value != "" {
+			return strings.Split(variable.Value, ",")
+		}
+	}
+
+	return nil
+}
+
+func (s *WorkflowService) dispatchCertificateEvent(ctx context.Context, eventType domain.WorkflowEventType, certificateId string) {
+	certificate, err := s.certificateRepo.GetById(ctx, certificateId)
+	if err != nil {
+		app.GetLogger().Warn(fmt.Sprintf("failed to get certificate #%s record", certificateId), slog.Any("error", err))
+		return
+	}
+
+	if err := s.dispatchEvent(ctx, &workflowEvent{Type: eventType, Certificate: certificate}); err != nil {
+		app.GetLogger().Warn(fmt.Sprintf("failed to dispatch event '%s'", eventType), slog.Any("error", err))
+	}
+}
+
+func (s *WorkflowService) dispatchWorkflowRunEvent(ctx context.Context, eventType domain.WorkflowEventType, workflowRunId string) {
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, workflowRunId)
+	if err != nil {
+		app.GetLogger().Warn(fmt.Sprintf("failed to get workflow run #%s record", workflowRunId), slog.Any("error", err))
+		return
+	}
+
+	if err := s.dispatchEvent(ctx, &workflowEvent{Type: eventType, WorkflowRun: workflowRun}); err != nil {
+		app.GetLogger().Warn(fmt.Sprintf("failed to dispatch event '%s'", eventType), slog.Any("error", err))
+	}
+}
+
+// 每日检查一次有效证书的剩余天数。
+// 由于剩余天数向下取整，每张证书在每个剩余天数上只会被检查到一次，因此订阅的工作流对同一张证书只会被触发一次；
+// 若在检查时服务未运行，当日的事件将会被错过。
+func (s *WorkflowService) dispatchCertificateExpiringEvents(ctx context.Context) error {
+	workflows, err := s.workflowRepo.ListEnabledEventDriven(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to list event-driven workflows", slog.Any("error", err))
+		return err
+	}
+
+	subscribed := slices.ContainsFunc(workflows, func(workflow *domain.Workflow) bool {
+		return workflow.GraphContent != nil && len(workflow.GraphContent.Nodes) > 0 &&
+			workflow.GraphContent.Nodes[0].Data.Config.AsStart().EventType == domain.WorkflowEventTypeCertificateExpiring
+	})
+	if !subscribed {
+		return nil
+	}
+
+	certificates, err := s.certificateRepo.ListActive(ctx)
+	if err != nil {
+		app.GetLogger().Error("failed to list active certificates", slog.Any("error", err))
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, certificate := range certificates {
+		// 已被续期的证书无需再触发到期事件
+		if certificate.IsRenewed {
+			continue
+		}
+
+		daysLeft := int(math.Floor(certificate.ValidityNotAfter.Sub(now).Hours() / 24))
+		event := &workflowEvent{Type: domain.WorkflowEventTypeCertificateExpiring, Certificate: certificate, DaysLeft: daysLeft}
+		if err := s.dispatchEventTo(ctx, event, workflows); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pocketbase/pocketbase/core"
 
@@ -11,42 +12,83 @@ import (
 
 func registerWorkflowRecordEvents() {
 	pb := app.GetApp()
-	pb.OnRecordCreateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+	pb.OnRecordCreateRequest(domain.CollectionNameWorkflow).BindFunc(onWorkflowRecordSaveRequest)
+	pb.OnRecordUpdateRequest(domain.CollectionNameWorkflow).BindFunc(onWorkflowRecordSaveRequest)
+	pb.OnRecordDeleteRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
 		if err := e.Next(); err != nil {
 			return err
 		}
 
-		if err := onWorkflowRecordCreateOrUpdate(e.Request.Context(), e.App, e.Record); err != nil {
+		if err := onWorkflowRecordDelete(e.Request.Context(), e.App, e.Record); err != nil {
 			app.GetLogger().Error(err.Error())
 			return err
 		}
 
 		return nil
 	})
-	pb.OnRecordUpdateRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
-		if err := e.Next(); err != nil {
-			return err
-		}
 
-		if err := onWorkflowRecordCreateOrUpdate(e.Request.Context(), e.App, e.Record); err != nil {
-			app.GetLogger().Error(err.Error())
-			return err
-		}
-
-		return nil
+	pb.OnRecordAfterCreateSuccess(domain.CollectionNameCertificate).BindFunc(func(e *core.RecordEvent) error {
+		onCertificateRecordCreated(e.Record)
+		return e.Next()
 	})
-	pb.OnRecordDeleteRequest(domain.CollectionNameWorkflow).BindFunc(func(e *core.RecordRequestEvent) error {
+	pb.OnRecordAfterUpdateSuccess(domain.CollectionNameCertificate).BindFunc(func(e *core.RecordEvent) error {
+		onCertificateRecordUpdated(e.Record)
+		return e.Next()
+	})
+	pb.OnRecordAfterUpdateSuccess(domain.CollectionNameWorkflowRun).BindFunc(func(e *core.RecordEvent) error {
+		onWorkflowRunRecordUpdated(e.Record)
+		return e.Next()
+	})
+}
+
+func onWorkflowRecordSaveRequest(e *core.RecordRequestEvent) error {
+	onWorkflowRecordBeforeCreateOrUpdate(e.Record)
+	published := isWorkflowGraphContentChanged(e.Record)
+
+	// 发布时须在同一事务中记录新版本，以免工作流已发布而版本缺失
+	pbApp := e.App
+	err := pbApp.RunInTransaction(func(txApp core.App) error {
+		e.App = txApp
+
 		if err := e.Next(); err != nil {
 			return err
 		}
 
-		if err := onWorkflowRecordDelete(e.Request.Context(), e.App, e.Record); err != nil {
-			app.GetLogger().Error(err.Error())
-			return err
+		if published {
+			if err := onWorkflowRecordPublished(e); err != nil {
+				app.GetLogger().Error(err.Error())
+				return err
+			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := onWorkflowRecordCreateOrUpdate(e.Request.Context(), pbApp, e.Record); err != nil {
+		app.GetLogger().Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func onWorkflowRecordBeforeCreateOrUpdate(record *core.Record) {
+	// 如果是 Webhook 触发，签发访问令牌及签名密钥；反之，吊销已签发的令牌及密钥
+	if record.GetString("trigger") != domain.WorkflowTriggerTypeWebhook.String() {
+		record.Set("webhookToken", "")
+		record.Set("webhookSecret", "")
+		return
+	}
+
+	if record.GetString("webhookToken") == "" {
+		record.Set("webhookToken", generateWebhookToken())
+	}
+	if record.GetString("webhookSecret") == "" {
+		record.Set("webhookSecret", generateWebhookSecret())
+	}
 }
 
 func onWorkflowRecordCreateOrUpdate(_ context.Context, _ core.App, record *core.Record) error {
@@ -71,6 +113,20 @@ func onWorkflowRecordCreateOrUpdate(_ context.Context, _ core.App, record *core.
 	return nil
 }
 
+func onWorkflowRecordPublished(e *core.RecordRequestEvent) error {
+	// 每次发布均记录一个新版本，以便追溯变更及回滚
+	author := ""
+	if e.Auth != nil {
+		author = e.Auth.Email()
+	}
+
+	if _, err := thisSvcInst().recordVersion(e.Request.Context(), e.App, e.Record, author, getVersionCommentFromRequest(e)); err != nil {
+		return fmt.Errorf("failed to record workflow version: %w", err)
+	}
+
+	return nil
+}
+
 func onWorkflowRecordDelete(_ context.Context, _ core.App, record *core.Record) error {
 	scheduler := app.GetScheduler()
 
@@ -79,3 +135,32 @@ func onWorkflowRecordDelete(_ context.Context, _ core.App, record *core.Record)
 
 	return nil
 }
+
+func onCertificateRecordCreated(record *core.Record) {
+	// 事件异步分发，以免阻塞证书的保存
+	go thisSvcInst().dispatchCertificateEvent(context.Background(), domain.WorkflowEventTypeCertificateCreated, record.Id)
+}
+
+func onCertificateRecordUpdated(record *core.Record) {
+	if !record.Original().GetBool("isRevoked") && record.GetBool("isRevoked") {
+		go thisSvcInst().dispatchCertificateEvent(context.Background(), domain.WorkflowEventTypeCertificateRevoked, record.Id)
+	}
+}
+
+func onWorkflowRunRecordUpdated(record *core.Record) {
+	// 试运行不产生事件
+	if record.GetString("trigger") == domain.WorkflowTriggerTypeDryRun.String() {
+		return
+	}
+
+	prevStatus := record.Original().GetString("status")
+	currStatus := record.GetString("status")
+	if prevStatus == currStatus {
+		return
+	}
+
+	switch currStatus {
+	case domain.WorkflowRunStatusTypeSucceeded.String(), domain.WorkflowRunStatusTypeFailed.String():
+		go thisSvcInst().dispatchWorkflowRunEvent(context.Background(), domain.WorkflowEventTypeWorkflowRunCompleted, record.Id)
+	}
+}
@@ -2,9 +2,14 @@ package workflow
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pocketbase/dbx"
@@ -14,21 +19,37 @@ import (
 	"github.com/certimate-go/certimate/internal/domain/dtos"
 	"github.com/certimate-go/certimate/internal/settings"
 	"github.com/certimate-go/certimate/internal/workflow/dispatcher"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
 )
 
 type WorkflowService struct {
 	dispatcher dispatcher.WorkflowDispatcher
 
-	workflowRepo    workflowRepository
-	workflowRunRepo workflowRunRepository
+	workflowRepo         workflowRepository
+	workflowRunRepo      workflowRunRepository
+	workflowVersionRepo  workflowVersionRepository
+	workflowApprovalRepo workflowApprovalRepository
+	accessRepo           accessRepository
+	certificateRepo      certificateRepository
+	privateCARepo        privateCARepository
+
+	webhookMtx        sync.Mutex // 串行处理 Webhook 触发，避免并发的请求绕过运行状态检查
+	webhookDeliveries *webhookDeliveryStore
 }
 
-func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository) *WorkflowService {
+func NewWorkflowService(workflowRepo workflowRepository, workflowRunRepo workflowRunRepository, workflowVersionRepo workflowVersionRepository, workflowApprovalRepo workflowApprovalRepository, accessRepo accessRepository, certificateRepo certificateRepository, privateCARepo privateCARepository) *WorkflowService {
 	srv := &WorkflowService{
 		dispatcher: dispatcher.GetSingletonDispatcher(),
 
-		workflowRepo:    workflowRepo,
-		workflowRunRepo: workflowRunRepo,
+		workflowRepo:         workflowRepo,
+		workflowRunRepo:      workflowRunRepo,
+		workflowVersionRepo:  workflowVersionRepo,
+		workflowApprovalRepo: workflowApprovalRepo,
+		accessRepo:           accessRepo,
+		certificateRepo:      certificateRepo,
+		privateCARepo:        privateCARepo,
+
+		webhookDeliveries: newWebhookDeliveryStore(),
 	}
 	return srv
 }
@@ -39,6 +60,11 @@ func (s *WorkflowService) InitSchedule(ctx context.Context) error {
 		s.cleanupHistoryRuns(context.Background())
 	})
 
+	// 每日检查证书剩余天数，触发订阅了证书即将到期事件的工作流
+	app.GetScheduler().MustAdd("dispatchCertificateExpiringEvents", "0 0 * * *", func() {
+		s.dispatchCertificateExpiringEvents(context.Background())
+	})
+
 	// 初始化工作流调度器
 	if err := s.dispatcher.Bootup(ctx); err != nil {
 		panic(err)
@@ -71,6 +97,7 @@ func (s *WorkflowService) GetStatistics(ctx context.Context) (*dtos.WorkflowStat
 		Concurrency:      stats.Concurrency,
 		PendingRunIds:    stats.PendingRunIds,
 		ProcessingRunIds: stats.ProcessingRunIds,
+		WaitingRunIds:    stats.WaitingRunIds,
 	}, nil
 }
 
@@ -80,20 +107,41 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 		return nil, err
 	}
 
-	if req.RunTrigger == domain.WorkflowTriggerTypeManual && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing) {
-		return nil, fmt.Errorf("workflow is already pending or processing")
+	if (req.RunTrigger == domain.WorkflowTriggerTypeManual || req.RunTrigger == domain.WorkflowTriggerTypeDryRun || req.RunTrigger == domain.WorkflowTriggerTypeWebhook) && (workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing || workflow.LastRunStatus == domain.WorkflowRunStatusTypeWaiting) {
+		return nil, fmt.Errorf("workflow is already pending, processing or waiting")
 	} else if workflow.GraphContent == nil {
 		return nil, fmt.Errorf("workflow graph content is empty")
 	} else if err := workflow.GraphContent.Verify(); err != nil {
 		return nil, fmt.Errorf("workflow graph content is invalid: %w", err)
 	}
 
+	runVariables := req.RunVariables
+	if len(req.Variables) > 0 {
+		overrides, err := resolveRunVariableOverrides(workflow.GraphContent, req.Variables)
+		if err != nil {
+			return nil, domain.NewError(http.StatusBadRequest, err.Error())
+		}
+		runVariables = append(slices.Clone(runVariables), overrides...)
+	}
+
+	// 关联当前已发布内容对应的版本；早于版本记录发布的工作流可能没有任何版本
+	versionId := ""
+	if version, err := s.workflowVersionRepo.GetLatestByWorkflowId(ctx, workflow.Id); err != nil {
+		if !domain.IsRecordNotFoundError(err) {
+			return nil, err
+		}
+	} else {
+		versionId = version.Id
+	}
+
 	workflowRun := &domain.WorkflowRun{
 		WorkflowId: workflow.Id,
 		Status:     domain.WorkflowRunStatusTypePending,
 		Trigger:    req.RunTrigger,
 		StartedAt:  time.Now(),
 		Graph:      workflow.GraphContent.Clone(),
+		Variables:  runVariables,
+		VersionId:  versionId,
 	}
 	if resp, err := s.workflowRunRepo.Save(ctx, workflowRun); err != nil {
 		return nil, err
@@ -108,6 +156,85 @@ func (s *WorkflowService) StartRun(ctx context.Context, req *dtos.WorkflowStartR
 	return &dtos.WorkflowStartRunResp{RunId: workflowRun.Id}, nil
 }
 
+func (s *WorkflowService) TriggerWebhook(ctx context.Context, req *dtos.WorkflowTriggerWebhookReq) (*dtos.WorkflowTriggerWebhookResp, error) {
+	// 为避免泄露工作流是否存在，令牌校验失败时统一返回相同的错误
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, errWebhookUnauthorized
+		}
+		return nil, err
+	} else if workflow.WebhookToken == "" || subtle.ConstantTimeCompare([]byte(workflow.WebhookToken), []byte(req.Token)) != 1 {
+		return nil, errWebhookUnauthorized
+	} else if !workflow.Enabled || workflow.Trigger != domain.WorkflowTriggerTypeWebhook {
+		return nil, errWebhookUnauthorized
+	}
+
+	now := time.Now()
+	if err := verifyWebhookSignature(workflow.WebhookSecret, req.Timestamp, req.Signature, req.Payload, now); err != nil {
+		return nil, domain.NewError(http.StatusForbidden, err.Error())
+	}
+
+	variables, err := resolveWebhookPayloadVariables(workflow.GraphContent, req.Payload)
+	if err != nil {
+		return nil, domain.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	// 签名覆盖了时间戳及请求体，因此以签名标识一次投递，拒绝在时间戳容差范围内重放的请求
+	deliveryKey := workflow.Id + ":" + strings.ToLower(req.Signature)
+	if !s.webhookDeliveries.Add(deliveryKey, now) {
+		return nil, domain.NewError(http.StatusConflict, "webhook delivery has already been accepted")
+	}
+
+	s.webhookMtx.Lock()
+	defer s.webhookMtx.Unlock()
+
+	res, err := s.StartRun(ctx, &dtos.WorkflowStartRunReq{
+		WorkflowId:   workflow.Id,
+		RunTrigger:   domain.WorkflowTriggerTypeWebhook,
+		RunVariables: variables,
+	})
+	if err != nil {
+		// 未能启动运行时允许发送方重试同一次投递
+		s.webhookDeliveries.Remove(deliveryKey)
+		return nil, err
+	}
+
+	return &dtos.WorkflowTriggerWebhookResp{RunId: res.RunId}, nil
+}
+
+func (s *WorkflowService) ReviewApproval(ctx context.Context, req *dtos.WorkflowReviewApprovalReq) (*dtos.WorkflowReviewApprovalResp, error) {
+	if err := engine.VerifyApprovalLink(req.RunId, req.NodeId, req.Action, req.Expires, req.Signature, time.Now()); err != nil {
+		return nil, domain.NewError(http.StatusForbidden, err.Error())
+	}
+
+	// 审批可能位于被调用的子工作流的运行中，因此以审批记录而非运行状态判断是否仍在等待
+	approval, err := s.workflowApprovalRepo.GetByWorkflowRunIdAndNodeId(ctx, req.RunId, req.NodeId)
+	if err != nil {
+		if domain.IsRecordNotFoundError(err) {
+			return nil, domain.NewError(http.StatusNotFound, "approval not found")
+		}
+		return nil, err
+	} else if !approval.IsPending() {
+		return nil, domain.NewError(http.StatusConflict, fmt.Sprintf("approval has already been %s", approval.Status))
+	}
+
+	// 审批链接无需登录即可访问，因此以审批人自行填写的名称及其来源 IP 标识审批人
+	operator := req.Operator
+	if operator == "" {
+		operator = "anonymous"
+	}
+	if req.RemoteIP != "" {
+		operator = fmt.Sprintf("%s (%s)", operator, req.RemoteIP)
+	}
+
+	if err := s.decideApproval(ctx, approval, req.Action == engine.ApprovalActionReject, operator, req.Comment); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowReviewApprovalResp{}, nil
+}
+
 func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCancelRunReq) (*dtos.WorkflowCancelRunResp, error) {
 	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
 	if err != nil {
@@ -119,8 +246,8 @@ func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCance
 		return nil, err
 	} else if workflowRun.WorkflowId != workflow.Id {
 		return nil, fmt.Errorf("workflow run not found")
-	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing {
-		return nil, fmt.Errorf("workflow run is not pending or processing")
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypePending && workflowRun.Status != domain.WorkflowRunStatusTypeProcessing && workflowRun.Status != domain.WorkflowRunStatusTypeWaiting {
+		return nil, fmt.Errorf("workflow run is not pending, processing or waiting")
 	}
 
 	if err := s.dispatcher.Cancel(ctx, workflowRun.Id); err != nil {
@@ -130,6 +257,201 @@ func (s *WorkflowService) CancelRun(ctx context.Context, req *dtos.WorkflowCance
 	return &dtos.WorkflowCancelRunResp{}, nil
 }
 
+func (s *WorkflowService) ResumeRun(ctx context.Context, req *dtos.WorkflowResumeRunReq) (*dtos.WorkflowResumeRunResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != workflow.Id {
+		return nil, fmt.Errorf("workflow run not found")
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypeWaiting {
+		return nil, fmt.Errorf("workflow run is not waiting")
+	}
+
+	approvals, err := s.workflowApprovalRepo.ListByWorkflowRunId(ctx, workflowRun.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	decided := 0
+	for _, approval := range approvals {
+		if !approval.IsPending() || (req.NodeId != "" && req.NodeId != approval.NodeId) {
+			continue
+		}
+
+		if err := s.decideApproval(ctx, approval, req.Rejected, req.Operator, req.Comment); err != nil {
+			return nil, err
+		}
+		decided++
+	}
+	if decided > 0 {
+		return &dtos.WorkflowResumeRunResp{}, nil
+	}
+
+	// 没有待审批的记录时，等待中的节点可能是无需审批记录的挂起（如手动 DNS-01 质询等待确认），直接唤醒之
+	signal := engine.ResumeSignal{
+		Rejected: req.Rejected,
+		Operator: req.Operator,
+		Comment:  req.Comment,
+	}
+	if err := s.dispatcher.Resume(ctx, workflowRun.Id, req.NodeId, signal); err != nil {
+		return nil, domain.NewError(http.StatusConflict, fmt.Sprintf("no pending approvals or suspended nodes: %s", err.Error()))
+	}
+
+	return &dtos.WorkflowResumeRunResp{}, nil
+}
+
+// 持久化审批决定，并尝试立即唤醒等待中的节点。
+// 若该运行当前不在调度器中（例如审批位于子工作流中，或服务正在重启），等待中的节点将通过定期查询审批记录得知决定。
+func (s *WorkflowService) decideApproval(ctx context.Context, approval *domain.WorkflowApproval, rejected bool, operator string, comment string) error {
+	approval.Status = domain.WorkflowApprovalStatusTypeApproved
+	if rejected {
+		approval.Status = domain.WorkflowApprovalStatusTypeRejected
+	}
+	approval.Operator = operator
+	approval.Comment = comment
+	approval.DecidedAt = time.Now()
+	if ok, err := s.workflowApprovalRepo.Decide(ctx, approval); err != nil {
+		return err
+	} else if !ok {
+		return domain.NewError(http.StatusConflict, "approval has already been decided")
+	}
+
+	signal := engine.ResumeSignal{
+		Rejected: rejected,
+		Operator: operator,
+		Comment:  comment,
+	}
+	if err := s.dispatcher.Resume(ctx, approval.RunId, approval.NodeId, signal); err != nil {
+		app.GetLogger().Debug(fmt.Sprintf("could not resume workrun #%s immediately, it will be resumed later", approval.RunId), slog.Any("error", err))
+	}
+
+	return nil
+}
+
+// 从失败的运行中记录的失败节点开始，发起一次新的运行。
+// 新运行沿用原运行的工作流图，失败节点之前的节点状态将从原运行的持久化输出中还原。
+func (s *WorkflowService) RetryRun(ctx context.Context, req *dtos.WorkflowRetryRunReq) (*dtos.WorkflowRetryRunResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	workflowRun, err := s.workflowRunRepo.GetById(ctx, req.RunId)
+	if err != nil {
+		return nil, err
+	} else if workflowRun.WorkflowId != workflow.Id {
+		return nil, fmt.Errorf("workflow run not found")
+	} else if workflowRun.Status != domain.WorkflowRunStatusTypeFailed {
+		return nil, fmt.Errorf("workflow run is not failed")
+	}
+
+	if workflow.LastRunStatus == domain.WorkflowRunStatusTypePending || workflow.LastRunStatus == domain.WorkflowRunStatusTypeProcessing || workflow.LastRunStatus == domain.WorkflowRunStatusTypeWaiting {
+		return nil, fmt.Errorf("workflow is already pending, processing or waiting")
+	} else if workflow.LastRunId != workflowRun.Id {
+		return nil, fmt.Errorf("only the last run of the workflow can be resumed")
+	} else if workflowRun.ErrorNodeId == "" {
+		return nil, fmt.Errorf("workflow run has no failed node to resume from")
+	} else if workflowRun.Graph == nil {
+		return nil, fmt.Errorf("workflow run graph is empty")
+	} else if _, ok := workflowRun.Graph.GetNodeById(workflowRun.ErrorNodeId); !ok {
+		return nil, fmt.Errorf("the failed node #%s does not exist in the workflow run graph", workflowRun.ErrorNodeId)
+	}
+
+	// 沿用失败运行的触发方式（如试运行重试后仍为试运行），重试来源记录在 ResumedFromRunId、ResumedFromNodeId 中
+	resumedRun := &domain.WorkflowRun{
+		WorkflowId:        workflow.Id,
+		Status:            domain.WorkflowRunStatusTypePending,
+		Trigger:           workflowRun.Trigger,
+		StartedAt:         time.Now(),
+		Graph:             workflowRun.Graph.Clone(),
+		ResumedFromRunId:  workflowRun.Id,
+		ResumedFromNodeId: workflowRun.ErrorNodeId,
+		Variables:         workflowRun.Variables,
+		VersionId:         workflowRun.VersionId,
+	}
+	if resp, err := s.workflowRunRepo.Save(ctx, resumedRun); err != nil {
+		return nil, err
+	} else {
+		resumedRun = resp
+	}
+
+	if err := s.dispatcher.Start(ctx, resumedRun.Id); err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowRetryRunResp{RunId: resumedRun.Id}, nil
+}
+
+func (s *WorkflowService) DiffVersions(ctx context.Context, req *dtos.WorkflowDiffVersionsReq) (*dtos.WorkflowDiffVersionsResp, error) {
+	var toVersion *domain.WorkflowVersion
+	var err error
+	if req.ToVersionId == "" {
+		toVersion, err = s.workflowVersionRepo.GetLatestByWorkflowId(ctx, req.WorkflowId)
+	} else {
+		toVersion, err = s.workflowVersionRepo.GetById(ctx, req.ToVersionId)
+	}
+	if err != nil {
+		return nil, err
+	} else if toVersion.WorkflowId != req.WorkflowId {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	var fromVersion *domain.WorkflowVersion
+	if req.FromVersionId == "" {
+		if toVersion.Version <= 1 {
+			return nil, domain.NewError(http.StatusBadRequest, "there is no previous version to compare with")
+		}
+		fromVersion, err = s.workflowVersionRepo.GetByWorkflowIdAndVersion(ctx, req.WorkflowId, toVersion.Version-1)
+	} else {
+		fromVersion, err = s.workflowVersionRepo.GetById(ctx, req.FromVersionId)
+	}
+	if err != nil {
+		return nil, err
+	} else if fromVersion.WorkflowId != req.WorkflowId {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	return &dtos.WorkflowDiffVersionsResp{
+		FromVersion:       fromVersion.Version,
+		ToVersion:         toVersion.Version,
+		WorkflowGraphDiff: domain.DiffWorkflowGraphs(fromVersion.Graph, toVersion.Graph),
+	}, nil
+}
+
+func (s *WorkflowService) RollbackVersion(ctx context.Context, req *dtos.WorkflowRollbackVersionReq) (*dtos.WorkflowRollbackVersionResp, error) {
+	workflow, err := s.workflowRepo.GetById(ctx, req.WorkflowId)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.workflowVersionRepo.GetById(ctx, req.VersionId)
+	if err != nil {
+		return nil, err
+	} else if version.WorkflowId != workflow.Id {
+		return nil, domain.ErrRecordNotFound
+	} else if version.Graph == nil || len(version.Graph.Nodes) == 0 {
+		return nil, fmt.Errorf("workflow version graph is empty")
+	} else if err := version.Graph.Verify(); err != nil {
+		return nil, fmt.Errorf("workflow version graph is invalid: %w", err)
+	}
+
+	comment := req.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("Rolled back to version %d", version.Version)
+	}
+	newVersion, err := s.publishGraph(ctx, workflow, version.Graph, req.Operator, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.WorkflowRollbackVersionResp{VersionId: newVersion.Id, Version: newVersion.Version}, nil
+}
+
 func (s *WorkflowService) Shutdown(ctx context.Context) {
 	s.dispatcher.Shutdown(ctx)
 }
@@ -140,6 +462,7 @@ func (s *WorkflowService) cleanupHistoryRuns(ctx context.Context) error {
 		ret, err := s.workflowRunRepo.DeleteWithExprs(ctx,
 			dbx.NewExp(fmt.Sprintf("status!='%s'", domain.WorkflowRunStatusTypePending)),
 			dbx.NewExp(fmt.Sprintf("status!='%s'", domain.WorkflowRunStatusTypeProcessing)),
+			dbx.NewExp(fmt.Sprintf("status!='%s'", domain.WorkflowRunStatusTypeWaiting)),
 			dbx.NewExp(fmt.Sprintf("endedAt<DATETIME('now', '-%d days')", globalSettingsForPersistence.WorkflowRunsRetentionMaxDays)),
 		)
 		if err != nil {
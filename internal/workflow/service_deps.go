@@ -4,14 +4,19 @@ import (
 	"context"
 
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/certimate-go/certimate/internal/domain"
 )
 
 type workflowRepository interface {
 	ListEnabledScheduled(ctx context.Context) ([]*domain.Workflow, error)
+	ListEnabledEventDriven(ctx context.Context) ([]*domain.Workflow, error)
+	ListAll(ctx context.Context) ([]*domain.Workflow, error)
+	ListByName(ctx context.Context, name string) ([]*domain.Workflow, error)
 	GetById(ctx context.Context, id string) (*domain.Workflow, error)
 	Save(ctx context.Context, workflow *domain.Workflow) (*domain.Workflow, error)
+	SaveWithTx(ctx context.Context, txApp core.App, workflow *domain.Workflow) (*domain.Workflow, error)
 }
 
 type workflowRunRepository interface {
@@ -20,3 +25,32 @@ type workflowRunRepository interface {
 	SaveWithCascading(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error)
 	DeleteWithExprs(ctx context.Context, exprs ...dbx.Expression) (int, error)
 }
+
+type workflowVersionRepository interface {
+	GetById(ctx context.Context, id string) (*domain.WorkflowVersion, error)
+	GetLatestByWorkflowId(ctx context.Context, workflowId string) (*domain.WorkflowVersion, error)
+	GetByWorkflowIdAndVersion(ctx context.Context, workflowId string, version int) (*domain.WorkflowVersion, error)
+	Save(ctx context.Context, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error)
+	SaveWithTx(ctx context.Context, txApp core.App, workflowVersion *domain.WorkflowVersion) (*domain.WorkflowVersion, error)
+}
+
+type workflowApprovalRepository interface {
+	GetByWorkflowRunIdAndNodeId(ctx context.Context, workflowRunId string, workflowNodeId string) (*domain.WorkflowApproval, error)
+	ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowApproval, error)
+	Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error)
+}
+
+type accessRepository interface {
+	GetById(ctx context.Context, id string) (*domain.Access, error)
+	ListByNameAndProvider(ctx context.Context, name string, provider string) ([]*domain.Access, error)
+}
+
+type privateCARepository interface {
+	List(ctx context.Context) ([]*domain.PrivateCA, error)
+	GetById(ctx context.Context, id string) (*domain.PrivateCA, error)
+}
+
+type certificateRepository interface {
+	ListActive(ctx context.Context) ([]*domain.Certificate, error)
+	GetById(ctx context.Context, id string) (*domain.Certificate, error)
+}
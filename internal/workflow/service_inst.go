@@ -16,6 +16,11 @@ func thisSvcInst() *WorkflowService {
 		thisSvc = NewWorkflowService(
 			repository.NewWorkflowRepository(),
 			repository.NewWorkflowRunRepository(),
+			repository.NewWorkflowVersionRepository(),
+			repository.NewWorkflowApprovalRepository(),
+			repository.NewAccessRepository(),
+			repository.NewCertificateRepository(),
+			repository.NewPrivateCARepository(),
 		)
 	})
 	return thisSvc
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+	"github.com/certimate-go/certimate/internal/domain/dtos"
+	"github.com/certimate-go/certimate/internal/workflow/dispatcher"
+	"github.com/certimate-go/certimate/internal/workflow/engine"
+)
+
+type mockWorkflowRunRepository struct {
+	workflowRunRepository
+
+	runs []*domain.WorkflowRun
+}
+
+func (r *mockWorkflowRunRepository) GetById(ctx context.Context, id string) (*domain.WorkflowRun, error) {
+	for _, run := range r.runs {
+		if run.Id == id {
+			return run, nil
+		}
+	}
+	return nil, domain.ErrRecordNotFound
+}
+
+func (r *mockWorkflowRunRepository) Save(ctx context.Context, workflowRun *domain.WorkflowRun) (*domain.WorkflowRun, error) {
+	if workflowRun.Id == "" {
+		workflowRun.Id = fmt.Sprintf("run_%d", len(r.runs)+1)
+	}
+	r.runs = append(r.runs, workflowRun)
+	return workflowRun, nil
+}
+
+type mockWorkflowApprovalRepository struct {
+	workflowApprovalRepository
+
+	approvals []*domain.WorkflowApproval
+}
+
+func (r *mockWorkflowApprovalRepository) ListByWorkflowRunId(ctx context.Context, workflowRunId string) ([]*domain.WorkflowApproval, error) {
+	approvals := make([]*domain.WorkflowApproval, 0)
+	for _, approval := range r.approvals {
+		if approval.RunId == workflowRunId {
+			approvals = append(approvals, approval)
+		}
+	}
+	return approvals, nil
+}
+
+func (r *mockWorkflowApprovalRepository) Decide(ctx context.Context, workflowApproval *domain.WorkflowApproval) (bool, error) {
+	return true, nil
+}
+
+type mockWorkflowDispatcher struct {
+	dispatcher.WorkflowDispatcher
+
+	suspendedNodeIds []string
+	resumed          []string
+	started          []string
+}
+
+func (d *mockWorkflowDispatcher) Start(ctx context.Context, runId string) error {
+	d.started = append(d.started, runId)
+	return nil
+}
+
+func (d *mockWorkflowDispatcher) Resume(ctx context.Context, runId string, nodeId string, signal engine.ResumeSignal) error {
+	for _, suspendedNodeId := range d.suspendedNodeIds {
+		if nodeId == "" || nodeId == suspendedNodeId {
+			d.resumed = append(d.resumed, runId+"/"+suspendedNodeId)
+			return nil
+		}
+	}
+
+	return errors.New("not suspended")
+}
+
+func TestResumeRun(t *testing.T) {
+	ctx := context.Background()
+	newService := func(approvals ...*domain.WorkflowApproval) (*WorkflowService, *mockWorkflowDispatcher) {
+		d := &mockWorkflowDispatcher{suspendedNodeIds: []string{"approve", "apply"}}
+		return &WorkflowService{
+			dispatcher:           d,
+			workflowRepo:         &mockBundleWorkflowRepository{workflows: []*domain.Workflow{{Meta: domain.Meta{Id: "wf_1"}}}},
+			workflowRunRepo:      &mockWorkflowRunRepository{runs: []*domain.WorkflowRun{{Meta: domain.Meta{Id: "run_1"}, WorkflowId: "wf_1", Status: domain.WorkflowRunStatusTypeWaiting}}},
+			workflowApprovalRepo: &mockWorkflowApprovalRepository{approvals: approvals},
+		}, d
+	}
+
+	t.Run("confirm manual dns challenge without approval record", func(t *testing.T) {
+		svc, d := newService()
+
+		_, err := svc.ResumeRun(ctx, &dtos.WorkflowResumeRunReq{WorkflowId: "wf_1", RunId: "run_1", NodeId: "apply"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run_1/apply"}, d.resumed)
+
+		_, err = svc.ResumeRun(ctx, &dtos.WorkflowResumeRunReq{WorkflowId: "wf_1", RunId: "run_1", NodeId: "other"})
+		assert.ErrorContains(t, err, "no pending approvals or suspended nodes")
+	})
+
+	t.Run("pending approval is decided instead", func(t *testing.T) {
+		approval := &domain.WorkflowApproval{RunId: "run_1", NodeId: "approve", Status: domain.WorkflowApprovalStatusTypePending}
+		svc, d := newService(approval)
+
+		_, err := svc.ResumeRun(ctx, &dtos.WorkflowResumeRunReq{WorkflowId: "wf_1", RunId: "run_1", NodeId: "approve"})
+		require.NoError(t, err)
+		assert.Equal(t, domain.WorkflowApprovalStatusTypeApproved, approval.Status)
+		assert.Equal(t, []string{"run_1/approve"}, d.resumed)
+
+		// 指定其他节点时，回退到直接唤醒挂起的节点
+		_, err = svc.ResumeRun(ctx, &dtos.WorkflowResumeRunReq{WorkflowId: "wf_1", RunId: "run_1", NodeId: "apply"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run_1/approve", "run_1/apply"}, d.resumed)
+	})
+}
+
+func TestRetryRun(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		trigger domain.WorkflowTriggerType
+	}{
+		{name: "Manual", trigger: domain.WorkflowTriggerTypeManual},
+		{name: "Scheduled", trigger: domain.WorkflowTriggerTypeScheduled},
+		{name: "DryRun", trigger: domain.WorkflowTriggerTypeDryRun},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failedRun := &domain.WorkflowRun{
+				Meta:        domain.Meta{Id: "run_0"},
+				WorkflowId:  "wf_1",
+				Status:      domain.WorkflowRunStatusTypeFailed,
+				Trigger:     tc.trigger,
+				Graph:       &domain.WorkflowGraph{Nodes: []*domain.WorkflowNode{{Id: "start", Type: domain.WorkflowNodeTypeStart}, {Id: "deploy", Type: domain.WorkflowNodeTypeBizDeploy}}},
+				ErrorNodeId: "deploy",
+			}
+			runRepo := &mockWorkflowRunRepository{runs: []*domain.WorkflowRun{failedRun}}
+			d := &mockWorkflowDispatcher{}
+			s := &WorkflowService{
+				dispatcher:      d,
+				workflowRepo:    &mockBundleWorkflowRepository{workflows: []*domain.Workflow{{Meta: domain.Meta{Id: "wf_1"}, LastRunId: "run_0", LastRunStatus: domain.WorkflowRunStatusTypeFailed}}},
+				workflowRunRepo: runRepo,
+			}
+
+			resp, err := s.RetryRun(ctx, &dtos.WorkflowRetryRunReq{WorkflowId: "wf_1", RunId: "run_0"})
+			require.NoError(t, err)
+
+			// 重试应沿用原触发方式，并单独记录重试来源
+			retriedRun, err := runRepo.GetById(ctx, resp.RunId)
+			require.NoError(t, err)
+			assert.Equal(t, tc.trigger, retriedRun.Trigger)
+			assert.Equal(t, "run_0", retriedRun.ResumedFromRunId)
+			assert.Equal(t, "deploy", retriedRun.ResumedFromNodeId)
+			assert.Equal(t, []string{resp.RunId}, d.started)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/samber/lo"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+// 将启动运行时传入的变量值转换为运行变量，用于覆盖开始节点中自定义变量的默认值。
+// 仅允许覆盖已定义的非密钥类型的变量，且变量值须符合其类型。
+func resolveRunVariableOverrides(graph *domain.WorkflowGraph, values map[string]string) ([]*domain.WorkflowRunVariable, error) {
+	if graph == nil || len(graph.Nodes) == 0 {
+		return nil, fmt.Errorf("workflow graph content is empty")
+	}
+
+	definitions := graph.Nodes[0].Data.Config.AsStart().Variables
+
+	keys := lo.Keys(values)
+	slices.Sort(keys)
+
+	variables := make([]*domain.WorkflowRunVariable, 0, len(keys))
+	for _, key := range keys {
+		definition, ok := lo.Find(definitions, func(item domain.WorkflowNodeConfigForStartVariable) bool { return item.Key == key })
+		if !ok {
+			return nil, fmt.Errorf("variable '%s' is not defined", key)
+		}
+
+		if err := definition.VerifyValue(values[key]); err != nil {
+			return nil, fmt.Errorf("invalid value of variable '%s': %w", key, err)
+		}
+
+		variables = append(variables, &domain.WorkflowRunVariable{
+			Key:       key,
+			Value:     values[key],
+			ValueType: definition.Type.String(),
+		})
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,120 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/pocketbase/pocketbase/core"
+
+	"github.com/certimate-go/certimate/internal/app"
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+// 判断工作流记录的已发布内容是否发生了变化。
+// 比较时忽略 JSON 的格式差异（如字段顺序、空白符等）。
+func isWorkflowGraphContentChanged(record *core.Record) bool {
+	unmarshal := func(r *core.Record) (any, error) {
+		var v any
+		if raw := r.GetString("graphContent"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &v); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	}
+
+	prevContent, err := unmarshal(record.Original())
+	if err != nil {
+		return true
+	}
+	currContent, err := unmarshal(record)
+	if err != nil {
+		return true
+	}
+
+	return !reflect.DeepEqual(prevContent, currContent)
+}
+
+// 在指定的事务中将工作流记录当前的已发布内容保存为一个新版本。
+func (s *WorkflowService) recordVersion(ctx context.Context, txApp core.App, record *core.Record, author string, comment string) (*domain.WorkflowVersion, error) {
+	if record.GetString("graphContent") == "" {
+		return nil, nil
+	}
+
+	graph := &domain.WorkflowGraph{}
+	if err := record.UnmarshalJSONField("graphContent", &graph); err != nil {
+		return nil, err
+	} else if graph == nil || len(graph.Nodes) == 0 {
+		return nil, nil
+	}
+
+	return s.workflowVersionRepo.SaveWithTx(ctx, txApp, &domain.WorkflowVersion{
+		WorkflowId: record.Id,
+		Graph:      graph,
+		Author:     author,
+		Comment:    comment,
+	})
+}
+
+// 发布工作流图，同时覆盖草稿及已发布内容，并记录一个新版本。
+// 与界面中的发布操作一致地依据开始节点更新触发方式；若工作流尚未保存，将一并创建。
+// 工作流与版本记录在同一事务中保存，定时任务在提交后更新。
+func (s *WorkflowService) publishGraph(ctx context.Context, workflow *domain.Workflow, graph *domain.WorkflowGraph, author string, comment string) (*domain.WorkflowVersion, error) {
+	startConfig := graph.Nodes[0].Data.Config.AsStart()
+	workflow.Trigger = startConfig.Trigger
+	workflow.TriggerCron = startConfig.TriggerCron
+	workflow.GraphDraft = graph.Clone()
+	workflow.GraphContent = graph.Clone()
+	workflow.HasDraft = false
+	workflow.HasContent = true
+	if workflow.Trigger == domain.WorkflowTriggerTypeWebhook {
+		if workflow.WebhookToken == "" {
+			workflow.WebhookToken = generateWebhookToken()
+		}
+		if workflow.WebhookSecret == "" {
+			workflow.WebhookSecret = generateWebhookSecret()
+		}
+	} else {
+		workflow.WebhookToken = ""
+		workflow.WebhookSecret = ""
+	}
+
+	var version *domain.WorkflowVersion
+	err := app.GetApp().RunInTransaction(func(txApp core.App) error {
+		if _, err := s.workflowRepo.SaveWithTx(ctx, txApp, workflow); err != nil {
+			return err
+		}
+
+		var err error
+		version, err = s.workflowVersionRepo.SaveWithTx(ctx, txApp, &domain.WorkflowVersion{
+			WorkflowId: workflow.Id,
+			Graph:      graph.Clone(),
+			Author:     author,
+			Comment:    comment,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !workflow.Enabled || workflow.Trigger != domain.WorkflowTriggerTypeScheduled {
+		app.GetScheduler().Remove(buildPbJobKey(workflow.Id))
+	} else if err := registerWorkflowJob(s, workflow.Id, workflow.TriggerCron); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// 读取发布请求中附带的版本说明。版本说明不属于工作流记录的字段，仅用于写入版本记录。
+func getVersionCommentFromRequest(e *core.RecordRequestEvent) string {
+	info, err := e.RequestInfo()
+	if err != nil || info.Body == nil {
+		return ""
+	}
+
+	comment, _ := info.Body["versionComment"].(string)
+	return comment
+}
@@ -0,0 +1,200 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/security"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+const (
+	// Webhook 请求时间戳允许的最大偏差，超出范围的请求将被视为重放而拒绝。
+	webhookTimestampTolerance = 5 * time.Minute
+	// Webhook 签名的前缀，形如 "sha256=<hex>"。
+	webhookSignaturePrefix = "sha256="
+
+	webhookTokenLength  = 32
+	webhookSecretLength = 48
+)
+
+var errWebhookUnauthorized = domain.NewError(http.StatusUnauthorized, "invalid webhook url")
+
+func generateWebhookToken() string {
+	return security.RandomString(webhookTokenLength)
+}
+
+func generateWebhookSecret() string {
+	return security.RandomString(webhookSecretLength)
+}
+
+// 计算 Webhook 请求签名：HMAC-SHA256(secret, timestamp + "." + payload)，以十六进制表示。
+func signWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifyWebhookSignature(secret string, timestamp string, signature string, payload []byte, now time.Time) error {
+	if secret == "" {
+		return errors.New("webhook secret is not configured")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid webhook timestamp")
+	}
+
+	if delta := now.Sub(time.Unix(unix, 0)); delta > webhookTimestampTolerance || delta < -webhookTimestampTolerance {
+		return errors.New("webhook timestamp is out of tolerance")
+	}
+
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return errors.New("invalid webhook signature")
+	}
+
+	expected := signWebhookPayload(secret, timestamp, payload)
+	actual := strings.ToLower(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if !hmac.Equal([]byte(expected), []byte(actual)) {
+		return errors.New("webhook signature mismatch")
+	}
+
+	return nil
+}
+
+// 已接受的 Webhook 投递，用于拒绝重放的请求。
+// 请求时间戳超出容差范围后即会被签名校验拒绝，因此投递记录只需保留到该时刻。
+type webhookDeliveryStore struct {
+	mtx        sync.Mutex
+	deliveries map[string]time.Time // 值为记录的过期时间
+}
+
+func newWebhookDeliveryStore() *webhookDeliveryStore {
+	return &webhookDeliveryStore{
+		deliveries: make(map[string]time.Time),
+	}
+}
+
+// 登记一次投递。如果该投递已被登记且尚未过期，则返回 false。
+func (s *webhookDeliveryStore) Add(key string, now time.Time) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for k, expires := range s.deliveries {
+		if !now.Before(expires) {
+			delete(s.deliveries, k)
+		}
+	}
+
+	if _, ok := s.deliveries[key]; ok {
+		return false
+	}
+
+	// 通过校验的请求时间戳至多比当前时间晚一个容差，因此其签名至多在两倍容差之后失效
+	s.deliveries[key] = now.Add(2 * webhookTimestampTolerance)
+	return true
+}
+
+func (s *webhookDeliveryStore) Remove(key string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.deliveries, key)
+}
+
+// 按开始节点中配置的映射关系，从 JSON 请求体中提取字段值作为运行变量。
+// 请求体中不存在的字段将被忽略；对象或数组类型的字段值将以 JSON 字符串的形式写入。
+func resolveWebhookPayloadVariables(graph *domain.WorkflowGraph, payload []byte) ([]*domain.WorkflowRunVariable, error) {
+	if graph == nil || len(graph.Nodes) == 0 {
+		return nil, nil
+	}
+
+	mappings := graph.Nodes[0].Data.Config.AsStart().WebhookPayloadMappings
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	var data any
+	if len(bytes.TrimSpace(payload)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(payload))
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return nil, fmt.Errorf("invalid webhook payload: %w", err)
+		}
+	}
+
+	variables := make([]*domain.WorkflowRunVariable, 0, len(mappings))
+	for _, mapping := range mappings {
+		if mapping.Path == "" || mapping.Variable == "" {
+			continue
+		}
+
+		value, ok := lookupWebhookPayloadValue(data, mapping.Path)
+		if !ok || value == nil {
+			continue
+		}
+
+		variable := &domain.WorkflowRunVariable{Key: "trigger." + mapping.Variable}
+		switch tv := value.(type) {
+		case string:
+			variable.Value = tv
+			variable.ValueType = "string"
+		case bool:
+			variable.Value = strconv.FormatBool(tv)
+			variable.ValueType = "boolean"
+		case json.Number:
+			// 运行变量中的数字只支持整数，非整数将以字符串的形式写入
+			if n, err := tv.Int64(); err == nil {
+				variable.Value = strconv.FormatInt(n, 10)
+				variable.ValueType = "number"
+			} else {
+				variable.Value = tv.String()
+				variable.ValueType = "string"
+			}
+		default:
+			raw, _ := json.Marshal(tv)
+			variable.Value = string(raw)
+			variable.ValueType = "string"
+		}
+		variables = append(variables, variable)
+	}
+
+	return variables, nil
+}
+
+func lookupWebhookPayloadValue(data any, path string) (any, bool) {
+	current := data
+	for _, segment := range strings.Split(path, ".") {
+		switch tv := current.(type) {
+		case map[string]any:
+			v, ok := tv[segment]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []any:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(tv) {
+				return nil, false
+			}
+			current = tv[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
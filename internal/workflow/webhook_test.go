@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/certimate-go/certimate/internal/domain"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	payload := []byte(`{"ref":"refs/tags/v1"}`)
+	signature := webhookSignaturePrefix + signWebhookPayload("secret", timestamp, payload)
+
+	testCases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		now       time.Time
+		wantErr   string
+	}{
+		{name: "valid", secret: "secret", timestamp: timestamp, signature: signature, now: now},
+		{name: "valid within tolerance", secret: "secret", timestamp: timestamp, signature: signature, now: now.Add(webhookTimestampTolerance)},
+		{name: "secret not configured", timestamp: timestamp, signature: signature, now: now, wantErr: "not configured"},
+		{name: "invalid timestamp", secret: "secret", timestamp: "abc", signature: signature, now: now, wantErr: "invalid webhook timestamp"},
+		{name: "expired timestamp", secret: "secret", timestamp: timestamp, signature: signature, now: now.Add(webhookTimestampTolerance + time.Second), wantErr: "out of tolerance"},
+		{name: "missing prefix", secret: "secret", timestamp: timestamp, signature: signature[len(webhookSignaturePrefix):], now: now, wantErr: "invalid webhook signature"},
+		{name: "wrong secret", secret: "other", timestamp: timestamp, signature: signature, now: now, wantErr: "mismatch"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifyWebhookSignature(tc.secret, tc.timestamp, tc.signature, payload, tc.now)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := newWebhookDeliveryStore()
+
+	assert.True(t, store.Add("wf:sig1", now))
+	assert.False(t, store.Add("wf:sig1", now.Add(time.Minute)), "replayed delivery should be rejected")
+	assert.True(t, store.Add("wf:sig2", now.Add(time.Minute)))
+
+	// 放弃的投递可以被重试
+	store.Remove("wf:sig2")
+	assert.True(t, store.Add("wf:sig2", now.Add(time.Minute)))
+
+	// 签名失效后，记录会被清理
+	assert.True(t, store.Add("wf:sig3", now.Add(2*webhookTimestampTolerance)))
+	assert.NotContains(t, store.deliveries, "wf:sig1")
+	assert.Contains(t, store.deliveries, "wf:sig2")
+}
+
+func TestResolveWebhookPayloadVariables(t *testing.T) {
+	graph := &domain.WorkflowGraph{
+		Nodes: []*domain.WorkflowNode{
+			{
+				Type: domain.WorkflowNodeTypeStart,
+				Data: domain.WorkflowNodeData{
+					Config: domain.WorkflowNodeConfig{
+						"webhookPayloadMappings": []any{
+							map[string]any{"path": "ref", "variable": "ref"},
+							map[string]any{"path": "commits.0.id", "variable": "commit"},
+							map[string]any{"path": "forced", "variable": "forced"},
+							map[string]any{"path": "size", "variable": "size"},
+							map[string]any{"path": "repository", "variable": "repository"},
+							map[string]any{"path": "missing", "variable": "missing"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	variables, err := resolveWebhookPayloadVariables(graph, []byte(`{"ref":"refs/tags/v1","commits":[{"id":"abc"}],"forced":true,"size":3,"repository":{"name":"certimate"}}`))
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, variable := range variables {
+		got[variable.Key] = variable.ValueType + ":" + variable.Value
+	}
+	assert.Equal(t, map[string]string{
+		"trigger.ref":        "string:refs/tags/v1",
+		"trigger.commit":     "string:abc",
+		"trigger.forced":     "boolean:true",
+		"trigger.size":       "number:3",
+		"trigger.repository": `string:{"name":"certimate"}`,
+	}, got)
+
+	_, err = resolveWebhookPayloadVariables(graph, []byte(`{`))
+	assert.Error(t, err)
+}
@@ -38,6 +38,7 @@ func main() {
 
 	pb.RootCmd.AddCommand(cmd.NewInternalCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewVersionCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewWorkflowCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewWinscCommand(pb))
 
 	isServeCmd := slices.Contains(os.Args[1:], "serve")
@@ -78,6 +79,7 @@ func main() {
 		pb.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
 			if pb.IsBootstrapped() {
 				workflow.Teardown()
+				routes.Teardown()
 			}
 
 			return e.Next()
@@ -0,0 +1,1339 @@
+package migrations
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	m "github.com/pocketbase/pocketbase/migrations"
+
+	snaps "github.com/certimate-go/certimate/migrations/snaps/v0.4"
+)
+
+func init() {
+	m.Register(func(app core.App) error {
+		tracer := NewTracer("v0.5.0")
+		tracer.Printf("go ...")
+
+		// create collection `acme_server_clients`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1579384326",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2825472961",
+							"max": 0,
+							"min": 0,
+							"name": "eabKid",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": true,
+							"id": "text1048753194",
+							"max": 0,
+							"min": 0,
+							"name": "eabHmacKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "json1587448267",
+							"maxSize": 0,
+							"name": "config",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3846031561",
+							"max": 0,
+							"min": 0,
+							"name": "accountKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2440379630",
+							"max": 0,
+							"min": 0,
+							"name": "accountKeyThumbprint",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1164938519",
+							"max": 0,
+							"min": 0,
+							"name": "accountStatus",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "json2031406452",
+							"maxSize": 0,
+							"name": "accountContact",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"hidden": false,
+							"id": "bool1260321794",
+							"name": "enabled",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "bool"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "acmesrvclient01",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_aSc7eAbKid` + "`" + ` ON ` + "`" + `acme_server_clients` + "`" + ` (` + "`" + `eabKid` + "`" + `)",
+						"CREATE INDEX ` + "`" + `idx_aSc7AcKeyTp` + "`" + ` ON ` + "`" + `acme_server_clients` + "`" + ` (` + "`" + `accountKeyThumbprint` + "`" + `)"
+					],
+					"name": "acme_server_clients",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'acme_server_clients' created")
+		}
+
+		// update collection `certificate`
+		//   - modify field `source` schema
+		//   - modify field `privateKey` schema
+		//   - add field `revokedReason`
+		//   - add field `revokedAt`
+		//   - add field `ariRenewalInfo`
+		//   - add field `acmeServerClientRef`
+		{
+			collection, err := app.FindCollectionByNameOrId("4szxr9x43tpj6np")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(1, []byte(`{
+				"hidden": false,
+				"id": "by9hetqi",
+				"maxSelect": 1,
+				"name": "source",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"request",
+					"upload",
+					"acmeserver"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"autogeneratePattern": "",
+				"help": "",
+				"hidden": false,
+				"id": "49qvwxcg",
+				"max": 100000,
+				"min": 0,
+				"name": "privateKey",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(20, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text3316428453",
+				"max": 0,
+				"min": 0,
+				"name": "revokedReason",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(21, []byte(`{
+				"hidden": false,
+				"id": "date1916315346",
+				"max": "",
+				"min": "",
+				"name": "revokedAt",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "date"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(22, []byte(`{
+				"hidden": false,
+				"id": "json2719508390",
+				"maxSize": 0,
+				"name": "ariRenewalInfo",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(23, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "acmesrvclient01",
+				"hidden": false,
+				"id": "relation3129545637",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "acmeServerClientRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `workflow`
+		//   - modify field `trigger` schema
+		//   - modify field `lastRunStatus` schema
+		//   - add field `webhookToken`
+		//   - add field `webhookSecret`
+		{
+			collection, err := app.FindCollectionByNameOrId("tovyif5ax6j62ur")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "vqoajwjq",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"webhook",
+					"event"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(11, []byte(`{
+				"hidden": false,
+				"id": "zivdxh23",
+				"maxSelect": 1,
+				"name": "lastRunStatus",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"pending",
+					"processing",
+					"waiting",
+					"succeeded",
+					"failed",
+					"canceled"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(5, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text1640297925",
+				"max": 0,
+				"min": 0,
+				"name": "webhookToken",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text3425861507",
+				"max": 0,
+				"min": 0,
+				"name": "webhookSecret",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `workflow_run`
+		//   - modify field `status` schema
+		//   - modify field `trigger` schema
+		//   - add field `errorNodeId`
+		//   - add field `resumedFromRunRef`
+		//   - add field `resumedFromNodeId`
+		//   - add field `variables`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(2, []byte(`{
+				"hidden": false,
+				"id": "qldmh0tw",
+				"maxSelect": 1,
+				"name": "status",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"pending",
+					"processing",
+					"waiting",
+					"succeeded",
+					"failed",
+					"canceled"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(3, []byte(`{
+				"hidden": false,
+				"id": "jlroa3fk",
+				"maxSelect": 1,
+				"name": "trigger",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "select",
+				"values": [
+					"manual",
+					"scheduled",
+					"dryrun",
+					"webhook",
+					"event"
+				]
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(8, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text2454628719",
+				"max": 0,
+				"min": 0,
+				"name": "errorNodeId",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(9, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "qjp8lygssgwyqyz",
+				"hidden": false,
+				"id": "relation1788245623",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "resumedFromRunRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(10, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": false,
+				"id": "text3972551540",
+				"max": 0,
+				"min": 0,
+				"name": "resumedFromNodeId",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(11, []byte(`{
+				"hidden": false,
+				"id": "json2253036410",
+				"maxSize": 0,
+				"name": "variables",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `workflow_output`
+		//   - add field `variables`
+		{
+			collection, err := app.FindCollectionByNameOrId("bqnxb95f2cooowp")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(6, []byte(`{
+				"hidden": false,
+				"id": "json3813726539",
+				"maxSize": 0,
+				"name": "variables",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "json"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `private_ca`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1579384326",
+							"max": 0,
+							"min": 0,
+							"name": "name",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2207624015",
+							"max": 0,
+							"min": 0,
+							"name": "rootCertificate",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": true,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3408913416",
+							"max": 0,
+							"min": 0,
+							"name": "rootPrivateKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1872009285",
+							"max": 0,
+							"min": 0,
+							"name": "intermediateCertificate",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2513394826",
+							"max": 0,
+							"min": 0,
+							"name": "intermediatePrivateKey",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "pvtca8r3k2m7x1q",
+					"indexes": [],
+					"name": "private_ca",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'private_ca' created")
+		}
+
+		// create collection `workflow_version`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "tovyif5ax6j62ur",
+							"hidden": false,
+							"id": "relation3371272342",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "workflowRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"hidden": false,
+							"id": "number1186117826",
+							"max": null,
+							"min": 1,
+							"name": "version",
+							"onlyInt": true,
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "number"
+						},
+						{
+							"hidden": false,
+							"id": "json772177811",
+							"maxSize": 5000000,
+							"name": "graph",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3182418120",
+							"max": 0,
+							"min": 0,
+							"name": "author",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3485334036",
+							"max": 0,
+							"min": 0,
+							"name": "comment",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "wfversion5k2m8q",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Wv4kQz8TmR` + "`" + ` ON ` + "`" + `workflow_version` + "`" + ` (` + "`" + `workflowRef` + "`" + `, ` + "`" + `version` + "`" + `)"
+					],
+					"name": "workflow_version",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'workflow_version' created")
+		}
+
+		// update collection `workflow_run`
+		//   - add field `versionRef`
+		{
+			collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(12, []byte(`{
+				"cascadeDelete": false,
+				"collectionId": "wfversion5k2m8q",
+				"hidden": false,
+				"id": "relation2428310442",
+				"maxSelect": 1,
+				"minSelect": 0,
+				"name": "versionRef",
+				"presentable": false,
+				"required": false,
+				"system": false,
+				"type": "relation"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// update collection `acme_accounts`
+		//   - add field `pendingPrivateKey`
+		{
+			collection, err := app.FindCollectionByNameOrId("012d7abbod1hwvr")
+			if err != nil {
+				return err
+			}
+
+			if err := collection.Fields.AddMarshaledJSONAt(4, []byte(`{
+				"autogeneratePattern": "",
+				"hidden": true,
+				"id": "text2207437390",
+				"max": 100000,
+				"min": 0,
+				"name": "pendingPrivateKey",
+				"pattern": "",
+				"presentable": false,
+				"primaryKey": false,
+				"required": false,
+				"system": false,
+				"type": "text"
+			}`)); err != nil {
+				return err
+			}
+
+			if err := app.Save(collection); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection '%s' updated", collection.Name)
+		}
+
+		// create collection `workflow_approval`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "tovyif5ax6j62ur",
+							"hidden": false,
+							"id": "relation3371272342",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "workflowRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "qjp8lygssgwyqyz",
+							"hidden": false,
+							"id": "relation821863227",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "runRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text157423495",
+							"max": 0,
+							"min": 0,
+							"name": "nodeId",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2063623452",
+							"max": 0,
+							"min": 0,
+							"name": "status",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date2341893206",
+							"max": "",
+							"min": "",
+							"name": "expiresAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3427140620",
+							"max": 0,
+							"min": 0,
+							"name": "operator",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text3485334036",
+							"max": 0,
+							"min": 0,
+							"name": "comment",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date1093427415",
+							"max": "",
+							"min": "",
+							"name": "decidedAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "wfapproval8n3q1",
+					"indexes": [
+						"CREATE UNIQUE INDEX ` + "`" + `idx_Ap7nR2kVxQ` + "`" + ` ON ` + "`" + `workflow_approval` + "`" + ` (` + "`" + `runRef` + "`" + `, ` + "`" + `nodeId` + "`" + `)",
+						"CREATE INDEX ` + "`" + `idx_Ap3mW8sLzT` + "`" + ` ON ` + "`" + `workflow_approval` + "`" + ` (` + "`" + `status` + "`" + `)"
+					],
+					"name": "workflow_approval",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'workflow_approval' created")
+		}
+
+		// create collection `acme_server_orders`
+		{
+			jsonData := `[
+				{
+					"fields": [
+						{
+							"autogeneratePattern": "[a-z0-9]{15}",
+							"hidden": false,
+							"id": "text3208210256",
+							"max": 15,
+							"min": 15,
+							"name": "id",
+							"pattern": "^[a-z0-9]+$",
+							"presentable": false,
+							"primaryKey": true,
+							"required": true,
+							"system": true,
+							"type": "text"
+						},
+						{
+							"cascadeDelete": true,
+							"collectionId": "acmesrvclient01",
+							"hidden": false,
+							"id": "relation2637380612",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "clientRef",
+							"presentable": false,
+							"required": true,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text2063623452",
+							"max": 0,
+							"min": 0,
+							"name": "status",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "date2341893206",
+							"max": "",
+							"min": "",
+							"name": "expiresAt",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "date"
+						},
+						{
+							"hidden": false,
+							"id": "json3866390105",
+							"maxSize": 0,
+							"name": "identifiers",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"hidden": false,
+							"id": "json1407361782",
+							"maxSize": 0,
+							"name": "authorizations",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "json"
+						},
+						{
+							"cascadeDelete": false,
+							"collectionId": "4szxr9x43tpj6np",
+							"hidden": false,
+							"id": "relation2196264538",
+							"maxSelect": 1,
+							"minSelect": 0,
+							"name": "certificateRef",
+							"presentable": false,
+							"required": false,
+							"system": false,
+							"type": "relation"
+						},
+						{
+							"autogeneratePattern": "",
+							"hidden": false,
+							"id": "text1574812785",
+							"max": 0,
+							"min": 0,
+							"name": "error",
+							"pattern": "",
+							"presentable": false,
+							"primaryKey": false,
+							"required": false,
+							"system": false,
+							"type": "text"
+						},
+						{
+							"hidden": false,
+							"id": "autodate2990389176",
+							"name": "created",
+							"onCreate": true,
+							"onUpdate": false,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						},
+						{
+							"hidden": false,
+							"id": "autodate3332085495",
+							"name": "updated",
+							"onCreate": true,
+							"onUpdate": true,
+							"presentable": false,
+							"system": false,
+							"type": "autodate"
+						}
+					],
+					"id": "acmesrvorder001",
+					"indexes": [
+						"CREATE INDEX ` + "`" + `idx_aSo4ClntRf` + "`" + ` ON ` + "`" + `acme_server_orders` + "`" + ` (` + "`" + `clientRef` + "`" + `)",
+						"CREATE INDEX ` + "`" + `idx_aSo9ExpAt` + "`" + ` ON ` + "`" + `acme_server_orders` + "`" + ` (` + "`" + `expiresAt` + "`" + `)"
+					],
+					"name": "acme_server_orders",
+					"system": false,
+					"type": "base"
+				}
+			]`
+
+			if err := app.ImportCollectionsByMarshaledJSON([]byte(jsonData), false); err != nil {
+				return err
+			}
+
+			tracer.Printf("collection 'acme_server_orders' created")
+		}
+
+		// adapt to new template engine
+		//   - escape literal "{{" in deployment provider configs, which were never rendered as templates before
+		//   - escape literal "{{" in notification subjects and messages, except for legacy placeholders like "{{ $key }}"
+		{
+			walker := &snaps.WorkflowGraphWalker{}
+			walker.Define(func(node *snaps.WorkflowNode) (_changed bool, _err error) {
+				_changed = false
+				_err = nil
+
+				nodeCfg := node.Data.Config
+				if nodeCfg == nil {
+					return
+				}
+
+				switch node.Type {
+				case "bizDeploy":
+					{
+						if providerCfg, ok := nodeCfg["providerConfig"].(map[string]any); ok {
+							if escaped, changed := escapeLiteralTemplateValue(providerCfg); changed {
+								nodeCfg["providerConfig"] = escaped
+								_changed = true
+							}
+						}
+					}
+
+				case "bizNotify":
+					{
+						for _, key := range []string{"subject", "message"} {
+							if text, ok := nodeCfg[key].(string); ok {
+								if escaped := escapeLiteralTemplateText(text, true); escaped != text {
+									nodeCfg[key] = escaped
+									_changed = true
+								}
+							}
+						}
+					}
+				}
+
+				return
+			})
+
+			// update collection `workflow`
+			//   - migrate field `graphDraft` / `graphContent`
+			{
+				collection, err := app.FindCollectionByNameOrId("tovyif5ax6j62ur")
+				if err != nil {
+					return err
+				}
+
+				records, err := app.FindAllRecords(collection)
+				if err != nil {
+					return err
+				}
+
+				for _, record := range records {
+					changed := false
+
+					if ret, err := walker.Migrate(record, "graphDraft"); err != nil {
+						return err
+					} else {
+						changed = changed || ret
+					}
+
+					if ret, err := walker.Migrate(record, "graphContent"); err != nil {
+						return err
+					} else {
+						changed = changed || ret
+					}
+
+					if changed {
+						if err := app.Save(record); err != nil {
+							return err
+						}
+
+						tracer.Printf("record #%s in collection '%s' updated", record.Id, collection.Name)
+					}
+				}
+			}
+
+			// update collection `workflow_run`
+			//   - migrate field `graph`
+			{
+				collection, err := app.FindCollectionByNameOrId("qjp8lygssgwyqyz")
+				if err != nil {
+					return err
+				}
+
+				records, err := app.FindAllRecords(collection)
+				if err != nil {
+					return err
+				}
+
+				for _, record := range records {
+					changed := false
+
+					if ret, err := walker.Migrate(record, "graph"); err != nil {
+						return err
+					} else {
+						changed = changed || ret
+					}
+
+					if changed {
+						if err := app.Save(record); err != nil {
+							return err
+						}
+
+						tracer.Printf("record #%s in collection '%s' updated", record.Id, collection.Name)
+					}
+				}
+			}
+		}
+
+		tracer.Printf("done")
+		return nil
+	}, func(app core.App) error {
+		return errors.ErrUnsupported
+	})
+}
+
+var reLegacyTemplateMustache = regexp.MustCompile(`\{\{\s*\$([^\s{}]+)\s*\}\}`)
+
+// escapeLiteralTemplateText escapes every literal "{{" in the text as `{{ "{{" }}`,
+// so that it renders to itself with the new template engine.
+// If keepLegacyMustache is true, legacy placeholders like "{{ $key }}" are kept as is.
+func escapeLiteralTemplateText(text string, keepLegacyMustache bool) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "{{", `{{ "{{" }}`)
+	}
+
+	if !keepLegacyMustache {
+		return escape(text)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range reLegacyTemplateMustache.FindAllStringIndex(text, -1) {
+		sb.WriteString(escape(text[last:loc[0]]))
+		sb.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sb.WriteString(escape(text[last:]))
+	return sb.String()
+}
+
+func escapeLiteralTemplateValue(value any) (_escaped any, _changed bool) {
+	switch v := value.(type) {
+	case string:
+		escaped := escapeLiteralTemplateText(v, false)
+		return escaped, escaped != v
+
+	case map[string]any:
+		changed := false
+		for key, item := range v {
+			if escaped, itemChanged := escapeLiteralTemplateValue(item); itemChanged {
+				v[key] = escaped
+				changed = true
+			}
+		}
+		return v, changed
+
+	case []any:
+		changed := false
+		for i, item := range v {
+			if escaped, itemChanged := escapeLiteralTemplateValue(item); itemChanged {
+				v[i] = escaped
+				changed = true
+			}
+		}
+		return v, changed
+	}
+
+	return value, false
+}
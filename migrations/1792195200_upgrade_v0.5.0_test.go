@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLiteralTemplateText(t *testing.T) {
+	testCases := []struct {
+		name               string
+		text               string
+		keepLegacyMustache bool
+		want               string
+	}{
+		{name: "plain text", text: "nginx -s reload", want: "nginx -s reload"},
+		{name: "docker format", text: "docker ps --format '{{.Names}}'", want: `docker ps --format '{{ "{{" }}.Names}}'`},
+		{name: "json body", text: `{"text": "{{ x }}"}`, want: `{"text": "{{ "{{" }} x }}"}`},
+		{name: "legacy placeholder", text: "{{ $certificate.commonName }} {{.Names}}", keepLegacyMustache: true, want: `{{ $certificate.commonName }} {{ "{{" }}.Names}}`},
+		{name: "legacy placeholder not kept", text: "{{ $certificate.commonName }}", want: `{{ "{{" }} $certificate.commonName }}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := escapeLiteralTemplateText(tc.text, tc.keepLegacyMustache)
+			assert.Equal(t, tc.want, got)
+
+			if !tc.keepLegacyMustache {
+				// 转义后的文本经模板渲染后应与原文一致，以确保旧版的命令仍可正常执行
+				tmpl, err := template.New("").Parse(got)
+				require.NoError(t, err)
+
+				var sb strings.Builder
+				require.NoError(t, tmpl.Execute(&sb, nil))
+				assert.Equal(t, tc.text, sb.String())
+			}
+		})
+	}
+}
+
+func TestEscapeLiteralTemplateValue(t *testing.T) {
+	providerCfg := map[string]any{
+		"postCommand": "docker ps --format '{{.Names}}'",
+		"headers":     []any{"X-Template: {{ y }}", 1},
+		"port":        22,
+	}
+
+	escaped, changed := escapeLiteralTemplateValue(providerCfg)
+	assert.True(t, changed)
+	assert.Equal(t, `docker ps --format '{{ "{{" }}.Names}}'`, escaped.(map[string]any)["postCommand"])
+	assert.Equal(t, []any{`X-Template: {{ "{{" }} y }}`, 1}, escaped.(map[string]any)["headers"])
+
+	_, changed = escapeLiteralTemplateValue(map[string]any{"postCommand": "nginx -s reload"})
+	assert.False(t, changed)
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/dns01"
+	"github.com/go-acme/lego/v5/log"
+)
+
+var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+
+type Config struct {
+	// 发布需要人工创建的 TXT 记录。
+	PublishFunc func(ctx context.Context, domain, fqdn, value string) error
+	// 阻塞直到人工确认 TXT 记录已创建，或超时。
+	ConfirmFunc func(ctx context.Context, timeout time.Duration) error
+
+	ConfirmationTimeout time.Duration
+	PropagationTimeout  time.Duration
+	PollingInterval     time.Duration
+}
+
+type DNSProvider struct {
+	config *Config
+
+	confirmOnce sync.Once
+	confirmErr  error
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		ConfirmationTimeout: 1 * time.Hour,
+		PropagationTimeout:  dns01.DefaultPropagationTimeout,
+		PollingInterval:     dns01.DefaultPollingInterval,
+	}
+}
+
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("manual: the configuration of the DNS provider is nil")
+	}
+
+	if config.PublishFunc == nil || config.ConfirmFunc == nil {
+		return nil, fmt.Errorf("manual: the interaction is not supported in current context")
+	}
+
+	return &DNSProvider{
+		config: config,
+	}, nil
+}
+
+func (d *DNSProvider) Present(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(ctx, domain, keyAuth)
+
+	// 记录的名称及值由 PublishFunc 负责展示，此处不再重复输出
+	if err := d.config.PublishFunc(ctx, domain, info.EffectiveFQDN, info.Value); err != nil {
+		return fmt.Errorf("manual: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DNSProvider) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(ctx, domain, keyAuth)
+
+	log.Info("manual: the TXT record can be removed now", log.DomainAttr(domain), slog.String("fqdn", info.EffectiveFQDN))
+
+	return nil
+}
+
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.ConfirmationTimeout + d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// WaitForConfirmation 阻塞直到人工确认所有 TXT 记录均已创建。
+// 一次证书申请中只会等待一次，后续调用将直接返回首次调用的结果。
+func (d *DNSProvider) WaitForConfirmation(ctx context.Context) error {
+	d.confirmOnce.Do(func() {
+		log.Info("manual: waiting for confirmation ...", slog.Duration("timeout", d.config.ConfirmationTimeout))
+
+		if err := d.config.ConfirmFunc(ctx, d.config.ConfirmationTimeout); err != nil {
+			d.confirmErr = fmt.Errorf("manual: %w", err)
+			return
+		}
+
+		log.Info("manual: confirmed")
+	})
+
+	return d.confirmErr
+}
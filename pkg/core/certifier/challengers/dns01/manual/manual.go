@@ -0,0 +1,44 @@
+package manual
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/certimate-go/certimate/pkg/core"
+	"github.com/certimate-go/certimate/pkg/core/certifier/challengers/dns01/manual/internal"
+)
+
+type ChallengerConfig struct {
+	// 发布需要人工创建的 TXT 记录的回调函数。
+	PublishFunc func(ctx context.Context, domain, fqdn, value string) error `json:"-"`
+	// 等待人工确认的回调函数。
+	ConfirmFunc func(ctx context.Context, timeout time.Duration) error `json:"-"`
+	// 等待人工确认的超时时间（单位：秒）。
+	// 零值时默认值 3600。
+	ConfirmationTimeout   int `json:"confirmationTimeout,omitempty"`
+	DnsPropagationTimeout int `json:"dnsPropagationTimeout,omitempty"`
+}
+
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	providerConfig := internal.NewDefaultConfig()
+	providerConfig.PublishFunc = config.PublishFunc
+	providerConfig.ConfirmFunc = config.ConfirmFunc
+	if config.ConfirmationTimeout != 0 {
+		providerConfig.ConfirmationTimeout = time.Duration(config.ConfirmationTimeout) * time.Second
+	}
+	if config.DnsPropagationTimeout != 0 {
+		providerConfig.PropagationTimeout = time.Duration(config.DnsPropagationTimeout) * time.Second
+	}
+
+	provider, err := internal.NewDNSProviderConfig(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,37 @@
+package local
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+
+	"github.com/certimate-go/certimate/pkg/core"
+)
+
+type ChallengerConfig struct {
+	// 监听的网络接口地址。
+	// 零值时监听所有网络接口。
+	ListenInterface string `json:"listenInterface,omitempty"`
+	// 监听的端口。
+	// 零值时默认值 443。
+	ListenPort int32 `json:"listenPort,omitempty"`
+}
+
+func NewChallenger(config *ChallengerConfig) (core.ACMEChallenger, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	if config.ListenPort < 0 || config.ListenPort > 65535 {
+		return nil, fmt.Errorf("local: invalid listen port: %d", config.ListenPort)
+	}
+
+	port := ""
+	if config.ListenPort != 0 {
+		port = strconv.Itoa(int(config.ListenPort))
+	}
+
+	provider := tlsalpn01.NewProviderServer(config.ListenInterface, port)
+	return provider, nil
+}
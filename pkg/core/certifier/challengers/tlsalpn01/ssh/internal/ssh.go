@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/go-acme/lego/v5/challenge"
+	"github.com/go-acme/lego/v5/challenge/tlsalpn01"
+	"github.com/go-acme/lego/v5/log"
+
+	"github.com/certimate-go/certimate/internal/tools/ssh"
+	xfilepath "github.com/certimate-go/certimate/pkg/utils/filepath"
+	xssh "github.com/certimate-go/certimate/pkg/utils/ssh"
+)
+
+var _ challenge.Provider = (*TLSALPNProvider)(nil)
+
+type Config struct {
+	ssh.Config
+
+	UseSCP        bool
+	CertDirPath   string
+	ReloadCommand string
+}
+
+func NewDefaultConfig() *Config {
+	defaultCfg := ssh.NewDefaultConfig()
+
+	return &Config{
+		Config: *defaultCfg,
+		UseSCP: false,
+	}
+}
+
+type TLSALPNProvider struct {
+	config *Config
+}
+
+func NewTLSALPNProviderConfig(config *Config) (*TLSALPNProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("the configuration of the acme challenge provider is nil")
+	}
+
+	if config.CertDirPath == "" {
+		return nil, fmt.Errorf("ssh: certificate directory path must be set")
+	}
+
+	return &TLSALPNProvider{
+		config: config,
+	}, nil
+}
+
+func (p *TLSALPNProvider) Present(ctx context.Context, domain, token, keyAuth string) error {
+	certPEM, keyPEM, err := tlsalpn01.ChallengeBlocks(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("ssh: failed to generate certificate for TLS-ALPN challenge: %w", err)
+	}
+
+	client, err := p.createSshClient()
+	if err != nil {
+		return fmt.Errorf("ssh: failed to create SSH client: %w", err)
+	}
+
+	log.Info("ssh: ssh connected")
+	defer func() {
+		client.Close()
+		log.Info("ssh: ssh closed")
+	}()
+
+	certPath, keyPath := p.challengeFilePaths(domain)
+	if err := xssh.WriteRemoteString(client.RawClient(), keyPath, string(keyPEM), p.config.UseSCP); err != nil {
+		return fmt.Errorf("ssh: failed to write private key file for TLS-ALPN challenge: %w", err)
+	}
+	if err := xssh.WriteRemoteString(client.RawClient(), certPath, string(certPEM), p.config.UseSCP); err != nil {
+		return fmt.Errorf("ssh: failed to write certificate file for TLS-ALPN challenge: %w", err)
+	}
+
+	log.Info("ssh: challenge certificate uploaded", slog.String("path", certPath))
+
+	if err := p.runReloadCommand(client, domain); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *TLSALPNProvider) CleanUp(ctx context.Context, domain, token, keyAuth string) error {
+	client, err := p.createSshClient()
+	if err != nil {
+		return fmt.Errorf("ssh: failed to create SSH client: %w", err)
+	}
+
+	log.Info("ssh: ssh connected")
+	defer func() {
+		client.Close()
+		log.Info("ssh: ssh closed")
+	}()
+
+	// 删除质询证书文件
+	certPath, keyPath := p.challengeFilePaths(domain)
+	if err := xssh.RemoveRemote(client.RawClient(), certPath, p.config.UseSCP); err != nil {
+		return fmt.Errorf("ssh: failed to remove certificate file after TLS-ALPN challenge: %w", err)
+	}
+	if err := xssh.RemoveRemote(client.RawClient(), keyPath, p.config.UseSCP); err != nil {
+		return fmt.Errorf("ssh: failed to remove private key file after TLS-ALPN challenge: %w", err)
+	}
+
+	log.Info("ssh: challenge certificate removed", slog.String("path", certPath))
+
+	if err := p.runReloadCommand(client, domain); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *TLSALPNProvider) challengeFilePaths(domain string) (string, string) {
+	filename := strings.ReplaceAll(domain, "*", "_")
+	certPath := xfilepath.Join(p.config.CertDirPath, filename+".crt")
+	keyPath := xfilepath.Join(p.config.CertDirPath, filename+".key")
+	return certPath, keyPath
+}
+
+func (p *TLSALPNProvider) runReloadCommand(client *ssh.Client, domain string) error {
+	if p.config.ReloadCommand == "" {
+		return nil
+	}
+
+	command := p.buildReloadCommand(domain)
+	stdout, stderr, err := xssh.RunCommand(client.RawClient(), command)
+	log.Debug("ssh: run reload command", slog.String("stdout", stdout), slog.String("stderr", stderr))
+	if err != nil {
+		return fmt.Errorf("ssh: failed to execute reload command (stdout: %s, stderr: %s): %w", stdout, stderr, err)
+	}
+
+	return nil
+}
+
+// 替换重载命令中的变量。变量值均已转义为单个 Shell 参数，命令中无需再加引号。
+func (p *TLSALPNProvider) buildReloadCommand(domain string) string {
+	certPath, keyPath := p.challengeFilePaths(domain)
+	command := p.config.ReloadCommand
+	command = strings.ReplaceAll(command, "${CERTIMATE_CHALLENGER_CMDVAR_DOMAIN}", shellQuote(domain))
+	command = strings.ReplaceAll(command, "${CERTIMATE_CHALLENGER_CMDVAR_CERTIFICATE_PATH}", shellQuote(certPath))
+	command = strings.ReplaceAll(command, "${CERTIMATE_CHALLENGER_CMDVAR_PRIVATEKEY_PATH}", shellQuote(keyPath))
+	return command
+}
+
+func (p *TLSALPNProvider) createSshClient() (*ssh.Client, error) {
+	clientCfg := ssh.NewDefaultConfig()
+	clientCfg.Host = p.config.Host
+	clientCfg.Port = p.config.Port
+	clientCfg.AuthMethod = ssh.AuthMethodType(p.config.AuthMethod)
+	clientCfg.Username = p.config.Username
+	clientCfg.Password = p.config.Password
+	clientCfg.Key = p.config.Key
+	clientCfg.KeyPassphrase = p.config.KeyPassphrase
+	for _, jumpServer := range p.config.JumpServers {
+		jumpServerCfg := ssh.NewServerConfig()
+		jumpServerCfg.Host = jumpServer.Host
+		jumpServerCfg.Port = jumpServer.Port
+		jumpServerCfg.AuthMethod = ssh.AuthMethodType(jumpServer.AuthMethod)
+		jumpServerCfg.Username = jumpServer.Username
+		jumpServerCfg.Password = jumpServer.Password
+		jumpServerCfg.Key = jumpServer.Key
+		jumpServerCfg.KeyPassphrase = jumpServer.KeyPassphrase
+		clientCfg.JumpServers = append(clientCfg.JumpServers, *jumpServerCfg)
+	}
+
+	client, err := ssh.NewClient(clientCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
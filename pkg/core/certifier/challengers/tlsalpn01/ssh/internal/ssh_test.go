@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSALPNProvider_BuildReloadCommand(t *testing.T) {
+	provider := &TLSALPNProvider{
+		config: &Config{
+			CertDirPath:   "/etc/certs",
+			ReloadCommand: "echo ${CERTIMATE_CHALLENGER_CMDVAR_DOMAIN} && cat ${CERTIMATE_CHALLENGER_CMDVAR_CERTIFICATE_PATH} ${CERTIMATE_CHALLENGER_CMDVAR_PRIVATEKEY_PATH}",
+		},
+	}
+
+	assert.Equal(t,
+		"echo 'example.com' && cat '/etc/certs/example.com.crt' '/etc/certs/example.com.key'",
+		provider.buildReloadCommand("example.com"),
+	)
+	assert.Equal(t,
+		`echo 'a.com;rm -rf /'\''' && cat '/etc/certs/a.com;rm -rf /'\''.crt' '/etc/certs/a.com;rm -rf /'\''.key'`,
+		provider.buildReloadCommand("a.com;rm -rf /'"),
+	)
+}
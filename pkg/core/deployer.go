@@ -21,6 +21,19 @@ type Deployer interface {
 	Deploy(ctx context.Context, certPEM, privkeyPEM string) (_res *DeployerDeployResult, _err error)
 }
 
+// 表示支持校验部署配置的 SSL 证书部署器的抽象类型接口。
+// 可选实现，试运行时将以此代替实际部署。
+type DeployerValidator interface {
+	// 校验授权凭据是否有效、部署目标是否存在，不会产生任何变更。
+	//
+	// 入参：
+	//   - ctx：上下文。
+	//
+	// 出参：
+	//   - err: 错误。
+	Validate(ctx context.Context) (_err error)
+}
+
 // 表示 SSL 证书部署结果的数据结构。
 type DeployerDeployResult struct {
 	ExtendedData map[string]any `json:"extendedData,omitempty"`
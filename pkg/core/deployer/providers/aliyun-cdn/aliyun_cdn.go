@@ -49,7 +49,10 @@ type Deployer struct {
 	sdkCertmgr core.Certmgr
 }
 
-var _ Provider = (*Deployer)(nil)
+var (
+	_ Provider               = (*Deployer)(nil)
+	_ core.DeployerValidator = (*Deployer)(nil)
+)
 
 func NewDeployer(config *DeployerConfig) (*Deployer, error) {
 	if config == nil {
@@ -174,6 +177,55 @@ func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*Dep
 	return &DeployResult{}, nil
 }
 
+func (d *Deployer) Validate(ctx context.Context) error {
+	// 查询域名列表，同时校验授权凭据
+	domainCandidates, err := d.getAllDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 检查待部署的域名是否存在
+	switch d.config.DomainMatchPattern {
+	case "", DOMAIN_MATCH_PATTERN_EXACT:
+		{
+			domain := normalizeDomain(d.config.Domain)
+			if domain == "" {
+				return fmt.Errorf("config `domain` is required")
+			}
+
+			if !lo.Contains(domainCandidates, domain) {
+				return fmt.Errorf("could not find cdn domain '%s'", domain)
+			}
+		}
+
+	case DOMAIN_MATCH_PATTERN_WILDCARD:
+		{
+			if d.config.Domain == "" {
+				return fmt.Errorf("config `domain` is required")
+			}
+
+			matched := lo.ContainsBy(domainCandidates, func(domain string) bool {
+				if strings.HasPrefix(d.config.Domain, "*.") {
+					return xcerthostname.IsMatch(d.config.Domain, domain)
+				}
+				return domain == d.config.Domain
+			})
+			if !matched {
+				return fmt.Errorf("could not find any domains matched by wildcard")
+			}
+		}
+
+	case DOMAIN_MATCH_PATTERN_CERTSAN:
+		d.logger.Info("the domains will be matched by certificate while deploying, skip checking")
+
+	default:
+		return fmt.Errorf("unsupported domain match pattern: '%s'", d.config.DomainMatchPattern)
+	}
+
+	d.logger.Info("cdn domains validated")
+	return nil
+}
+
 func (d *Deployer) getAllDomains(ctx context.Context) ([]string, error) {
 	domains := make([]string, 0)
 
@@ -5,7 +5,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -62,7 +64,10 @@ type Deployer struct {
 	logger *slog.Logger
 }
 
-var _ Provider = (*Deployer)(nil)
+var (
+	_ Provider               = (*Deployer)(nil)
+	_ core.DeployerValidator = (*Deployer)(nil)
+)
 
 func NewDeployer(config *DeployerConfig) (*Deployer, error) {
 	if config == nil {
@@ -219,6 +224,107 @@ func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*Dep
 	return &DeployResult{}, nil
 }
 
+func (d *Deployer) Validate(ctx context.Context) error {
+	// 检查 Shell 执行环境
+	switch d.config.ShellEnv {
+	case "", SHELL_ENV_SH, SHELL_ENV_CMD, SHELL_ENV_POWERSHELL:
+	default:
+		return fmt.Errorf("unsupported shell env '%s'", d.config.ShellEnv)
+	}
+
+	// 检查证书格式所需的配置项
+	switch d.config.FileFormat {
+	case FILE_FORMAT_PEM:
+		if d.config.FilePathForKey == "" && d.config.FilePathForCrt == "" && d.config.FilePathForCrtOnlyServer == "" && d.config.FilePathForCrtOnlyIntermedia == "" {
+			return fmt.Errorf("at least one of the output file paths is required")
+		}
+
+	case FILE_FORMAT_PFX:
+		if d.config.PfxPassword == "" {
+			return fmt.Errorf("config `pfxPassword` is required")
+		}
+		if _, err := xcertpfx.ResolvePfxEncoder(d.config.PfxEncoder); err != nil {
+			return fmt.Errorf("config `pfxEncoder` is invalid: %w", err)
+		}
+
+	case FILE_FORMAT_JKS:
+		if d.config.JksAlias == "" {
+			return fmt.Errorf("config `jksAlias` is required")
+		}
+		if d.config.JksKeypass == "" {
+			return fmt.Errorf("config `jksKeypass` is required")
+		}
+		if d.config.JksStorepass == "" {
+			return fmt.Errorf("config `jksStorepass` is required")
+		}
+
+	default:
+		return fmt.Errorf("unsupported file format '%s'", d.config.FileFormat)
+	}
+
+	// 检查输出文件所在目录是否存在且可写
+	for _, path := range []string{d.config.FilePathForKey, d.config.FilePathForCrt, d.config.FilePathForCrtOnlyServer, d.config.FilePathForCrtOnlyIntermedia} {
+		if path == "" {
+			continue
+		}
+
+		if err := checkOutputPathWritable(path); err != nil {
+			return err
+		}
+		d.logger.Info("output path is writable", slog.String("path", path))
+	}
+
+	return nil
+}
+
+func checkOutputPathWritable(path string) error {
+	// 文件已存在时，需可以覆盖写入
+	if fi, err := os.Stat(path); err == nil {
+		if fi.IsDir() {
+			return fmt.Errorf("output path '%s' is a directory", path)
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("output file '%s' is not writable: %w", path, err)
+		}
+		file.Close()
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat output path '%s': %w", path, err)
+	}
+
+	// 目录不存在时，部署时会逐级创建，因此向上查找最近的已存在的目录
+	dir := filepath.Dir(path)
+	for {
+		fi, err := os.Stat(dir)
+		if err == nil {
+			if !fi.IsDir() {
+				return fmt.Errorf("output directory '%s' is not a directory", dir)
+			}
+			break
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to stat output directory '%s': %w", dir, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("output directory '%s' does not exist", dir)
+		}
+		dir = parent
+	}
+
+	// 通过创建临时文件来检查目录是否可写
+	file, err := os.CreateTemp(dir, ".certimate-*")
+	if err != nil {
+		return fmt.Errorf("output directory '%s' is not writable: %w", dir, err)
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	return nil
+}
+
 func execCommand(shellEnv string, command string) (string, string, error) {
 	var cmd *exec.Cmd
 
@@ -1,6 +1,9 @@
 package local_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -99,3 +102,82 @@ func TestProvider(t *testing.T) {
 		it.TestDeploy(t, provider, it.TestDeployArgs{CertPath: fTestCertPath, KeyPath: fTestKeyPath})
 	})
 }
+
+func TestDeployer_Validate(t *testing.T) {
+	tempDir := t.TempDir()
+	existingFile := filepath.Join(tempDir, "existing.pem")
+	require.NoError(t, os.WriteFile(existingFile, []byte{}, 0o644))
+
+	testCases := []struct {
+		name    string
+		config  *impl.DeployerConfig
+		wantErr bool
+	}{
+		{
+			name:   "PEM",
+			config: &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: filepath.Join(tempDir, "cert.pem")},
+		},
+		{
+			name:   "PEM_ExistingFile",
+			config: &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: existingFile},
+		},
+		{
+			name:   "PEM_MissingDirectory",
+			config: &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: filepath.Join(tempDir, "a", "b", "cert.pem")},
+		},
+		{
+			name:    "PEM_PathIsDirectory",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: tempDir},
+			wantErr: true,
+		},
+		{
+			name:    "PEM_ParentIsFile",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: filepath.Join(tempDir, "cert.pem"), FilePathForKey: filepath.Join(existingFile, "key.pem")},
+			wantErr: true,
+		},
+		{
+			name:    "PEM_NoPaths",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM},
+			wantErr: true,
+		},
+		{
+			name:    "PFX_NoPassword",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PFX},
+			wantErr: true,
+		},
+		{
+			name:    "PFX_InvalidEncoder",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PFX, PfxPassword: "secret", PfxEncoder: "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "JKS_NoAlias",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_JKS, JksKeypass: "secret", JksStorepass: "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "UnsupportedShellEnv",
+			config:  &impl.DeployerConfig{FileFormat: impl.FILE_FORMAT_PEM, FilePathForCrt: filepath.Join(tempDir, "cert.pem"), ShellEnv: "zsh"},
+			wantErr: true,
+		},
+		{
+			name:    "UnsupportedFileFormat",
+			config:  &impl.DeployerConfig{FileFormat: "der"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := impl.NewDeployer(tc.config)
+			require.NoError(t, err)
+
+			err = provider.Validate(context.Background())
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
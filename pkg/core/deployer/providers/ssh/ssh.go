@@ -84,7 +84,10 @@ type Deployer struct {
 	logger *slog.Logger
 }
 
-var _ Provider = (*Deployer)(nil)
+var (
+	_ Provider               = (*Deployer)(nil)
+	_ core.DeployerValidator = (*Deployer)(nil)
+)
 
 func NewDeployer(config *DeployerConfig) (*Deployer, error) {
 	if config == nil {
@@ -249,6 +252,18 @@ func (d *Deployer) Deploy(ctx context.Context, certPEM, privkeyPEM string) (*Dep
 	return &DeployResult{}, nil
 }
 
+func (d *Deployer) Validate(ctx context.Context) error {
+	// 连接到 SSH，以校验服务器地址及登录凭据
+	sshClient, err := createSshClient(*d.config)
+	if err != nil {
+		return fmt.Errorf("failed to create SSH client: %w", err)
+	}
+	defer sshClient.Close()
+	d.logger.Info("ssh connected")
+
+	return nil
+}
+
 func createSshClient(config DeployerConfig) (*ssh.Client, error) {
 	clientCfg := ssh.NewDefaultConfig()
 	clientCfg.Host = config.SshHost
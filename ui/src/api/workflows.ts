@@ -1,4 +1,5 @@
 import { WORKFLOW_TRIGGERS } from "@/domain/workflow";
+import { type WorkflowGraphDiff } from "@/domain/workflowVersion";
 
 import { get as httpGet, post as httpPost } from "./_api";
 
@@ -14,11 +15,12 @@ export const getStats = () => {
   });
 };
 
-export const startRun = (workflowId: string) => {
+export const startRun = (workflowId: string, options?: { dryRun?: boolean; variables?: Record<string, string> }) => {
   return httpPost({
     url: `/api/workflows/${encodeURIComponent(workflowId)}/runs`,
     body: {
-      trigger: WORKFLOW_TRIGGERS.MANUAL,
+      trigger: options?.dryRun ? WORKFLOW_TRIGGERS.DRYRUN : WORKFLOW_TRIGGERS.MANUAL,
+      variables: options?.variables,
     },
   });
 };
@@ -28,3 +30,51 @@ export const cancelRun = (workflowId: string, runId: string) => {
     url: `/api/workflows/${encodeURIComponent(workflowId)}/runs/${encodeURIComponent(runId)}/cancel`,
   });
 };
+
+export const resumeRun = (workflowId: string, runId: string, options?: { nodeId?: string; rejected?: boolean; comment?: string }) => {
+  return httpPost({
+    url: `/api/workflows/${encodeURIComponent(workflowId)}/runs/${encodeURIComponent(runId)}/resume`,
+    body: options ?? {},
+  });
+};
+
+export const retryRun = (workflowId: string, runId: string) => {
+  type RespData = {
+    runId: string;
+  };
+
+  return httpPost<RespData>({
+    url: `/api/workflows/${encodeURIComponent(workflowId)}/runs/${encodeURIComponent(runId)}/retry`,
+  });
+};
+
+export const diffVersions = (workflowId: string, options: { from?: string; to?: string }) => {
+  type RespData = WorkflowGraphDiff & {
+    fromVersion: number;
+    toVersion: number;
+  };
+
+  const params = new URLSearchParams();
+  if (options.from) {
+    params.set("from", options.from);
+  }
+  if (options.to) {
+    params.set("to", options.to);
+  }
+
+  return httpGet<RespData>({
+    url: `/api/workflows/${encodeURIComponent(workflowId)}/versions/diff?${params.toString()}`,
+  });
+};
+
+export const rollbackVersion = (workflowId: string, versionId: string, options?: { comment?: string }) => {
+  type RespData = {
+    versionId: string;
+    version: number;
+  };
+
+  return httpPost<RespData>({
+    url: `/api/workflows/${encodeURIComponent(workflowId)}/versions/${encodeURIComponent(versionId)}/rollback`,
+    body: options ?? {},
+  });
+};
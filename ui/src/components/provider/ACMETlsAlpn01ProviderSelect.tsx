@@ -0,0 +1,77 @@
+import { useMemo } from "react";
+import { useTranslation } from "react-i18next";
+import { Avatar, Select, Typography, theme } from "antd";
+
+import { type ACMETlsAlpn01Provider, acmeTlsAlpn01ProvidersMap } from "@/domain/provider";
+import { matchSearchOption } from "@/utils/search";
+
+import { type SharedSelectProps, useSelectDataSource } from "./_shared";
+
+export interface ACMETlsAlpn01ProviderSelectProps extends SharedSelectProps<ACMETlsAlpn01Provider> {
+  showAvailability?: boolean;
+}
+
+const ACMETlsAlpn01ProviderSelect = ({ showAvailability, onFilter, ...props }: ACMETlsAlpn01ProviderSelectProps) => {
+  const { t } = useTranslation();
+
+  const { token: themeToken } = theme.useToken();
+
+  const dataSources = useSelectDataSource({
+    dataSource: Array.from(acmeTlsAlpn01ProvidersMap.values()),
+    filters: [onFilter!],
+  });
+  const options = useMemo(() => {
+    const convert = (providers: ACMETlsAlpn01Provider[]): Array<{ key: string; value: string; label: string; data: ACMETlsAlpn01Provider }> => {
+      return providers.map((provider) => ({
+        key: provider.type,
+        value: provider.type,
+        label: t(provider.name),
+        data: provider,
+      }));
+    };
+
+    return showAvailability
+      ? [
+          {
+            label: t("provider.text.available_group"),
+            options: convert(dataSources.available),
+          },
+          {
+            label: t("provider.text.unavailable_group"),
+            options: convert(dataSources.unavailable),
+          },
+        ].filter((group) => group.options.length > 0)
+      : convert(dataSources.filtered);
+  }, [showAvailability, dataSources]);
+
+  const renderOption = (key: string) => {
+    const provider = acmeTlsAlpn01ProvidersMap.get(key);
+    return (
+      <div className="flex items-center gap-2 truncate overflow-hidden">
+        <Avatar shape="square" src={provider?.icon} size="small" />
+        <Typography.Text ellipsis>{t(provider?.name ?? "")}</Typography.Text>
+      </div>
+    );
+  };
+
+  return (
+    <Select
+      {...props}
+      labelRender={({ value }) => {
+        if (value != null) {
+          return renderOption(value as string);
+        }
+
+        return <span style={{ color: themeToken.colorTextPlaceholder }}>{props.placeholder}</span>;
+      }}
+      options={options}
+      optionLabelProp={void 0}
+      optionRender={(option) => renderOption(option.data.value as string)}
+      showSearch={{
+        filterOption: (inputValue, option) => matchSearchOption(inputValue, option!),
+      }}
+    />
+  );
+};
+
+export default ACMETlsAlpn01ProviderSelect;
@@ -1,7 +1,7 @@
 import { useEffect, useMemo, useRef, useState } from "react";
 import { useTranslation } from "react-i18next";
 import { EditorState, FlowLayoutDefault } from "@flowgram.ai/fixed-layout-editor";
-import { IconBrowserShare, IconBug, IconCheck, IconDots, IconDownload, IconSettings2, IconTransferOut } from "@tabler/icons-react";
+import { IconBrowserShare, IconBug, IconCheck, IconDots, IconDownload, IconRepeat, IconSettings2, IconTransferOut } from "@tabler/icons-react";
 import { useRequest } from "ahooks";
 import { Alert, App, Button, Card, Divider, Dropdown, Empty, Skeleton, Table, type TableProps, Tooltip, Typography, theme } from "antd";
 import dayjs from "dayjs";
@@ -10,6 +10,7 @@ import { ClientResponseError } from "pocketbase";
 import CertificateDetailDrawer from "@/components/certificate/CertificateDetailDrawer";
 import Show from "@/components/Show";
 import { type CertificateModel } from "@/domain/certificate";
+import { WORKFLOW_TRIGGERS } from "@/domain/workflow";
 import { WorkflowLogLevel, type WorkflowLogModel } from "@/domain/workflowLog";
 import { WORKFLOW_RUN_STATUSES, type WorkflowRunModel } from "@/domain/workflowRun";
 import { useBrowserTheme } from "@/hooks";
@@ -83,6 +84,18 @@ const WorkflowRunDetail = ({ className, style, ...props }: WorkflowRunDetailProp
           }[mergedData.status] ?? ("info" as const)
         }
       />
+      {!!mergedData.resumedFromRunRef && (
+        <Alert
+          className="mt-1"
+          icon={<IconRepeat size="1em" />}
+          showIcon
+          title={
+            <div className="text-xs">
+              {t("workflow_run.base.resumed_from", { runId: mergedData.resumedFromRunRef, nodeId: mergedData.resumedFromNodeId })}
+            </div>
+          }
+        />
+      )}
       {!!mergedData.error && (
         <Alert
           className="mt-1"
@@ -188,7 +201,7 @@ const WorkflowRunLogs = ({ runData }: { runData: WorkflowRunModel }) => {
 
   const { theme: browserTheme } = useBrowserTheme();
 
-  const { id: runId, status: runStatus } = runData;
+  const { id: runId, status: runStatus, trigger: runTrigger } = runData;
 
   type Log = Pick<WorkflowLogModel, "timestamp" | "level" | "message" | "data">;
   type LogGroup = { id: string; name: string; records: Log[] };
@@ -278,6 +291,22 @@ const WorkflowRunLogs = ({ runData }: { runData: WorkflowRunModel }) => {
     );
   };
 
+  const renderDryRunValidation = (group: LogGroup) => {
+    // 试运行时，部署节点会在日志中附带校验结果，据此逐个节点标注是否已校验
+    if (runTrigger !== WORKFLOW_TRIGGERS.DRYRUN) return null;
+
+    const validated = group.records.map((record) => record.data?.["deployment.validated"]).find((value) => typeof value === "boolean");
+    if (validated == null) return null;
+
+    return validated ? (
+      <span className="ml-2 text-success">{`[${t("workflow_run.logs.dryrun_validation.validated")}]`}</span>
+    ) : (
+      <Tooltip title={t("workflow_run.logs.dryrun_validation.not_validated.tooltip")}>
+        <span className="ml-2 text-warning">{`[${t("workflow_run.logs.dryrun_validation.not_validated")}]`}</span>
+      </Tooltip>
+    );
+  };
+
   const handleDownloadClick = () => {
     const NEWLINE = "\n";
     const logstr = listData
@@ -365,6 +394,7 @@ const WorkflowRunLogs = ({ runData }: { runData: WorkflowRunModel }) => {
                 <div className="truncate text-xs/loose">
                   <span className="font-mono text-stone-400">{`#${group.id}\u00A0`}</span>
                   <span>{group.name}</span>
+                  {renderDryRunValidation(group)}
                 </div>
                 <div className="flex flex-col text-xs/relaxed">{group.records.map((record) => renderLogRecord(record))}</div>
               </div>
@@ -113,3 +113,16 @@ export const getAllPreviousNodes = (node: FlowNodeEntity): FlowNodeEntity[] => {
   // console.log(prevNodes);
   return prevNodes;
 };
+
+export const getStartNode = (node: FlowNodeEntity): FlowNodeEntity | undefined => {
+  let current: FlowNodeEntity | undefined = node;
+  while (current) {
+    if (current.isStart) {
+      return current;
+    }
+
+    current = current.pre ?? current.parent;
+  }
+
+  return void 0;
+};
@@ -1,6 +1,13 @@
 import { useEffect, useState } from "react";
 
-import { type ACMEDns01ProviderType, type ACMEHttp01ProviderType, ACME_DNS01_PROVIDERS, ACME_HTTP01_PROVIDERS } from "@/domain/provider";
+import {
+  type ACMEDns01ProviderType,
+  type ACMEHttp01ProviderType,
+  type ACMETlsAlpn01ProviderType,
+  ACME_DNS01_PROVIDERS,
+  ACME_HTTP01_PROVIDERS,
+  ACME_TLSALPN01_PROVIDERS,
+} from "@/domain/provider";
 
 import BizApplyNodeConfigFieldsProviderAliyunESA from "./BizApplyNodeConfigFieldsProviderAliyunESA";
 import BizApplyNodeConfigFieldsProviderAWSLightsail from "./BizApplyNodeConfigFieldsProviderAWSLightsail";
@@ -12,6 +19,8 @@ import BizApplyNodeConfigFieldsProviderLocal from "./BizApplyNodeConfigFieldsPro
 import BizApplyNodeConfigFieldsProviderOracleCloudDNS from "./BizApplyNodeConfigFieldsProviderOracleCloudDNS";
 import BizApplyNodeConfigFieldsProviderS3 from "./BizApplyNodeConfigFieldsProviderS3";
 import BizApplyNodeConfigFieldsProviderSSH from "./BizApplyNodeConfigFieldsProviderSSH";
+import BizApplyNodeConfigFieldsProviderTlsAlpnLocal from "./BizApplyNodeConfigFieldsProviderTlsAlpnLocal";
+import BizApplyNodeConfigFieldsProviderTlsAlpnSSH from "./BizApplyNodeConfigFieldsProviderTlsAlpnSSH";
 import BizApplyNodeConfigFieldsProviderUCloudUDNR from "./BizApplyNodeConfigFieldsProviderUCloudUDNR";
 
 const acmeDns01ProviderComponentMap: Partial<Record<ACMEDns01ProviderType, React.ComponentType<any>>> = {
@@ -44,8 +53,17 @@ const acmeHttp01ProviderComponentMap: Partial<Record<ACMEHttp01ProviderType, Rea
   [ACME_HTTP01_PROVIDERS.SSH]: BizApplyNodeConfigFieldsProviderSSH,
 };
 
+const acmeTlsAlpn01ProviderComponentMap: Partial<Record<ACMETlsAlpn01ProviderType, React.ComponentType<any>>> = {
+  /*
+    注意：如果追加新的子组件，请保持以 ASCII 排序。
+    NOTICE: If you add new child component, please keep ASCII order.
+    */
+  [ACME_TLSALPN01_PROVIDERS.LOCAL]: BizApplyNodeConfigFieldsProviderTlsAlpnLocal,
+  [ACME_TLSALPN01_PROVIDERS.SSH]: BizApplyNodeConfigFieldsProviderTlsAlpnSSH,
+};
+
 const useComponent = (
-  challenge: "dns-01" | "http-01",
+  challenge: "dns-01" | "http-01" | "tls-alpn-01",
   provider: string,
   { initProps, deps = [] }: { initProps?: (provider: string) => any; deps?: unknown[] }
 ) => {
@@ -55,7 +73,9 @@ const useComponent = (
         ? acmeDns01ProviderComponentMap[provider as ACMEDns01ProviderType]
         : challenge === "http-01"
           ? acmeHttp01ProviderComponentMap[provider as ACMEHttp01ProviderType]
-          : void 0;
+          : challenge === "tls-alpn-01"
+            ? acmeTlsAlpn01ProviderComponentMap[provider as ACMETlsAlpn01ProviderType]
+            : void 0;
     if (!Component) return null;
 
     const props = initProps?.(provider);
@@ -0,0 +1,69 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, InputNumber } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { isPortNumber } from "@/utils/validator";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderTlsAlpnLocal = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "listenInterface"]}
+        initialValue={initialValues.listenInterface}
+        label={t("workflow_node.apply.form.local_listen_interface.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_listen_interface.tooltip") }}></span>}
+      >
+        <Input allowClear placeholder={t("workflow_node.apply.form.local_listen_interface.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "listenPort"]}
+        initialValue={initialValues.listenPort}
+        label={t("workflow_node.apply.form.local_listen_port.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.local_listen_port.tooltip") }}></span>}
+      >
+        <InputNumber style={{ width: "100%" }} min={1} max={65535} placeholder={t("workflow_node.apply.form.local_listen_port.placeholder")} />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    listenPort: 443,
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t } = i18n;
+
+  return z.object({
+    listenInterface: z.string().nullish(),
+    listenPort: z.coerce
+      .number()
+      .refine((v) => isPortNumber(v), t("common.errmsg.port_invalid"))
+      .or(z.literal(""))
+      .nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderTlsAlpnLocal, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
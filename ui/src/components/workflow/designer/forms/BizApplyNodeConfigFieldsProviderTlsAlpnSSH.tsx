@@ -0,0 +1,74 @@
+import { getI18n, useTranslation } from "react-i18next";
+import { Form, Input, Switch } from "antd";
+import { createSchemaFieldRule } from "antd-zod";
+import { z } from "zod";
+
+import { useFormNestedFieldsContext } from "./_context";
+
+const BizApplyNodeConfigFieldsProviderTlsAlpnSSH = () => {
+  const { i18n, t } = useTranslation();
+
+  const { parentNamePath } = useFormNestedFieldsContext();
+  const formSchema = z.object({
+    [parentNamePath]: getSchema({ i18n }),
+  });
+  const formRule = createSchemaFieldRule(formSchema);
+  const initialValues = getInitialValues();
+
+  return (
+    <>
+      <Form.Item
+        name={[parentNamePath, "certDirPath"]}
+        initialValue={initialValues.certDirPath}
+        label={t("workflow_node.apply.form.ssh_cert_dir_path.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_cert_dir_path.tooltip") }}></span>}
+      >
+        <Input placeholder={t("workflow_node.apply.form.ssh_cert_dir_path.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "reloadCommand"]}
+        initialValue={initialValues.reloadCommand}
+        label={t("workflow_node.apply.form.ssh_reload_command.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_reload_command.tooltip") }}></span>}
+      >
+        <Input.TextArea autoSize={{ minRows: 2, maxRows: 8 }} placeholder={t("workflow_node.apply.form.ssh_reload_command.placeholder")} />
+      </Form.Item>
+
+      <Form.Item
+        name={[parentNamePath, "useSCP"]}
+        initialValue={initialValues.useSCP}
+        label={t("workflow_node.apply.form.ssh_use_scp.label")}
+        rules={[formRule]}
+        tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.ssh_use_scp.tooltip") }}></span>}
+      >
+        <Switch />
+      </Form.Item>
+    </>
+  );
+};
+
+const getInitialValues = (): Nullish<z.infer<ReturnType<typeof getSchema>>> => {
+  return {
+    certDirPath: "/etc/certimate/tls-alpn/",
+  };
+};
+
+const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }) => {
+  const { t: _ } = i18n;
+
+  return z.object({
+    certDirPath: z.string().nonempty(),
+    reloadCommand: z.string().nullish(),
+    useSCP: z.boolean().nullish(),
+  });
+};
+
+const _default = Object.assign(BizApplyNodeConfigFieldsProviderTlsAlpnSSH, {
+  getInitialValues,
+  getSchema,
+});
+
+export default _default;
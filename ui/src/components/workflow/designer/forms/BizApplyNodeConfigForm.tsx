@@ -30,10 +30,11 @@ import FileTextInput from "@/components/FileTextInput";
 import MultipleSplitValueInput from "@/components/MultipleSplitValueInput";
 import ACMEDns01ProviderSelect from "@/components/provider/ACMEDns01ProviderSelect";
 import ACMEHttp01ProviderSelect from "@/components/provider/ACMEHttp01ProviderSelect";
+import ACMETlsAlpn01ProviderSelect from "@/components/provider/ACMETlsAlpn01ProviderSelect";
 import CAProviderSelect from "@/components/provider/CAProviderSelect";
 import Show from "@/components/Show";
 import { type AccessModel } from "@/domain/access";
-import { CA_PROVIDERS, acmeDns01ProvidersMap, acmeHttp01ProvidersMap, caProvidersMap } from "@/domain/provider";
+import { CA_PROVIDERS, acmeDns01ProvidersMap, acmeHttp01ProvidersMap, acmeTlsAlpn01ProvidersMap, caProvidersMap } from "@/domain/provider";
 import { type WorkflowNodeConfigForBizApply, defaultNodeConfigForBizApply } from "@/domain/workflow";
 import { useAntdForm, useZustandShallowSelector } from "@/hooks";
 import { useAccessesStore } from "@/stores/access";
@@ -54,6 +55,7 @@ const IDENTIFIER_IP = "ip" as const;
 
 const CHALLENGE_TYPE_DNS01 = "dns-01" as const;
 const CHALLENGE_TYPE_HTTP01 = "http-01" as const;
+const CHALLENGE_TYPE_TLSALPN01 = "tls-alpn-01" as const;
 
 const KEY_SOURCE_AUTO = "auto" as const;
 const KEY_SOURCE_REUSE = "reuse" as const;
@@ -76,6 +78,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
     if (option.reserve) return false;
     if (fieldChallengeType === CHALLENGE_TYPE_DNS01) return acmeDns01ProvidersMap.get(fieldProvider)?.provider === option.provider;
     if (fieldChallengeType === CHALLENGE_TYPE_HTTP01) return acmeHttp01ProvidersMap.get(fieldProvider)?.provider === option.provider;
+    if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01) return acmeTlsAlpn01ProvidersMap.get(fieldProvider)?.provider === option.provider;
     return false;
   };
   const accessOptionFilterForCA = (_: string, option: AccessModel) => {
@@ -134,6 +137,15 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
           }
         }
         break;
+
+      case CHALLENGE_TYPE_TLSALPN01:
+        {
+          if (fieldProvider) {
+            const provider = acmeTlsAlpn01ProvidersMap.get(fieldProvider);
+            return !provider?.builtin;
+          }
+        }
+        break;
     }
 
     return false;
@@ -163,6 +175,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
         .filter((access) => {
           if (fieldChallengeType === CHALLENGE_TYPE_DNS01) return acmeDns01ProvidersMap.get(fieldProvider)?.provider === access.provider;
           if (fieldChallengeType === CHALLENGE_TYPE_HTTP01) return acmeHttp01ProvidersMap.get(fieldProvider)?.provider === access.provider;
+          if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01) return acmeTlsAlpn01ProvidersMap.get(fieldProvider)?.provider === access.provider;
           return false;
         });
       if (availableAccesses.length === 1) {
@@ -244,6 +257,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
           formInst.setFieldValue("providerConfig", void 0);
 
           resetFieldIfInvalid("httpDelayWait");
+          resetFieldIfInvalid("tlsAlpnDelayWait");
         }
         break;
 
@@ -256,6 +270,20 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
           resetFieldIfInvalid("dnsPropagationWait");
           resetFieldIfInvalid("dnsPropagationTimeout");
           resetFieldIfInvalid("dnsTTL");
+          resetFieldIfInvalid("tlsAlpnDelayWait");
+        }
+        break;
+
+      case CHALLENGE_TYPE_TLSALPN01:
+        {
+          formInst.setFieldValue("provider", void 0);
+          formInst.setFieldValue("providerAccessId", void 0);
+          formInst.setFieldValue("providerConfig", void 0);
+
+          resetFieldIfInvalid("dnsPropagationWait");
+          resetFieldIfInvalid("dnsPropagationTimeout");
+          resetFieldIfInvalid("dnsTTL");
+          resetFieldIfInvalid("httpDelayWait");
         }
         break;
     }
@@ -322,7 +350,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                     <span
                       dangerouslySetInnerHTML={{
                         __html:
-                          fieldChallengeType === CHALLENGE_TYPE_HTTP01
+                          fieldChallengeType === CHALLENGE_TYPE_HTTP01 || fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
                             ? t("workflow_node.apply.form.domains.help_no_wildcard")
                             : t("workflow_node.apply.form.domains.help"),
                       }}
@@ -387,6 +415,7 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                   DNS-01
                 </Radio.Button>
                 <Radio.Button value={CHALLENGE_TYPE_HTTP01}>HTTP-01</Radio.Button>
+                <Radio.Button value={CHALLENGE_TYPE_TLSALPN01}>TLS-ALPN-01</Radio.Button>
               </Radio.Group>
             </Form.Item>
 
@@ -398,7 +427,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                   ? t("workflow_node.apply.form.provider_dns01.label")
                   : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                     ? t("workflow_node.apply.form.provider_http01.label")
-                    : t("workflow_node.apply.form.provider.label")
+                    : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                      ? t("workflow_node.apply.form.provider_tlsalpn01.label")
+                      : t("workflow_node.apply.form.provider.label")
               }
               rules={[formRule]}
             >
@@ -418,6 +449,14 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                   onSelect={handleProviderSelect}
                   onClear={handleProviderSelect}
                 />
+              ) : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01 ? (
+                <ACMETlsAlpn01ProviderSelect
+                  placeholder={t("workflow_node.apply.form.provider_tlsalpn01.placeholder")}
+                  showAvailability
+                  showSearch
+                  onSelect={handleProviderSelect}
+                  onClear={handleProviderSelect}
+                />
               ) : (
                 <Select disabled placeholder={t("workflow_node.apply.form.provider.placeholder")} />
               )}
@@ -431,7 +470,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                   ? t("workflow_node.apply.form.provider_access_dns01.label")
                   : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                     ? t("workflow_node.apply.form.provider_access_http01.label")
-                    : t("workflow_node.apply.form.provider_access.label")
+                    : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                      ? t("workflow_node.apply.form.provider_access_tlsalpn01.label")
+                      : t("workflow_node.apply.form.provider_access.label")
               }
             >
               <div className="absolute -top-1.5 right-0 -translate-y-full">
@@ -443,11 +484,18 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                       <IconPlus size="1.25em" />
                     </Button>
                   }
-                  usage={fieldChallengeType === CHALLENGE_TYPE_DNS01 ? "dns" : fieldChallengeType === CHALLENGE_TYPE_HTTP01 ? "hosting" : "dns-hosting"}
+                  usage={
+                    fieldChallengeType === CHALLENGE_TYPE_DNS01
+                      ? "dns"
+                      : fieldChallengeType === CHALLENGE_TYPE_HTTP01 || fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                        ? "hosting"
+                        : "dns-hosting"
+                  }
                   afterSubmit={(record) => {
                     if (!accessOptionFilter(record.provider, record)) return;
                     if (fieldChallengeType === CHALLENGE_TYPE_DNS01 && acmeDns01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
                     if (fieldChallengeType === CHALLENGE_TYPE_HTTP01 && acmeHttp01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
+                    if (fieldChallengeType === CHALLENGE_TYPE_TLSALPN01 && acmeTlsAlpn01ProvidersMap.get(fieldProvider!)?.provider !== record.provider) return;
                     formInst.setFieldValue("providerAccessId", record.id);
                   }}
                 />
@@ -460,7 +508,9 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
                       ? t("workflow_node.apply.form.provider_access_dns01.placeholder")
                       : fieldChallengeType === CHALLENGE_TYPE_HTTP01
                         ? t("workflow_node.apply.form.provider_access_http01.placeholder")
-                        : t("workflow_node.apply.form.provider_access.placeholder")
+                        : fieldChallengeType === CHALLENGE_TYPE_TLSALPN01
+                          ? t("workflow_node.apply.form.provider_access_tlsalpn01.placeholder")
+                          : t("workflow_node.apply.form.provider_access.placeholder")
                   }
                   showSearch
                   onFilter={accessOptionFilter}
@@ -742,6 +792,23 @@ const BizApplyNodeConfigForm = ({ node, ...props }: BizApplyNodeConfigFormProps)
               />
             </Form.Item>
 
+            <Form.Item
+              name="tlsAlpnDelayWait"
+              hidden={fieldChallengeType !== CHALLENGE_TYPE_TLSALPN01}
+              label={t("workflow_node.apply.form.tls_alpn_delay_wait.label")}
+              rules={[formRule]}
+              tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.apply.form.tls_alpn_delay_wait.tooltip") }}></span>}
+            >
+              <Input
+                type="number"
+                allowClear
+                min={0}
+                max={3600}
+                placeholder={t("workflow_node.apply.form.tls_alpn_delay_wait.placeholder")}
+                suffix={t("workflow_node.apply.form.tls_alpn_delay_wait.unit")}
+              />
+            </Form.Item>
+
             <Form.Item
               name="disableFollowCNAME"
               hidden={fieldChallengeType !== CHALLENGE_TYPE_DNS01}
@@ -1050,7 +1117,7 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
           return v.split(MULTIPLE_INPUT_SEPARATOR).every((e) => isIPv4(e) || isIPv6(e));
         }, t("common.errmsg.ip_invalid")),
       contactEmail: z.email(),
-      challengeType: z.enum([CHALLENGE_TYPE_DNS01, CHALLENGE_TYPE_HTTP01]),
+      challengeType: z.enum([CHALLENGE_TYPE_DNS01, CHALLENGE_TYPE_HTTP01, CHALLENGE_TYPE_TLSALPN01]),
       provider: z.string().nonempty(),
       providerAccessId: z.string().nullish(),
       providerConfig: z.any().nullish(),
@@ -1077,6 +1144,7 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
       dnsPropagationTimeout: z.coerce.number().int().gt(0).or(z.literal("")).nullish(),
       dnsTTL: z.coerce.number().int().gt(0).or(z.literal("")).nullish(),
       httpDelayWait: z.coerce.number().int().gt(0).or(z.literal("")).nullish(),
+      tlsAlpnDelayWait: z.coerce.number().int().gt(0).or(z.literal("")).nullish(),
       disableCommonName: z.boolean().nullish(),
       disableFollowCNAME: z.boolean().nullish(),
       disableARI: z.boolean().nullish(),
@@ -1140,6 +1208,18 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
               }
             }
             break;
+
+          case CHALLENGE_TYPE_TLSALPN01:
+            {
+              if (values.domains && values.domains.includes("*")) {
+                ctx.addIssue({
+                  code: "custom",
+                  message: t("workflow_node.apply.form.challenge_type.errmsg.no_wildcard_in_tlsalpn01"),
+                  path: ["challengeType"],
+                });
+              }
+            }
+            break;
         }
       }
 
@@ -1197,6 +1277,19 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
               }
             }
             break;
+
+          case CHALLENGE_TYPE_TLSALPN01:
+            {
+              const provider = acmeTlsAlpn01ProvidersMap.get(values.provider);
+              if (!provider?.builtin && !values.providerAccessId) {
+                ctx.addIssue({
+                  code: "custom",
+                  message: t("workflow_node.deploy.form.provider_access.placeholder"),
+                  path: ["providerAccessId"],
+                });
+              }
+            }
+            break;
         }
       }
 
@@ -13,11 +13,17 @@ import DeploymentProviderSelect from "@/components/provider/DeploymentProviderSe
 import Show from "@/components/Show";
 import { type AccessModel } from "@/domain/access";
 import { deploymentProvidersMap } from "@/domain/provider";
-import { type WorkflowNodeConfigForBizDeploy, defaultNodeConfigForBizDeploy } from "@/domain/workflow";
+import {
+  WORKFLOW_EVENT_TYPES,
+  WORKFLOW_TRIGGERS,
+  type WorkflowNodeConfigForBizDeploy,
+  type WorkflowNodeConfigForStart,
+  defaultNodeConfigForBizDeploy,
+} from "@/domain/workflow";
 import { useAntdForm, useZustandShallowSelector } from "@/hooks";
 import { useAccessesStore } from "@/stores/access";
 
-import { getAllPreviousNodes } from "../_util";
+import { getAllPreviousNodes, getStartNode } from "../_util";
 import { FormNestedFieldsContextProvider, NodeFormContextProvider } from "./_context";
 import BizDeployNodeConfigFieldsProvider from "./BizDeployNodeConfigFieldsProvider";
 import { NodeType } from "../nodes/typings";
@@ -68,7 +74,7 @@ const BizDeployNodeConfigForm = ({ node, ...props }: BizDeployNodeConfigFormProp
   const fieldProviderAccessId = Form.useWatch("providerAccessId", { form: formInst, preserve: true });
 
   const certificateOutputNodeIdOptions = useMemo(() => {
-    return getAllPreviousNodes(node)
+    const options = getAllPreviousNodes(node)
       .filter((node) => node.flowNodeType === NodeType.BizApply || node.flowNodeType === NodeType.BizUpload)
       .map((node) => {
         return {
@@ -76,6 +82,17 @@ const BizDeployNodeConfigForm = ({ node, ...props }: BizDeployNodeConfigFormProp
           value: node.id,
         };
       });
+
+    // 由证书事件触发时，开始节点将输出触发事件的证书
+    const startNode = getStartNode(node);
+    if (startNode && hasStartNodeCertificateOutput(startNode.form?.getValueIn("config"))) {
+      options.unshift({
+        label: startNode.form?.getValueIn("name"),
+        value: startNode.id,
+      });
+    }
+
+    return options;
   }, [node]);
 
   const renderNestedFieldProviderComponent = BizDeployNodeConfigFieldsProvider.useComponent(fieldProvider, {});
@@ -241,6 +258,12 @@ const BizDeployNodeConfigForm = ({ node, ...props }: BizDeployNodeConfigFormProp
   );
 };
 
+const hasStartNodeCertificateOutput = (startConfig?: WorkflowNodeConfigForStart) => {
+  return (
+    startConfig?.trigger === WORKFLOW_TRIGGERS.EVENT && !!startConfig.eventType && startConfig.eventType !== WORKFLOW_EVENT_TYPES.WORKFLOW_RUN_COMPLETED
+  );
+};
+
 const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
   const { t } = i18n;
 
@@ -285,6 +308,7 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
 const _default = Object.assign(BizDeployNodeConfigForm, {
   getAnchorItems,
   getSchema,
+  hasStartNodeCertificateOutput,
 });
 
 export default _default;
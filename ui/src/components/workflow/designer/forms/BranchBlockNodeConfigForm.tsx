@@ -1,7 +1,7 @@
 import { useMemo, useRef } from "react";
 import { getI18n, useTranslation } from "react-i18next";
 import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
-import { type AnchorProps, Form, type FormInstance } from "antd";
+import { type AnchorProps, Form, type FormInstance, Input } from "antd";
 import { createSchemaFieldRule } from "antd-zod";
 import { z } from "zod";
 
@@ -69,6 +69,16 @@ const BranchBlockNodeConfigForm = ({ node, ...props }: BranchBlockNodeConfigForm
           <Form.Item name="expression" label={t("workflow_node.branch_block.form.expression.label")} rules={[formRule]} validateTrigger={false}>
             <BranchBlockNodeConfigExprInputBox ref={exprInputBoxRef} />
           </Form.Item>
+
+          <Form.Item
+            name="expressionText"
+            label={t("workflow_node.branch_block.form.expression_text.label")}
+            extra={t("workflow_node.branch_block.form.expression_text.help")}
+            rules={[formRule]}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.branch_block.form.expression_text.tooltip") }}></span>}
+          >
+            <Input.TextArea autoSize={{ minRows: 2, maxRows: 8 }} placeholder={t("workflow_node.branch_block.form.expression_text.placeholder")} />
+          </Form.Item>
         </div>
       </Form>
     </NodeFormContextProvider>
@@ -138,6 +148,7 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
         if (!v) return true;
         return exprSchema.safeParse(v).success;
       }, t("workflow_node.branch_block.form.expression.errmsg.invalid")),
+    expressionText: z.string().nullish(),
   });
 };
 
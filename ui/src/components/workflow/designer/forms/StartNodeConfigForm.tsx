@@ -1,16 +1,29 @@
 ﻿import { useEffect, useMemo, useState } from "react";
 import { getI18n, useTranslation } from "react-i18next";
 import { type FlowNodeEntity } from "@flowgram.ai/fixed-layout-editor";
-import { IconDice6 } from "@tabler/icons-react";
-import { type AnchorProps, Button, Form, type FormInstance, Input, Radio, Space, Tooltip } from "antd";
+import { IconCircleMinus, IconCirclePlus, IconDice6 } from "@tabler/icons-react";
+import { useRequest } from "ahooks";
+import { type AnchorProps, Button, Form, type FormInstance, Input, InputNumber, Radio, Select, Space, Tooltip } from "antd";
 import { createSchemaFieldRule } from "antd-zod";
 import dayjs from "dayjs";
 import { z } from "zod";
 
+import AccessSelect from "@/components/access/AccessSelect";
 import Show from "@/components/Show";
 import Tips from "@/components/Tips";
-import { WORKFLOW_TRIGGERS, type WorkflowNodeConfigForStart, defaultNodeConfigForStart } from "@/domain/workflow";
-import { useAntdForm } from "@/hooks";
+import {
+  WORKFLOW_EVENT_TYPES,
+  WORKFLOW_TRIGGERS,
+  WORKFLOW_VARIABLE_SECRET_ENV_PREFIX,
+  WORKFLOW_VARIABLE_SECRET_SOURCES,
+  WORKFLOW_VARIABLE_TYPES,
+  type WorkflowNodeConfigForStart,
+  defaultNodeConfigForStart,
+} from "@/domain/workflow";
+import { WORKFLOW_RUN_STATUSES } from "@/domain/workflowRun";
+import { useAntdForm, useZustandShallowSelector } from "@/hooks";
+import { list as listWorkflows } from "@/repository/workflow";
+import { useWorkflowStore } from "@/stores/workflow";
 import { getNextCronExecutions, validateCronExpression } from "@/utils/cron";
 
 import { NodeFormContextProvider } from "./_context";
@@ -28,6 +41,8 @@ const StartNodeConfigForm = ({ node, ...props }: StartNodeConfigFormProps) => {
 
   const { i18n, t } = useTranslation();
 
+  const { workflow } = useWorkflowStore(useZustandShallowSelector(["workflow"]));
+
   const initialValues = useMemo(() => {
     return node.form?.getValueIn("config") as WorkflowNodeConfigForStart | undefined;
   }, [node]);
@@ -47,14 +62,54 @@ const StartNodeConfigForm = ({ node, ...props }: StartNodeConfigFormProps) => {
     setFieldTriggerCronExpectedExecutions(getNextCronExecutions(fieldTriggerCron!, 5));
   }, [fieldTriggerCron]);
 
+  const fieldEventType = Form.useWatch("eventType", formInst);
+
+  const { data: eventWorkflowOptions, loading: eventWorkflowOptionsLoading } = useRequest(
+    () => {
+      return listWorkflows({ perPage: 500 }).then((res) =>
+        res.items.filter((item) => item.id !== workflow.id).map((item) => ({ label: item.name, value: item.id }))
+      );
+    },
+    {
+      ready: fieldTrigger === WORKFLOW_TRIGGERS.EVENT,
+      refreshDeps: [fieldTrigger, workflow.id],
+    }
+  );
+
   const handleTriggerChange = (value: string) => {
     if (value === WORKFLOW_TRIGGERS.SCHEDULED) {
       formInst.setFieldValue("triggerCron", initialValues?.triggerCron || "0 0 * * *");
     } else {
       formInst.setFieldValue("triggerCron", void 0);
     }
+
+    if (value === WORKFLOW_TRIGGERS.EVENT) {
+      formInst.setFieldValue("eventType", initialValues?.eventType || WORKFLOW_EVENT_TYPES.CERTIFICATE_CREATED);
+    } else {
+      formInst.setFieldValue("eventType", void 0);
+      formInst.setFieldValue("eventWorkflowId", void 0);
+      formInst.setFieldValue("eventExpiringDays", void 0);
+      formInst.setFieldValue("eventRunStatus", void 0);
+    }
   };
 
+  const handleEventTypeChange = (value: string) => {
+    if (value === WORKFLOW_EVENT_TYPES.CERTIFICATE_EXPIRING) {
+      formInst.setFieldValue("eventExpiringDays", initialValues?.eventExpiringDays || 30);
+    } else {
+      formInst.setFieldValue("eventExpiringDays", void 0);
+    }
+
+    if (value !== WORKFLOW_EVENT_TYPES.WORKFLOW_RUN_COMPLETED) {
+      formInst.setFieldValue("eventRunStatus", void 0);
+    }
+  };
+
+  const webhookUrl = useMemo(() => {
+    if (!workflow.id || !workflow.webhookToken) return "";
+    return `${window.location.origin}/api/webhooks/workflows/${encodeURIComponent(workflow.id)}/${encodeURIComponent(workflow.webhookToken)}`;
+  }, [workflow.id, workflow.webhookToken]);
+
   const handleRandomCronClick = () => {
     const m = Math.floor(Math.random() * 60);
     const h = Math.floor(Math.random() * 24);
@@ -69,6 +124,8 @@ const StartNodeConfigForm = ({ node, ...props }: StartNodeConfigFormProps) => {
             <Radio.Group onChange={(e) => handleTriggerChange(e.target.value)}>
               <Radio value={WORKFLOW_TRIGGERS.MANUAL}>{t("workflow_node.start.form.trigger.option.manual.label")}</Radio>
               <Radio value={WORKFLOW_TRIGGERS.SCHEDULED}>{t("workflow_node.start.form.trigger.option.scheduled.label")}</Radio>
+              <Radio value={WORKFLOW_TRIGGERS.WEBHOOK}>{t("workflow_node.start.form.trigger.option.webhook.label")}</Radio>
+              <Radio value={WORKFLOW_TRIGGERS.EVENT}>{t("workflow_node.start.form.trigger.option.event.label")}</Radio>
             </Radio.Group>
           </Form.Item>
 
@@ -108,6 +165,210 @@ const StartNodeConfigForm = ({ node, ...props }: StartNodeConfigFormProps) => {
               <Tips message={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.trigger_cron.guide") }}></span>} />
             </Form.Item>
           </Show>
+
+          <Show when={fieldTrigger === WORKFLOW_TRIGGERS.WEBHOOK}>
+            <Show
+              when={!!webhookUrl && workflow.trigger === WORKFLOW_TRIGGERS.WEBHOOK}
+              fallback={
+                <Form.Item>
+                  <Tips message={t("workflow_node.start.form.webhook_url.placeholder")} />
+                </Form.Item>
+              }
+            >
+              <Form.Item label={t("workflow_node.start.form.webhook_url.label")}>
+                <Input value={webhookUrl} variant="filled" readOnly />
+              </Form.Item>
+
+              <Form.Item label={t("workflow_node.start.form.webhook_secret.label")}>
+                <Input.Password value={workflow.webhookSecret} variant="filled" readOnly />
+              </Form.Item>
+            </Show>
+
+            <Form.Item
+              label={t("workflow_node.start.form.webhook_payload_mappings.label")}
+              tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.webhook_payload_mappings.tooltip") }}></span>}
+            >
+              <Form.List name="webhookPayloadMappings">
+                {(fields, { add, remove }) => (
+                  <div className="flex flex-col gap-2">
+                    {fields.map(({ key, name: index }) => (
+                      <div key={key} className="flex items-center gap-2">
+                        <Form.Item className="mb-0 flex-1" name={[index, "path"]} rules={[{ required: true }]}>
+                          <Input placeholder={t("workflow_node.start.form.webhook_payload_mappings.path.placeholder")} />
+                        </Form.Item>
+                        <Form.Item className="mb-0 flex-1" name={[index, "variable"]} rules={[{ required: true }]}>
+                          <Input addonBefore="trigger." placeholder={t("workflow_node.start.form.webhook_payload_mappings.variable.placeholder")} />
+                        </Form.Item>
+                        <Button color="default" icon={<IconCircleMinus size="1.25em" />} type="text" onClick={() => remove(index)} />
+                      </div>
+                    ))}
+                    <Button className="w-full" type="dashed" icon={<IconCirclePlus size="1.25em" />} onClick={() => add({ path: "", variable: "" })}>
+                      {t("workflow_node.start.form.webhook_payload_mappings.add.button")}
+                    </Button>
+                  </div>
+                )}
+              </Form.List>
+            </Form.Item>
+
+            <Form.Item>
+              <Tips message={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.webhook_secret.guide") }}></span>} />
+            </Form.Item>
+          </Show>
+
+          <Show when={fieldTrigger === WORKFLOW_TRIGGERS.EVENT}>
+            <Form.Item name="eventType" label={t("workflow_node.start.form.event_type.label")} rules={[formRule]}>
+              <Select
+                options={Object.values(WORKFLOW_EVENT_TYPES).map((value) => ({
+                  label: t(`workflow_node.start.form.event_type.option.${value.replaceAll(".", "_")}.label`),
+                  value: value,
+                }))}
+                placeholder={t("workflow_node.start.form.event_type.placeholder")}
+                onChange={handleEventTypeChange}
+              />
+            </Form.Item>
+
+            <Form.Item
+              name="eventExpiringDays"
+              hidden={fieldEventType !== WORKFLOW_EVENT_TYPES.CERTIFICATE_EXPIRING}
+              label={t("workflow_node.start.form.event_expiring_days.label")}
+              tooltip={t("workflow_node.start.form.event_expiring_days.tooltip")}
+              rules={[formRule]}
+            >
+              <InputNumber
+                className="w-full"
+                min={1}
+                placeholder={t("workflow_node.start.form.event_expiring_days.placeholder")}
+                addonAfter={t("workflow_node.start.form.event_expiring_days.unit")}
+              />
+            </Form.Item>
+
+            <Form.Item
+              name="eventWorkflowId"
+              label={t("workflow_node.start.form.event_workflow_id.label")}
+              tooltip={t("workflow_node.start.form.event_workflow_id.tooltip")}
+              rules={[formRule]}
+            >
+              <Select
+                allowClear
+                loading={eventWorkflowOptionsLoading}
+                options={eventWorkflowOptions}
+                placeholder={t("workflow_node.start.form.event_workflow_id.placeholder")}
+                showSearch
+                optionFilterProp="label"
+              />
+            </Form.Item>
+
+            <Form.Item
+              name="eventRunStatus"
+              hidden={fieldEventType !== WORKFLOW_EVENT_TYPES.WORKFLOW_RUN_COMPLETED}
+              label={t("workflow_node.start.form.event_run_status.label")}
+              rules={[formRule]}
+            >
+              <Radio.Group>
+                <Radio value="">{t("workflow_node.start.form.event_run_status.option.any.label")}</Radio>
+                <Radio value={WORKFLOW_RUN_STATUSES.SUCCEEDED}>{t("workflow_node.start.form.event_run_status.option.succeeded.label")}</Radio>
+                <Radio value={WORKFLOW_RUN_STATUSES.FAILED}>{t("workflow_node.start.form.event_run_status.option.failed.label")}</Radio>
+              </Radio.Group>
+            </Form.Item>
+
+            <Form.Item>
+              <Tips message={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.event_type.guide") }}></span>} />
+            </Form.Item>
+          </Show>
+        </div>
+
+        <div id="variables" data-anchor="variables">
+          <Form.Item
+            label={t("workflow_node.start.form.variables.label")}
+            tooltip={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.variables.tooltip") }}></span>}
+          >
+            <Form.List name="variables">
+              {(fields, { add, remove }) => (
+                <div className="flex flex-col gap-2">
+                  {fields.map(({ key, name: index }) => (
+                    <div key={key} className="flex flex-col gap-2 rounded-md border border-solid border-gray-200 p-2 dark:border-gray-700">
+                      <div className="flex items-center gap-2">
+                        <Form.Item className="mb-0 flex-1" name={[index, "key"]} rules={[formRule]}>
+                          <Input placeholder={t("workflow_node.start.form.variables.key.placeholder")} />
+                        </Form.Item>
+                        <Form.Item className="mb-0 w-32" name={[index, "type"]} rules={[formRule]}>
+                          <Select
+                            options={Object.values(WORKFLOW_VARIABLE_TYPES).map((value) => ({
+                              label: t(`workflow_node.start.form.variables.type.option.${value}.label`),
+                              value: value,
+                            }))}
+                          />
+                        </Form.Item>
+                        <Button color="default" icon={<IconCircleMinus size="1.25em" />} type="text" onClick={() => remove(index)} />
+                      </div>
+                      <Form.Item
+                        noStyle
+                        dependencies={[
+                          ["variables", index, "type"],
+                          ["variables", index, "secretSource"],
+                        ]}
+                      >
+                        {() => {
+                          const fieldType = formInst.getFieldValue(["variables", index, "type"]);
+                          const fieldSecretSource = formInst.getFieldValue(["variables", index, "secretSource"]);
+
+                          if (fieldType !== WORKFLOW_VARIABLE_TYPES.SECRET) {
+                            return (
+                              <Form.Item className="mb-0" name={[index, "defaultValue"]} rules={[formRule]}>
+                                <Input placeholder={t("workflow_node.start.form.variables.default_value.placeholder")} />
+                              </Form.Item>
+                            );
+                          }
+
+                          return (
+                            <div className="flex items-center gap-2">
+                              <Form.Item className="mb-0 w-32" name={[index, "secretSource"]} rules={[formRule]}>
+                                <Select
+                                  options={Object.values(WORKFLOW_VARIABLE_SECRET_SOURCES).map((value) => ({
+                                    label: t(`workflow_node.start.form.variables.secret_source.option.${value}.label`),
+                                    value: value,
+                                  }))}
+                                  placeholder={t("workflow_node.start.form.variables.secret_source.placeholder")}
+                                />
+                              </Form.Item>
+                              <Show when={fieldSecretSource === WORKFLOW_VARIABLE_SECRET_SOURCES.ACCESS}>
+                                <Form.Item className="mb-0 flex-1" name={[index, "secretAccessId"]} rules={[formRule]}>
+                                  <AccessSelect placeholder={t("workflow_node.start.form.variables.secret_access_id.placeholder")} showSearch />
+                                </Form.Item>
+                                <Form.Item className="mb-0 flex-1" name={[index, "secretAccessField"]} rules={[formRule]}>
+                                  <Input placeholder={t("workflow_node.start.form.variables.secret_access_field.placeholder")} />
+                                </Form.Item>
+                              </Show>
+                              <Show when={fieldSecretSource === WORKFLOW_VARIABLE_SECRET_SOURCES.ENV}>
+                                <Form.Item className="mb-0 flex-1" name={[index, "secretEnvName"]} rules={[formRule]}>
+                                  <Input placeholder={WORKFLOW_VARIABLE_SECRET_ENV_PREFIX + "XXX"} />
+                                </Form.Item>
+                              </Show>
+                            </div>
+                          );
+                        }}
+                      </Form.Item>
+                      <Form.Item className="mb-0" name={[index, "description"]} rules={[formRule]}>
+                        <Input placeholder={t("workflow_node.start.form.variables.description.placeholder")} />
+                      </Form.Item>
+                    </div>
+                  ))}
+                  <Button
+                    className="w-full"
+                    type="dashed"
+                    icon={<IconCirclePlus size="1.25em" />}
+                    onClick={() => add({ key: "", type: WORKFLOW_VARIABLE_TYPES.STRING })}
+                  >
+                    {t("workflow_node.start.form.variables.add.button")}
+                  </Button>
+                </div>
+              )}
+            </Form.List>
+          </Form.Item>
+
+          <Form.Item>
+            <Tips message={<span dangerouslySetInnerHTML={{ __html: t("workflow_node.start.form.variables.guide") }}></span>} />
+          </Form.Item>
         </div>
       </Form>
     </NodeFormContextProvider>
@@ -117,7 +378,7 @@ const StartNodeConfigForm = ({ node, ...props }: StartNodeConfigFormProps) => {
 const getAnchorItems = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> }): Required<AnchorProps>["items"] => {
   const { t } = i18n;
 
-  return ["parameters"].map((key) => ({
+  return ["parameters", "variables"].map((key) => ({
     key: key,
     title: t(`workflow_node.start.form_anchor.${key}.tab`),
     href: "#" + key,
@@ -138,8 +399,116 @@ const getSchema = ({ i18n = getI18n() }: { i18n?: ReturnType<typeof getI18n> })
     .object({
       trigger: z.string().nonempty(),
       triggerCron: z.string().nullish(),
+      webhookPayloadMappings: z
+        .array(
+          z.object({
+            path: z.string().nonempty(),
+            variable: z.string().regex(/^[A-Za-z_][A-Za-z0-9_.]*$/, t("workflow_node.start.form.webhook_payload_mappings.variable.errmsg.invalid")),
+          })
+        )
+        .nullish(),
+      eventType: z.string().nullish(),
+      eventWorkflowId: z.string().nullish(),
+      eventExpiringDays: z.coerce.number().int().positive().nullish(),
+      eventRunStatus: z.string().nullish(),
+      variables: z
+        .array(
+          z
+            .object({
+              key: z.string().regex(/^[A-Za-z_][A-Za-z0-9_]*$/, t("workflow_node.start.form.variables.key.errmsg.invalid")),
+              type: z.enum(WORKFLOW_VARIABLE_TYPES),
+              defaultValue: z.string().nullish(),
+              description: z.string().nullish(),
+              secretSource: z.string().nullish(),
+              secretAccessId: z.string().nullish(),
+              secretAccessField: z.string().nullish(),
+              secretEnvName: z.string().nullish(),
+            })
+            .superRefine((variable, ctx) => {
+              if (variable.type === WORKFLOW_VARIABLE_TYPES.SECRET) {
+                if (!variable.secretSource) {
+                  ctx.addIssue({
+                    code: "custom",
+                    message: t("workflow_node.start.form.variables.secret_source.placeholder"),
+                    path: ["secretSource"],
+                  });
+                } else if (variable.secretSource === WORKFLOW_VARIABLE_SECRET_SOURCES.ACCESS) {
+                  if (!variable.secretAccessId) {
+                    ctx.addIssue({
+                      code: "custom",
+                      message: t("workflow_node.start.form.variables.secret_access_id.placeholder"),
+                      path: ["secretAccessId"],
+                    });
+                  }
+                  if (!variable.secretAccessField) {
+                    ctx.addIssue({
+                      code: "custom",
+                      message: t("workflow_node.start.form.variables.secret_access_field.placeholder"),
+                      path: ["secretAccessField"],
+                    });
+                  }
+                } else if (variable.secretSource === WORKFLOW_VARIABLE_SECRET_SOURCES.ENV) {
+                  if (!variable.secretEnvName?.startsWith(WORKFLOW_VARIABLE_SECRET_ENV_PREFIX) || variable.secretEnvName === WORKFLOW_VARIABLE_SECRET_ENV_PREFIX) {
+                    ctx.addIssue({
+                      code: "custom",
+                      message: t("workflow_node.start.form.variables.secret_env_name.errmsg.invalid", { prefix: WORKFLOW_VARIABLE_SECRET_ENV_PREFIX }),
+                      path: ["secretEnvName"],
+                    });
+                  }
+                }
+                return;
+              }
+
+              if (variable.defaultValue) {
+                const valid =
+                  variable.type === WORKFLOW_VARIABLE_TYPES.NUMBER
+                    ? /^[-+]?\d+$/.test(variable.defaultValue)
+                    : variable.type === WORKFLOW_VARIABLE_TYPES.BOOLEAN
+                      ? ["true", "false"].includes(variable.defaultValue)
+                      : variable.type === WORKFLOW_VARIABLE_TYPES.DATETIME
+                        ? dayjs(variable.defaultValue).isValid()
+                        : true;
+                if (!valid) {
+                  ctx.addIssue({
+                    code: "custom",
+                    message: t("workflow_node.start.form.variables.default_value.errmsg.invalid"),
+                    path: ["defaultValue"],
+                  });
+                }
+              }
+            })
+        )
+        .nullish(),
     })
     .superRefine((values, ctx) => {
+      const variableKeys = new Set<string>();
+      values.variables?.forEach((variable, index) => {
+        if (variableKeys.has(variable.key)) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.start.form.variables.key.errmsg.duplicate"),
+            path: ["variables", index, "key"],
+          });
+        }
+        variableKeys.add(variable.key);
+      });
+
+      if (values.trigger === WORKFLOW_TRIGGERS.EVENT) {
+        if (!values.eventType) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.start.form.event_type.placeholder"),
+            path: ["eventType"],
+          });
+        } else if (values.eventType === WORKFLOW_EVENT_TYPES.CERTIFICATE_EXPIRING && !values.eventExpiringDays) {
+          ctx.addIssue({
+            code: "custom",
+            message: t("workflow_node.start.form.event_expiring_days.placeholder"),
+            path: ["eventExpiringDays"],
+          });
+        }
+      }
+
       if (values.trigger === WORKFLOW_TRIGGERS.SCHEDULED) {
         const scTriggerCron = z.string().refine((v) => validateCronExpression(v), t("workflow_node.start.form.trigger_cron.errmsg.invalid"));
         const spTriggerCron = scTriggerCron.safeParse(values.triggerCron);
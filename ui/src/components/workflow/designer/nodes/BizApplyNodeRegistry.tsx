@@ -3,7 +3,7 @@ import { FeedbackLevel, Field } from "@flowgram.ai/fixed-layout-editor";
 import { IconContract } from "@tabler/icons-react";
 import { Avatar } from "antd";
 
-import { acmeDns01ProvidersMap, acmeHttp01ProvidersMap } from "@/domain/provider";
+import { acmeDns01ProvidersMap, acmeHttp01ProvidersMap, acmeTlsAlpn01ProvidersMap } from "@/domain/provider";
 import { type WorkflowNodeConfigForBizApply, newNode } from "@/domain/workflow";
 
 import { BaseNode } from "./_shared";
@@ -43,10 +43,10 @@ export const BizApplyNodeRegistry: NodeRegistry = {
       const { t } = getI18n();
 
       type MapValueType<M> = M extends Map<string, infer V> ? V : never;
-      const acmeProvidersMap = new Map<string, MapValueType<typeof acmeDns01ProvidersMap | typeof acmeHttp01ProvidersMap>>([
-        ...acmeDns01ProvidersMap,
-        ...acmeHttp01ProvidersMap,
-      ]);
+      const acmeProvidersMap = new Map<
+        string,
+        MapValueType<typeof acmeDns01ProvidersMap | typeof acmeHttp01ProvidersMap | typeof acmeTlsAlpn01ProvidersMap>
+      >([...acmeDns01ProvidersMap, ...acmeHttp01ProvidersMap, ...acmeTlsAlpn01ProvidersMap]);
 
       return (
         <BaseNode
@@ -6,7 +6,7 @@ import { Avatar } from "antd";
 import { deploymentProvidersMap } from "@/domain/provider";
 import { newNode } from "@/domain/workflow";
 
-import { getAllPreviousNodes } from "../_util";
+import { getAllPreviousNodes, getStartNode } from "../_util";
 import { BaseNode } from "./_shared";
 import { NodeKindType, type NodeRegistry, NodeType } from "./typings";
 import BizDeployNodeConfigForm from "../forms/BizDeployNodeConfigForm";
@@ -42,6 +42,10 @@ export const BizDeployNodeRegistry: NodeRegistry = {
         if (value == null) return;
 
         const prevNodeIds = getAllPreviousNodes(node).map((e) => e.id);
+        const startNode = getStartNode(node);
+        if (startNode && BizDeployNodeConfigForm.hasStartNodeCertificateOutput(startNode.form?.getValueIn("config"))) {
+          prevNodeIds.push(startNode.id);
+        }
         if (!prevNodeIds.includes(value)) {
           return {
             message: "Invalid input",
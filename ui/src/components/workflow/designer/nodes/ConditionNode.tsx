@@ -115,15 +115,19 @@ export const BranchBlockNodeRegistry: NodeRegistry = {
             <>
               <div className="flex items-center justify-center gap-2">
                 <div className="flex items-center justify-center">
-                  <Field<Expr> name="config.expression">
-                    {({ field: { value } }) => (
-                      <>
-                        {value == null ? (
-                          <IconFilter size="1.25em" stroke="1.25" />
-                        ) : (
-                          <IconFilterFilled color="var(--color-primary)" size="1.25em" stroke="1.25" />
+                  <Field<string> name="config.expressionText">
+                    {({ field: { value: text } }) => (
+                      <Field<Expr> name="config.expression">
+                        {({ field: { value } }) => (
+                          <>
+                            {value == null && !text ? (
+                              <IconFilter size="1.25em" stroke="1.25" />
+                            ) : (
+                              <IconFilterFilled color="var(--color-primary)" size="1.25em" stroke="1.25" />
+                            )}
+                          </>
                         )}
-                      </>
+                      </Field>
                     )}
                   </Field>
                 </div>
@@ -133,16 +137,26 @@ export const BranchBlockNodeRegistry: NodeRegistry = {
               </div>
               <div className="mt-1">
                 <div className="truncate">
-                  <Field<Expr> name="config.expression">
-                    {({ field: { value } }) => (
-                      <Typography.Text className="text-xs" type="secondary">
-                        {value == null
-                          ? t("workflow_node.branch_block.state.no")
-                          : value.type === ExprType.Logical && value.operator === "and"
-                            ? t("workflow_node.branch_block.state.and")
-                            : t("workflow_node.branch_block.state.or")}
-                      </Typography.Text>
-                    )}
+                  <Field<string> name="config.expressionText">
+                    {({ field: { value: text } }) =>
+                      text ? (
+                        <Typography.Text className="text-xs" type="secondary">
+                          {text}
+                        </Typography.Text>
+                      ) : (
+                        <Field<Expr> name="config.expression">
+                          {({ field: { value } }) => (
+                            <Typography.Text className="text-xs" type="secondary">
+                              {value == null
+                                ? t("workflow_node.branch_block.state.no")
+                                : value.type === ExprType.Logical && value.operator === "and"
+                                  ? t("workflow_node.branch_block.state.and")
+                                  : t("workflow_node.branch_block.state.or")}
+                            </Typography.Text>
+                          )}
+                        </Field>
+                      )
+                    }
                   </Field>
                 </div>
               </div>
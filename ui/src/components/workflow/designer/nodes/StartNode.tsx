@@ -59,7 +59,11 @@ export const StartNodeRegistry: NodeRegistry = {
                         ? t("workflow.props.trigger.scheduled")
                         : fieldTrigger === WORKFLOW_TRIGGERS.MANUAL
                           ? t("workflow.props.trigger.manual")
-                          : t("workflow.detail.design.editor.placeholder")}
+                          : fieldTrigger === WORKFLOW_TRIGGERS.WEBHOOK
+                            ? t("workflow.props.trigger.webhook")
+                            : fieldTrigger === WORKFLOW_TRIGGERS.EVENT
+                              ? t("workflow.props.trigger.event")
+                              : t("workflow.detail.design.editor.placeholder")}
                     </div>
                     <div>
                       <Field name="config.triggerCron">
@@ -15,13 +15,17 @@ export interface CertificateModel extends BaseModel {
   validityNotAfter: ISO8601String;
   isRenewed: boolean;
   isRevoked: boolean;
+  revokedReason?: string;
+  revokedAt?: ISO8601String;
   workflowRef: string;
+  acmeServerClientRef?: string;
   expand?: {
     workflowRef?: Pick<WorkflowModel, "id" | "name" | "description">;
   };
 }
 
 export const CERTIFICATE_SOURCES = Object.freeze({
+  ACMESERVER: "acmeserver",
   REQUEST: "request",
   UPLOAD: "upload",
 } as const);
@@ -602,6 +602,43 @@ export const acmeHttp01ProvidersMap: Map<ACMEHttp01Provider["type"] | string, AC
 );
 // #endregion
 
+// #region ACMETLSALPN01Provider
+/*
+  注意：如果追加新的常量值，请保持以 ASCII 排序。
+  NOTICE: If you add new constant, please keep ASCII order.
+ */
+export const ACME_TLSALPN01_PROVIDERS = Object.freeze({
+  LOCAL: `${ACCESS_PROVIDERS.LOCAL}`,
+  SSH: `${ACCESS_PROVIDERS.SSH}`,
+} as const);
+
+export type ACMETlsAlpn01ProviderType = (typeof ACME_TLSALPN01_PROVIDERS)[keyof typeof ACME_TLSALPN01_PROVIDERS];
+
+export interface ACMETlsAlpn01Provider extends BaseProviderWithAccess<ACMETlsAlpn01ProviderType> {}
+
+export const acmeTlsAlpn01ProvidersMap: Map<ACMETlsAlpn01Provider["type"] | string, ACMETlsAlpn01Provider> = new Map(
+  /*
+    注意：此处的顺序决定显示在前端的顺序。
+    NOTICE: The following order determines the order displayed at the frontend.
+   */
+  (
+    [
+      [ACME_TLSALPN01_PROVIDERS.LOCAL, "provider.local", "builtin"],
+      [ACME_TLSALPN01_PROVIDERS.SSH, "provider.ssh"],
+    ] satisfies Array<[ACMETlsAlpn01ProviderType, string, "builtin"] | [ACMETlsAlpn01ProviderType, string]>
+  ).map(([type, name, builtin]) => [
+    type,
+    {
+      type: type,
+      name: name,
+      icon: accessProvidersMap.get(type.split("-")[0])!.icon,
+      provider: type.split("-")[0] as AccessProviderType,
+      builtin: builtin === "builtin",
+    },
+  ])
+);
+// #endregion
+
 // #region DeploymentProvider
 /*
   注意：如果追加新的常量值，请保持以 ASCII 排序。
@@ -9,6 +9,8 @@ export interface WorkflowModel extends BaseModel {
   description?: string;
   trigger: string;
   triggerCron?: string;
+  webhookToken?: string;
+  webhookSecret?: string;
   enabled?: boolean;
   graphDraft?: WorkflowGraph;
   graphContent?: WorkflowGraph;
@@ -29,20 +31,39 @@ export interface WorkflowGraph {
 export const WORKFLOW_TRIGGERS = Object.freeze({
   SCHEDULED: "scheduled",
   MANUAL: "manual",
+  DRYRUN: "dryrun",
+  WEBHOOK: "webhook",
+  EVENT: "event",
 } as const);
 
 export type WorkflowTriggerType = (typeof WORKFLOW_TRIGGERS)[keyof typeof WORKFLOW_TRIGGERS];
 
+export const WORKFLOW_EVENT_TYPES = Object.freeze({
+  CERTIFICATE_EXPIRING: "certificate.expiring",
+  CERTIFICATE_REVOKED: "certificate.revoked",
+  CERTIFICATE_CREATED: "certificate.created",
+  WORKFLOW_RUN_COMPLETED: "workflow_run.completed",
+} as const);
+
+export type WorkflowEventType = (typeof WORKFLOW_EVENT_TYPES)[keyof typeof WORKFLOW_EVENT_TYPES];
+
 // #region Node
 export const WORKFLOW_NODE_TYPES = Object.freeze({
   START: "start",
   END: "end",
   DELAY: "delay",
+  FOREACH: "forEach",
   CONDITION: "condition",
   BRANCHBLOCK: "branchBlock",
   TRYCATCH: "tryCatch",
   TRYBLOCK: "tryBlock",
   CATCHBLOCK: "catchBlock",
+  PARALLEL: "parallel",
+  PARALLELBLOCK: "parallelBlock",
+  CALLWORKFLOW: "callWorkflow",
+  APPROVAL: "approval",
+  APPROVEBLOCK: "approveBlock",
+  REJECTBLOCK: "rejectBlock",
   BIZ_APPLY: "bizApply",
   BIZ_UPLOAD: "bizUpload",
   BIZ_MONITOR: "bizMonitor",
@@ -59,14 +80,72 @@ export type WorkflowNode = {
     name?: string;
     disabled?: boolean;
     config?: Record<string, unknown>;
+    retry?: WorkflowNodeRetryPolicy;
+    timeout?: number;
     [key: string]: unknown;
   };
   blocks?: WorkflowNode[];
 };
 
+export const WORKFLOW_NODE_RETRY_BACKOFFS = Object.freeze({
+  EXPONENTIAL: "exponential",
+  FIXED: "fixed",
+} as const);
+
+export type WorkflowNodeRetryBackoffType = (typeof WORKFLOW_NODE_RETRY_BACKOFFS)[keyof typeof WORKFLOW_NODE_RETRY_BACKOFFS];
+
+export type WorkflowNodeRetryPolicy = {
+  maxAttempts: number;
+  backoff?: WorkflowNodeRetryBackoffType;
+  interval?: number;
+  maxInterval?: number;
+  retryOn?: string[];
+};
+
 export type WorkflowNodeConfigForStart = {
   trigger: string;
   triggerCron?: string;
+  webhookPayloadMappings?: WorkflowNodeConfigForStartWebhookPayloadMapping[];
+  eventType?: WorkflowEventType;
+  eventWorkflowId?: string;
+  eventExpiringDays?: number;
+  eventRunStatus?: string;
+  variables?: WorkflowNodeConfigForStartVariable[];
+};
+
+export type WorkflowNodeConfigForStartWebhookPayloadMapping = {
+  path: string;
+  variable: string;
+};
+
+export const WORKFLOW_VARIABLE_TYPES = Object.freeze({
+  STRING: "string",
+  NUMBER: "number",
+  BOOLEAN: "boolean",
+  DATETIME: "datetime",
+  SECRET: "secret",
+} as const);
+
+export type WorkflowVariableType = (typeof WORKFLOW_VARIABLE_TYPES)[keyof typeof WORKFLOW_VARIABLE_TYPES];
+
+export const WORKFLOW_VARIABLE_SECRET_SOURCES = Object.freeze({
+  ACCESS: "access",
+  ENV: "env",
+} as const);
+
+export type WorkflowVariableSecretSourceType = (typeof WORKFLOW_VARIABLE_SECRET_SOURCES)[keyof typeof WORKFLOW_VARIABLE_SECRET_SOURCES];
+
+export const WORKFLOW_VARIABLE_SECRET_ENV_PREFIX = "CERTIMATE_WORKFLOW_SECRET_";
+
+export type WorkflowNodeConfigForStartVariable = {
+  key: string;
+  type: WorkflowVariableType;
+  defaultValue?: string;
+  description?: string;
+  secretSource?: WorkflowVariableSecretSourceType;
+  secretAccessId?: string;
+  secretAccessField?: string;
+  secretEnvName?: string;
 };
 
 export const defaultNodeConfigForStart = (): Partial<WorkflowNodeConfigForStart> => {
@@ -83,14 +162,92 @@ export const defaultNodeConfigForDelay = (): Partial<WorkflowNodeConfigForDelay>
   return {};
 };
 
+export type WorkflowNodeConfigForForEach = {
+  items?: string;
+  itemsVariable?: string;
+  itemsVariableNodeId?: string;
+  itemsSeparator?: string;
+};
+
+export const defaultNodeConfigForForEach = (): Partial<WorkflowNodeConfigForForEach> => {
+  return {
+    itemsSeparator: ";",
+  };
+};
+
+export const WORKFLOW_CONDITION_MODES = Object.freeze({
+  ALL: "all",
+  EXCLUSIVE: "exclusive",
+} as const);
+
+export type WorkflowConditionModeType = (typeof WORKFLOW_CONDITION_MODES)[keyof typeof WORKFLOW_CONDITION_MODES];
+
+export type WorkflowNodeConfigForCondition = {
+  mode?: WorkflowConditionModeType;
+};
+
 export type WorkflowNodeConfigForBranchBlock = {
   expression?: Expr;
+  expressionText?: string;
+  isDefault?: boolean;
 };
 
 export const defaultNodeConfigForBranchBlock = (): Partial<WorkflowNodeConfigForBranchBlock> => {
   return {};
 };
 
+export type WorkflowNodeConfigForParallel = {
+  maxConcurrency?: number;
+};
+
+export const defaultNodeConfigForParallel = (): Partial<WorkflowNodeConfigForParallel> => {
+  return {};
+};
+
+export const WORKFLOW_CALL_MODES = Object.freeze({
+  INLINE: "inline",
+  CHILD: "child",
+} as const);
+
+export type WorkflowCallModeType = (typeof WORKFLOW_CALL_MODES)[keyof typeof WORKFLOW_CALL_MODES];
+
+export type WorkflowNodeConfigForCallWorkflow = {
+  workflowId: string;
+  mode?: WorkflowCallModeType;
+  inputs?: { nodeId: string; name: string }[];
+};
+
+export const defaultNodeConfigForCallWorkflow = (): Partial<WorkflowNodeConfigForCallWorkflow> => {
+  return {
+    mode: WORKFLOW_CALL_MODES.INLINE,
+    inputs: [],
+  };
+};
+
+export const WORKFLOW_APPROVAL_TIMEOUT_ACTIONS = Object.freeze({
+  REJECT: "reject",
+  APPROVE: "approve",
+} as const);
+
+export type WorkflowApprovalTimeoutActionType = (typeof WORKFLOW_APPROVAL_TIMEOUT_ACTIONS)[keyof typeof WORKFLOW_APPROVAL_TIMEOUT_ACTIONS];
+
+export type WorkflowNodeConfigForApproval = {
+  subject: string;
+  message: string;
+  provider: string;
+  providerAccessId: string;
+  providerConfig?: Record<string, unknown>;
+  timeout: number;
+  timeoutAction?: WorkflowApprovalTimeoutActionType;
+};
+
+export const defaultNodeConfigForApproval = (): Partial<WorkflowNodeConfigForApproval> => {
+  return {
+    timeout: 86400,
+    timeoutAction: WORKFLOW_APPROVAL_TIMEOUT_ACTIONS.REJECT,
+  };
+};
+
 export type WorkflowNodeConfigForBizApply = {
   identifier: "domain" | "ip";
   domains: string;
@@ -100,12 +257,14 @@ export type WorkflowNodeConfigForBizApply = {
   provider: string;
   providerAccessId: string;
   providerConfig?: Record<string, unknown>;
+  providerMappings?: WorkflowNodeConfigForBizApplyProviderMapping[];
   caProvider?: string;
   caProviderAccessId?: string;
   caProviderConfig?: Record<string, unknown>;
   keySource: string;
   keyAlgorithm: string;
   keyContent?: string;
+  csrContent?: string;
   validityLifetime?: string;
   acmeProfile?: string;
   nameservers?: string;
@@ -113,11 +272,19 @@ export type WorkflowNodeConfigForBizApply = {
   dnsPropagationTimeout?: number;
   dnsTTL?: number;
   httpDelayWait?: number;
+  tlsAlpnDelayWait?: number;
   disableFollowCNAME?: boolean;
   disableARI?: boolean;
   skipBeforeExpiryDays: number;
 };
 
+export type WorkflowNodeConfigForBizApplyProviderMapping = {
+  zone: string;
+  provider: string;
+  providerAccessId: string;
+  providerConfig?: Record<string, unknown>;
+};
+
 export const defaultNodeConfigForBizApply = (): Partial<WorkflowNodeConfigForBizApply> => {
   return {
     challengeType: "dns-01" as const,
@@ -278,6 +445,79 @@ export const newNode = (type: WorkflowNodeType, { i18n = getI18n() }: { i18n?: R
         },
       };
 
+    case WORKFLOW_NODE_TYPES.FOREACH:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.for_each.default_name"),
+          config: defaultNodeConfigForForEach(),
+        },
+        blocks: [],
+      };
+
+    case WORKFLOW_NODE_TYPES.PARALLEL:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.parallel.default_name"),
+          config: defaultNodeConfigForParallel(),
+        },
+        blocks: [newNode(WORKFLOW_NODE_TYPES.PARALLELBLOCK, { i18n }), newNode(WORKFLOW_NODE_TYPES.PARALLELBLOCK, { i18n })],
+      };
+
+    case WORKFLOW_NODE_TYPES.PARALLELBLOCK:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.parallel_block.default_name"),
+        },
+        blocks: [],
+      };
+
+    case WORKFLOW_NODE_TYPES.CALLWORKFLOW:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.call_workflow.default_name"),
+          config: defaultNodeConfigForCallWorkflow(),
+        },
+      };
+
+    case WORKFLOW_NODE_TYPES.APPROVAL:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.approval.default_name"),
+          config: defaultNodeConfigForApproval(),
+        },
+        blocks: [newNode(WORKFLOW_NODE_TYPES.APPROVEBLOCK, { i18n }), newNode(WORKFLOW_NODE_TYPES.REJECTBLOCK, { i18n })],
+      };
+
+    case WORKFLOW_NODE_TYPES.APPROVEBLOCK:
+      return {
+        id: newNodeId(),
+        type: type,
+        data: {
+          name: t("workflow_node.approve_block.default_name"),
+        },
+        blocks: [],
+      };
+
+    case WORKFLOW_NODE_TYPES.REJECTBLOCK:
+      return {
+        id: newNodeId(),
+        type: type,
+        blocks: [newNode(WORKFLOW_NODE_TYPES.END, { i18n })],
+        data: {
+          name: t("workflow_node.reject_block.default_name"),
+        },
+      };
+
     case WORKFLOW_NODE_TYPES.BIZ_APPLY:
       return {
         id: newNodeId(),
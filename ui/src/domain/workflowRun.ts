@@ -8,6 +8,10 @@ export interface WorkflowRunModel extends BaseModel {
   endedAt: ISO8601String;
   graph?: WorkflowGraph;
   error?: string;
+  errorNodeId?: string;
+  resumedFromRunRef?: string;
+  resumedFromNodeId?: string;
+  versionRef?: string;
   outputs?: Array<{
     type: string;
     name: string;
@@ -22,6 +26,7 @@ export interface WorkflowRunModel extends BaseModel {
 export const WORKFLOW_RUN_STATUSES = Object.freeze({
   PENDING: "pending",
   PROCESSING: "processing",
+  WAITING: "waiting",
   SUCCEEDED: "succeeded",
   FAILED: "failed",
   CANCELED: "canceled",
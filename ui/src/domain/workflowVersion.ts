@@ -0,0 +1,37 @@
+import { type WorkflowGraph } from "./workflow";
+
+export interface WorkflowVersionModel extends BaseModel {
+  workflowRef: string;
+  version: number;
+  graph?: WorkflowGraph;
+  author?: string;
+  comment?: string;
+}
+
+export interface WorkflowGraphDiff {
+  addedNodes: WorkflowGraphDiffNode[];
+  removedNodes: WorkflowGraphDiffNode[];
+  changedNodes: WorkflowGraphDiffNode[];
+}
+
+export interface WorkflowGraphDiffNode {
+  id: string;
+  type: string;
+  name: string;
+  changes?: WorkflowGraphDiffChange[];
+}
+
+export interface WorkflowGraphDiffChange {
+  path: string;
+  type: WorkflowGraphDiffChangeType;
+  oldValue?: unknown;
+  newValue?: unknown;
+}
+
+export const WORKFLOW_GRAPH_DIFF_CHANGE_TYPES = Object.freeze({
+  ADDED: "added",
+  REMOVED: "removed",
+  MODIFIED: "modified",
+} as const);
+
+export type WorkflowGraphDiffChangeType = (typeof WORKFLOW_GRAPH_DIFF_CHANGE_TYPES)[keyof typeof WORKFLOW_GRAPH_DIFF_CHANGE_TYPES];
@@ -9,6 +9,7 @@ import nlsSettings from "./nls.settings.json";
 import nlsWorkflow from "./nls.workflow.json";
 import nlsWorkflowNodes from "./nls.workflow.nodes.json";
 import nlsWorkflowRuns from "./nls.workflow.runs.json";
+import nlsWorkflowVersions from "./nls.workflow.versions.json";
 import { buildTranslations } from "../utils";
 
 export default Object.freeze(
@@ -23,6 +24,7 @@ export default Object.freeze(
     nlsCertificate,
     nlsWorkflow,
     nlsWorkflowNodes,
-    nlsWorkflowRuns
+    nlsWorkflowRuns,
+    nlsWorkflowVersions
   )
 );
@@ -1,7 +1,7 @@
 import { useEffect, useMemo, useRef, useState } from "react";
 import { useTranslation } from "react-i18next";
 import { Outlet, useLocation, useNavigate, useParams } from "react-router-dom";
-import { IconEdit, IconHistory, IconPlayerPlay, IconRobot } from "@tabler/icons-react";
+import { IconEdit, IconHistory, IconPlayerPlay, IconRobot, IconVersions } from "@tabler/icons-react";
 import { useSize } from "ahooks";
 import { App, Button, Input, type InputRef, Segmented, Skeleton, Spin } from "antd";
 
@@ -40,6 +40,7 @@ const WorkflowDetail = () => {
   const tabs = [
     ["design", "workflow.detail.design.tab", <IconRobot size="1em" />],
     ["runs", "workflow.detail.runs.tab", <IconHistory size="1em" />],
+    ["versions", "workflow.detail.versions.tab", <IconVersions size="1em" />],
   ] satisfies [string, string, React.ReactElement][];
   const [tabValue, setTabValue] = useState<string>(() => location.pathname.split("/")[3]);
   useEffect(() => {
@@ -64,7 +65,7 @@ const WorkflowDetail = () => {
     setRunButtonLoading(running);
   }, [workflow.lastRunStatus]);
 
-  const handleRunClick = () => {
+  const handleRunClick = (dryRun?: boolean) => {
     const { promise, resolve } = Promise.withResolvers();
     if (workflow.hasDraft) {
       modal.confirm({
@@ -80,7 +81,7 @@ const WorkflowDetail = () => {
       try {
         setRunButtonLoading(true);
 
-        await startWorkflowRun(workflow.id);
+        await startWorkflowRun(workflow.id, { dryRun });
 
         message.info(t("workflow.text.running_prompt"));
       } catch (err) {
@@ -136,7 +137,10 @@ const WorkflowDetail = () => {
             <Show when={initialized}>
               <div className="flex items-center gap-2 not-md:justify-end">
                 <Button onClick={handleActiveClick}>{workflow.enabled ? t("workflow.action.disable.button") : t("workflow.action.enable.button")}</Button>
-                <Button disabled={runButtonDisabled} icon={<IconPlayerPlay size="1.25em" />} loading={runButtonLoading} type="primary" onClick={handleRunClick}>
+                <Button disabled={runButtonDisabled || runButtonLoading} onClick={() => handleRunClick(true)}>
+                  {t("workflow.action.dryrun.button")}
+                </Button>
+                <Button disabled={runButtonDisabled} icon={<IconPlayerPlay size="1.25em" />} loading={runButtonLoading} type="primary" onClick={() => handleRunClick()}>
                   {t("workflow.action.execute.button")}
                 </Button>
               </div>
@@ -3,7 +3,7 @@ import { useTranslation } from "react-i18next";
 import { FlowLayoutDefault } from "@flowgram.ai/fixed-layout-editor";
 import { IconArrowBackUp, IconDots, IconTransferIn, IconTransferOut } from "@tabler/icons-react";
 import { useDeepCompareEffect } from "ahooks";
-import { Alert, App, Button, Card, Dropdown, Result, Space, theme } from "antd";
+import { Alert, App, Button, Card, Dropdown, Input, Result, Space, theme } from "antd";
 import { debounce } from "radash";
 
 import Show from "@/components/Show";
@@ -84,12 +84,23 @@ const WorkflowDetailDesign = () => {
       return;
     }
 
+    let comment = "";
     modal.confirm({
       title: t("workflow.detail.design.action.publish.modal.title"),
-      content: t("workflow.detail.design.action.publish.modal.content"),
+      content: (
+        <div className="flex flex-col gap-2">
+          <span>{t("workflow.detail.design.action.publish.modal.content")}</span>
+          <Input.TextArea
+            autoSize={{ minRows: 2, maxRows: 5 }}
+            maxLength={500}
+            placeholder={t("workflow.detail.design.action.publish.modal.comment.placeholder")}
+            onChange={(e) => (comment = e.target.value.trim())}
+          />
+        </div>
+      ),
       onOk: async () => {
         try {
-          await workflowStore.publish();
+          await workflowStore.publish(comment);
 
           message.success(t("common.text.operation_succeeded"));
         } catch (err) {
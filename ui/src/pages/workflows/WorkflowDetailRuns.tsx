@@ -59,6 +59,12 @@ const WorkflowDetailRuns = () => {
           return t("workflow_run.props.trigger.scheduled");
         } else if (record.trigger === WORKFLOW_TRIGGERS.MANUAL) {
           return t("workflow_run.props.trigger.manual");
+        } else if (record.trigger === WORKFLOW_TRIGGERS.DRYRUN) {
+          return t("workflow_run.props.trigger.dryrun");
+        } else if (record.trigger === WORKFLOW_TRIGGERS.WEBHOOK) {
+          return t("workflow_run.props.trigger.webhook");
+        } else if (record.trigger === WORKFLOW_TRIGGERS.EVENT) {
+          return t("workflow_run.props.trigger.event");
         }
 
         return <></>;
@@ -0,0 +1,355 @@
+import { useState } from "react";
+import { useTranslation } from "react-i18next";
+import { IconArrowBackUp, IconDots, IconGitCompare, IconVersions } from "@tabler/icons-react";
+import { useRequest } from "ahooks";
+import { App, Button, Dropdown, Modal, Skeleton, Table, type TableProps, Tag, Typography } from "antd";
+import dayjs from "dayjs";
+import { ClientResponseError } from "pocketbase";
+
+import { diffVersions as diffWorkflowVersions, rollbackVersion as rollbackWorkflowVersion } from "@/api/workflows";
+import Empty from "@/components/Empty";
+import Show from "@/components/Show";
+import { type WorkflowGraphDiff, type WorkflowGraphDiffChange, type WorkflowGraphDiffNode, type WorkflowVersionModel } from "@/domain/workflowVersion";
+import { useAppSettings, useZustandShallowSelector } from "@/hooks";
+import { list as listWorkflowVersions } from "@/repository/workflowVersion";
+import { useWorkflowStore } from "@/stores/workflow";
+import { unwrapErrMsg } from "@/utils/error";
+
+const WorkflowDetailVersions = () => {
+  const { t } = useTranslation();
+
+  const { message, modal, notification } = App.useApp();
+
+  const { appSettings: globalAppSettings } = useAppSettings();
+
+  const { workflow } = useWorkflowStore(useZustandShallowSelector(["workflow"]));
+
+  const [page, setPage] = useState<number>(1);
+  const [pageSize, setPageSize] = useState<number>(globalAppSettings.defaultPerPage!);
+
+  const [tableData, setTableData] = useState<WorkflowVersionModel[]>([]);
+  const [tableTotal, setTableTotal] = useState<number>(0);
+  const [latestVersion, setLatestVersion] = useState<number>(0);
+  const tableColumns: TableProps<WorkflowVersionModel>["columns"] = [
+    {
+      key: "version",
+      title: t("workflow_version.props.version"),
+      width: 160,
+      render: (_, record) => (
+        <div className="flex items-center gap-2">
+          <span className="font-mono">v{record.version}</span>
+          <Show when={record.version === latestVersion}>
+            <Tag color="processing">{t("workflow_version.text.current")}</Tag>
+          </Show>
+        </div>
+      ),
+    },
+    {
+      key: "author",
+      title: t("workflow_version.props.author"),
+      ellipsis: true,
+      render: (_, record) => record.author || "-",
+    },
+    {
+      key: "comment",
+      title: t("workflow_version.props.comment"),
+      ellipsis: true,
+      render: (_, record) => record.comment || "-",
+    },
+    {
+      key: "createdAt",
+      title: t("workflow_version.props.created_at"),
+      ellipsis: true,
+      render: (_, record) => dayjs(record.created).format("YYYY-MM-DD HH:mm:ss"),
+    },
+    {
+      key: "$action",
+      align: "end",
+      fixed: "right",
+      width: 64,
+      render: (_, record) => {
+        const diffPreviousDisabled = record.version <= 1;
+        const diffLatestDisabled = record.version === latestVersion;
+        const rollbackDisabled = record.version === latestVersion;
+
+        return (
+          <Dropdown
+            menu={{
+              items: [
+                {
+                  key: "diff_previous",
+                  label: t("workflow_version.action.diff_previous.menu"),
+                  icon: (
+                    <span className="anticon scale-125">
+                      <IconGitCompare size="1em" />
+                    </span>
+                  ),
+                  disabled: diffPreviousDisabled,
+                  onClick: () => {
+                    handleRecordDiffPreviousClick(record);
+                  },
+                },
+                {
+                  key: "diff_latest",
+                  label: t("workflow_version.action.diff_latest.menu"),
+                  icon: (
+                    <span className="anticon scale-125">
+                      <IconGitCompare size="1em" />
+                    </span>
+                  ),
+                  disabled: diffLatestDisabled,
+                  onClick: () => {
+                    handleRecordDiffLatestClick(record);
+                  },
+                },
+                {
+                  type: "divider",
+                },
+                {
+                  key: "rollback",
+                  label: t("workflow_version.action.rollback.menu"),
+                  icon: (
+                    <span className="anticon scale-125">
+                      <IconArrowBackUp size="1em" />
+                    </span>
+                  ),
+                  danger: true,
+                  disabled: rollbackDisabled,
+                  onClick: () => {
+                    handleRecordRollbackClick(record);
+                  },
+                },
+              ],
+            }}
+            trigger={["click"]}
+          >
+            <Button icon={<IconDots size="1.25em" />} type="text" />
+          </Dropdown>
+        );
+      },
+    },
+  ];
+
+  const {
+    loading,
+    error: loadError,
+    run: refreshData,
+  } = useRequest(
+    () => {
+      return listWorkflowVersions({
+        workflowId: workflow.id,
+        page: page,
+        perPage: pageSize,
+      });
+    },
+    {
+      refreshDeps: [workflow.id, page, pageSize],
+      onSuccess: (res) => {
+        setTableData(res.items);
+        setTableTotal(res.totalItems);
+        if (page === 1) {
+          setLatestVersion(res.items[0]?.version ?? 0);
+        }
+      },
+      onError: (err) => {
+        if (err instanceof ClientResponseError && err.isAbort) {
+          return;
+        }
+
+        console.error(err);
+        notification.error({ title: t("common.text.request_error"), description: unwrapErrMsg(err) });
+
+        throw err;
+      },
+    }
+  );
+
+  const handlePaginationChange = (page: number, pageSize: number) => {
+    setPage(page);
+    setPageSize(pageSize);
+  };
+
+  const [diffOpen, setDiffOpen] = useState(false);
+  const [diffLoading, setDiffLoading] = useState(false);
+  const [diffData, setDiffData] = useState<WorkflowGraphDiff & { fromVersion: number; toVersion: number }>();
+
+  const openDiff = async (options: { from?: string; to?: string }) => {
+    setDiffOpen(true);
+    setDiffLoading(true);
+    setDiffData(void 0);
+
+    try {
+      const resp = await diffWorkflowVersions(workflow.id, options);
+      setDiffData(resp.data);
+    } catch (err) {
+      setDiffOpen(false);
+
+      console.error(err);
+      notification.error({ title: t("common.text.request_error"), description: unwrapErrMsg(err) });
+    } finally {
+      setDiffLoading(false);
+    }
+  };
+
+  const handleRecordDiffPreviousClick = (workflowVersion: WorkflowVersionModel) => {
+    openDiff({ to: workflowVersion.id });
+  };
+
+  const handleRecordDiffLatestClick = (workflowVersion: WorkflowVersionModel) => {
+    openDiff({ from: workflowVersion.id });
+  };
+
+  const handleRecordRollbackClick = (workflowVersion: WorkflowVersionModel) => {
+    modal.confirm({
+      title: t("workflow_version.action.rollback.modal.title", { version: workflowVersion.version }),
+      content: <span dangerouslySetInnerHTML={{ __html: t("workflow_version.action.rollback.modal.content") }} />,
+      icon: (
+        <span className="anticon" role="img">
+          <IconArrowBackUp className="text-warning" size="1em" />
+        </span>
+      ),
+      onOk: async () => {
+        try {
+          await rollbackWorkflowVersion(workflow.id, workflowVersion.id);
+
+          message.success(t("common.text.operation_succeeded"));
+
+          setPage(1);
+          refreshData();
+        } catch (err) {
+          console.error(err);
+          notification.error({ title: t("common.text.request_error"), description: unwrapErrMsg(err) });
+        }
+      },
+    });
+  };
+
+  return (
+    <div className="container">
+      <div className="pt-9">
+        <Table<WorkflowVersionModel>
+          columns={tableColumns}
+          dataSource={tableData}
+          loading={loading}
+          locale={{
+            emptyText: loading ? (
+              <Skeleton />
+            ) : (
+              <Empty
+                className="py-24"
+                title={loadError ? t("common.text.nodata_failed") : t("workflow_version.text.nodata")}
+                description={loadError ? unwrapErrMsg(loadError) : t("workflow_version.text.nodata_description")}
+                icon={<IconVersions size={24} />}
+              />
+            ),
+          }}
+          pagination={{
+            current: page,
+            pageSize: pageSize,
+            total: tableTotal,
+            showSizeChanger: true,
+            onChange: handlePaginationChange,
+            onShowSizeChange: handlePaginationChange,
+          }}
+          rowKey={(record) => record.id}
+          scroll={{ x: "max(100%, 960px)" }}
+        />
+
+        <Modal
+          footer={null}
+          open={diffOpen}
+          title={diffData ? t("workflow_version.action.diff.modal.title", { from: diffData.fromVersion, to: diffData.toVersion }) : " "}
+          width={720}
+          onCancel={() => setDiffOpen(false)}
+        >
+          <Show when={!diffLoading && !!diffData} fallback={<Skeleton active />}>
+            <WorkflowVersionDiffView data={diffData!} />
+          </Show>
+        </Modal>
+      </div>
+    </div>
+  );
+};
+
+const WorkflowVersionDiffView = ({ data }: { data: WorkflowGraphDiff }) => {
+  const { t } = useTranslation();
+
+  if (data.addedNodes.length === 0 && data.removedNodes.length === 0 && data.changedNodes.length === 0) {
+    return <Typography.Text type="secondary">{t("workflow_version.text.no_changes")}</Typography.Text>;
+  }
+
+  const renderNode = (node: WorkflowGraphDiffNode) => (
+    <div className="flex items-center gap-2">
+      <span>{node.name || "-"}</span>
+      <Typography.Text className="font-mono text-xs" type="secondary">
+        {node.type} #{node.id}
+      </Typography.Text>
+    </div>
+  );
+
+  const renderValue = (value: unknown) => {
+    if (value == null) return "-";
+    if (typeof value === "string") return value;
+    return JSON.stringify(value);
+  };
+
+  const renderChange = (change: WorkflowGraphDiffChange) => (
+    <div key={change.path} className="flex items-start gap-2 text-xs">
+      <Tag className="shrink-0" color={change.type === "added" ? "success" : change.type === "removed" ? "error" : "warning"}>
+        {t(`workflow_version.diff.change.${change.type}`)}
+      </Tag>
+      <span className="shrink-0 font-mono">{change.path}</span>
+      <span className="break-all text-gray-500">
+        <Show when={change.type !== "added"}>
+          <del>{renderValue(change.oldValue)}</del>
+        </Show>
+        <Show when={change.type === "modified"}>{" → "}</Show>
+        <Show when={change.type !== "removed"}>
+          <span>{renderValue(change.newValue)}</span>
+        </Show>
+      </span>
+    </div>
+  );
+
+  return (
+    <div className="flex flex-col gap-4">
+      <Show when={data.addedNodes.length > 0}>
+        <div>
+          <Typography.Title level={5}>{t("workflow_version.diff.added_nodes")}</Typography.Title>
+          <div className="flex flex-col gap-1">
+            {data.addedNodes.map((node) => (
+              <div key={node.id}>{renderNode(node)}</div>
+            ))}
+          </div>
+        </div>
+      </Show>
+
+      <Show when={data.removedNodes.length > 0}>
+        <div>
+          <Typography.Title level={5}>{t("workflow_version.diff.removed_nodes")}</Typography.Title>
+          <div className="flex flex-col gap-1">
+            {data.removedNodes.map((node) => (
+              <div key={node.id}>{renderNode(node)}</div>
+            ))}
+          </div>
+        </div>
+      </Show>
+
+      <Show when={data.changedNodes.length > 0}>
+        <div>
+          <Typography.Title level={5}>{t("workflow_version.diff.changed_nodes")}</Typography.Title>
+          <div className="flex flex-col gap-3">
+            {data.changedNodes.map((node) => (
+              <div key={node.id}>
+                {renderNode(node)}
+                <div className="mt-1 flex flex-col gap-1 pl-2">{node.changes?.map((change) => renderChange(change))}</div>
+              </div>
+            ))}
+          </div>
+        </div>
+      </Show>
+    </div>
+  );
+};
+
+export default WorkflowDetailVersions;
@@ -81,6 +81,10 @@ const WorkflowList = () => {
               <Typography.Text type="secondary">{record.triggerCron || "\u00A0"}</Typography.Text>
             </div>
           );
+        } else if (trigger === WORKFLOW_TRIGGERS.WEBHOOK) {
+          return <Typography.Text>{t("workflow.props.trigger.webhook")}</Typography.Text>;
+        } else if (trigger === WORKFLOW_TRIGGERS.EVENT) {
+          return <Typography.Text>{t("workflow.props.trigger.event")}</Typography.Text>;
         }
       },
     },
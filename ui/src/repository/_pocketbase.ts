@@ -22,5 +22,6 @@ export const COLLECTION_NAME_CERTIFICATE = "certificate";
 export const COLLECTION_NAME_SETTINGS = "settings";
 export const COLLECTION_NAME_WORKFLOW = "workflow";
 export const COLLECTION_NAME_WORKFLOW_RUN = "workflow_run";
+export const COLLECTION_NAME_WORKFLOW_VERSION = "workflow_version";
 export const COLLECTION_NAME_WORKFLOW_OUTPUT = "workflow_output";
 export const COLLECTION_NAME_WORKFLOW_LOG = "workflow_logs";
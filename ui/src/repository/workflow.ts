@@ -12,6 +12,8 @@ const _commonFields = [
   "description",
   "trigger",
   "triggerCron",
+  "webhookToken",
+  "webhookSecret",
   "enabled",
   "hasDraft",
   "hasContent",
@@ -71,12 +73,14 @@ export const get = async (id: string) => {
   });
 };
 
-export const save = async (record: MaybeModelRecord<WorkflowModel>) => {
+export const save = async (record: MaybeModelRecord<WorkflowModel>, options?: { versionComment?: string }) => {
+  // 发布说明不是工作流的字段，仅随请求一并提交，由服务端写入版本记录
+  const body = options?.versionComment ? { ...record, versionComment: options.versionComment } : record;
   if (record.id) {
-    return await pbco.update<WorkflowModel>(record.id as string, record);
+    return await pbco.update<WorkflowModel>(record.id as string, body);
   }
 
-  return await pbco.create<WorkflowModel>(record);
+  return await pbco.create<WorkflowModel>(body);
 };
 
 export const remove = async (record: MaybeModelRecordWithId<WorkflowModel> | MaybeModelRecordWithId<WorkflowModel>[]) => {
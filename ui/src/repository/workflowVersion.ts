@@ -0,0 +1,23 @@
+﻿import { type WorkflowVersionModel } from "@/domain/workflowVersion";
+
+import { COLLECTION_NAME_WORKFLOW_VERSION, getPocketBase } from "./_pocketbase";
+
+const pb = getPocketBase();
+const pbco = pb.collection(COLLECTION_NAME_WORKFLOW_VERSION);
+
+const _commonFields = ["id", "workflowRef", "version", "author", "comment", "created", "updated"];
+
+export const list = async ({ workflowId, page = 1, perPage = 10 }: { workflowId: string; page?: number; perPage?: number }) => {
+  return await pbco.getList<WorkflowVersionModel>(page, perPage, {
+    fields: _commonFields.join(","),
+    filter: pb.filter("workflowRef={:workflowId}", { workflowId }),
+    sort: "-version",
+    requestKey: null,
+  });
+};
+
+export const get = async (id: string) => {
+  return await pbco.getOne<WorkflowVersionModel>(id, {
+    requestKey: null,
+  });
+};
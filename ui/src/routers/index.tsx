@@ -19,6 +19,7 @@ import SettingsSSLProvider from "@/pages/settings/SettingsSSLProvider";
 import WorkflowDetail from "@/pages/workflows/WorkflowDetail";
 import WorkflowDetailDesign from "@/pages/workflows/WorkflowDetailDesign";
 import WorkflowDetailRuns from "@/pages/workflows/WorkflowDetailRuns";
+import WorkflowDetailVersions from "@/pages/workflows/WorkflowDetailVersions";
 import WorkflowList from "@/pages/workflows/WorkflowList";
 import WorkflowNew from "@/pages/workflows/WorkflowNew";
 
@@ -63,6 +64,10 @@ export const router = createHashRouter([
             path: "/workflows/:id/runs",
             element: <WorkflowDetailRuns />,
           },
+          {
+            path: "/workflows/:id/versions",
+            element: <WorkflowDetailVersions />,
+          },
         ],
       },
       {
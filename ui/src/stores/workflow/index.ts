@@ -114,26 +114,31 @@ export const useWorkflowStore = create<WorkflowStore>((set, get) => {
       });
     },
 
-    publish: async () => {
+    publish: async (comment) => {
       ensureInitialized();
 
       const graph = get().workflow.graphDraft!;
       if (graph?.nodes?.[0]?.type !== WORKFLOW_NODE_TYPES.START) throw "Workflow nodes tree of draft in invalid";
       const startConfig = graph.nodes[0].data.config as WorkflowNodeConfigForStart;
-      const resp = await saveWorkflow({
-        id: get().workflow.id!,
-        trigger: startConfig.trigger,
-        triggerCron: startConfig.triggerCron,
-        graphContent: graph,
-        hasContent: true,
-        hasDraft: false,
-      });
+      const resp = await saveWorkflow(
+        {
+          id: get().workflow.id!,
+          trigger: startConfig.trigger,
+          triggerCron: startConfig.triggerCron,
+          graphContent: graph,
+          hasContent: true,
+          hasDraft: false,
+        },
+        { versionComment: comment }
+      );
 
       set((state) => {
         return {
           workflow: produce(state.workflow, (draft) => {
             draft.trigger = resp.trigger;
             draft.triggerCron = resp.triggerCron;
+            draft.webhookToken = resp.webhookToken;
+            draft.webhookSecret = resp.webhookSecret;
             draft.graphContent = resp.graphContent;
             draft.hasContent = resp.hasContent;
             draft.hasDraft = resp.hasDraft;
@@ -162,6 +167,8 @@ export const useWorkflowStore = create<WorkflowStore>((set, get) => {
           workflow: produce(state.workflow, (draft) => {
             draft.trigger = resp.trigger;
             draft.triggerCron = resp.triggerCron;
+            draft.webhookToken = resp.webhookToken;
+            draft.webhookSecret = resp.webhookSecret;
             draft.hasContent = resp.hasContent;
             draft.graphDraft = resp.graphDraft;
             draft.hasDraft = resp.hasDraft;
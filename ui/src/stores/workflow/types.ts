@@ -14,7 +14,7 @@ export interface WorkflowActions {
   setEnabled(enabled: Required<WorkflowModel>["enabled"]): void;
 
   orchestrate(graph: WorkflowGraph): void;
-  publish(): void;
+  publish(comment?: string): void;
   rollback(): void;
 }
 